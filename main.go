@@ -42,6 +42,7 @@ import (
 	"github.com/submariner-io/submariner/pkg/natdiscovery"
 	"github.com/submariner-io/submariner/pkg/types"
 	"github.com/submariner-io/submariner/pkg/versions"
+	"github.com/submariner-io/submariner/pkg/webhook"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -144,12 +145,18 @@ func main() {
 	})
 	logger.FatalOnError(err, "Error creating gateway instance")
 
-	err = gw.Run(signals.SetupSignalHandler())
+	ctx := signals.SetupSignalHandler()
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	if submSpec.WebhookPort != 0 {
+		startWebhookServer(ctx, &submSpec)
+	}
+
+	err = gw.Run(ctx)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	if err := httpServer.Shutdown(ctx); err != nil {
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
 		logger.Errorf(err, "Error shutting down metrics HTTP server")
 	}
 
@@ -170,3 +177,16 @@ func startHTTPServer(spec *types.SubmarinerSpecification) *http.Server {
 
 	return srv
 }
+
+func startWebhookServer(ctx context.Context, spec *types.SubmarinerSpecification) {
+	server, err := webhook.NewServer(spec.WebhookPort, spec.WebhookCertDir)
+	logger.FatalOnError(err, "Error creating the webhook server")
+
+	logger.Infof("Starting the webhook server on port %d", spec.WebhookPort)
+
+	go func() {
+		if err := server.Start(ctx); err != nil {
+			logger.Errorf(err, "Error running the webhook server")
+		}
+	}()
+}
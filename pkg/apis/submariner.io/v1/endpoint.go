@@ -102,3 +102,37 @@ func (ep *EndpointSpec) hasSameBackendConfig(other *EndpointSpec) bool {
 
 	return equality.Semantic.DeepEqual(ep.BackendConfig, other.BackendConfig)
 }
+
+// GetCapabilities returns the capabilities advertised by the Endpoint. If the Endpoint doesn't advertise any, because its
+// gateway predates capability publication, the capabilities of such a legacy gateway are inferred from its spec.
+func (ep *EndpointSpec) GetCapabilities() *EndpointCapabilities {
+	if ep.Capabilities != nil {
+		return ep.Capabilities
+	}
+
+	legacy := &EndpointCapabilities{
+		Backends: []string{ep.Backend},
+	}
+
+	if ep.BackendConfig[NATTDiscoveryPortConfig] != "" {
+		// The initial version of the NAT discovery protocol.
+		legacy.NATDiscoveryVersion = 1
+	}
+
+	if ep.HealthCheckIP != "" {
+		legacy.HealthCheckProbes = []string{HealthCheckProbeICMP}
+	}
+
+	return legacy
+}
+
+// SupportsHealthCheckProbe returns whether the Endpoint supports the given health check probe type.
+func (ep *EndpointSpec) SupportsHealthCheckProbe(probe string) bool {
+	for _, p := range ep.GetCapabilities().HealthCheckProbes {
+		if p == probe {
+			return true
+		}
+	}
+
+	return false
+}
@@ -27,6 +27,7 @@ import (
 var _ = Describe("EndpointSpec", func() {
 	Context("GenerateName", testGenerateName)
 	Context("Equals", testEquals)
+	Context("GetCapabilities", testGetCapabilities)
 })
 
 func testGenerateName() {
@@ -138,3 +139,39 @@ func testEquals() {
 		})
 	})
 }
+
+func testGetCapabilities() {
+	var spec *v1.EndpointSpec
+
+	BeforeEach(func() {
+		spec = &v1.EndpointSpec{
+			Backend:       "libreswan",
+			HealthCheckIP: "10.1.0.1",
+			BackendConfig: map[string]string{v1.NATTDiscoveryPortConfig: "4490"},
+		}
+	})
+
+	When("the Endpoint publishes its capabilities", func() {
+		It("should return them", func() {
+			spec.Capabilities = &v1.EndpointCapabilities{NATDiscoveryVersion: 2}
+			Expect(spec.GetCapabilities()).To(Equal(spec.Capabilities))
+		})
+	})
+
+	When("the Endpoint doesn't publish its capabilities", func() {
+		It("should infer those of a legacy gateway", func() {
+			Expect(spec.GetCapabilities()).To(Equal(&v1.EndpointCapabilities{
+				Backends:            []string{"libreswan"},
+				NATDiscoveryVersion: 1,
+				HealthCheckProbes:   []string{v1.HealthCheckProbeICMP},
+			}))
+		})
+
+		Context("and has no NAT discovery port", func() {
+			It("should not infer a NAT discovery protocol version", func() {
+				spec.BackendConfig = nil
+				Expect(spec.GetCapabilities().NATDiscoveryVersion).To(BeZero())
+			})
+		})
+	})
+}
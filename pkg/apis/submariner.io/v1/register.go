@@ -61,6 +61,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&GatewayRouteList{},
 		&NonGatewayRoute{},
 		&NonGatewayRouteList{},
+		&RoutingPolicy{},
+		&RoutingPolicyList{},
+		&InterClusterNetworkPolicy{},
+		&InterClusterNetworkPolicyList{},
+		&ConnectivitySummary{},
+		&ConnectivitySummaryList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 
@@ -23,6 +23,7 @@ import (
 	"net"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -86,6 +87,53 @@ type EndpointSpec struct {
 	NATEnabled    bool              `json:"nat_enabled"`
 	Backend       string            `json:"backend"`
 	BackendConfig map[string]string `json:"backend_config,omitempty"`
+	// TransitRoutes advertises the remote clusters reachable through this transit (hub) gateway. Transit routing isn't
+	// supported by the libreswan cable driver.
+	// +optional
+	TransitRoutes []TransitRoute `json:"transitRoutes,omitempty"`
+	// Capabilities advertises the features supported by the gateway. It's not set by gateways predating capability
+	// publication.
+	// +optional
+	Capabilities *EndpointCapabilities `json:"capabilities,omitempty"`
+}
+
+// EndpointCapabilities advertises the features supported by a gateway so gateways running different versions can
+// negotiate the features they have in common.
+type EndpointCapabilities struct {
+	// Backends lists the cable drivers the gateway supports, that is the registered cable drivers.
+	// +optional
+	Backends []string `json:"backends,omitempty"`
+	// NATDiscoveryVersion is the highest NAT discovery protocol version the gateway supports. Zero means the gateway
+	// doesn't take part in NAT discovery.
+	// +optional
+	NATDiscoveryVersion int32 `json:"natDiscoveryVersion,omitempty"`
+	// HealthCheckProbes lists the health check probe types the gateway supports.
+	// +optional
+	HealthCheckProbes []string `json:"healthCheckProbes,omitempty"`
+	// IPv6 indicates whether the gateway supports IPv6 subnets. It's always false as IPv6 isn't supported yet.
+	// +optional
+	IPv6 bool `json:"ipv6,omitempty"`
+	// Globalnet indicates whether the gateway's cluster uses Globalnet.
+	// +optional
+	Globalnet bool `json:"globalnet,omitempty"`
+}
+
+// Health check probe types.
+const (
+	HealthCheckProbeICMP    = "icmp"
+	HealthCheckProbePathMTU = "pathMTU"
+)
+
+// TransitRoute describes the reachability of a remote cluster's subnets through one or more transit gateways.
+// Transit routing requires a route-based cable driver, such as vxlan or wireguard, on the transit gateways and the clusters
+// that use them. It isn't supported by the libreswan cable driver, the default, whose IPsec policies only cover the subnets
+// of the clusters at each end of a cable, so gateways configured for transit with libreswan fail to start.
+type TransitRoute struct {
+	ClusterID string   `json:"cluster_id"`
+	Subnets   []string `json:"subnets"`
+	// Path lists the IDs of the transit clusters the remote cluster is reached through, starting with the nearest one.
+	// It's used to prevent routing loops.
+	Path []string `json:"path"`
 }
 
 const (
@@ -98,6 +146,15 @@ const (
 	TCPMssValue             = "submariner.io/tcp-clamp-mss"
 )
 
+// EndpointHeartbeatAnnotation holds the time, in RFC 3339 format, an Endpoint was last renewed on the broker by its gateway.
+// Endpoints whose heartbeat is too old are considered stale and removed from the broker.
+const EndpointHeartbeatAnnotation = "submariner.io/heartbeat"
+
+// MaintenanceAnnotation, when set to "true" on the local Cluster, temporarily takes the cluster out of the multi-cluster mesh
+// without uninstalling it: its Endpoint is withdrawn from the broker and no cables are installed, while the Globalnet
+// allocations are kept. Removing the annotation restores connectivity.
+const MaintenanceAnnotation = "submariner.io/maintenance"
+
 // Valid PublicIP resolvers.
 const (
 	IPv4         = "ipv4" // ipv4:1.2.3.4
@@ -139,8 +196,23 @@ type GatewayStatus struct {
 	LocalEndpoint EndpointSpec `json:"localEndpoint"`
 	StatusFailure string       `json:"statusFailure"`
 	Connections   []Connection `json:"connections"`
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// TransitRoutes lists the remote clusters that aren't connected directly but are reached through transit gateways.
+	// It's always empty with the libreswan cable driver, which doesn't support transit routing.
+	// +optional
+	TransitRoutes []TransitRoute `json:"transitRoutes,omitempty"`
 }
 
+const (
+	// GatewayRemoteCIDROverlap indicates whether Endpoints from remote clusters were rejected because their subnets overlap
+	// with the local subnets.
+	GatewayRemoteCIDROverlap = "RemoteCIDROverlap"
+
+	// GatewayMaintenance indicates whether the local cluster is in maintenance mode.
+	GatewayMaintenance = "Maintenance"
+)
+
 // LatencySpec describes the round trip time information for a packet
 // between the gateway pods of two clusters.
 type LatencyRTTSpec struct {
@@ -174,6 +246,9 @@ type Connection struct {
 	UsingNAT      bool             `json:"usingNAT,omitempty"`
 	// +optional
 	LatencyRTT *LatencyRTTSpec `json:"latencyRTT,omitempty"`
+	// PathMTU is the largest packet size, in bytes, measured to reach the remote endpoint through the connection.
+	// +optional
+	PathMTU int `json:"pathMTU,omitempty"`
 }
 
 type ConnectionStatus string
@@ -182,6 +257,8 @@ const (
 	Connected       ConnectionStatus = "connected"
 	Connecting      ConnectionStatus = "connecting"
 	ConnectionError ConnectionStatus = "error"
+	// Excluded indicates the remote cluster is intentionally not connected as it isn't allowed by the peering policy.
+	Excluded ConnectionStatus = "excluded"
 )
 
 func NewConnection(endpointSpec *EndpointSpec, usedIP string, nat bool) *Connection {
@@ -222,9 +299,21 @@ type GlobalEgressIPSpec struct {
 	// Selects specific pods in the namespace of this GlobalEgressIP to which this GlobalEgressIP applies. If not specified,
 	// all pods in the namespace are selected.
 	// If a pod matches multiple GlobalEgressIP objects, there is no guarantee from which GlobalEgressIP its
-	// GlobalIP will be assigned.
+	// GlobalIP will be assigned. A GlobalEgressIP with a PodSelector takes precedence over one without, and any
+	// GlobalEgressIP takes precedence over a ClusterGlobalEgressIP.
 	// +optional
 	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+
+	// The maximum rate, in bytes per second, at which the selected pods may send traffic to remote clusters. Traffic
+	// exceeding this rate is dropped. If not specified, the bandwidth is not limited.
+	// +optional
+	BandwidthLimit *resource.Quantity `json:"bandwidthLimit,omitempty"`
+
+	// The maximum number of concurrent connections the selected pods may initiate to remote clusters. New connections
+	// exceeding this number are dropped. If not specified, the number of connections is not limited.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConnections *int `json:"maxConnections,omitempty"`
 }
 
 type GlobalEgressIPConditionType string
@@ -232,6 +321,9 @@ type GlobalEgressIPConditionType string
 const (
 	GlobalEgressIPAllocated GlobalEgressIPConditionType = "Allocated"
 	GlobalEgressIPUpdated   GlobalEgressIPConditionType = "Updated"
+	// GlobalIPPoolExhaustion indicates whether the global IP pool had reached a usage threshold, or was exhausted, when
+	// the object's GlobalIPs were allocated.
+	GlobalIPPoolExhaustion GlobalEgressIPConditionType = "PoolExhaustion"
 )
 
 type GlobalEgressIPStatus struct {
@@ -257,7 +349,9 @@ type GlobalEgressIPList struct {
 // +kubebuilder:resource:scope="Cluster",shortName="cgeip"
 // +kubebuilder:subresource:status
 // ClusterGlobalEgressIP defines a policy for allocating GlobalIPs at the cluster level to be used when no GlobalEgressIP
-// applies.
+// applies. The instance with the well-known name "cluster-egress.submariner.io" applies to all pods in the cluster.
+// Additional instances may select pods across namespaces via a PodSelector and/or a NamespaceSelector; these take
+// precedence over the well-known instance.
 type ClusterGlobalEgressIP struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -277,6 +371,19 @@ type ClusterGlobalEgressIPSpec struct {
 	// +kubebuilder:validation:Maximum=20
 	// +optional
 	NumberOfIPs *int `json:"numGlobalIPs,omitempty"`
+
+	// Selects the pods, across all namespaces selected by the NamespaceSelector, to which this ClusterGlobalEgressIP
+	// applies. If not specified, all pods in the selected namespaces are selected. Not supported for the well-known
+	// instance.
+	// If a pod matches multiple ClusterGlobalEgressIP objects, there is no guarantee from which ClusterGlobalEgressIP its
+	// GlobalIP will be assigned.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+
+	// Selects the namespaces whose pods this ClusterGlobalEgressIP applies to. If not specified, pods in all namespaces
+	// are selected. Not supported for the well-known instance.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -325,6 +432,9 @@ const (
 	ClusterIPService         TargetType = "ClusterIPService"
 	HeadlessServicePod       TargetType = "HeadlessServicePod"
 	HeadlessServiceEndpoints TargetType = "HeadlessServiceEndpoints"
+	// Pod targets an individual Pod, referenced by PodRef, independently of any Service. The GlobalIP is retained while
+	// the Pod's IP changes and traffic is forwarded to its current IP.
+	Pod TargetType = "Pod"
 )
 
 type GlobalIngressIPStatus struct {
@@ -392,3 +502,209 @@ type RoutePolicySpec struct {
 	// Specifies the remote CIDRs available via the next hop
 	RemoteCIDRs []string `json:"remoteCIDRs"`
 }
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName="rtpol"
+// +kubebuilder:subresource:status
+
+// RoutingPolicy pins traffic destined to specific remote CIDRs, optionally only from Pods in selected namespaces,
+// to a chosen gateway node and/or egress interface instead of the active gateway.
+type RoutingPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RoutingPolicySpec `json:"spec"`
+
+	// +optional
+	Status RoutingPolicyStatus `json:"status,omitempty"`
+}
+
+type RoutingPolicySpec struct {
+	// RemoteCIDRs specifies the remote CIDRs whose traffic is routed according to this policy.
+	RemoteCIDRs []string `json:"remoteCIDRs"`
+
+	// NamespaceSelector restricts the policy to traffic from Pods in the selected namespaces. If not specified,
+	// traffic from all sources is routed according to this policy.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// GatewayNode specifies the name of the gateway node through which the traffic is routed. If not specified,
+	// each node routes the traffic through its EgressInterface.
+	// +optional
+	GatewayNode string `json:"gatewayNode,omitempty"`
+
+	// EgressInterface specifies the interface on the gateway node through which the traffic leaves the node.
+	// +optional
+	EgressInterface string `json:"egressInterface,omitempty"`
+}
+
+type RoutingPolicyStatus struct {
+	// Nodes reports whether each node has applied the policy.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Nodes []RoutingPolicyNodeStatus `json:"nodes,omitempty"`
+}
+
+type RoutingPolicyNodeStatus struct {
+	// Name is the name of the node.
+	Name string `json:"name"`
+
+	// Applied indicates whether the policy was successfully applied on the node.
+	Applied bool `json:"applied"`
+
+	// Message provides details on the state of the policy on the node, in particular why it could not be applied.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastUpdated is the time the status was last updated.
+	LastUpdated metav1.Time `json:"lastUpdated"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type RoutingPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []RoutingPolicy `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope="Cluster",shortName="icnp"
+
+// InterClusterNetworkPolicy restricts which remote clusters may reach the selected local Pods. Local Pods not selected
+// by any policy remain reachable from all the remote clusters. Policies aren't enforced with the OVN-Kubernetes network
+// plugin, as it doesn't forward the inter-cluster traffic through the gateway node's FORWARD chain.
+type InterClusterNetworkPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec InterClusterNetworkPolicySpec `json:"spec"`
+}
+
+type InterClusterNetworkPolicySpec struct {
+	// NamespaceSelector selects the local namespaces whose Pods the policy applies to. An empty selector selects all
+	// namespaces.
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+
+	// PodSelector restricts the policy to the matching Pods in the selected namespaces. An empty selector selects all
+	// the Pods.
+	// +optional
+	PodSelector metav1.LabelSelector `json:"podSelector,omitempty"`
+
+	// Ingress lists the remote peers allowed to reach the selected Pods. If empty, all traffic from the remote clusters
+	// to the selected Pods is dropped.
+	// +optional
+	Ingress []InterClusterNetworkPolicyPeer `json:"ingress,omitempty"`
+}
+
+// InterClusterNetworkPolicyPeer identifies remote peers by cluster and, optionally, by address. Selecting remote
+// namespaces or Pods by label isn't supported: their labels, and the global egress IPs allocated to them, aren't
+// visible in the local cluster. Remote namespaces can still be allowed by listing their global egress IPs in CIDRs.
+type InterClusterNetworkPolicyPeer struct {
+	// ClusterIDs specifies the IDs of the remote clusters allowed, as published in their Endpoints.
+	ClusterIDs []string `json:"clusterIDs"`
+
+	// CIDRs restricts the peer to these addresses within the remote clusters' subnets, for instance the global egress
+	// IPs allocated to a remote namespace when Globalnet is enabled. If not specified, all of the remote clusters'
+	// subnets are allowed.
+	// +optional
+	CIDRs []string `json:"cidrs,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type InterClusterNetworkPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []InterClusterNetworkPolicy `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope="Cluster",shortName="cs"
+// +kubebuilder:printcolumn:JSONPath=".status.expectedClusters",name="Expected",type="integer"
+// +kubebuilder:printcolumn:JSONPath=".status.connectedClusters",name="Connected",type="integer"
+// +kubebuilder:printcolumn:JSONPath=".status.conditions[?(@.type==\"Ready\")].status",name="Ready",type="string"
+
+// ConnectivitySummary summarizes the connectivity of the local cluster to each remote cluster, as reported by the local
+// Gateways, so the health of the mesh can be watched from a single resource.
+type ConnectivitySummary struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Status            ConnectivitySummaryStatus `json:"status"`
+}
+
+type ConnectivitySummaryStatus struct {
+	// ExpectedClusters is the number of remote clusters the local cluster is expected to connect to.
+	ExpectedClusters int `json:"expectedClusters"`
+
+	// ConnectedClusters is the number of expected remote clusters the local cluster is connected to.
+	ConnectedClusters int `json:"connectedClusters"`
+
+	// Clusters lists the connectivity to each remote cluster, sorted by cluster ID.
+	// +optional
+	Clusters []RemoteClusterConnectivity `json:"clusters,omitempty"`
+
+	// Conditions holds the aggregate Ready and Degraded conditions.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+type RemoteClusterConnectivity struct {
+	ClusterID string `json:"clusterID"`
+
+	// Expected indicates whether a connection to the remote cluster is expected, that is it has a synced Endpoint.
+	Expected bool `json:"expected"`
+
+	// Connected indicates whether an active local Gateway is connected to the remote cluster.
+	Connected bool `json:"connected"`
+
+	// Status is the status of the connection as reported by the active local Gateway, if any.
+	// +optional
+	Status ConnectionStatus `json:"status,omitempty"`
+
+	// Backend is the cable driver used to connect to the remote cluster.
+	// +optional
+	Backend string `json:"backend,omitempty"`
+
+	// LatencyRTT is the round trip time to the remote cluster. It's only updated when it changes significantly.
+	// +optional
+	LatencyRTT *LatencyRTTSpec `json:"latencyRTT,omitempty"`
+
+	// +optional
+	UsingNAT bool `json:"usingNAT,omitempty"`
+
+	// LastError is the last error reported for the connection. It's retained after the connection recovers.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// LastErrorTime is the time the last error was first reported.
+	// +optional
+	LastErrorTime *metav1.Time `json:"lastErrorTime,omitempty"`
+}
+
+const (
+	// ConnectivityReady indicates whether the local cluster is connected to all the expected remote clusters.
+	ConnectivityReady = "Ready"
+
+	// ConnectivityDegraded indicates whether a connection to an expected remote cluster is failing or there's no active
+	// local Gateway.
+	ConnectivityDegraded = "Degraded"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ConnectivitySummaryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ConnectivitySummary `json:"items"`
+}
@@ -0,0 +1,252 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts this Cluster to the v1 hub version.
+func (src *Cluster) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1.Cluster)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1.ClusterSpec{
+		ClusterID:   src.Spec.ClusterID,
+		ColorCodes:  src.Spec.ColorCodes,
+		ServiceCIDR: src.Spec.ServiceCIDR,
+		ClusterCIDR: src.Spec.ClusterCIDR,
+		GlobalCIDR:  src.Spec.GlobalCIDR,
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the v1 hub version to this Cluster.
+func (dst *Cluster) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1.Cluster)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = ClusterSpec{
+		ClusterID:   src.Spec.ClusterID,
+		ColorCodes:  src.Spec.ColorCodes,
+		ServiceCIDR: src.Spec.ServiceCIDR,
+		ClusterCIDR: src.Spec.ClusterCIDR,
+		GlobalCIDR:  src.Spec.GlobalCIDR,
+	}
+
+	return nil
+}
+
+// ConvertTo converts this Endpoint to the v1 hub version.
+func (src *Endpoint) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1.Endpoint)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1.EndpointSpec{
+		ClusterID:     src.Spec.ClusterID,
+		CableName:     src.Spec.CableName,
+		HealthCheckIP: src.Spec.HealthCheckIP,
+		Hostname:      src.Spec.Hostname,
+		Subnets:       src.Spec.Subnets,
+		PrivateIP:     src.Spec.PrivateIP,
+		PublicIP:      src.Spec.PublicIP,
+		NATEnabled:    src.Spec.NATEnabled,
+		Backend:       src.Spec.Backend,
+		BackendConfig: src.Spec.BackendConfig.toV1(),
+	}
+
+	for i := range src.Spec.TransitRoutes {
+		route := &src.Spec.TransitRoutes[i]
+		dst.Spec.TransitRoutes = append(dst.Spec.TransitRoutes, v1.TransitRoute{
+			ClusterID: route.ClusterID,
+			Subnets:   route.Subnets,
+			Path:      route.Path,
+		})
+	}
+
+	if src.Spec.Capabilities != nil {
+		dst.Spec.Capabilities = &v1.EndpointCapabilities{
+			Backends:            src.Spec.Capabilities.Backends,
+			NATDiscoveryVersion: src.Spec.Capabilities.NATDiscoveryVersion,
+			HealthCheckProbes:   src.Spec.Capabilities.HealthCheckProbes,
+			IPv6:                src.Spec.Capabilities.IPv6,
+			Globalnet:           src.Spec.Capabilities.Globalnet,
+		}
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the v1 hub version to this Endpoint.
+func (dst *Endpoint) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1.Endpoint)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = EndpointSpec{
+		ClusterID:     src.Spec.ClusterID,
+		CableName:     src.Spec.CableName,
+		HealthCheckIP: src.Spec.HealthCheckIP,
+		Hostname:      src.Spec.Hostname,
+		Subnets:       src.Spec.Subnets,
+		PrivateIP:     src.Spec.PrivateIP,
+		PublicIP:      src.Spec.PublicIP,
+		NATEnabled:    src.Spec.NATEnabled,
+		Backend:       src.Spec.Backend,
+		BackendConfig: backendConfigFromV1(src.Spec.BackendConfig),
+	}
+
+	for i := range src.Spec.TransitRoutes {
+		route := &src.Spec.TransitRoutes[i]
+		dst.Spec.TransitRoutes = append(dst.Spec.TransitRoutes, TransitRoute{
+			ClusterID: route.ClusterID,
+			Subnets:   route.Subnets,
+			Path:      route.Path,
+		})
+	}
+
+	if src.Spec.Capabilities != nil {
+		dst.Spec.Capabilities = &EndpointCapabilities{
+			Backends:            src.Spec.Capabilities.Backends,
+			NATDiscoveryVersion: src.Spec.Capabilities.NATDiscoveryVersion,
+			HealthCheckProbes:   src.Spec.Capabilities.HealthCheckProbes,
+			IPv6:                src.Spec.Capabilities.IPv6,
+			Globalnet:           src.Spec.Capabilities.Globalnet,
+		}
+	}
+
+	return nil
+}
+
+// backendConfigFromV1 parses the well-known v1 backend config entries into their typed fields. Entries that aren't
+// well-known, or whose values don't parse, are kept as is in the DriverConfig so no information is lost.
+func backendConfigFromV1(from map[string]string) BackendConfig {
+	to := BackendConfig{}
+
+	for key, value := range from {
+		var parsed bool
+
+		switch key {
+		case v1.UDPPortConfig:
+			to.UDPPort, parsed = parsePort(value)
+		case v1.NATTDiscoveryPortConfig:
+			to.NATTDiscoveryPort, parsed = parsePort(value)
+		case v1.PreferredServerConfig:
+			to.PreferredServer, parsed = parseBool(value)
+		case v1.UsingLoadBalancer:
+			to.UsingLoadBalancer, parsed = parseBool(value)
+		case v1.PublicIP:
+			to.PublicIPResolvers, parsed = parsePublicIPResolvers(value)
+		}
+
+		if !parsed {
+			if to.DriverConfig == nil {
+				to.DriverConfig = map[string]string{}
+			}
+
+			to.DriverConfig[key] = value
+		}
+	}
+
+	return to
+}
+
+func (c *BackendConfig) toV1() map[string]string {
+	to := map[string]string{}
+
+	for key, value := range c.DriverConfig {
+		to[key] = value
+	}
+
+	if c.UDPPort != nil {
+		to[v1.UDPPortConfig] = strconv.Itoa(int(*c.UDPPort))
+	}
+
+	if c.NATTDiscoveryPort != nil {
+		to[v1.NATTDiscoveryPortConfig] = strconv.Itoa(int(*c.NATTDiscoveryPort))
+	}
+
+	if c.PreferredServer != nil {
+		to[v1.PreferredServerConfig] = strconv.FormatBool(*c.PreferredServer)
+	}
+
+	if c.UsingLoadBalancer != nil {
+		to[v1.UsingLoadBalancer] = strconv.FormatBool(*c.UsingLoadBalancer)
+	}
+
+	if len(c.PublicIPResolvers) > 0 {
+		resolvers := make([]string, len(c.PublicIPResolvers))
+		for i := range c.PublicIPResolvers {
+			resolvers[i] = fmt.Sprintf("%s:%s", c.PublicIPResolvers[i].Type, c.PublicIPResolvers[i].Value)
+		}
+
+		to[v1.PublicIP] = strings.Join(resolvers, ",")
+	}
+
+	if len(to) == 0 {
+		return nil
+	}
+
+	return to
+}
+
+func parsePort(value string) (*int32, bool) {
+	port, err := strconv.ParseUint(value, 10, 16)
+	if err != nil || port < 1 {
+		return nil, false
+	}
+
+	p := int32(port)
+
+	return &p, true
+}
+
+func parseBool(value string) (*bool, bool) {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, false
+	}
+
+	return &b, true
+}
+
+func parsePublicIPResolvers(value string) ([]PublicIPResolver, bool) {
+	var resolvers []PublicIPResolver
+
+	for _, resolver := range strings.Split(value, ",") {
+		parts := strings.Split(strings.TrimSpace(resolver), ":")
+		if len(parts) != 2 || parts[1] == "" {
+			return nil, false
+		}
+
+		switch t := PublicIPResolverType(parts[0]); t {
+		case IPv4Resolver, LoadBalancerResolver, APIResolver, DNSResolver:
+			resolvers = append(resolvers, PublicIPResolver{Type: t, Value: parts[1]})
+		default:
+			return nil, false
+		}
+	}
+
+	return resolvers, true
+}
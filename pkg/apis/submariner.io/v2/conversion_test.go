@@ -0,0 +1,133 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	v2 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("Endpoint conversion", func() {
+	var v1Endpoint *v1.Endpoint
+
+	BeforeEach(func() {
+		v1Endpoint = &v1.Endpoint{
+			ObjectMeta: metav1.ObjectMeta{Name: "east-cable"},
+			Spec: v1.EndpointSpec{
+				ClusterID: "east",
+				CableName: "submariner-cable-east-192-68-1-2",
+				Hostname:  "redsox",
+				Subnets:   []string{"10.0.0.0/16"},
+				PrivateIP: "192.68.1.2",
+				PublicIP:  "1.2.3.4",
+				Backend:   "libreswan",
+				BackendConfig: map[string]string{
+					v1.UDPPortConfig:                   "4500",
+					v1.NATTDiscoveryPortConfig:         "4490",
+					v1.PreferredServerConfig:           "true",
+					v1.PublicIP:                        "ipv4:1.2.3.4,lb:gateway-lb",
+					v1.PreferredServerConfig + "-time": "1700000000",
+				},
+				Capabilities: &v1.EndpointCapabilities{
+					Backends:            []string{"libreswan", "wireguard"},
+					NATDiscoveryVersion: 1,
+					HealthCheckProbes:   []string{v1.HealthCheckProbeICMP},
+					Globalnet:           true,
+				},
+			},
+		}
+	})
+
+	It("should convert the well-known v1 backend config to typed fields", func() {
+		v2Endpoint := &v2.Endpoint{}
+		Expect(v2Endpoint.ConvertFrom(v1Endpoint)).To(Succeed())
+
+		Expect(v2Endpoint.Name).To(Equal(v1Endpoint.Name))
+		Expect(v2Endpoint.Spec.ClusterID).To(Equal(v1Endpoint.Spec.ClusterID))
+		Expect(v2Endpoint.Spec.BackendConfig).To(Equal(v2.BackendConfig{
+			UDPPort:           ptr.To(int32(4500)),
+			NATTDiscoveryPort: ptr.To(int32(4490)),
+			PreferredServer:   ptr.To(true),
+			PublicIPResolvers: []v2.PublicIPResolver{
+				{Type: v2.IPv4Resolver, Value: "1.2.3.4"},
+				{Type: v2.LoadBalancerResolver, Value: "gateway-lb"},
+			},
+			DriverConfig: map[string]string{v1.PreferredServerConfig + "-time": "1700000000"},
+		}))
+	})
+
+	It("should round trip to v1", func() {
+		v2Endpoint := &v2.Endpoint{}
+		Expect(v2Endpoint.ConvertFrom(v1Endpoint)).To(Succeed())
+
+		converted := &v1.Endpoint{}
+		Expect(v2Endpoint.ConvertTo(converted)).To(Succeed())
+		Expect(converted).To(Equal(v1Endpoint))
+	})
+
+	When("a well-known v1 backend config value is invalid", func() {
+		BeforeEach(func() {
+			v1Endpoint.Spec.BackendConfig[v1.UDPPortConfig] = "not-a-port"
+		})
+
+		It("should preserve it in the driver config", func() {
+			v2Endpoint := &v2.Endpoint{}
+			Expect(v2Endpoint.ConvertFrom(v1Endpoint)).To(Succeed())
+			Expect(v2Endpoint.Spec.BackendConfig.UDPPort).To(BeNil())
+			Expect(v2Endpoint.Spec.BackendConfig.DriverConfig).To(HaveKeyWithValue(v1.UDPPortConfig, "not-a-port"))
+
+			converted := &v1.Endpoint{}
+			Expect(v2Endpoint.ConvertTo(converted)).To(Succeed())
+			Expect(converted).To(Equal(v1Endpoint))
+		})
+	})
+})
+
+var _ = Describe("Cluster conversion", func() {
+	It("should round trip to v1", func() {
+		v1Cluster := &v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "east"},
+			Spec: v1.ClusterSpec{
+				ClusterID:   "east",
+				ServiceCIDR: []string{"100.0.0.0/16"},
+				ClusterCIDR: []string{"10.0.0.0/16"},
+				GlobalCIDR:  []string{"242.0.0.0/16"},
+			},
+		}
+
+		v2Cluster := &v2.Cluster{}
+		Expect(v2Cluster.ConvertFrom(v1Cluster)).To(Succeed())
+		Expect(v2Cluster.Spec.ServiceCIDR).To(Equal(v1Cluster.Spec.ServiceCIDR))
+
+		converted := &v1.Cluster{}
+		Expect(v2Cluster.ConvertTo(converted)).To(Succeed())
+		Expect(converted).To(Equal(v1Cluster))
+	})
+})
+
+func TestV2(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "V2 API suite")
+}
@@ -0,0 +1,25 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// +k8s:deepcopy-gen=package,register
+// +groupName=submariner.io
+
+// Package v2 contains the v2 version of the Endpoint and Cluster APIs, with typed backend configuration. The v1 version
+// remains the storage (hub) version; v2 objects are converted to and from it by the conversion webhook. The webhook is
+// served by the gateway on member clusters and by the standalone submariner-webhook on the broker cluster.
+package v2
@@ -0,0 +1,159 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type Cluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ClusterSpec `json:"spec"`
+}
+
+type ClusterSpec struct {
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:MinLength=1
+	ClusterID string `json:"clusterID"`
+	// +optional
+	ColorCodes  []string `json:"colorCodes,omitempty"`
+	ServiceCIDR []string `json:"serviceCIDR"`
+	ClusterCIDR []string `json:"clusterCIDR"`
+	// +optional
+	GlobalCIDR []string `json:"globalCIDR,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []Cluster `json:"items"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type Endpoint struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              EndpointSpec `json:"spec"`
+}
+
+type EndpointSpec struct {
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:MinLength=1
+	ClusterID string `json:"clusterID"`
+	// +kubebuilder:validation:MinLength=1
+	CableName string `json:"cableName"`
+	// +optional
+	HealthCheckIP string   `json:"healthCheckIP,omitempty"`
+	Hostname      string   `json:"hostname"`
+	Subnets       []string `json:"subnets"`
+	PrivateIP     string   `json:"privateIP"`
+	// +optional
+	PublicIP   string `json:"publicIP,omitempty"`
+	NATEnabled bool   `json:"natEnabled"`
+	Backend    string `json:"backend"`
+	// +optional
+	BackendConfig BackendConfig `json:"backendConfig,omitempty"`
+	// +optional
+	TransitRoutes []TransitRoute `json:"transitRoutes,omitempty"`
+	// +optional
+	Capabilities *EndpointCapabilities `json:"capabilities,omitempty"`
+}
+
+// EndpointCapabilities advertises the features supported by a gateway.
+type EndpointCapabilities struct {
+	// Backends lists the cable drivers the gateway supports, that is the registered cable drivers.
+	// +optional
+	Backends []string `json:"backends,omitempty"`
+	// NATDiscoveryVersion is the highest NAT discovery protocol version the gateway supports.
+	// +optional
+	NATDiscoveryVersion int32 `json:"natDiscoveryVersion,omitempty"`
+	// HealthCheckProbes lists the health check probe types the gateway supports.
+	// +optional
+	HealthCheckProbes []string `json:"healthCheckProbes,omitempty"`
+	// IPv6 indicates whether the gateway supports IPv6 subnets. It's always false as IPv6 isn't supported yet.
+	// +optional
+	IPv6 bool `json:"ipv6,omitempty"`
+	// Globalnet indicates whether the gateway's cluster uses Globalnet.
+	// +optional
+	Globalnet bool `json:"globalnet,omitempty"`
+}
+
+// BackendConfig holds the typed configuration of the cable driver backend.
+type BackendConfig struct {
+	// UDPPort is the UDP port the cable driver uses for the tunnel.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	UDPPort *int32 `json:"udpPort,omitempty"`
+	// NATTDiscoveryPort is the UDP port used for NAT discovery.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	NATTDiscoveryPort *int32 `json:"nattDiscoveryPort,omitempty"`
+	// PreferredServer indicates the endpoint prefers to act as the server side of the connections.
+	// +optional
+	PreferredServer *bool `json:"preferredServer,omitempty"`
+	// PublicIPResolvers lists, in order, the sources used to resolve the endpoint's public IP.
+	// +optional
+	PublicIPResolvers []PublicIPResolver `json:"publicIPResolvers,omitempty"`
+	// UsingLoadBalancer indicates the endpoint is exposed through a load balancer.
+	// +optional
+	UsingLoadBalancer *bool `json:"usingLoadBalancer,omitempty"`
+	// DriverConfig holds the cable driver specific configuration.
+	// +optional
+	DriverConfig map[string]string `json:"driverConfig,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=ipv4;lb;api;dns
+type PublicIPResolverType string
+
+const (
+	IPv4Resolver         PublicIPResolverType = "ipv4"
+	LoadBalancerResolver PublicIPResolverType = "lb"
+	APIResolver          PublicIPResolverType = "api"
+	DNSResolver          PublicIPResolverType = "dns"
+)
+
+type PublicIPResolver struct {
+	Type PublicIPResolverType `json:"type"`
+	// +kubebuilder:validation:MinLength=1
+	Value string `json:"value"`
+}
+
+// TransitRoute describes the reachability of a remote cluster's subnets through one or more transit gateways.
+type TransitRoute struct {
+	ClusterID string   `json:"clusterID"`
+	Subnets   []string `json:"subnets"`
+	// Path lists the IDs of the transit clusters the remote cluster is reached through, starting with the nearest one.
+	Path []string `json:"path"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type EndpointList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []Endpoint `json:"items"`
+}
@@ -20,6 +20,7 @@ package cable
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/submariner-io/admiral/pkg/log"
@@ -54,6 +55,14 @@ type Driver interface {
 	Cleanup() error
 }
 
+// EndpointUpdater is implemented by drivers that can apply changes to the subnets of a connected remote endpoint
+// incrementally, without tearing down the existing connection.
+type EndpointUpdater interface {
+	// UpdateEndpoint updates the connection, previously established by ConnectToEndpoint, to the given endpoint whose
+	// subnets changed. Only the added or removed subnets are configured, the traffic to the other subnets isn't disrupted.
+	UpdateEndpoint(endpointInfo *natdiscovery.NATEndpointInfo) error
+}
+
 // Function prototype to create a new driver.
 type DriverCreateFunc func(localEndpoint *types.SubmarinerEndpoint, localCluster *types.SubmarinerCluster) (Driver, error)
 
@@ -95,6 +104,18 @@ func NewDriver(localEndpoint *types.SubmarinerEndpoint, localCluster *types.Subm
 	return driverCreate(localEndpoint, localCluster)
 }
 
+// GetDriverNames returns the sorted names of the supported drivers.
+func GetDriverNames() []string {
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
 // Sets the default cable driver name, if it is not specified by user.
 func SetDefaultCableDriver(driver string) {
 	defaultCableDriver = driver
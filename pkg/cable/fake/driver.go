@@ -41,6 +41,8 @@ type Driver struct {
 	ErrOnConnectToEndpoint      error
 	disconnectFromEndpoint      chan *types.SubmarinerEndpoint
 	ErrOnDisconnectFromEndpoint error
+	updateEndpoint              chan *natdiscovery.NATEndpointInfo
+	ErrOnUpdateEndpoint         error
 }
 
 func New() *Driver {
@@ -49,6 +51,7 @@ func New() *Driver {
 		activeConnections:      map[string]v1.Connection{},
 		connectToEndpoint:      make(chan *natdiscovery.NATEndpointInfo, 50),
 		disconnectFromEndpoint: make(chan *types.SubmarinerEndpoint, 50),
+		updateEndpoint:         make(chan *natdiscovery.NATEndpointInfo, 50),
 	}
 }
 
@@ -122,6 +125,26 @@ func (d *Driver) DisconnectFromEndpoint(endpoint *types.SubmarinerEndpoint) erro
 	return nil
 }
 
+func (d *Driver) UpdateEndpoint(endpointInfo *natdiscovery.NATEndpointInfo) error {
+	// We'll panic if endpointInfo is nil, this is intentional
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	err := d.ErrOnUpdateEndpoint
+	if err != nil {
+		d.ErrOnUpdateEndpoint = nil
+		return err
+	}
+
+	connection := d.activeConnections[endpointInfo.Endpoint.Spec.CableName]
+	connection.Endpoint = endpointInfo.Endpoint.Spec
+	d.activeConnections[endpointInfo.Endpoint.Spec.CableName] = connection
+
+	d.updateEndpoint <- endpointInfo
+
+	return nil
+}
+
 func (d *Driver) GetName() string {
 	return DriverName
 }
@@ -146,6 +169,14 @@ func (d *Driver) AwaitNoDisconnectFromEndpoint() {
 	Consistently(d.disconnectFromEndpoint, 500*time.Millisecond).ShouldNot(Receive(), "DisconnectFromEndpoint was unexpectedly called")
 }
 
+func (d *Driver) AwaitUpdateEndpoint(expected *natdiscovery.NATEndpointInfo) {
+	Eventually(d.updateEndpoint, 5).Should(Receive(Equal(expected)))
+}
+
+func (d *Driver) AwaitNoUpdateEndpoint() {
+	Consistently(d.updateEndpoint, 500*time.Millisecond).ShouldNot(Receive(), "UpdateEndpoint was unexpectedly called")
+}
+
 func (d *Driver) Cleanup() error {
 	return nil
 }
@@ -39,6 +39,7 @@ import (
 	"github.com/submariner-io/submariner/pkg/natdiscovery"
 	"github.com/submariner-io/submariner/pkg/netlink"
 	"github.com/submariner-io/submariner/pkg/types"
+	"k8s.io/utils/set"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -59,6 +60,8 @@ type libreswan struct {
 	localEndpoint types.SubmarinerEndpoint
 	// This tracks the requested connections
 	connections []subv1.Connection
+	// This tracks, per remote cable, the index of each remote subnet in the names of its connections
+	remoteSubnetSlots map[string]map[string]int
 
 	secretKey string
 	logFile   string
@@ -134,6 +137,7 @@ func NewLibreswan(localEndpoint *types.SubmarinerEndpoint, _ *types.SubmarinerCl
 		defaultNATTPort:       int32(defaultNATTPort),
 		localEndpoint:         *localEndpoint,
 		connections:           []subv1.Connection{},
+		remoteSubnetSlots:     map[string]map[string]int{},
 		forceUDPEncapsulation: ipSecSpec.ForceEncaps,
 		plutoStarted:          false,
 	}, nil
@@ -240,21 +244,18 @@ func (i *libreswan) refreshConnectionStatus() error {
 		remoteSubnets := extractSubnets(&i.connections[j].Endpoint)
 		rx, tx := 0, 0
 
-		for lsi := range localSubnets {
-			for rsi := range remoteSubnets {
-				connectionName := fmt.Sprintf("%s-%d-%d", i.connections[j].Endpoint.CableName, lsi, rsi)
-				subRx, okRx := activeConnectionsRx[connectionName]
-				subTx, okTx := activeConnectionsTx[connectionName]
-
-				if okRx || okTx {
-					i.connections[j].Status = subv1.Connected
-					isConnected = true
-					rx += subRx
-					tx += subTx
-				} else {
-					logger.V(log.DEBUG).Infof("Connection %q not found in active connections obtained from whack: %v, %v",
-						connectionName, activeConnectionsRx, activeConnectionsTx)
-				}
+		for _, connectionName := range i.connectionNames(i.connections[j].Endpoint.CableName, localSubnets, remoteSubnets) {
+			subRx, okRx := activeConnectionsRx[connectionName]
+			subTx, okTx := activeConnectionsTx[connectionName]
+
+			if okRx || okTx {
+				i.connections[j].Status = subv1.Connected
+				isConnected = true
+				rx += subRx
+				tx += subTx
+			} else {
+				logger.V(log.DEBUG).Infof("Connection %q not found in active connections obtained from whack: %v, %v",
+					connectionName, activeConnectionsRx, activeConnectionsTx)
 			}
 		}
 
@@ -370,20 +371,13 @@ func (i *libreswan) ConnectToEndpoint(endpointInfo *natdiscovery.NATEndpointInfo
 
 	logger.Infof("Creating connection(s) for %v in %s mode", endpoint, connectionMode)
 
+	slots := assignSubnetSlots(nil, rightSubnets)
+	i.remoteSubnetSlots[endpoint.Spec.CableName] = slots
+
 	if len(leftSubnets) > 0 && len(rightSubnets) > 0 {
 		for lsi, leftSubnet := range leftSubnets {
 			for rsi, rightSubnet := range rightSubnets {
-				connectionName := fmt.Sprintf("%s-%d-%d", endpoint.Spec.CableName, lsi, rsi)
-
-				switch connectionMode {
-				case operationModeBidirectional:
-					err = i.bidirectionalConnectToEndpoint(connectionName, endpointInfo, leftSubnet, rightSubnet, rightNATTPort)
-				case operationModeServer:
-					err = i.serverConnectToEndpoint(connectionName, endpointInfo, leftSubnet, rightSubnet, lsi, rsi)
-				case operationModeClient:
-					err = i.clientConnectToEndpoint(connectionName, endpointInfo, leftSubnet, rightSubnet, rightNATTPort, lsi, rsi)
-				}
-
+				err = i.connectSubnets(connectionMode, endpointInfo, leftSubnet, rightSubnet, rightNATTPort, lsi, rsi, slots[rightSubnet])
 				if err != nil {
 					return "", err
 				}
@@ -398,6 +392,52 @@ func (i *libreswan) ConnectToEndpoint(endpointInfo *natdiscovery.NATEndpointInfo
 	return endpointInfo.UseIP, nil
 }
 
+// connectSubnets creates the connection, i.e. the child SA, between the given local and remote subnets. The connection is
+// named after the remote subnet's slot while, in server and client mode, it's identified by the remote subnet's index.
+func (i *libreswan) connectSubnets(connectionMode operationMode, endpointInfo *natdiscovery.NATEndpointInfo,
+	leftSubnet, rightSubnet string, rightNATTPort int32, lsi, rsi, slot int,
+) error {
+	connectionName := connectionNameFor(endpointInfo.Endpoint.Spec.CableName, lsi, slot)
+
+	switch connectionMode {
+	case operationModeBidirectional:
+		return i.bidirectionalConnectToEndpoint(connectionName, endpointInfo, leftSubnet, rightSubnet, rightNATTPort)
+	case operationModeServer:
+		return i.serverConnectToEndpoint(connectionName, endpointInfo, leftSubnet, rightSubnet, lsi, rsi)
+	case operationModeClient:
+		return i.clientConnectToEndpoint(connectionName, endpointInfo, leftSubnet, rightSubnet, rightNATTPort, lsi, rsi)
+	}
+
+	return nil
+}
+
+func connectionNameFor(cableName string, lsi, slot int) string {
+	return fmt.Sprintf("%s-%d-%d", cableName, lsi, slot)
+}
+
+// connectionNames returns the names of the connections between the given local and remote subnets of the given cable.
+func (i *libreswan) connectionNames(cableName string, leftSubnets, rightSubnets []string) []string {
+	slots := i.subnetSlotsFor(cableName, rightSubnets)
+	names := make([]string, 0, len(leftSubnets)*len(rightSubnets))
+
+	for lsi := range leftSubnets {
+		for _, rightSubnet := range rightSubnets {
+			names = append(names, connectionNameFor(cableName, lsi, slots[rightSubnet]))
+		}
+	}
+
+	return names
+}
+
+// subnetSlotsFor returns the slots tracked for the remote subnets of the given cable, defaulting to their indexes.
+func (i *libreswan) subnetSlotsFor(cableName string, rightSubnets []string) map[string]int {
+	if slots, found := i.remoteSubnetSlots[cableName]; found {
+		return slots
+	}
+
+	return assignSubnetSlots(nil, rightSubnets)
+}
+
 func (i *libreswan) bidirectionalConnectToEndpoint(connectionName string, endpointInfo *natdiscovery.NATEndpointInfo,
 	leftSubnet, rightSubnet string, rightNATTPort int32,
 ) error {
@@ -540,30 +580,170 @@ func (i *libreswan) DisconnectFromEndpoint(endpoint *types.SubmarinerEndpoint) e
 
 	logger.Infof("Deleting connection to %v", endpoint)
 
-	if len(leftSubnets) > 0 && len(rightSubnets) > 0 {
-		for lsi := range leftSubnets {
-			for rsi := range rightSubnets {
-				connectionName := fmt.Sprintf("%s-%d-%d", endpoint.Spec.CableName, lsi, rsi)
-				args := []string{"--delete", "--name", connectionName}
-
-				if err := whack(args...); err != nil {
-					var exitError *exec.ExitError
-					if errors.As(err, &exitError) {
-						logger.Errorf(err, "Error deleting a connection with args %v; got exit code %d", args, exitError.ExitCode())
-					} else {
-						return errors.Wrapf(err, "error deleting a connection with args %v", args)
-					}
-				}
-			}
+	for _, connectionName := range i.connectionNames(endpoint.Spec.CableName, leftSubnets, rightSubnets) {
+		if err := deleteConnection(connectionName); err != nil {
+			return err
 		}
 	}
 
+	delete(i.remoteSubnetSlots, endpoint.Spec.CableName)
 	i.connections = removeConnectionForEndpoint(i.connections, endpoint)
 	cable.RecordDisconnected(cableDriverName, &i.localEndpoint.Spec, &endpoint.Spec)
 
 	return nil
 }
 
+func deleteConnection(connectionName string) error {
+	args := []string{"--delete", "--name", connectionName}
+
+	if err := whack(args...); err != nil {
+		var exitError *exec.ExitError
+		if errors.As(err, &exitError) {
+			logger.Errorf(err, "Error deleting a connection with args %v; got exit code %d", args, exitError.ExitCode())
+		} else {
+			return errors.Wrapf(err, "error deleting a connection with args %v", args)
+		}
+	}
+
+	return nil
+}
+
+// UpdateEndpoint updates the connection to the given endpoint in place. Only the connections for the remote subnets that
+// were removed or added are deleted or created, the others are left untouched. The connections keep their names, based on
+// the slot of their remote subnet, across updates. In server and client mode, they're also identified by the index of their
+// remote subnet, as seen by both gateways, so those for the remote subnets whose index changed are re-created.
+func (i *libreswan) UpdateEndpoint(endpointInfo *natdiscovery.NATEndpointInfo) error {
+	// We'll panic if endpointInfo is nil, this is intentional
+	endpoint := &endpointInfo.Endpoint
+
+	var existing *subv1.Connection
+
+	for j := range i.connections {
+		if i.connections[j].Endpoint.CableName == endpoint.Spec.CableName {
+			existing = &i.connections[j]
+		}
+	}
+
+	if existing == nil {
+		return errors.Errorf("no existing connection to endpoint %q", endpoint.Spec.CableName)
+	}
+
+	rightNATTPort, err := endpoint.Spec.GetBackendPort(subv1.UDPPortConfig, i.defaultNATTPort)
+	if err != nil {
+		logger.Warningf("Error parsing %q from remote endpoint %q - using port %d instead: %v", subv1.UDPPortConfig,
+			endpoint.Spec.CableName, i.defaultNATTPort, err)
+	}
+
+	leftSubnets := extractSubnets(&i.localEndpoint.Spec)
+	prevRightSubnets := extractSubnets(&existing.Endpoint)
+	rightSubnets := extractSubnets(&endpoint.Spec)
+	connectionMode := i.calculateOperationMode(&endpoint.Spec)
+
+	prevSlots := i.subnetSlotsFor(endpoint.Spec.CableName, prevRightSubnets)
+	removed, added := diffSubnets(prevRightSubnets, rightSubnets, connectionMode != operationModeBidirectional)
+
+	for _, rightSubnet := range removed {
+		for lsi, leftSubnet := range leftSubnets {
+			logger.Infof("Deleting the connection from %q to %q for %q", leftSubnet, rightSubnet, endpoint.Spec.CableName)
+
+			if err := deleteConnection(connectionNameFor(endpoint.Spec.CableName, lsi, prevSlots[rightSubnet])); err != nil {
+				return err
+			}
+		}
+
+		delete(prevSlots, rightSubnet)
+	}
+
+	slots := assignSubnetSlots(prevSlots, rightSubnets)
+	i.remoteSubnetSlots[endpoint.Spec.CableName] = slots
+
+	for rsi, rightSubnet := range rightSubnets {
+		if !added.Has(rightSubnet) {
+			continue
+		}
+
+		for lsi, leftSubnet := range leftSubnets {
+			logger.Infof("Creating the connection from %q to %q for %q in %s mode", leftSubnet, rightSubnet,
+				endpoint.Spec.CableName, connectionMode)
+
+			err := i.connectSubnets(connectionMode, endpointInfo, leftSubnet, rightSubnet, rightNATTPort, lsi, rsi, slots[rightSubnet])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	existing.Endpoint = endpoint.Spec
+
+	return nil
+}
+
+// diffSubnets returns the subnets removed from, and added to, the previous subnets. If byIndex is set, a subnet whose index
+// changed is also considered removed and added.
+func diffSubnets(prevSubnets, subnets []string, byIndex bool) (removed []string, added set.Set[string]) {
+	prevIndexes := map[string]int{}
+	for index, subnet := range prevSubnets {
+		prevIndexes[subnet] = index
+	}
+
+	indexes := map[string]int{}
+	for index, subnet := range subnets {
+		indexes[subnet] = index
+	}
+
+	changed := func(subnet string, from, to map[string]int) bool {
+		toIndex, found := to[subnet]
+		return !found || (byIndex && toIndex != from[subnet])
+	}
+
+	for _, subnet := range prevSubnets {
+		if changed(subnet, prevIndexes, indexes) {
+			removed = append(removed, subnet)
+		}
+	}
+
+	added = set.New[string]()
+
+	for _, subnet := range subnets {
+		if changed(subnet, indexes, prevIndexes) {
+			added.Insert(subnet)
+		}
+	}
+
+	return removed, added
+}
+
+// assignSubnetSlots returns the slot of each subnet: the subnets with a previous slot keep it and the others get the lowest
+// free slots.
+func assignSubnetSlots(prevSlots map[string]int, subnets []string) map[string]int {
+	slots := map[string]int{}
+	used := set.New[int]()
+
+	for _, subnet := range subnets {
+		if slot, found := prevSlots[subnet]; found {
+			slots[subnet] = slot
+			used.Insert(slot)
+		}
+	}
+
+	next := 0
+
+	for _, subnet := range subnets {
+		if _, found := slots[subnet]; found {
+			continue
+		}
+
+		for used.Has(next) {
+			next++
+		}
+
+		slots[subnet] = next
+		used.Insert(next)
+	}
+
+	return slots
+}
+
 func removeConnectionForEndpoint(connections []subv1.Connection, endpoint *types.SubmarinerEndpoint) []subv1.Connection {
 	for j := range connections {
 		if connections[j].Endpoint.CableName == endpoint.Spec.CableName {
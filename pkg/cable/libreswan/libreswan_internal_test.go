@@ -29,8 +29,86 @@ import (
 var _ = Describe("Libreswan", func() {
 	Describe("IPsec port configuration", testIPsecPortConfiguration)
 	Describe("trafficStatusRE", testTrafficStatusRE)
+	Describe("diffSubnets", testDiffSubnets)
+	Describe("assignSubnetSlots", testAssignSubnetSlots)
 })
 
+func testDiffSubnets() {
+	When("the subnets are unchanged", func() {
+		It("should return no changes", func() {
+			removed, added := diffSubnets([]string{"10.0.0.0/16", "10.1.0.0/16"}, []string{"10.0.0.0/16", "10.1.0.0/16"}, true)
+			Expect(removed).To(BeEmpty())
+			Expect(added.UnsortedList()).To(BeEmpty())
+		})
+	})
+
+	When("a subnet is appended", func() {
+		It("should return it as added", func() {
+			removed, added := diffSubnets([]string{"10.0.0.0/16"}, []string{"10.0.0.0/16", "10.1.0.0/16"}, true)
+			Expect(removed).To(BeEmpty())
+			Expect(added.UnsortedList()).To(ConsistOf("10.1.0.0/16"))
+		})
+	})
+
+	When("a subnet is inserted before the others", func() {
+		It("should return only it as added when diffing by value", func() {
+			removed, added := diffSubnets([]string{"10.0.0.0/16", "10.1.0.0/16"},
+				[]string{"10.2.0.0/16", "10.0.0.0/16", "10.1.0.0/16"}, false)
+			Expect(removed).To(BeEmpty())
+			Expect(added.UnsortedList()).To(ConsistOf("10.2.0.0/16"))
+		})
+
+		It("should also return the shifted subnets as removed and added when diffing by index", func() {
+			removed, added := diffSubnets([]string{"10.0.0.0/16", "10.1.0.0/16"},
+				[]string{"10.2.0.0/16", "10.0.0.0/16", "10.1.0.0/16"}, true)
+			Expect(removed).To(Equal([]string{"10.0.0.0/16", "10.1.0.0/16"}))
+			Expect(added.UnsortedList()).To(ConsistOf("10.2.0.0/16", "10.0.0.0/16", "10.1.0.0/16"))
+		})
+	})
+
+	When("a subnet in the middle is removed", func() {
+		It("should return only it as removed when diffing by value", func() {
+			removed, added := diffSubnets([]string{"10.0.0.0/16", "10.1.0.0/16", "10.2.0.0/16"},
+				[]string{"10.0.0.0/16", "10.2.0.0/16"}, false)
+			Expect(removed).To(Equal([]string{"10.1.0.0/16"}))
+			Expect(added.UnsortedList()).To(BeEmpty())
+		})
+	})
+}
+
+func testAssignSubnetSlots() {
+	When("there are no previous slots", func() {
+		It("should assign the subnet indexes", func() {
+			Expect(assignSubnetSlots(nil, []string{"10.0.0.0/16", "10.1.0.0/16"})).To(Equal(map[string]int{
+				"10.0.0.0/16": 0,
+				"10.1.0.0/16": 1,
+			}))
+		})
+	})
+
+	When("a subnet is inserted before the others", func() {
+		It("should keep the previous slots and assign the next free one", func() {
+			Expect(assignSubnetSlots(map[string]int{"10.0.0.0/16": 0, "10.1.0.0/16": 1},
+				[]string{"10.2.0.0/16", "10.0.0.0/16", "10.1.0.0/16"})).To(Equal(map[string]int{
+				"10.0.0.0/16": 0,
+				"10.1.0.0/16": 1,
+				"10.2.0.0/16": 2,
+			}))
+		})
+	})
+
+	When("a subnet was removed", func() {
+		It("should reuse its slot", func() {
+			Expect(assignSubnetSlots(map[string]int{"10.0.0.0/16": 0, "10.2.0.0/16": 2},
+				[]string{"10.0.0.0/16", "10.2.0.0/16", "10.3.0.0/16"})).To(Equal(map[string]int{
+				"10.0.0.0/16": 0,
+				"10.2.0.0/16": 2,
+				"10.3.0.0/16": 1,
+			}))
+		})
+	})
+}
+
 func testTrafficStatusRE() {
 	When("Parsing a normal connection", func() {
 		It("should match", func() {
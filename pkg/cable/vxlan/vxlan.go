@@ -37,6 +37,7 @@ import (
 	"github.com/submariner-io/submariner/pkg/types"
 	"github.com/vishvananda/netlink"
 	"golang.org/x/sys/unix"
+	"k8s.io/utils/set"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -304,16 +305,7 @@ func (v *vxlan) ConnectToEndpoint(endpointInfo *natdiscovery.NATEndpointInfo) (s
 		return endpointInfo.UseIP, fmt.Errorf("failed to add remoteIP %q to the forwarding database: %w", remoteIP, err)
 	}
 
-	var ipAddress net.IP
-
-	cniIface, err := cni.Discover(v.localCluster.Spec.ClusterCIDR[0])
-	if err == nil {
-		ipAddress = net.ParseIP(cniIface.IPAddress)
-	} else {
-		logger.Errorf(nil, "Failed to get the CNI interface IP for cluster CIDR %q, host-networking use-cases may not work",
-			v.localCluster.Spec.ClusterCIDR[0])
-		ipAddress = nil
-	}
+	ipAddress := v.getCNIInterfaceIPAddress()
 
 	err = v.vxlanIface.AddRoute(allowedIPs, remoteVtepIP, ipAddress)
 
@@ -332,6 +324,65 @@ func (v *vxlan) ConnectToEndpoint(endpointInfo *natdiscovery.NATEndpointInfo) (s
 	return endpointInfo.UseIP, nil
 }
 
+func (v *vxlan) getCNIInterfaceIPAddress() net.IP {
+	cniIface, err := cni.Discover(v.localCluster.Spec.ClusterCIDR[0])
+	if err != nil {
+		logger.Errorf(nil, "Failed to get the CNI interface IP for cluster CIDR %q, host-networking use-cases may not work",
+			v.localCluster.Spec.ClusterCIDR[0])
+		return nil
+	}
+
+	return net.ParseIP(cniIface.IPAddress)
+}
+
+// UpdateEndpoint updates the connection to the given endpoint in place by only adding the routes for the added subnets
+// and deleting those for the removed subnets.
+func (v *vxlan) UpdateEndpoint(endpointInfo *natdiscovery.NATEndpointInfo) error {
+	// We'll panic if endpointInfo is nil, this is intentional
+	remoteEndpoint := &endpointInfo.Endpoint
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	var existing *v1.Connection
+
+	for i := range v.connections {
+		if v.connections[i].Endpoint.CableName == remoteEndpoint.Spec.CableName {
+			existing = &v.connections[i]
+		}
+	}
+
+	if existing == nil {
+		return fmt.Errorf("no existing connection to endpoint %q", remoteEndpoint.Spec.CableName)
+	}
+
+	prevSubnets := set.New(existing.Endpoint.Subnets...)
+	subnets := set.New(remoteEndpoint.Spec.Subnets...)
+
+	if removed := parseSubnets(prevSubnets.Difference(subnets).SortedList()); len(removed) > 0 {
+		if err := v.vxlanIface.DelRoute(removed); err != nil {
+			return fmt.Errorf("failed to remove route for the CIDR %q: %w", removed, err)
+		}
+	}
+
+	if added := parseSubnets(subnets.Difference(prevSubnets).SortedList()); len(added) > 0 {
+		remoteVtepIP, err := v.getVxlanVtepIPAddress(remoteEndpoint.Spec.PrivateIP)
+		if err != nil {
+			return fmt.Errorf("failed to derive the vxlan vtepIP for %s: %w", remoteEndpoint.Spec.PrivateIP, err)
+		}
+
+		if err := v.vxlanIface.AddRoute(added, remoteVtepIP, v.getCNIInterfaceIPAddress()); err != nil {
+			return fmt.Errorf("failed to add route for the CIDR %q with remoteVtepIP %q: %w", added, remoteVtepIP, err)
+		}
+	}
+
+	existing.Endpoint = remoteEndpoint.Spec
+
+	logger.V(log.DEBUG).Infof("Done updating endpoint for cluster %s", remoteEndpoint.Spec.ClusterID)
+
+	return nil
+}
+
 func (v *vxlan) DisconnectFromEndpoint(remoteEndpoint *types.SubmarinerEndpoint) error {
 	// We'll panic if remoteEndpoint is nil, this is intentional
 	logger.V(log.DEBUG).Infof("Removing endpoint %#v", remoteEndpoint)
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"reflect"
 	"sync"
 	"time"
 
@@ -240,7 +241,13 @@ func (w *wireguard) ConnectToEndpoint(endpointInfo *natdiscovery.NATEndpointInfo
 	if found {
 		if oldKey, err := keyFromSpec(&oldCon.Endpoint); err == nil {
 			if oldKey.String() == remoteKey.String() {
-				// Existing connection, update status and skip.
+				// Existing connection, update its subnets if needed and status, and skip.
+				if !reflect.DeepEqual(oldCon.Endpoint.Subnets, remoteEndpoint.Spec.Subnets) {
+					if err := w.updatePeerAllowedIPs(oldCon, &remoteEndpoint.Spec, oldKey); err != nil {
+						return "", err
+					}
+				}
+
 				w.updatePeerStatus(oldCon, oldKey)
 				logger.V(log.DEBUG).Infof("Skipping connect for existing peer key %s", oldKey)
 
@@ -306,6 +313,51 @@ func (w *wireguard) ConnectToEndpoint(endpointInfo *natdiscovery.NATEndpointInfo
 	return ip, nil
 }
 
+// UpdateEndpoint updates the allowed IPs of the peer for the given endpoint in place, the peer's session is kept.
+func (w *wireguard) UpdateEndpoint(endpointInfo *natdiscovery.NATEndpointInfo) error {
+	// We'll panic if endpointInfo is nil, this is intentional
+	remoteEndpoint := &endpointInfo.Endpoint
+
+	remoteKey, err := keyFromSpec(&remoteEndpoint.Spec)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse peer public key")
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.keyMismatch(remoteEndpoint.Spec.ClusterID, remoteKey) {
+		return fmt.Errorf("no existing peer with key %s for cluster %s", remoteKey, remoteEndpoint.Spec.ClusterID)
+	}
+
+	return w.updatePeerAllowedIPs(w.connections[remoteEndpoint.Spec.ClusterID], &remoteEndpoint.Spec, remoteKey)
+}
+
+func (w *wireguard) updatePeerAllowedIPs(connection *v1.Connection, remoteEndpoint *v1.EndpointSpec, key *wgtypes.Key) error {
+	allowedIPs := parseSubnets(remoteEndpoint.Subnets)
+
+	logger.V(log.DEBUG).Infof("Updating the allowed IPs of peer %s from %v to %v", key, connection.Endpoint.Subnets,
+		remoteEndpoint.Subnets)
+
+	// With UpdateOnly, the peer's endpoint and handshake state are left as is.
+	err := w.client.ConfigureDevice(DefaultDeviceName, wgtypes.Config{
+		ReplacePeers: false,
+		Peers: []wgtypes.PeerConfig{{
+			PublicKey:         *key,
+			UpdateOnly:        true,
+			ReplaceAllowedIPs: true,
+			AllowedIPs:        allowedIPs,
+		}},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update the allowed IPs of peer %s", key)
+	}
+
+	connection.Endpoint = *remoteEndpoint
+
+	return nil
+}
+
 func keyFromSpec(ep *v1.EndpointSpec) (*wgtypes.Key, error) {
 	s, found := ep.BackendConfig[PublicKey]
 	if !found {
@@ -21,13 +21,16 @@ package cableengine
 //nolint:gci // The supported driver imports are kept separate.
 import (
 	"reflect"
+	"sort"
 	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/log"
 	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/cable"
+	"github.com/submariner-io/submariner/pkg/endpoint"
 	"github.com/submariner-io/submariner/pkg/natdiscovery"
+	"github.com/submariner-io/submariner/pkg/peering"
 	"github.com/submariner-io/submariner/pkg/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -61,6 +64,8 @@ type Engine interface {
 	GetHAStatus() v1.HAStatus
 	// SetupNATDiscovery configures the handler for nat discovery of the endpoints.
 	SetupNATDiscovery(natDiscovery natdiscovery.Interface)
+	// SetPeeringPolicy restricts the remote clusters cables are installed to.
+	SetPeeringPolicy(policy *peering.Policy)
 
 	// Cleanup performs the necessary steps to uninstall the cable driver.
 	Cleanup() error
@@ -76,6 +81,10 @@ type engine struct {
 	natEndpointInfoCh   chan *natdiscovery.NATEndpointInfo
 	natDiscoveryPending map[string]int
 	installedCables     map[string]metav1.Time
+	peeringPolicy       *peering.Policy
+	// incompatibleCables holds the connections to the remote endpoints whose capabilities are incompatible with the local
+	// endpoint, keyed by cable name.
+	incompatibleCables map[string]v1.Connection
 }
 
 var logger = log.Logger{Logger: logf.Log.WithName("CableEngine")}
@@ -88,6 +97,7 @@ func NewEngine(localCluster *types.SubmarinerCluster, localEndpoint *types.Subma
 		localEndpoint:       *localEndpoint,
 		natDiscoveryPending: map[string]int{},
 		installedCables:     map[string]metav1.Time{},
+		incompatibleCables:  map[string]v1.Connection{},
 	}
 }
 
@@ -133,6 +143,13 @@ func (i *engine) startDriver() error {
 	return errors.Wrap(i.driver.Init(), "error initializing the cable driver")
 }
 
+func (i *engine) SetPeeringPolicy(policy *peering.Policy) {
+	i.Lock()
+	defer i.Unlock()
+
+	i.peeringPolicy = policy
+}
+
 func (i *engine) SetupNATDiscovery(natDiscovery natdiscovery.Interface) {
 	i.natDiscovery = natDiscovery
 	i.natEndpointInfoCh = natDiscovery.GetReadyChannel()
@@ -191,13 +208,20 @@ func (i *engine) installCableWithNATInfo(rnat *natdiscovery.NATEndpointInfo) err
 		}
 
 		if endpoint.CreationTimestamp.Equal(&prevTimestamp) && active.Endpoint.CableName == endpoint.Spec.CableName {
-			// There could be scenarios where the cableName would be the same but the endpoint IP or specific driver
+			// There could be scenarios where the cableName would be the same but the endpoint IP, subnets or specific driver
 			// config has changed.
 			if active.UsingIP == rnat.UseIP && active.UsingNAT == rnat.UseNAT &&
 				reflect.DeepEqual(active.Endpoint.BackendConfig, endpoint.Spec.BackendConfig) {
-				logger.V(log.TRACE).Infof("Connection info (IP: %s, NAT: %v, BackendConfig: %v) for cable %q is unchanged"+
-					" - not re-installing", active.UsingIP, active.UsingNAT, active.Endpoint.BackendConfig, active.Endpoint.CableName)
-				return nil
+				if reflect.DeepEqual(active.Endpoint.Subnets, endpoint.Spec.Subnets) {
+					logger.V(log.TRACE).Infof("Connection info (IP: %s, NAT: %v, BackendConfig: %v) for cable %q is unchanged"+
+						" - not re-installing", active.UsingIP, active.UsingNAT, active.Endpoint.BackendConfig, active.Endpoint.CableName)
+					return nil
+				}
+
+				// Only the subnets changed so try to update the cable in place.
+				if i.updateCable(rnat, active) {
+					return nil
+				}
 			}
 
 			logger.V(log.DEBUG).Infof("New connection info (IP: %s, NAT: %v, BackendConfig: %v) for cable %q differs from"+
@@ -227,6 +251,27 @@ func (i *engine) installCableWithNATInfo(rnat *natdiscovery.NATEndpointInfo) err
 	return nil
 }
 
+// updateCable applies a change to the subnets of an installed cable in place, if the driver supports it, and returns
+// whether it succeeded.
+func (i *engine) updateCable(rnat *natdiscovery.NATEndpointInfo, active *v1.Connection) bool {
+	updater, ok := i.driver.(cable.EndpointUpdater)
+	if !ok {
+		return false
+	}
+
+	logger.Infof("Updating the subnets of Endpoint cable %q from %v to %v", active.Endpoint.CableName, active.Endpoint.Subnets,
+		rnat.Endpoint.Spec.Subnets)
+
+	if err := updater.UpdateEndpoint(rnat); err != nil {
+		logger.Errorf(err, "Error updating Endpoint cable %q - re-installing", active.Endpoint.CableName)
+		return false
+	}
+
+	logger.Infof("Successfully updated Endpoint cable %q", active.Endpoint.CableName)
+
+	return true
+}
+
 func (i *engine) InstallCable(endpoint *v1.Endpoint) error {
 	if endpoint.Spec.ClusterID == i.localCluster.ID {
 		logger.V(log.TRACE).Infof("Not installing cable for local cluster")
@@ -239,6 +284,23 @@ func (i *engine) InstallCable(endpoint *v1.Endpoint) error {
 	}
 
 	i.Lock()
+
+	if !i.peeringPolicy.AllowsClusterID(endpoint.Spec.ClusterID) {
+		i.Unlock()
+		logger.Infof("Not installing cable %q as cluster %q is excluded by the peering policy", endpoint.Spec.CableName,
+			endpoint.Spec.ClusterID)
+
+		return nil
+	}
+
+	if err := i.checkCompatibility(endpoint); err != nil {
+		i.Unlock()
+		logger.Warningf("Not installing cable %q as the remote gateway is incompatible: %v", endpoint.Spec.CableName, err)
+
+		// The remote endpoint may have been updated to an incompatible version after its cable was installed.
+		return i.removeCable(endpoint)
+	}
+
 	i.natDiscoveryPending[endpoint.Spec.CableName]++
 	i.Unlock()
 
@@ -247,12 +309,37 @@ func (i *engine) InstallCable(endpoint *v1.Endpoint) error {
 	return nil
 }
 
+// checkCompatibility checks the remote endpoint's capabilities are compatible with the local endpoint's, recording
+// a ConnectionError explaining the incompatibility if they aren't.
+func (i *engine) checkCompatibility(remote *v1.Endpoint) error {
+	err := endpoint.CheckCompatibility(&i.localEndpoint.Spec, &remote.Spec)
+	if err != nil {
+		connection := v1.NewConnection(&remote.Spec, "", false)
+		connection.SetStatus(v1.ConnectionError, "Incompatible remote gateway: %v", err)
+		i.incompatibleCables[remote.Spec.CableName] = *connection
+
+		return err //nolint:wrapcheck  // Let the caller wrap it
+	}
+
+	delete(i.incompatibleCables, remote.Spec.CableName)
+
+	return nil
+}
+
 func (i *engine) RemoveCable(endpoint *v1.Endpoint) error {
 	if endpoint.Spec.ClusterID == i.localCluster.ID {
 		logger.V(log.DEBUG).Infof("Cables are not added/removed for the local cluster, skipping removal")
 		return nil
 	}
 
+	i.Lock()
+	delete(i.incompatibleCables, endpoint.Spec.CableName)
+	i.Unlock()
+
+	return i.removeCable(endpoint)
+}
+
+func (i *engine) removeCable(endpoint *v1.Endpoint) error {
 	logger.Infof("Removing Endpoint cable %q", endpoint.Spec.CableName)
 
 	i.natDiscovery.RemoveEndpoint(endpoint.Spec.CableName)
@@ -297,13 +384,34 @@ func (i *engine) ListCableConnections() ([]v1.Connection, error) {
 	defer i.Unlock()
 
 	if i.running {
-		return i.driver.GetConnections() //nolint:wrapcheck  // Let the caller wrap it
+		connections, err := i.driver.GetConnections()
+		if err != nil {
+			return nil, err //nolint:wrapcheck  // Let the caller wrap it
+		}
+
+		return append(connections, i.listIncompatibleConnections()...), nil
 	}
 
 	// if not running, we can safely report that no connections exist.
 	return []v1.Connection{}, nil
 }
 
+func (i *engine) listIncompatibleConnections() []v1.Connection {
+	names := make([]string, 0, len(i.incompatibleCables))
+	for name := range i.incompatibleCables {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	connections := make([]v1.Connection, len(names))
+	for j, name := range names {
+		connections[j] = i.incompatibleCables[name]
+	}
+
+	return connections
+}
+
 func (i *engine) Cleanup() error {
 	if i.driver != nil {
 		return i.driver.Cleanup() //nolint:wrapcheck  // No need to wrap this error
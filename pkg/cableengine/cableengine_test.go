@@ -33,6 +33,7 @@ import (
 	"github.com/submariner-io/submariner/pkg/cable/fake"
 	"github.com/submariner-io/submariner/pkg/cableengine"
 	"github.com/submariner-io/submariner/pkg/natdiscovery"
+	"github.com/submariner-io/submariner/pkg/peering"
 	"github.com/submariner-io/submariner/pkg/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -87,6 +88,7 @@ var _ = Describe("Cable Engine", func() {
 				CableName:     fmt.Sprintf("submariner-cable-%s-1.1.1.1", remoteClusterID),
 				PrivateIP:     "1.1.1.1",
 				PublicIP:      "2.2.2.2",
+				Backend:       fake.DriverName,
 				BackendConfig: map[string]string{"port": "1234"},
 			},
 		}
@@ -206,6 +208,29 @@ var _ = Describe("Cable Engine", func() {
 					})
 				})
 
+				Context("but different subnets", func() {
+					BeforeEach(func() {
+						newEndpoint.Spec.Subnets = append(newEndpoint.Spec.Subnets, "172.3.0.0/16")
+					})
+
+					It("should update the connection to the endpoint in place", func() {
+						fakeDriver.AwaitUpdateEndpoint(natEndpointInfoFor(newEndpoint))
+						fakeDriver.AwaitNoDisconnectFromEndpoint()
+						fakeDriver.AwaitNoConnectToEndpoint()
+					})
+
+					Context("and the driver fails to update the connection", func() {
+						BeforeEach(func() {
+							fakeDriver.ErrOnUpdateEndpoint = errors.New("fake update error")
+						})
+
+						It("should disconnect from the previous endpoint and connect to the new one", func() {
+							fakeDriver.AwaitDisconnectFromEndpoint(&prevEndpoint.Spec)
+							fakeDriver.AwaitConnectToEndpoint(natEndpointInfoFor(newEndpoint))
+						})
+					})
+				})
+
 				Context(" and connection info", func() {
 					It("should not disconnect from the previous endpoint nor connect to the new one", func() {
 						fakeDriver.AwaitNoDisconnectFromEndpoint()
@@ -220,6 +245,7 @@ var _ = Describe("Cable Engine", func() {
 				otherEndpoint := subv1.Endpoint{Spec: subv1.EndpointSpec{
 					ClusterID: "other",
 					CableName: "submariner-cable-other-1.1.1.1",
+					Backend:   fake.DriverName,
 				}}
 
 				Expect(engine.InstallCable(&otherEndpoint)).To(Succeed())
@@ -250,6 +276,61 @@ var _ = Describe("Cable Engine", func() {
 		})
 	})
 
+	When("install cable for a remote endpoint excluded by the peering policy", func() {
+		JustBeforeEach(func() {
+			policy, err := peering.NewPolicy(nil, []string{remoteEndpoint.Spec.ClusterID}, "")
+			Expect(err).To(Succeed())
+
+			engine.SetPeeringPolicy(policy)
+		})
+
+		It("should not connect to the endpoint", func() {
+			Expect(engine.InstallCable(remoteEndpoint)).To(Succeed())
+			fakeDriver.AwaitNoConnectToEndpoint()
+		})
+	})
+
+	When("install cable for a remote endpoint with incompatible capabilities", func() {
+		BeforeEach(func() {
+			remoteEndpoint.Spec.Backend = "other"
+			remoteEndpoint.Spec.Capabilities = &subv1.EndpointCapabilities{Backends: []string{"other"}}
+		})
+
+		It("should not connect to the endpoint and report a connection error", func() {
+			Expect(engine.InstallCable(remoteEndpoint)).To(Succeed())
+			fakeDriver.AwaitNoConnectToEndpoint()
+
+			connections, err := engine.ListCableConnections()
+			Expect(err).To(Succeed())
+			Expect(connections).To(HaveLen(1))
+			Expect(connections[0].Endpoint).To(Equal(remoteEndpoint.Spec))
+			Expect(connections[0].Status).To(Equal(subv1.ConnectionError))
+			Expect(connections[0].StatusMessage).To(ContainSubstring(`doesn't support the %q cable driver`, fake.DriverName))
+		})
+
+		Context("and the endpoint is subsequently removed", func() {
+			It("should no longer report a connection error", func() {
+				Expect(engine.InstallCable(remoteEndpoint)).To(Succeed())
+				Expect(engine.RemoveCable(remoteEndpoint)).To(Succeed())
+				Expect(engine.ListCableConnections()).To(BeEmpty())
+			})
+		})
+
+		Context("and a cable was previously installed for the endpoint", func() {
+			It("should disconnect from the endpoint", func() {
+				compatible := remoteEndpoint.DeepCopy()
+				compatible.Spec.Backend = fake.DriverName
+				compatible.Spec.Capabilities = nil
+
+				Expect(engine.InstallCable(compatible)).To(Succeed())
+				fakeDriver.AwaitConnectToEndpoint(natEndpointInfoFor(compatible))
+
+				Expect(engine.InstallCable(remoteEndpoint)).To(Succeed())
+				fakeDriver.AwaitDisconnectFromEndpoint(&remoteEndpoint.Spec)
+			})
+		})
+	})
+
 	When("install cable for a local endpoint", func() {
 		It("should not connect to the endpoint", func() {
 			Expect(engine.InstallCable(localEndpoint)).To(Succeed())
@@ -25,6 +25,7 @@ import (
 	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/cableengine"
 	"github.com/submariner-io/submariner/pkg/natdiscovery"
+	"github.com/submariner-io/submariner/pkg/peering"
 	"github.com/submariner-io/submariner/pkg/types"
 )
 
@@ -41,6 +42,7 @@ type Engine struct { //nolint:gocritic // This mutex is exposed but we tweak it
 	ErrOnStart                error
 	ErrOnCleanup              error
 	onCleanup                 chan struct{}
+	PeeringPolicy             *peering.Policy
 }
 
 var _ cableengine.Engine = &Engine{}
@@ -117,6 +119,13 @@ func (e *Engine) VerifyRemoveCable(expected *v1.EndpointSpec) {
 	Eventually(e.removeCable, 5).Should(Receive(Equal(expected)), "RemoveCable was not invoked")
 }
 
+func (e *Engine) SetPeeringPolicy(policy *peering.Policy) {
+	e.Lock()
+	defer e.Unlock()
+
+	e.PeeringPolicy = policy
+}
+
 func (e *Engine) SetupNATDiscovery(_ natdiscovery.Interface) {
 }
 
@@ -34,6 +34,7 @@ type LatencyInfo struct {
 	ConnectionError  string
 	ConnectionStatus ConnectionStatus
 	Spec             *submarinerv1.LatencyRTTSpec
+	PathMTU          int
 }
 
 type ConnectionStatus string
@@ -56,7 +57,10 @@ type Config struct {
 	ClusterID          string
 	PingInterval       uint
 	MaxPacketLossCount uint
-	NewPinger          func(PingerConfig) PingerInterface
+	// PathMTUInterval specifies, in seconds, how often the path MTU to each remote endpoint is measured. Zero disables
+	// path MTU discovery.
+	PathMTUInterval uint
+	NewPinger       func(PingerConfig) PingerInterface
 }
 
 type controller struct {
@@ -144,6 +148,11 @@ func (h *controller) endpointCreatedOrUpdated(obj runtime.Object, _ int) bool {
 		return false
 	}
 
+	if !endpointCreated.Spec.SupportsHealthCheckProbe(submarinerv1.HealthCheckProbeICMP) {
+		logger.Infof("Endpoint %q doesn't support ICMP health check probes - will not monitor endpoint health", endpointCreated.Name)
+		return false
+	}
+
 	h.Lock()
 	defer h.Unlock()
 
@@ -166,6 +175,11 @@ func (h *controller) endpointCreatedOrUpdated(obj runtime.Object, _ int) bool {
 		pingerConfig.Interval = time.Second * time.Duration(h.config.PingInterval)
 	}
 
+	// Gateways predating path MTU probing don't advertise it.
+	if h.config.PathMTUInterval != 0 && endpointCreated.Spec.SupportsHealthCheckProbe(submarinerv1.HealthCheckProbePathMTU) {
+		pingerConfig.PathMTUInterval = time.Second * time.Duration(h.config.PathMTUInterval)
+	}
+
 	newPingerFunc := h.config.NewPinger
 	if newPingerFunc == nil {
 		newPingerFunc = NewPinger
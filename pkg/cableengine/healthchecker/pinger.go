@@ -26,6 +26,7 @@ import (
 	"github.com/pkg/errors"
 	probing "github.com/prometheus-community/pro-bing"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 const (
@@ -60,6 +61,11 @@ type PingerConfig struct {
 	Interval           time.Duration
 	Timeout            time.Duration
 	MaxPacketLossCount uint
+	// PathMTUInterval specifies how often the path MTU is measured. Zero disables path MTU discovery.
+	PathMTUInterval time.Duration
+	// MaxPathMTU is the largest path MTU probed for. It defaults to the MTU of the default gateway interface.
+	MaxPathMTU   int
+	ProbePathMTU PathMTUProbeFunc
 }
 
 type pingerInfo struct {
@@ -71,6 +77,10 @@ type pingerInfo struct {
 	statistics         statistics
 	failureMsg         string
 	connectionStatus   ConnectionStatus
+	pathMTUInterval    time.Duration
+	maxPathMTU         int
+	probePathMTU       PathMTUProbeFunc
+	pathMTU            int
 	stopCh             chan struct{}
 }
 
@@ -80,6 +90,9 @@ func NewPinger(config PingerConfig) PingerInterface {
 		pingInterval:       config.Interval,
 		pingTimeout:        config.Timeout,
 		maxPacketLossCount: config.MaxPacketLossCount,
+		pathMTUInterval:    config.PathMTUInterval,
+		maxPathMTU:         config.MaxPathMTU,
+		probePathMTU:       config.ProbePathMTU,
 		statistics: statistics{
 			size:         size,
 			previousRtts: make([]uint64, size),
@@ -99,6 +112,10 @@ func NewPinger(config PingerConfig) PingerInterface {
 		p.pingTimeout = defaultPingTimeout
 	}
 
+	if p.probePathMTU == nil {
+		p.probePathMTU = probePathMTU
+	}
+
 	return p
 }
 
@@ -119,6 +136,10 @@ func (p *pingerInfo) Start() {
 			}
 		}
 	}()
+
+	if p.pathMTUInterval > 0 {
+		go wait.Until(p.updatePathMTU, p.pathMTUInterval, p.stopCh)
+	}
 }
 
 func (p *pingerInfo) Stop() {
@@ -207,6 +228,7 @@ func (p *pingerInfo) GetLatencyInfo() *LatencyInfo {
 	return &LatencyInfo{
 		ConnectionStatus: p.connectionStatus,
 		ConnectionError:  p.failureMsg,
+		PathMTU:          p.pathMTU,
 		Spec: &submarinerv1.LatencyRTTSpec{
 			Last:    time.Duration(p.statistics.lastRtt).String(),
 			Min:     time.Duration(p.statistics.minRtt).String(),
@@ -0,0 +1,131 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthchecker
+
+import (
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	probing "github.com/prometheus-community/pro-bing"
+	netlinkAPI "github.com/submariner-io/submariner/pkg/netlink"
+)
+
+const (
+	// minPathMTU is the smallest MTU every IPv4 host must accept (RFC 791), the path MTU is never reported lower.
+	minPathMTU = 576
+
+	// icmpOverhead is the size of the IPv4 and ICMP headers added to the probe payload.
+	icmpOverhead = 28
+
+	defaultMaxPathMTU = 1500
+
+	pathMTUProbeCount   = 3
+	pathMTUProbeTimeout = 3 * time.Second
+)
+
+// PathMTUProbeFunc sends packets of the given size, with the don't fragment bit set, to the IP and returns whether
+// any of them made it through.
+type PathMTUProbeFunc func(ip string, mtu int) (bool, error)
+
+// discoverPathMTU binary searches, between minPathMTU and maxMTU, the largest packet size the probe gets through.
+// It returns 0 if not even a minimal sized packet gets through.
+func discoverPathMTU(ip string, maxMTU int, probe PathMTUProbeFunc) (int, error) {
+	ok, err := probe(ip, minPathMTU)
+	if err != nil || !ok {
+		return 0, err
+	}
+
+	low, high := minPathMTU, maxMTU
+
+	for low < high {
+		mid := (low + high + 1) / 2
+
+		ok, err := probe(ip, mid)
+		if err != nil {
+			return 0, err
+		}
+
+		if ok {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+
+	return low, nil
+}
+
+func probePathMTU(ip string, mtu int) (bool, error) {
+	pinger, err := probing.NewPinger(ip)
+	if err != nil {
+		return false, errors.Wrapf(err, "error creating the path MTU pinger")
+	}
+
+	pinger.SetPrivileged(Privileged)
+	pinger.SetDoNotFragment(true)
+	pinger.Size = mtu - icmpOverhead
+	pinger.Count = pathMTUProbeCount
+	pinger.Interval = pathMTUProbeTimeout / pathMTUProbeCount
+	pinger.Timeout = pathMTUProbeTimeout
+
+	err = pinger.Run()
+
+	// The kernel refuses to send packets larger than the MTU it already knows for the path.
+	if errors.Is(err, syscall.EMSGSIZE) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, errors.Wrapf(err, "error running the path MTU pinger")
+	}
+
+	return pinger.Statistics().PacketsRecv > 0, nil
+}
+
+func defaultPathMTUCeiling() int {
+	iface, err := netlinkAPI.GetDefaultGatewayInterface()
+	if err != nil {
+		logger.Warningf("Unable to determine the default gateway interface MTU, assuming %d: %v", defaultMaxPathMTU, err)
+		return defaultMaxPathMTU
+	}
+
+	return iface.MTU
+}
+
+func (p *pingerInfo) updatePathMTU() {
+	if p.maxPathMTU == 0 {
+		p.maxPathMTU = defaultPathMTUCeiling()
+	}
+
+	pathMTU, err := discoverPathMTU(p.ip, p.maxPathMTU, p.probePathMTU)
+	if err != nil {
+		logger.Errorf(err, "Error discovering the path MTU to the remote endpoint IP %q", p.ip)
+		return
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	if pathMTU != p.pathMTU {
+		logger.Infof("Path MTU to the remote endpoint IP %q is %d", p.ip, pathMTU)
+	}
+
+	p.pathMTU = pathMTU
+}
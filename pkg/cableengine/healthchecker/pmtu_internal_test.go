@@ -0,0 +1,70 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthchecker
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("discoverPathMTU", func() {
+	var probed []int
+
+	probeWithPathMTU := func(pathMTU int) PathMTUProbeFunc {
+		return func(_ string, mtu int) (bool, error) {
+			probed = append(probed, mtu)
+			return mtu <= pathMTU, nil
+		}
+	}
+
+	BeforeEach(func() {
+		probed = nil
+	})
+
+	When("the path MTU is below the maximum", func() {
+		It("should find it", func() {
+			Expect(discoverPathMTU("1.2.3.4", 1500, probeWithPathMTU(1372))).To(Equal(1372))
+			Expect(len(probed)).To(BeNumerically("<=", 12))
+		})
+	})
+
+	When("the path MTU is the maximum", func() {
+		It("should return the maximum", func() {
+			Expect(discoverPathMTU("1.2.3.4", 1500, probeWithPathMTU(9000))).To(Equal(1500))
+		})
+	})
+
+	When("no probe gets through", func() {
+		It("should return zero", func() {
+			Expect(discoverPathMTU("1.2.3.4", 1500, probeWithPathMTU(0))).To(Equal(0))
+			Expect(probed).To(Equal([]int{minPathMTU}))
+		})
+	})
+
+	When("probing fails", func() {
+		It("should return an error", func() {
+			_, err := discoverPathMTU("1.2.3.4", 1500, func(_ string, _ int) (bool, error) {
+				return false, errors.New("mock error")
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -35,6 +35,7 @@ import (
 	"github.com/submariner-io/submariner/pkg/cableengine"
 	"github.com/submariner-io/submariner/pkg/cableengine/healthchecker"
 	v1typed "github.com/submariner-io/submariner/pkg/client/clientset/versioned/typed/submariner.io/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -47,6 +48,9 @@ type GatewaySyncer struct {
 	engine      cableengine.Engine
 	version     string
 	statusError error
+	conditions  []metav1.Condition
+	excluded    []v1.EndpointSpec
+	transit     []v1.TransitRoute
 	healthCheck healthchecker.Interface
 }
 
@@ -103,6 +107,34 @@ func (gs *GatewaySyncer) SetGatewayStatusError(ctx context.Context, err error) {
 	gs.syncGatewayStatusSafe(ctx)
 }
 
+// SetCondition sets the given condition in the Gateway status, replacing any existing condition of the same type.
+func (gs *GatewaySyncer) SetCondition(ctx context.Context, condition *metav1.Condition) {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	meta.SetStatusCondition(&gs.conditions, *condition)
+	gs.syncGatewayStatusSafe(ctx)
+}
+
+// SetExcludedEndpoints sets the remote Endpoints that are intentionally not connected by the peering policy. They're
+// reported in the Gateway status as connections with the Excluded status.
+func (gs *GatewaySyncer) SetExcludedEndpoints(ctx context.Context, endpoints []v1.EndpointSpec) {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	gs.excluded = endpoints
+	gs.syncGatewayStatusSafe(ctx)
+}
+
+// SetTransitRoutes sets the remote clusters that are reached through transit gateways, with the path to each.
+func (gs *GatewaySyncer) SetTransitRoutes(ctx context.Context, routes []v1.TransitRoute) {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	gs.transit = routes
+	gs.syncGatewayStatusSafe(ctx)
+}
+
 func (gs *GatewaySyncer) gatewayResourceInterface() resource.Interface[*v1.Gateway] {
 	return &resource.InterfaceFuncs[*v1.Gateway]{
 		GetFunc:    gs.client.Get,
@@ -236,6 +268,8 @@ func (gs *GatewaySyncer) generateGatewayObject() *v1.Gateway {
 			latencyInfo := gs.healthCheck.GetLatencyInfo(&connection.Endpoint)
 			if latencyInfo != nil {
 				connection.LatencyRTT = latencyInfo.Spec
+				connection.PathMTU = latencyInfo.PathMTU
+
 				if connection.Status == v1.Connected {
 					lastRTT, _ := time.ParseDuration(latencyInfo.Spec.Last)
 					cable.RecordConnectionLatency(localEndpoint.Spec.Backend, &localEndpoint.Spec, &connection.Endpoint, lastRTT.Seconds())
@@ -258,8 +292,24 @@ func (gs *GatewaySyncer) generateGatewayObject() *v1.Gateway {
 		}
 	}
 
+	for i := range gs.excluded {
+		connection := v1.NewConnection(&gs.excluded[i], "", false)
+		connection.SetStatus(v1.Excluded, "Cluster %q is excluded by the peering policy", gs.excluded[i].ClusterID)
+		connections = append(connections, *connection)
+	}
+
 	gateway.Status.Connections = connections
 
+	if len(gs.transit) > 0 {
+		gateway.Status.TransitRoutes = make([]v1.TransitRoute, len(gs.transit))
+		copy(gateway.Status.TransitRoutes, gs.transit)
+	}
+
+	if len(gs.conditions) > 0 {
+		gateway.Status.Conditions = make([]metav1.Condition, len(gs.conditions))
+		copy(gateway.Status.Conditions, gs.conditions)
+	}
+
 	logger.V(log.TRACE).Infof("Generated Gateway object: %+v", gateway)
 
 	return &gateway
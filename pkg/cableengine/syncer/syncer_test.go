@@ -193,6 +193,62 @@ func testGatewaySyncing() {
 			t.awaitGatewayUpdated(t.expectedGateway)
 		})
 	})
+
+	When("a condition is set", func() {
+		It("should update the Gateway resource with the condition", func() {
+			t.awaitGatewayUpdated(t.expectedGateway)
+
+			condition := metav1.Condition{
+				Type:               submarinerv1.GatewayRemoteCIDROverlap,
+				Status:             metav1.ConditionTrue,
+				Reason:             "RemoteCIDROverlap",
+				Message:            "Overlapping subnets",
+				LastTransitionTime: metav1.Now(),
+			}
+
+			t.expectedGateway.Status.Conditions = []metav1.Condition{condition}
+
+			t.syncer.SetCondition(context.Background(), &condition)
+			t.awaitGatewayUpdated(t.expectedGateway)
+		})
+	})
+
+	When("transit routes are set", func() {
+		It("should update the Gateway resource with the transit routes", func() {
+			t.awaitGatewayUpdated(t.expectedGateway)
+
+			routes := []submarinerv1.TransitRoute{{
+				ClusterID: "south",
+				Subnets:   []string{"172.3.0.0/16"},
+				Path:      []string{"hub"},
+			}}
+
+			t.expectedGateway.Status.TransitRoutes = routes
+
+			t.syncer.SetTransitRoutes(context.Background(), routes)
+			t.awaitGatewayUpdated(t.expectedGateway)
+		})
+	})
+
+	When("excluded endpoints are set", func() {
+		It("should update the Gateway resource with excluded connections", func() {
+			t.awaitGatewayUpdated(t.expectedGateway)
+
+			excluded := submarinerv1.EndpointSpec{
+				ClusterID: "spoke",
+				CableName: "submariner-cable-spoke-10-253-1-2",
+			}
+
+			t.expectedGateway.Status.Connections = []submarinerv1.Connection{{
+				Status:        submarinerv1.Excluded,
+				StatusMessage: `Cluster "spoke" is excluded by the peering policy`,
+				Endpoint:      excluded,
+			}}
+
+			t.syncer.SetExcludedEndpoints(context.Background(), []submarinerv1.EndpointSpec{excluded})
+			t.awaitGatewayUpdated(t.expectedGateway)
+		})
+	})
 }
 
 func testStaleGatewayCleanup() {
@@ -443,6 +499,16 @@ func testGatewayLatencyInfo() {
 			})
 
 			t.awaitGatewayUpdated(t.expectedGateway)
+
+			t.expectedGateway.Status.Connections[0].PathMTU = 1400
+
+			t.pinger.SetLatencyInfo(&healthchecker.LatencyInfo{
+				ConnectionStatus: healthchecker.Connected,
+				Spec:             t.expectedGateway.Status.Connections[0].LatencyRTT,
+				PathMTU:          1400,
+			})
+
+			t.awaitGatewayUpdated(t.expectedGateway)
 		})
 	})
 }
@@ -67,6 +67,13 @@ func OverlappingSubnets(localServiceCIDRs, localPodCIDRs, remoteSubnets []string
 	return nil
 }
 
+// Validate returns an error if the given string isn't a valid CIDR.
+func Validate(cidr string) error {
+	_, _, err := net.ParseCIDR(cidr)
+
+	return errors.Wrapf(err, "error parsing CIDR %q", cidr)
+}
+
 func IsOverlapping(cidrList []string, cidr string) (bool, error) {
 	_, newNet, err := net.ParseCIDR(cidr)
 	if err != nil {
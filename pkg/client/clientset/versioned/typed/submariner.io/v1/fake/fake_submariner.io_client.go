@@ -36,6 +36,10 @@ func (c *FakeSubmarinerV1) ClusterGlobalEgressIPs(namespace string) v1.ClusterGl
 	return &FakeClusterGlobalEgressIPs{c, namespace}
 }
 
+func (c *FakeSubmarinerV1) ConnectivitySummaries() v1.ConnectivitySummaryInterface {
+	return &FakeConnectivitySummaries{c}
+}
+
 func (c *FakeSubmarinerV1) Endpoints(namespace string) v1.EndpointInterface {
 	return &FakeEndpoints{c, namespace}
 }
@@ -56,10 +60,18 @@ func (c *FakeSubmarinerV1) GlobalIngressIPs(namespace string) v1.GlobalIngressIP
 	return &FakeGlobalIngressIPs{c, namespace}
 }
 
+func (c *FakeSubmarinerV1) InterClusterNetworkPolicies() v1.InterClusterNetworkPolicyInterface {
+	return &FakeInterClusterNetworkPolicies{c}
+}
+
 func (c *FakeSubmarinerV1) NonGatewayRoutes(namespace string) v1.NonGatewayRouteInterface {
 	return &FakeNonGatewayRoutes{c, namespace}
 }
 
+func (c *FakeSubmarinerV1) RoutingPolicies(namespace string) v1.RoutingPolicyInterface {
+	return &FakeRoutingPolicies{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeSubmarinerV1) RESTClient() rest.Interface {
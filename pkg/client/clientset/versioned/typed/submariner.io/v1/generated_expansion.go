@@ -22,6 +22,8 @@ type ClusterExpansion interface{}
 
 type ClusterGlobalEgressIPExpansion interface{}
 
+type ConnectivitySummaryExpansion interface{}
+
 type EndpointExpansion interface{}
 
 type GatewayExpansion interface{}
@@ -32,4 +34,8 @@ type GlobalEgressIPExpansion interface{}
 
 type GlobalIngressIPExpansion interface{}
 
+type InterClusterNetworkPolicyExpansion interface{}
+
 type NonGatewayRouteExpansion interface{}
+
+type RoutingPolicyExpansion interface{}
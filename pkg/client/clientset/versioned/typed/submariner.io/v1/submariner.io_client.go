@@ -30,12 +30,15 @@ type SubmarinerV1Interface interface {
 	RESTClient() rest.Interface
 	ClustersGetter
 	ClusterGlobalEgressIPsGetter
+	ConnectivitySummariesGetter
 	EndpointsGetter
 	GatewaysGetter
 	GatewayRoutesGetter
 	GlobalEgressIPsGetter
 	GlobalIngressIPsGetter
+	InterClusterNetworkPoliciesGetter
 	NonGatewayRoutesGetter
+	RoutingPoliciesGetter
 }
 
 // SubmarinerV1Client is used to interact with features provided by the submariner.io group.
@@ -51,6 +54,10 @@ func (c *SubmarinerV1Client) ClusterGlobalEgressIPs(namespace string) ClusterGlo
 	return newClusterGlobalEgressIPs(c, namespace)
 }
 
+func (c *SubmarinerV1Client) ConnectivitySummaries() ConnectivitySummaryInterface {
+	return newConnectivitySummaries(c)
+}
+
 func (c *SubmarinerV1Client) Endpoints(namespace string) EndpointInterface {
 	return newEndpoints(c, namespace)
 }
@@ -71,10 +78,18 @@ func (c *SubmarinerV1Client) GlobalIngressIPs(namespace string) GlobalIngressIPI
 	return newGlobalIngressIPs(c, namespace)
 }
 
+func (c *SubmarinerV1Client) InterClusterNetworkPolicies() InterClusterNetworkPolicyInterface {
+	return newInterClusterNetworkPolicies(c)
+}
+
 func (c *SubmarinerV1Client) NonGatewayRoutes(namespace string) NonGatewayRouteInterface {
 	return newNonGatewayRoutes(c, namespace)
 }
 
+func (c *SubmarinerV1Client) RoutingPolicies(namespace string) RoutingPolicyInterface {
+	return newRoutingPolicies(c, namespace)
+}
+
 // NewForConfig creates a new SubmarinerV1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -57,6 +57,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().Clusters().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("clusterglobalegressips"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().ClusterGlobalEgressIPs().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("connectivitysummaries"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().ConnectivitySummaries().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("endpoints"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().Endpoints().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("gateways"):
@@ -67,8 +69,12 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().GlobalEgressIPs().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("globalingressips"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().GlobalIngressIPs().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("interclusternetworkpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().InterClusterNetworkPolicies().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("nongatewayroutes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().NonGatewayRoutes().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("routingpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().RoutingPolicies().Informer()}, nil
 
 	}
 
@@ -28,6 +28,8 @@ type Interface interface {
 	Clusters() ClusterInformer
 	// ClusterGlobalEgressIPs returns a ClusterGlobalEgressIPInformer.
 	ClusterGlobalEgressIPs() ClusterGlobalEgressIPInformer
+	// ConnectivitySummaries returns a ConnectivitySummaryInformer.
+	ConnectivitySummaries() ConnectivitySummaryInformer
 	// Endpoints returns a EndpointInformer.
 	Endpoints() EndpointInformer
 	// Gateways returns a GatewayInformer.
@@ -38,8 +40,12 @@ type Interface interface {
 	GlobalEgressIPs() GlobalEgressIPInformer
 	// GlobalIngressIPs returns a GlobalIngressIPInformer.
 	GlobalIngressIPs() GlobalIngressIPInformer
+	// InterClusterNetworkPolicies returns a InterClusterNetworkPolicyInformer.
+	InterClusterNetworkPolicies() InterClusterNetworkPolicyInformer
 	// NonGatewayRoutes returns a NonGatewayRouteInformer.
 	NonGatewayRoutes() NonGatewayRouteInformer
+	// RoutingPolicies returns a RoutingPolicyInformer.
+	RoutingPolicies() RoutingPolicyInformer
 }
 
 type version struct {
@@ -63,6 +69,11 @@ func (v *version) ClusterGlobalEgressIPs() ClusterGlobalEgressIPInformer {
 	return &clusterGlobalEgressIPInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// ConnectivitySummaries returns a ConnectivitySummaryInformer.
+func (v *version) ConnectivitySummaries() ConnectivitySummaryInformer {
+	return &connectivitySummaryInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // Endpoints returns a EndpointInformer.
 func (v *version) Endpoints() EndpointInformer {
 	return &endpointInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -88,7 +99,17 @@ func (v *version) GlobalIngressIPs() GlobalIngressIPInformer {
 	return &globalIngressIPInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// InterClusterNetworkPolicies returns a InterClusterNetworkPolicyInformer.
+func (v *version) InterClusterNetworkPolicies() InterClusterNetworkPolicyInformer {
+	return &interClusterNetworkPolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // NonGatewayRoutes returns a NonGatewayRouteInformer.
 func (v *version) NonGatewayRoutes() NonGatewayRouteInformer {
 	return &nonGatewayRouteInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// RoutingPolicies returns a RoutingPolicyInformer.
+func (v *version) RoutingPolicies() RoutingPolicyInformer {
+	return &routingPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -34,6 +34,10 @@ type ClusterGlobalEgressIPListerExpansion interface{}
 // ClusterGlobalEgressIPNamespaceLister.
 type ClusterGlobalEgressIPNamespaceListerExpansion interface{}
 
+// ConnectivitySummaryListerExpansion allows custom methods to be added to
+// ConnectivitySummaryLister.
+type ConnectivitySummaryListerExpansion interface{}
+
 // EndpointListerExpansion allows custom methods to be added to
 // EndpointLister.
 type EndpointListerExpansion interface{}
@@ -74,6 +78,10 @@ type GlobalIngressIPListerExpansion interface{}
 // GlobalIngressIPNamespaceLister.
 type GlobalIngressIPNamespaceListerExpansion interface{}
 
+// InterClusterNetworkPolicyListerExpansion allows custom methods to be added to
+// InterClusterNetworkPolicyLister.
+type InterClusterNetworkPolicyListerExpansion interface{}
+
 // NonGatewayRouteListerExpansion allows custom methods to be added to
 // NonGatewayRouteLister.
 type NonGatewayRouteListerExpansion interface{}
@@ -81,3 +89,11 @@ type NonGatewayRouteListerExpansion interface{}
 // NonGatewayRouteNamespaceListerExpansion allows custom methods to be added to
 // NonGatewayRouteNamespaceLister.
 type NonGatewayRouteNamespaceListerExpansion interface{}
+
+// RoutingPolicyListerExpansion allows custom methods to be added to
+// RoutingPolicyLister.
+type RoutingPolicyListerExpansion interface{}
+
+// RoutingPolicyNamespaceListerExpansion allows custom methods to be added to
+// RoutingPolicyNamespaceLister.
+type RoutingPolicyNamespaceListerExpansion interface{}
@@ -22,6 +22,7 @@ package cni
 const (
 	Generic       = "generic"
 	Calico        = "calico"
+	Cilium        = "cilium"
 	CanalFlannel  = "canal-flannel"
 	Flannel       = "flannel"
 	KindNet       = "kindnet"
@@ -31,5 +32,5 @@ const (
 )
 
 func GetNetworkPlugins() []string {
-	return []string{Generic, Calico, CanalFlannel, Flannel, KindNet, OpenShiftSDN, OVNKubernetes, WeaveNet}
+	return []string{Generic, Calico, Cilium, CanalFlannel, Flannel, KindNet, OpenShiftSDN, OVNKubernetes, WeaveNet}
 }
@@ -0,0 +1,357 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectivity
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/admiral/pkg/resource"
+	"github.com/submariner-io/admiral/pkg/util"
+	"github.com/submariner-io/admiral/pkg/watcher"
+	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/cableengine/syncer"
+	v1typed "github.com/submariner-io/submariner/pkg/client/clientset/versioned/typed/submariner.io/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// SummaryName is the name of the cluster-scoped ConnectivitySummary maintained by the controller.
+const SummaryName = "submariner"
+
+const (
+	allConnectedReason         = "AllConnected"
+	clustersNotConnectedReason = "ClustersNotConnected"
+	noActiveGatewayReason      = "NoActiveGateway"
+	connectionErrorsReason     = "ConnectionErrors"
+	noConnectionErrorsReason   = "NoConnectionErrors"
+)
+
+// latencyChangeThreshold is the relative change of the average RTT to a remote cluster above which the latency in the
+// summary is updated. Smaller changes are ignored so the summary isn't rewritten on every health check.
+const latencyChangeThreshold = 0.2
+
+type controller struct {
+	sync.Mutex
+	client          v1typed.ConnectivitySummaryInterface
+	localClusterID  string
+	resourceWatcher watcher.Interface
+}
+
+var logger = log.Logger{Logger: logf.Log.WithName("Connectivity")}
+
+// StartController starts a controller that maintains the ConnectivitySummary from the local Gateways and the remote
+// Endpoints in the given namespace. It runs on every gateway pod, not just the leader, so the summary still reflects the
+// loss of the active Gateway. The summary is also re-evaluated periodically so a Gateway that stopped reporting is detected.
+func StartController(client v1typed.ConnectivitySummaryInterface, localClusterID, namespace string, config *watcher.Config,
+	stopCh <-chan struct{},
+) error {
+	logger.Info("Starting the connectivity summary controller")
+
+	c := &controller{
+		client:         client,
+		localClusterID: localClusterID,
+	}
+
+	handler := watcher.EventHandlerFuncs{
+		OnCreateFunc: c.handleEvent,
+		OnUpdateFunc: c.handleEvent,
+		OnDeleteFunc: c.handleEvent,
+	}
+
+	config.ResourceConfigs = []watcher.ResourceConfig{
+		{
+			Name:            "Connectivity summary Gateway watcher",
+			ResourceType:    &v1.Gateway{},
+			Handler:         handler,
+			SourceNamespace: namespace,
+		},
+		{
+			Name:            "Connectivity summary Endpoint watcher",
+			ResourceType:    &v1.Endpoint{},
+			Handler:         handler,
+			SourceNamespace: namespace,
+		},
+	}
+
+	var err error
+
+	c.resourceWatcher, err = watcher.New(config)
+	if err != nil {
+		return errors.Wrap(err, "error creating the resource watcher")
+	}
+
+	err = c.resourceWatcher.Start(stopCh)
+	if err != nil {
+		return errors.Wrap(err, "error starting the resource watcher")
+	}
+
+	go wait.Until(func() {
+		c.handleEvent(nil, 0)
+	}, syncer.GatewayUpdateInterval, stopCh)
+
+	return nil
+}
+
+func (c *controller) handleEvent(_ runtime.Object, _ int) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	gateways := c.resourceWatcher.ListResources(&v1.Gateway{}, labels.Everything())
+	endpoints := c.resourceWatcher.ListResources(&v1.Endpoint{}, labels.Everything())
+
+	summary := &v1.ConnectivitySummary{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: SummaryName,
+		},
+	}
+
+	summary.Status = c.buildStatus(gateways, endpoints, &v1.ConnectivitySummaryStatus{})
+
+	_, err := util.CreateOrUpdate[*v1.ConnectivitySummary](context.TODO(), c.summaryResourceInterface(), summary,
+		func(existing *v1.ConnectivitySummary) (*v1.ConnectivitySummary, error) {
+			existing.Status = c.buildStatus(gateways, endpoints, &existing.Status)
+			return existing, nil
+		})
+	if err != nil {
+		logger.Errorf(err, "Error updating the ConnectivitySummary %q", SummaryName)
+		return true
+	}
+
+	return false
+}
+
+func (c *controller) summaryResourceInterface() resource.Interface[*v1.ConnectivitySummary] {
+	return &resource.InterfaceFuncs[*v1.ConnectivitySummary]{
+		GetFunc:    c.client.Get,
+		CreateFunc: c.client.Create,
+		UpdateFunc: c.client.Update,
+		DeleteFunc: c.client.Delete,
+	}
+}
+
+// buildStatus builds the summary status from the connections reported by the active Gateways and the synced remote
+// Endpoints. The last error of each remote cluster and the condition transition times are carried over from the previous
+// status, as is the latency unless it changed significantly.
+func (c *controller) buildStatus(gateways, endpoints []runtime.Object, previous *v1.ConnectivitySummaryStatus,
+) v1.ConnectivitySummaryStatus {
+	byCluster := map[string]*v1.RemoteClusterConnectivity{}
+
+	clusterFor := func(clusterID string) *v1.RemoteClusterConnectivity {
+		if byCluster[clusterID] == nil {
+			byCluster[clusterID] = &v1.RemoteClusterConnectivity{ClusterID: clusterID}
+		}
+
+		return byCluster[clusterID]
+	}
+
+	for _, obj := range endpoints {
+		endpoint := obj.(*v1.Endpoint)
+		if endpoint.Spec.ClusterID == c.localClusterID {
+			continue
+		}
+
+		cluster := clusterFor(endpoint.Spec.ClusterID)
+		cluster.Expected = true
+		cluster.Backend = endpoint.Spec.Backend
+	}
+
+	hasActiveGateway := false
+
+	for _, obj := range gateways {
+		gateway := obj.(*v1.Gateway)
+		if gateway.Status.HAStatus != v1.HAStatusActive || isStale(gateway) {
+			continue
+		}
+
+		hasActiveGateway = true
+
+		for i := range gateway.Status.Connections {
+			setConnection(clusterFor(gateway.Status.Connections[i].Endpoint.ClusterID), &gateway.Status.Connections[i])
+		}
+	}
+
+	previousByCluster := map[string]*v1.RemoteClusterConnectivity{}
+	for i := range previous.Clusters {
+		previousByCluster[previous.Clusters[i].ClusterID] = &previous.Clusters[i]
+	}
+
+	status := v1.ConnectivitySummaryStatus{
+		Clusters:   make([]v1.RemoteClusterConnectivity, 0, len(byCluster)),
+		Conditions: append([]metav1.Condition(nil), previous.Conditions...),
+	}
+
+	for _, cluster := range byCluster {
+		setLastError(cluster, previousByCluster[cluster.ClusterID])
+		setLatency(cluster, previousByCluster[cluster.ClusterID])
+
+		if cluster.Expected {
+			status.ExpectedClusters++
+
+			if cluster.Connected {
+				status.ConnectedClusters++
+			}
+		}
+
+		status.Clusters = append(status.Clusters, *cluster)
+	}
+
+	sort.Slice(status.Clusters, func(i, j int) bool {
+		return status.Clusters[i].ClusterID < status.Clusters[j].ClusterID
+	})
+
+	setConditions(&status, hasActiveGateway)
+
+	return status
+}
+
+// isStale returns whether the given Gateway stopped reporting its status, eg because its pod died.
+func isStale(gateway *v1.Gateway) bool {
+	timestamp, err := strconv.ParseInt(gateway.Annotations[syncer.UpdateTimestampAnnotation], 10, 64)
+	if err != nil {
+		return false
+	}
+
+	return time.Since(time.Unix(timestamp, 0)) >= syncer.GatewayStaleTimeout
+}
+
+// setConnection records the given connection for the remote cluster, preferring an established one if the cluster
+// has several connections.
+func setConnection(cluster *v1.RemoteClusterConnectivity, connection *v1.Connection) {
+	if cluster.Connected {
+		return
+	}
+
+	cluster.Status = connection.Status
+	cluster.Connected = connection.Status == v1.Connected
+	cluster.LatencyRTT = connection.LatencyRTT
+	cluster.UsingNAT = connection.UsingNAT
+
+	if connection.Endpoint.Backend != "" {
+		cluster.Backend = connection.Endpoint.Backend
+	}
+
+	if connection.Status == v1.ConnectionError {
+		cluster.LastError = connection.StatusMessage
+	}
+}
+
+func setLastError(cluster, previous *v1.RemoteClusterConnectivity) {
+	if previous != nil && (cluster.LastError == "" || cluster.LastError == previous.LastError) {
+		cluster.LastError = previous.LastError
+		cluster.LastErrorTime = previous.LastErrorTime
+
+		return
+	}
+
+	if cluster.LastError != "" {
+		now := metav1.Now()
+		cluster.LastErrorTime = &now
+	}
+}
+
+// setLatency keeps the previous latency of the remote cluster if the average RTT didn't change by more than the
+// latencyChangeThreshold.
+func setLatency(cluster, previous *v1.RemoteClusterConnectivity) {
+	if cluster.LatencyRTT == nil || previous == nil || previous.LatencyRTT == nil {
+		return
+	}
+
+	current, err := time.ParseDuration(cluster.LatencyRTT.Average)
+	if err != nil {
+		return
+	}
+
+	last, err := time.ParseDuration(previous.LatencyRTT.Average)
+	if err != nil || last <= 0 {
+		return
+	}
+
+	if math.Abs(float64(current-last))/float64(last) <= latencyChangeThreshold {
+		cluster.LatencyRTT = previous.LatencyRTT
+	}
+}
+
+func setConditions(status *v1.ConnectivitySummaryStatus, hasActiveGateway bool) {
+	var notConnected, failing []string
+
+	for i := range status.Clusters {
+		cluster := &status.Clusters[i]
+		if !cluster.Expected || cluster.Connected {
+			continue
+		}
+
+		notConnected = append(notConnected, cluster.ClusterID)
+
+		if cluster.Status == v1.ConnectionError {
+			failing = append(failing, fmt.Sprintf("%s (%s)", cluster.ClusterID, cluster.LastError))
+		}
+	}
+
+	ready := metav1.Condition{
+		Type:    v1.ConnectivityReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  allConnectedReason,
+		Message: fmt.Sprintf("Connected to all %d expected remote clusters", status.ExpectedClusters),
+	}
+
+	degraded := metav1.Condition{
+		Type:    v1.ConnectivityDegraded,
+		Status:  metav1.ConditionFalse,
+		Reason:  noConnectionErrorsReason,
+		Message: "No connection to an expected remote cluster is failing",
+	}
+
+	switch {
+	case !hasActiveGateway:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = noActiveGatewayReason
+		ready.Message = "There's no active Gateway in the local cluster"
+
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = noActiveGatewayReason
+		degraded.Message = ready.Message
+	case len(notConnected) > 0:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = clustersNotConnectedReason
+		ready.Message = fmt.Sprintf("Connected to %d of %d expected remote clusters, not connected to: %s",
+			status.ConnectedClusters, status.ExpectedClusters, strings.Join(notConnected, ", "))
+	}
+
+	if len(failing) > 0 {
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = connectionErrorsReason
+		degraded.Message = fmt.Sprintf("The connections to these remote clusters are failing: %s", strings.Join(failing, "; "))
+	}
+
+	meta.SetStatusCondition(&status.Conditions, ready)
+	meta.SetStatusCondition(&status.Conditions, degraded)
+}
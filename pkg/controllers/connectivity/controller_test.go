@@ -0,0 +1,315 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectivity_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/admiral/pkg/log/kzerolog"
+	"github.com/submariner-io/admiral/pkg/syncer/test"
+	"github.com/submariner-io/admiral/pkg/watcher"
+	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/cableengine/syncer"
+	fakeSubmariner "github.com/submariner-io/submariner/pkg/client/clientset/versioned/fake"
+	v1typed "github.com/submariner-io/submariner/pkg/client/clientset/versioned/typed/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/controllers/connectivity"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	fakeClient "k8s.io/client-go/dynamic/fake"
+	kubeScheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	namespace      = "submariner"
+	localClusterID = "west"
+)
+
+func init() {
+	kzerolog.AddFlags(nil)
+}
+
+var _ = BeforeSuite(func() {
+	kzerolog.InitK8sLogging()
+})
+
+var _ = Describe("Connectivity summary controller", func() {
+	var (
+		config    *watcher.Config
+		gateways  dynamic.ResourceInterface
+		endpoints dynamic.ResourceInterface
+		summaries v1typed.ConnectivitySummaryInterface
+		clientset *fakeSubmariner.Clientset
+		gateway   *v1.Gateway
+		stopCh    chan struct{}
+	)
+
+	BeforeEach(func() {
+		Expect(v1.AddToScheme(kubeScheme.Scheme)).To(Succeed())
+
+		scheme := runtime.NewScheme()
+		Expect(v1.AddToScheme(scheme)).To(Succeed())
+
+		client := fakeClient.NewSimpleDynamicClient(scheme)
+
+		restMapper := test.GetRESTMapperFor(&v1.Gateway{}, &v1.Endpoint{})
+
+		gateways = client.Resource(*test.GetGroupVersionResourceFor(restMapper, &v1.Gateway{})).Namespace(namespace)
+		endpoints = client.Resource(*test.GetGroupVersionResourceFor(restMapper, &v1.Endpoint{})).Namespace(namespace)
+		clientset = fakeSubmariner.NewSimpleClientset()
+		summaries = clientset.SubmarinerV1().ConnectivitySummaries()
+
+		config = &watcher.Config{
+			RestMapper: restMapper,
+			Client:     client,
+			Scheme:     scheme,
+		}
+
+		gateway = &v1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gateway-node",
+				Namespace: namespace,
+			},
+			Status: v1.GatewayStatus{
+				HAStatus: v1.HAStatusActive,
+			},
+		}
+
+		test.CreateResource(endpoints, newEndpoint(localClusterID))
+		test.CreateResource(endpoints, newEndpoint("east"))
+		test.CreateResource(endpoints, newEndpoint("north"))
+	})
+
+	JustBeforeEach(func() {
+		stopCh = make(chan struct{})
+
+		Expect(connectivity.StartController(summaries, localClusterID, namespace, config, stopCh)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		close(stopCh)
+	})
+
+	awaitCondition := func(condType string, status metav1.ConditionStatus) *v1.ConnectivitySummary {
+		var summary *v1.ConnectivitySummary
+
+		Eventually(func() metav1.ConditionStatus {
+			var err error
+
+			summary, err = summaries.Get(context.TODO(), connectivity.SummaryName, metav1.GetOptions{})
+			if err != nil {
+				return ""
+			}
+
+			cond := meta.FindStatusCondition(summary.Status.Conditions, condType)
+			if cond == nil {
+				return ""
+			}
+
+			return cond.Status
+		}).Should(Equal(status), "Condition %q", condType)
+
+		return summary
+	}
+
+	When("all expected remote clusters are connected", func() {
+		BeforeEach(func() {
+			gateway.Status.Connections = []v1.Connection{
+				newConnection("east", v1.Connected, ""),
+				newConnection("north", v1.Connected, ""),
+			}
+
+			test.CreateResource(gateways, gateway)
+		})
+
+		It("should report Ready", func() {
+			summary := awaitCondition(v1.ConnectivityReady, metav1.ConditionTrue)
+			Expect(summary.Status.ExpectedClusters).To(Equal(2))
+			Expect(summary.Status.ConnectedClusters).To(Equal(2))
+			Expect(summary.Status.Clusters).To(HaveLen(2))
+			Expect(summary.Status.Clusters[0].ClusterID).To(Equal("east"))
+			Expect(summary.Status.Clusters[0].Connected).To(BeTrue())
+			Expect(summary.Status.Clusters[1].ClusterID).To(Equal("north"))
+
+			awaitCondition(v1.ConnectivityDegraded, metav1.ConditionFalse)
+		})
+	})
+
+	When("a connection to an expected remote cluster is failing", func() {
+		BeforeEach(func() {
+			gateway.Status.Connections = []v1.Connection{
+				newConnection("east", v1.Connected, ""),
+				newConnection("north", v1.ConnectionError, "handshake failed"),
+			}
+
+			test.CreateResource(gateways, gateway)
+		})
+
+		It("should report Degraded and record the last error", func() {
+			summary := awaitCondition(v1.ConnectivityDegraded, metav1.ConditionTrue)
+			Expect(summary.Status.ConnectedClusters).To(Equal(1))
+			Expect(summary.Status.Clusters[1].LastError).To(Equal("handshake failed"))
+			Expect(summary.Status.Clusters[1].LastErrorTime).ToNot(BeNil())
+
+			awaitCondition(v1.ConnectivityReady, metav1.ConditionFalse)
+		})
+
+		Context("and subsequently recovers", func() {
+			It("should report Ready and retain the last error", func() {
+				awaitCondition(v1.ConnectivityDegraded, metav1.ConditionTrue)
+
+				gateway.Status.Connections[1] = newConnection("north", v1.Connected, "")
+				test.UpdateResource(gateways, gateway)
+
+				summary := awaitCondition(v1.ConnectivityReady, metav1.ConditionTrue)
+				Expect(summary.Status.Clusters[1].LastError).To(Equal("handshake failed"))
+
+				awaitCondition(v1.ConnectivityDegraded, metav1.ConditionFalse)
+			})
+		})
+	})
+
+	When("there's no active Gateway", func() {
+		BeforeEach(func() {
+			gateway.Status.HAStatus = v1.HAStatusPassive
+			test.CreateResource(gateways, gateway)
+		})
+
+		It("should report not Ready", func() {
+			summary := awaitCondition(v1.ConnectivityReady, metav1.ConditionFalse)
+			Expect(meta.FindStatusCondition(summary.Status.Conditions, v1.ConnectivityReady).Reason).To(Equal("NoActiveGateway"))
+			Expect(summary.Status.ConnectedClusters).To(BeZero())
+		})
+	})
+
+	When("the active Gateway stops reporting its status", func() {
+		BeforeEach(func() {
+			gateway.Status.Connections = []v1.Connection{
+				newConnection("east", v1.Connected, ""),
+				newConnection("north", v1.Connected, ""),
+			}
+
+			gateway.Annotations = map[string]string{
+				syncer.UpdateTimestampAnnotation: strconv.FormatInt(time.Now().UTC().Unix(), 10),
+			}
+
+			test.CreateResource(gateways, gateway)
+		})
+
+		It("should report not Ready once it's stale", func() {
+			awaitCondition(v1.ConnectivityReady, metav1.ConditionTrue)
+
+			gateway.Annotations[syncer.UpdateTimestampAnnotation] = strconv.FormatInt(
+				time.Now().Add(-2*syncer.GatewayStaleTimeout).UTC().Unix(), 10)
+			test.UpdateResource(gateways, gateway)
+
+			summary := awaitCondition(v1.ConnectivityReady, metav1.ConditionFalse)
+			Expect(meta.FindStatusCondition(summary.Status.Conditions, v1.ConnectivityReady).Reason).To(Equal("NoActiveGateway"))
+		})
+	})
+
+	When("the latency of a connection changes", func() {
+		var countWrites func() int
+
+		BeforeEach(func() {
+			gateway.Status.Connections = []v1.Connection{
+				newConnection("east", v1.Connected, ""),
+				newConnection("north", v1.Connected, ""),
+			}
+
+			gateway.Status.Connections[0].LatencyRTT = &v1.LatencyRTTSpec{Last: "10ms", Average: "10ms"}
+
+			test.CreateResource(gateways, gateway)
+
+			countWrites = func() int {
+				count := 0
+
+				for _, action := range clientset.Actions() {
+					if action.GetVerb() == "create" || action.GetVerb() == "update" {
+						count++
+					}
+				}
+
+				return count
+			}
+		})
+
+		getLatency := func() string {
+			summary, err := summaries.Get(context.TODO(), connectivity.SummaryName, metav1.GetOptions{})
+			if err != nil || len(summary.Status.Clusters) == 0 || summary.Status.Clusters[0].LatencyRTT == nil {
+				return ""
+			}
+
+			return summary.Status.Clusters[0].LatencyRTT.Average
+		}
+
+		It("should only update the summary when the change is significant", func() {
+			Eventually(getLatency).Should(Equal("10ms"))
+
+			writes := countWrites()
+
+			for _, average := range []string{"10.5ms", "11ms", "9ms", "11.5ms"} {
+				gateway.Status.Connections[0].LatencyRTT = &v1.LatencyRTTSpec{Last: average, Average: average}
+				test.UpdateResource(gateways, gateway)
+			}
+
+			Consistently(countWrites, 300*time.Millisecond).Should(Equal(writes))
+			Expect(getLatency()).To(Equal("10ms"))
+
+			gateway.Status.Connections[0].LatencyRTT = &v1.LatencyRTTSpec{Last: "20ms", Average: "20ms"}
+			test.UpdateResource(gateways, gateway)
+
+			Eventually(getLatency).Should(Equal("20ms"))
+			Expect(countWrites()).To(Equal(writes + 1))
+		})
+	})
+})
+
+func newEndpoint(clusterID string) *v1.Endpoint {
+	return &v1.Endpoint{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterID + "-submariner-cable",
+			Namespace: namespace,
+		},
+		Spec: v1.EndpointSpec{
+			ClusterID: clusterID,
+			CableName: "submariner-cable-" + clusterID,
+			Backend:   "libreswan",
+		},
+	}
+}
+
+func newConnection(clusterID string, status v1.ConnectionStatus, message string) v1.Connection {
+	return v1.Connection{
+		Status:        status,
+		StatusMessage: message,
+		Endpoint:      newEndpoint(clusterID).Spec,
+	}
+}
+
+func TestConnectivityController(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Connectivity summary controller Suite")
+}
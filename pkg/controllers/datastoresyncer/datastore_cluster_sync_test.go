@@ -34,6 +34,7 @@ import (
 var (
 	_ = Describe("Cluster syncing", testClusterSyncing)
 	_ = Describe("Cluster cleanup", testClusterCleanup)
+	_ = Describe("Cluster maintenance mode", testClusterMaintenance)
 )
 
 func testClusterSyncing() {
@@ -150,3 +151,78 @@ func testClusterCleanup() {
 		test.AwaitNoResource(t.localClusters, otherClusterID)
 	})
 }
+
+func testClusterMaintenance() {
+	t := newTestDriver()
+
+	setMaintenance := func(cluster *submarinerv1.Cluster, inMaintenance bool) *submarinerv1.Cluster {
+		cluster.Annotations = nil
+		if inMaintenance {
+			cluster.Annotations = map[string]string{submarinerv1.MaintenanceAnnotation: "true"}
+		}
+
+		return cluster
+	}
+
+	maintenanceStatus := func() metav1.ConditionStatus {
+		condition := t.getMaintenanceCondition()
+		if condition == nil {
+			return ""
+		}
+
+		return condition.Status
+	}
+
+	When("the local Cluster enters and leaves maintenance mode", func() {
+		It("should withdraw the local Endpoint from the broker and publish it again", func() {
+			awaitEndpoint(t.brokerEndpoints, &t.localEndpoint.Spec)
+
+			cluster := newCluster(&t.localCluster.Spec)
+			test.UpdateResource(t.localClusters, setMaintenance(cluster, true))
+
+			test.AwaitNoResource(t.brokerEndpoints, getEndpointName(&t.localEndpoint.Spec))
+			Eventually(maintenanceStatus).Should(Equal(metav1.ConditionTrue))
+			Expect(t.getMaintenanceCondition().Message).To(ContainSubstring(submarinerv1.MaintenanceAnnotation))
+
+			awaitEndpoint(t.localEndpoints, &t.localEndpoint.Spec)
+
+			test.UpdateResource(t.localClusters, setMaintenance(cluster, false))
+
+			awaitEndpoint(t.brokerEndpoints, &t.localEndpoint.Spec)
+			Eventually(maintenanceStatus).Should(Equal(metav1.ConditionFalse))
+		})
+	})
+
+	When("the local Cluster is in maintenance mode on startup", func() {
+		BeforeEach(func() {
+			test.CreateResource(t.localClusters, setMaintenance(newCluster(&t.localCluster.Spec), true))
+		})
+
+		It("should preserve it and not publish the local Endpoint to the broker", func() {
+			awaitEndpoint(t.localEndpoints, &t.localEndpoint.Spec)
+			Eventually(maintenanceStatus).Should(Equal(metav1.ConditionTrue))
+
+			time.Sleep(500 * time.Millisecond)
+			test.AwaitNoResource(t.brokerEndpoints, getEndpointName(&t.localEndpoint.Spec))
+
+			obj := test.AwaitResource(t.localClusters, t.localCluster.Spec.ClusterID)
+			Expect(obj.GetAnnotations()).To(HaveKeyWithValue(submarinerv1.MaintenanceAnnotation, "true"))
+		})
+	})
+
+	When("the local Cluster is in maintenance mode on startup and the local Endpoint was previously published", func() {
+		BeforeEach(func() {
+			test.CreateResource(t.localClusters, setMaintenance(newCluster(&t.localCluster.Spec), true))
+			test.CreateResource(t.localEndpoints, newEndpoint(&t.localEndpoint.Spec))
+			test.CreateResource(t.brokerEndpoints, test.SetClusterIDLabel(newEndpoint(&t.localEndpoint.Spec), clusterID))
+		})
+
+		It("should withdraw the local Endpoint from the broker", func() {
+			test.AwaitNoResource(t.brokerEndpoints, getEndpointName(&t.localEndpoint.Spec))
+			Eventually(maintenanceStatus).Should(Equal(metav1.ConditionTrue))
+
+			time.Sleep(500 * time.Millisecond)
+			test.AwaitNoResource(t.brokerEndpoints, getEndpointName(&t.localEndpoint.Spec))
+		})
+	})
+}
@@ -31,7 +31,9 @@ import (
 	"github.com/submariner-io/admiral/pkg/syncer/test"
 	testutil "github.com/submariner-io/admiral/pkg/test"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/controllers/datastoresyncer"
 	"github.com/submariner-io/submariner/pkg/globalnet/constants"
+	"github.com/submariner-io/submariner/pkg/peering"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -42,6 +44,7 @@ var (
 	_ = Describe("Endpoint syncing", testEndpointSyncing)
 	_ = Describe("Endpoint exclusivity", testEndpointExclusivity)
 	_ = Describe("Endpoint cleanup", testEndpointCleanup)
+	_ = Describe("Stale Endpoint removal", testStaleEndpointRemoval)
 )
 
 func testEndpointSyncing() {
@@ -107,6 +110,254 @@ func testEndpointSyncing() {
 		})
 	})
 
+	When("a remote Endpoint's subnets overlap with the local subnets", func() {
+		var endpoint *submarinerv1.Endpoint
+
+		BeforeEach(func() {
+			endpoint = newEndpoint(&submarinerv1.EndpointSpec{
+				CableName: fmt.Sprintf("submariner-cable-%s-10-253-1-2", otherClusterID),
+				ClusterID: otherClusterID,
+				PrivateIP: "10.253.1.2",
+				Subnets:   []string{"10.1.0.0/16", "172.1.0.0/16"},
+			})
+		})
+
+		It("should not sync it and should report the overlap", func() {
+			awaitEndpoint(t.brokerEndpoints, &t.localEndpoint.Spec)
+
+			name := test.CreateResource(t.brokerEndpoints, test.SetClusterIDLabel(endpoint, endpoint.Spec.ClusterID)).GetName()
+
+			Eventually(t.getOverlapCondition).ShouldNot(BeNil())
+			condition := t.getOverlapCondition()
+			Expect(condition.Type).To(Equal(submarinerv1.GatewayRemoteCIDROverlap))
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring(otherClusterID))
+			Expect(condition.Message).To(ContainSubstring("10.1.0.0/16 overlaps 10.0.0.0/14"))
+			Expect(condition.Message).To(ContainSubstring("non-overlapping Globalnet CIDRs"))
+
+			Eventually(t.recorder.Events).Should(Receive(And(ContainSubstring(corev1.EventTypeWarning),
+				ContainSubstring(name), ContainSubstring("10.1.0.0/16 overlaps 10.0.0.0/14"))))
+
+			testutil.EnsureNoResource(resource.ForDynamic(t.localEndpoints), name)
+
+			Expect(t.brokerEndpoints.Delete(context.TODO(), name, metav1.DeleteOptions{})).To(Succeed())
+
+			Eventually(func() metav1.ConditionStatus {
+				return t.getOverlapCondition().Status
+			}).Should(Equal(metav1.ConditionFalse))
+		})
+
+		Context("and Globalnet isn't enabled", func() {
+			BeforeEach(func() {
+				globalCIDR := t.localCluster.Spec.GlobalCIDR
+				t.localCluster.Spec.GlobalCIDR = nil
+
+				DeferCleanup(func() {
+					t.localCluster.Spec.GlobalCIDR = globalCIDR
+				})
+			})
+
+			It("should suggest enabling Globalnet", func() {
+				test.CreateResource(t.brokerEndpoints, test.SetClusterIDLabel(endpoint, endpoint.Spec.ClusterID))
+
+				Eventually(t.getOverlapCondition).ShouldNot(BeNil())
+				Expect(t.getOverlapCondition().Message).To(ContainSubstring("Enable Globalnet"))
+			})
+		})
+	})
+
+	When("a remote Endpoint's cluster is denied by the peering policy", func() {
+		var endpoint *submarinerv1.Endpoint
+
+		BeforeEach(func() {
+			var err error
+
+			t.peeringPolicy, err = peering.NewPolicy(nil, []string{otherClusterID}, "")
+			Expect(err).To(Succeed())
+
+			endpoint = newEndpoint(&submarinerv1.EndpointSpec{
+				CableName: fmt.Sprintf("submariner-cable-%s-10-253-1-2", otherClusterID),
+				ClusterID: otherClusterID,
+				PrivateIP: "10.253.1.2",
+				Subnets:   []string{"172.1.0.0/16"},
+			})
+		})
+
+		It("should not sync it and should report it as excluded", func() {
+			awaitEndpoint(t.brokerEndpoints, &t.localEndpoint.Spec)
+
+			name := test.CreateResource(t.brokerEndpoints, test.SetClusterIDLabel(endpoint, endpoint.Spec.ClusterID)).GetName()
+
+			Eventually(t.getExcludedEndpoints).Should(Equal([]submarinerv1.EndpointSpec{endpoint.Spec}))
+			testutil.EnsureNoResource(resource.ForDynamic(t.localEndpoints), name)
+
+			Expect(t.brokerEndpoints.Delete(context.TODO(), name, metav1.DeleteOptions{})).To(Succeed())
+			Eventually(t.getExcludedEndpoints).Should(BeEmpty())
+		})
+	})
+
+	When("the peering policy selects remote clusters by their labels", func() {
+		var (
+			endpoint *submarinerv1.Endpoint
+			cluster  *submarinerv1.Cluster
+		)
+
+		BeforeEach(func() {
+			var err error
+
+			t.peeringPolicy, err = peering.NewPolicy(nil, nil, "role=hub")
+			Expect(err).To(Succeed())
+
+			endpoint = newEndpoint(&submarinerv1.EndpointSpec{
+				CableName: fmt.Sprintf("submariner-cable-%s-10-253-1-2", otherClusterID),
+				ClusterID: otherClusterID,
+				PrivateIP: "10.253.1.2",
+				Subnets:   []string{"172.1.0.0/16"},
+			})
+
+			cluster = newCluster(&submarinerv1.ClusterSpec{
+				ClusterID:   otherClusterID,
+				ClusterCIDR: []string{"172.1.0.0/16"},
+			})
+		})
+
+		Context("and the remote Cluster matches", func() {
+			BeforeEach(func() {
+				cluster.Labels = map[string]string{"role": "hub"}
+			})
+
+			It("should sync the remote Endpoint once its Cluster is synced", func() {
+				test.CreateResource(t.brokerEndpoints, test.SetClusterIDLabel(endpoint, endpoint.Spec.ClusterID))
+				test.CreateResource(t.brokerClusters, test.SetClusterIDLabel(cluster, otherClusterID))
+
+				awaitEndpoint(t.localEndpoints, &endpoint.Spec)
+				Expect(t.getExcludedEndpoints()).To(BeEmpty())
+			})
+		})
+
+		Context("and the remote Cluster doesn't match", func() {
+			BeforeEach(func() {
+				cluster.Labels = map[string]string{"role": "spoke"}
+			})
+
+			It("should not sync the remote Endpoint and should report it as excluded", func() {
+				test.CreateResource(t.brokerClusters, test.SetClusterIDLabel(cluster, otherClusterID))
+				awaitCluster(t.localClusters, &cluster.Spec)
+
+				name := test.CreateResource(t.brokerEndpoints, test.SetClusterIDLabel(endpoint, endpoint.Spec.ClusterID)).GetName()
+
+				Eventually(t.getExcludedEndpoints).Should(HaveLen(1))
+				testutil.EnsureNoResource(resource.ForDynamic(t.localEndpoints), name)
+			})
+		})
+	})
+
+	When("transit is enabled and a remote Endpoint is synced", func() {
+		BeforeEach(func() {
+			t.transitEnabled = true
+		})
+
+		It("should advertise a transit route to the remote cluster in the local Endpoint", func() {
+			awaitEndpoint(t.brokerEndpoints, &t.localEndpoint.Spec)
+
+			endpoint := newEndpoint(&submarinerv1.EndpointSpec{
+				CableName: fmt.Sprintf("submariner-cable-%s-10-253-1-2", otherClusterID),
+				ClusterID: otherClusterID,
+				PrivateIP: "10.253.1.2",
+				Subnets:   []string{"172.1.0.0/16"},
+			})
+
+			test.CreateResource(t.brokerEndpoints, test.SetClusterIDLabel(endpoint, endpoint.Spec.ClusterID))
+
+			expected := t.localEndpoint.Spec
+			expected.TransitRoutes = []submarinerv1.TransitRoute{{
+				ClusterID: otherClusterID,
+				Subnets:   endpoint.Spec.Subnets,
+				Path:      []string{clusterID},
+			}}
+
+			awaitEndpoint(t.brokerEndpoints, &expected)
+		})
+	})
+
+	When("a remote transit Endpoint advertises routes to clusters configured to be reached through transit", func() {
+		var endpoint *submarinerv1.Endpoint
+
+		BeforeEach(func() {
+			t.transitClusters = []string{"south", "north"}
+
+			endpoint = newEndpoint(&submarinerv1.EndpointSpec{
+				CableName: fmt.Sprintf("submariner-cable-%s-10-253-1-2", otherClusterID),
+				ClusterID: otherClusterID,
+				PrivateIP: "10.253.1.2",
+				Subnets:   []string{"172.1.0.0/16"},
+				TransitRoutes: []submarinerv1.TransitRoute{
+					{
+						ClusterID: "south",
+						Subnets:   []string{"172.3.0.0/16"},
+						Path:      []string{otherClusterID},
+					},
+					{
+						ClusterID: "north",
+						Subnets:   []string{"172.4.0.0/16"},
+						Path:      []string{otherClusterID, clusterID},
+					},
+				},
+			})
+		})
+
+		It("should route the loop-free transit subnets via the transit Endpoint and report the path", func() {
+			test.CreateResource(t.brokerEndpoints, test.SetClusterIDLabel(endpoint, endpoint.Spec.ClusterID))
+
+			expected := endpoint.Spec
+			expected.Subnets = []string{"172.1.0.0/16", "172.3.0.0/16"}
+			awaitEndpoint(t.localEndpoints, &expected)
+
+			Eventually(t.getTransitRoutes).Should(Equal([]submarinerv1.TransitRoute{endpoint.Spec.TransitRoutes[0]}))
+		})
+
+		It("should not sync the Endpoints of the transit clusters", func() {
+			test.CreateResource(t.brokerEndpoints, test.SetClusterIDLabel(endpoint, endpoint.Spec.ClusterID))
+
+			south := newEndpoint(&submarinerv1.EndpointSpec{
+				CableName: "submariner-cable-south-10-253-1-3",
+				ClusterID: "south",
+				PrivateIP: "10.253.1.3",
+				Subnets:   []string{"172.3.0.0/16"},
+			})
+
+			name := test.CreateResource(t.brokerEndpoints, test.SetClusterIDLabel(south, south.Spec.ClusterID)).GetName()
+
+			expected := endpoint.Spec
+			expected.Subnets = []string{"172.1.0.0/16", "172.3.0.0/16"}
+			awaitEndpoint(t.localEndpoints, &expected)
+
+			testutil.EnsureNoResource(resource.ForDynamic(t.localEndpoints), name)
+			Expect(t.getExcludedEndpoints()).To(BeEmpty())
+		})
+	})
+
+	When("a remote transit Endpoint advertises routes to clusters not configured to be reached through transit", func() {
+		It("should not route the transit subnets via the transit Endpoint", func() {
+			endpoint := newEndpoint(&submarinerv1.EndpointSpec{
+				CableName: fmt.Sprintf("submariner-cable-%s-10-253-1-2", otherClusterID),
+				ClusterID: otherClusterID,
+				PrivateIP: "10.253.1.2",
+				Subnets:   []string{"172.1.0.0/16"},
+				TransitRoutes: []submarinerv1.TransitRoute{{
+					ClusterID: "south",
+					Subnets:   []string{"172.3.0.0/16"},
+					Path:      []string{otherClusterID},
+				}},
+			})
+
+			test.CreateResource(t.brokerEndpoints, test.SetClusterIDLabel(endpoint, endpoint.Spec.ClusterID))
+
+			awaitEndpoint(t.localEndpoints, &endpoint.Spec)
+			Consistently(t.getTransitRoutes).Should(BeEmpty())
+		})
+	})
+
 	When("a remote Endpoint is synced locally", func() {
 		It("should not try to re-sync to the broker", func() {
 			awaitEndpoint(t.brokerEndpoints, &t.localEndpoint.Spec)
@@ -279,3 +530,118 @@ func testEndpointCleanup() {
 		test.AwaitNoResource(t.localEndpoints, existingRemoteEndpoint.GetName())
 	})
 }
+
+func testStaleEndpointRemoval() {
+	t := newTestDriver()
+
+	var remoteEndpoint *submarinerv1.Endpoint
+
+	BeforeEach(func() {
+		t.staleEndpointTTL = time.Hour
+
+		remoteEndpoint = newEndpoint(&submarinerv1.EndpointSpec{
+			CableName: fmt.Sprintf("submariner-cable-%s-10-253-1-2", otherClusterID),
+			ClusterID: otherClusterID,
+			PrivateIP: "10.253.1.2",
+			Subnets:   []string{"200.0.0.0/16", "20.0.0.0/14"},
+		})
+
+		test.SetClusterIDLabel(remoteEndpoint, otherClusterID)
+		test.CreateResource(t.brokerClusters, test.SetClusterIDLabel(newCluster(&submarinerv1.ClusterSpec{ClusterID: otherClusterID}),
+			otherClusterID))
+	})
+
+	setHeartbeat := func(heartbeat time.Time) {
+		remoteEndpoint.Annotations = map[string]string{
+			submarinerv1.EndpointHeartbeatAnnotation: heartbeat.UTC().Format(time.RFC3339),
+		}
+	}
+
+	It("should publish the local Endpoint to the broker with a heartbeat", func() {
+		awaitEndpoint(t.brokerEndpoints, &t.localEndpoint.Spec)
+
+		obj := test.AwaitResource(t.brokerEndpoints, getEndpointName(&t.localEndpoint.Spec))
+		heartbeat, err := time.Parse(time.RFC3339, obj.GetAnnotations()[submarinerv1.EndpointHeartbeatAnnotation])
+		Expect(err).To(Succeed())
+		Expect(heartbeat).To(BeTemporally("~", time.Now(), time.Minute))
+	})
+
+	When("the local Endpoint and Cluster are removed from the broker", func() {
+		BeforeEach(func() {
+			DeferCleanup(datastoresyncer.SetHeartbeatInterval(100 * time.Millisecond))
+		})
+
+		It("should publish them again on the next heartbeat renewal", func() {
+			awaitEndpoint(t.brokerEndpoints, &t.localEndpoint.Spec)
+			awaitCluster(t.brokerClusters, &t.localCluster.Spec)
+
+			endpointName := getEndpointName(&t.localEndpoint.Spec)
+			Expect(t.brokerEndpoints.Delete(context.TODO(), endpointName, metav1.DeleteOptions{})).To(Succeed())
+			Expect(t.brokerClusters.Delete(context.TODO(), clusterID, metav1.DeleteOptions{})).To(Succeed())
+
+			awaitEndpoint(t.brokerEndpoints, &t.localEndpoint.Spec)
+			awaitCluster(t.brokerClusters, &t.localCluster.Spec)
+		})
+	})
+
+	When("a remote Endpoint's heartbeat is older than the TTL", func() {
+		BeforeEach(func() {
+			setHeartbeat(time.Now().Add(-2 * time.Hour))
+			test.CreateResource(t.brokerEndpoints, remoteEndpoint)
+		})
+
+		It("should remove the Endpoint and its Cluster from the broker and record an Event", func() {
+			test.AwaitNoResource(t.brokerEndpoints, remoteEndpoint.Name)
+			test.AwaitNoResource(t.brokerClusters, otherClusterID)
+
+			Eventually(t.recorder.Events).Should(Receive(And(ContainSubstring(corev1.EventTypeNormal),
+				ContainSubstring("StaleEndpointRemoved"), ContainSubstring(remoteEndpoint.Name))))
+
+			test.AwaitNoResource(t.localEndpoints, remoteEndpoint.Name)
+		})
+	})
+
+	When("a remote Endpoint's heartbeat is within the TTL", func() {
+		BeforeEach(func() {
+			setHeartbeat(time.Now())
+			test.CreateResource(t.brokerEndpoints, remoteEndpoint)
+		})
+
+		It("should not remove the Endpoint", func() {
+			awaitEndpoint(t.localEndpoints, &remoteEndpoint.Spec)
+
+			time.Sleep(500 * time.Millisecond)
+			test.AwaitResource(t.brokerEndpoints, remoteEndpoint.Name)
+			test.AwaitResource(t.brokerClusters, otherClusterID)
+		})
+	})
+
+	When("a remote Endpoint has no heartbeat", func() {
+		BeforeEach(func() {
+			test.CreateResource(t.brokerEndpoints, remoteEndpoint)
+		})
+
+		It("should not remove the Endpoint", func() {
+			awaitEndpoint(t.localEndpoints, &remoteEndpoint.Spec)
+
+			time.Sleep(500 * time.Millisecond)
+			test.AwaitResource(t.brokerEndpoints, remoteEndpoint.Name)
+		})
+	})
+
+	When("no TTL is configured", func() {
+		BeforeEach(func() {
+			t.staleEndpointTTL = 0
+
+			setHeartbeat(time.Now().Add(-2 * time.Hour))
+			test.CreateResource(t.brokerEndpoints, remoteEndpoint)
+		})
+
+		It("should not remove stale Endpoints", func() {
+			awaitEndpoint(t.localEndpoints, &remoteEndpoint.Spec)
+
+			time.Sleep(500 * time.Millisecond)
+			test.AwaitResource(t.brokerEndpoints, remoteEndpoint.Name)
+		})
+	})
+}
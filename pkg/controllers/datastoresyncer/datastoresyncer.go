@@ -21,6 +21,9 @@ package datastoresyncer
 import (
 	"context"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/federate"
@@ -31,40 +34,84 @@ import (
 	"github.com/submariner-io/admiral/pkg/util"
 	"github.com/submariner-io/admiral/pkg/watcher"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
-	"github.com/submariner-io/submariner/pkg/cidr"
+	"github.com/submariner-io/submariner/pkg/peering"
 	"github.com/submariner-io/submariner/pkg/types"
 	k8sv1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/set"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 type DatastoreSyncer struct {
-	localCluster    types.SubmarinerCluster
-	localEndpoint   types.SubmarinerEndpoint
-	localNodeName   string
-	syncerConfig    broker.SyncerConfig
-	updateFederator federate.Federator
+	localCluster       types.SubmarinerCluster
+	localEndpoint      types.SubmarinerEndpoint
+	localNodeName      string
+	syncerConfig       broker.SyncerConfig
+	syncer             *broker.Syncer
+	updateFederator    federate.Federator
+	peeringPolicy      *peering.Policy
+	transitEnabled     bool
+	transitClusters    set.Set[string]
+	staleEndpointTTL   time.Duration
+	statusReporter     *StatusReporter
+	statusMutex        sync.Mutex
+	remoteCIDROverlaps map[string]remoteCIDROverlap
+	excludedEndpoints  map[string]submarinerv1.EndpointSpec
+	pendingEndpoints   map[string]bool
+	remoteEndpoints    map[string]*submarinerv1.Endpoint
+	transitRoutes      map[string][]submarinerv1.TransitRoute
+	reportedTransit    []submarinerv1.TransitRoute
+	localEndpointMutex sync.Mutex
+	inMaintenance      bool
+}
+
+// StatusReporter reports the remote Endpoints that aren't synced to the local datastore.
+type StatusReporter struct {
+	// Recorder, if set, records an Event against the local Cluster for each Endpoint rejected due to overlapping subnets.
+	Recorder record.EventRecorder
+	// SetCondition, if set, is called with the updated GatewayRemoteCIDROverlap condition when the rejected Endpoints change.
+	SetCondition func(ctx context.Context, condition *metav1.Condition)
+	// SetExcludedEndpoints, if set, is called with the Endpoints excluded by the peering policy when they change.
+	SetExcludedEndpoints func(ctx context.Context, endpoints []submarinerv1.EndpointSpec)
+	// SetTransitRoutes, if set, is called with the remote clusters reached through transit gateways when they change.
+	SetTransitRoutes func(ctx context.Context, routes []submarinerv1.TransitRoute)
 }
 
 var logger = log.Logger{Logger: logf.Log.WithName("DSSyncer")}
 
+var (
+	endpointsGVR = submarinerv1.SchemeGroupVersion.WithResource("endpoints")
+	clustersGVR  = submarinerv1.SchemeGroupVersion.WithResource("clusters")
+)
+
 func New(syncerConfig *broker.SyncerConfig, localCluster *types.SubmarinerCluster,
-	localEndpoint *types.SubmarinerEndpoint,
+	localEndpoint *types.SubmarinerEndpoint, peeringPolicy *peering.Policy, transitEnabled bool, transitClusters []string,
+	staleEndpointTTL time.Duration, statusReporter *StatusReporter,
 ) *DatastoreSyncer {
 	// We'll panic if syncerConfig, localCluster or localEndpoint are nil, this is intentional
 	syncerConfig.LocalClusterID = localCluster.Spec.ClusterID
 
 	return &DatastoreSyncer{
-		localCluster:  *localCluster,
-		localEndpoint: *localEndpoint,
-		syncerConfig:  *syncerConfig,
+		localCluster:       *localCluster,
+		localEndpoint:      *localEndpoint,
+		syncerConfig:       *syncerConfig,
+		peeringPolicy:      peeringPolicy,
+		transitEnabled:     transitEnabled,
+		transitClusters:    set.New(transitClusters...),
+		staleEndpointTTL:   staleEndpointTTL,
+		statusReporter:     statusReporter,
+		remoteCIDROverlaps: map[string]remoteCIDROverlap{},
+		excludedEndpoints:  map[string]submarinerv1.EndpointSpec{},
+		pendingEndpoints:   map[string]bool{},
+		remoteEndpoints:    map[string]*submarinerv1.Endpoint{},
+		transitRoutes:      map[string][]submarinerv1.TransitRoute{},
 	}
 }
 
@@ -81,6 +128,14 @@ func (d *DatastoreSyncer) Start(ctx context.Context) error {
 		return err
 	}
 
+	d.syncer = syncer
+
+	// The maintenance mode must be known before the syncer starts so the local Endpoint isn't synced to the broker meanwhile.
+	localCluster, err := d.newLocalCluster(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "error retrieving the local submariner Cluster")
+	}
+
 	err = syncer.Start(ctx.Done())
 	if err != nil {
 		return errors.WithMessage(err, "error starting the syncer")
@@ -90,14 +145,31 @@ func (d *DatastoreSyncer) Start(ctx context.Context) error {
 		return errors.WithMessage(err, "could not ensure exclusive submariner Endpoint")
 	}
 
-	if err := d.createLocalCluster(ctx, syncer.GetLocalFederator()); err != nil {
-		return errors.WithMessage(err, "error creating the local submariner Cluster")
+	logger.Infof("Creating local submariner Cluster: %#v ", d.localCluster)
+
+	if err := syncer.GetLocalFederator().Distribute(ctx, localCluster); err != nil {
+		return errors.Wrap(err, "error creating the local submariner Cluster")
+	}
+
+	if d.isInMaintenance() {
+		// The local Endpoint may have been published before the restart so withdraw it regardless.
+		if err := d.publishLocalEndpoint(ctx, false); err != nil {
+			return errors.WithMessage(err, "error withdrawing the local submariner Endpoint")
+		}
+
+		d.reportMaintenance(ctx, true)
 	}
 
-	if err := d.createOrUpdateLocalEndpoint(ctx, syncer.GetLocalFederator()); err != nil {
+	d.localEndpointMutex.Lock()
+	err = d.createOrUpdateLocalEndpoint(ctx, syncer.GetLocalFederator())
+	d.localEndpointMutex.Unlock()
+
+	if err != nil {
 		return errors.WithMessage(err, "error creating the local submariner Endpoint")
 	}
 
+	d.startHeartbeat(ctx)
+
 	if len(d.localCluster.Spec.GlobalCIDR) > 0 {
 		if err := d.startNodeWatcher(ctx.Done()); err != nil {
 			return errors.WithMessage(err, "startNodeWatcher returned error")
@@ -123,20 +195,12 @@ func (d *DatastoreSyncer) Cleanup(ctx context.Context) error {
 		}
 	}
 
-	err = d.cleanupResources(ctx, localClient.Resource(schema.GroupVersionResource{
-		Group:    submarinerv1.SchemeGroupVersion.Group,
-		Version:  submarinerv1.SchemeGroupVersion.Version,
-		Resource: "endpoints",
-	}), syncer)
+	err = d.cleanupResources(ctx, localClient.Resource(endpointsGVR), syncer)
 	if err != nil {
 		return err
 	}
 
-	err = d.cleanupResources(ctx, localClient.Resource(schema.GroupVersionResource{
-		Group:    submarinerv1.SchemeGroupVersion.Group,
-		Version:  submarinerv1.SchemeGroupVersion.Version,
-		Resource: "clusters",
-	}), syncer)
+	err = d.cleanupResources(ctx, localClient.Resource(clustersGVR), syncer)
 	if err != nil {
 		return err
 	}
@@ -179,17 +243,27 @@ func (d *DatastoreSyncer) cleanupResources(ctx context.Context, client dynamic.N
 }
 
 func (d *DatastoreSyncer) createSyncer() (*broker.Syncer, error) {
+	var endpointResyncPeriod time.Duration
+	if d.peeringPolicy.HasClusterSelector() {
+		// The Cluster labels may change after the remote Endpoints were processed so periodically re-evaluate them.
+		endpointResyncPeriod = peeringResyncPeriod
+	}
+
 	d.syncerConfig.ResourceConfigs = []broker.ResourceConfig{
 		{
-			LocalSourceNamespace: d.syncerConfig.LocalNamespace,
-			LocalResourceType:    &submarinerv1.Cluster{},
-			BrokerResourceType:   &submarinerv1.Cluster{},
+			LocalSourceNamespace:   d.syncerConfig.LocalNamespace,
+			LocalResourceType:      &submarinerv1.Cluster{},
+			TransformLocalToBroker: d.onLocalClusterSync,
+			BrokerResourceType:     &submarinerv1.Cluster{},
 		},
 		{
-			LocalSourceNamespace:   d.syncerConfig.LocalNamespace,
-			LocalResourceType:      &submarinerv1.Endpoint{},
-			TransformBrokerToLocal: d.shouldSyncRemoteEndpoint,
-			BrokerResourceType:     &submarinerv1.Endpoint{},
+			LocalSourceNamespace:      d.syncerConfig.LocalNamespace,
+			LocalResourceType:         &submarinerv1.Endpoint{},
+			TransformLocalToBroker:    d.shouldSyncLocalEndpoint,
+			TransformBrokerToLocal:    d.shouldSyncRemoteEndpoint,
+			BrokerResourceType:        &submarinerv1.Endpoint{},
+			BrokerResourcesEquivalent: d.areRemoteEndpointsEquivalent,
+			BrokerResyncPeriod:        endpointResyncPeriod,
 		},
 	}
 
@@ -198,25 +272,78 @@ func (d *DatastoreSyncer) createSyncer() (*broker.Syncer, error) {
 	return syncer, errors.Wrap(err, "error creating the syncer")
 }
 
-func (d *DatastoreSyncer) shouldSyncRemoteEndpoint(obj runtime.Object, _ int,
-	_ resourceSyncer.Operation,
+func (d *DatastoreSyncer) shouldSyncRemoteEndpoint(obj runtime.Object, numRequeues int,
+	op resourceSyncer.Operation,
 ) (runtime.Object, bool) {
 	endpoint := obj.(*submarinerv1.Endpoint)
 
-	for _, localSubnet := range d.localEndpoint.Spec.Subnets {
-		overlap, err := cidr.IsOverlapping(endpoint.Spec.Subnets, localSubnet)
-		if err != nil {
-			logger.Errorf(err, "Unable to validate if remote CIDR overlaps with local CIDR")
-			return nil, false
+	conflicts, err := d.overlappingSubnets(endpoint)
+	if err != nil {
+		logger.Errorf(err, "Unable to validate if remote CIDR overlaps with local CIDR")
+		return nil, false
+	}
+
+	if op == resourceSyncer.Delete {
+		d.setRemoteCIDROverlap(endpoint, nil)
+		d.setExcludedEndpoint(endpoint, false)
+		d.setPendingEndpoint(endpoint, false)
+
+		if err := d.setRemoteEndpoint(endpoint, false); err != nil {
+			return nil, true
 		}
+	} else {
+		d.setRemoteCIDROverlap(endpoint, conflicts)
+	}
+
+	if len(conflicts) > 0 {
+		logger.Errorf(nil, "Skip processing the remote endpoint %#v as subnets are overlapping: %s", endpoint,
+			strings.Join(conflicts, ", "))
+		return nil, d.setRemoteEndpoint(endpoint, false) != nil
+	}
+
+	if op == resourceSyncer.Delete {
+		return obj, false
+	}
 
-		if overlap {
-			logger.Errorf(nil, "Skip processing the remote endpoint %#v as subnets are overlapping", endpoint)
-			return nil, false
+	allowed, found := d.isPeeringAllowed(endpoint.Spec.ClusterID)
+	d.setPendingEndpoint(endpoint, !found)
+
+	if !found {
+		if numRequeues < maxClusterWaitRequeues {
+			logger.V(log.DEBUG).Infof("The Cluster for remote endpoint %q isn't synced yet - requeueing", endpoint.Name)
+			return nil, true
 		}
+
+		// The periodic resync re-evaluates pending Endpoints so give up here rather than requeueing forever.
+		logger.Warningf("The Cluster for remote endpoint %q still isn't synced - it will be re-evaluated on the next resync",
+			endpoint.Name)
+
+		return nil, false
+	}
+
+	d.setExcludedEndpoint(endpoint, !allowed)
+
+	viaTransit := d.transitClusters.Has(endpoint.Spec.ClusterID)
+
+	if err := d.setRemoteEndpoint(endpoint, allowed && !viaTransit); err != nil {
+		return nil, true
+	}
+
+	if !allowed {
+		logger.Infof("Skip processing the remote endpoint %q as cluster %q is excluded by the peering policy", endpoint.Name,
+			endpoint.Spec.ClusterID)
+
+		return nil, d.deleteExcludedEndpoint(endpoint) != nil
+	}
+
+	if viaTransit {
+		logger.Infof("Skip processing the remote endpoint %q as cluster %q is reached through transit gateways", endpoint.Name,
+			endpoint.Spec.ClusterID)
+
+		return nil, d.deleteExcludedEndpoint(endpoint) != nil
 	}
 
-	return obj, false
+	return d.withTransitSubnets(endpoint), false
 }
 
 func (d *DatastoreSyncer) ensureExclusiveEndpoint(ctx context.Context, syncer *broker.Syncer) error {
@@ -294,9 +421,9 @@ func (d *DatastoreSyncer) createNodeWatcher(stopCh <-chan struct{}) error {
 	return nil
 }
 
-func (d *DatastoreSyncer) createLocalCluster(ctx context.Context, federator federate.Federator) error {
-	logger.Infof("Creating local submariner Cluster: %#v ", d.localCluster)
-
+// newLocalCluster returns the local Cluster to create, preserving the maintenance mode of the existing one across restarts,
+// and records whether it's in maintenance mode.
+func (d *DatastoreSyncer) newLocalCluster(ctx context.Context) (*submarinerv1.Cluster, error) {
 	cluster := &submarinerv1.Cluster{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: resource.EnsureValidName(d.localCluster.Spec.ClusterID),
@@ -304,7 +431,23 @@ func (d *DatastoreSyncer) createLocalCluster(ctx context.Context, federator fede
 		Spec: d.localCluster.Spec,
 	}
 
-	return federator.Distribute(ctx, cluster) //nolint:wrapcheck  // Let the caller wrap it
+	existing, err := d.syncerConfig.LocalClient.Resource(clustersGVR).Namespace(d.syncerConfig.LocalNamespace).Get(ctx,
+		cluster.Name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, errors.Wrapf(err, "error retrieving the local submariner Cluster %q", cluster.Name)
+	}
+
+	if err == nil {
+		if value, ok := existing.GetAnnotations()[submarinerv1.MaintenanceAnnotation]; ok {
+			cluster.Annotations = map[string]string{submarinerv1.MaintenanceAnnotation: value}
+		}
+	}
+
+	d.localEndpointMutex.Lock()
+	d.inMaintenance = cluster.InMaintenance()
+	d.localEndpointMutex.Unlock()
+
+	return cluster, nil
 }
 
 func (d *DatastoreSyncer) createOrUpdateLocalEndpoint(ctx context.Context, federator federate.Federator) error {
@@ -322,5 +465,26 @@ func (d *DatastoreSyncer) createOrUpdateLocalEndpoint(ctx context.Context, feder
 		Spec: d.localEndpoint.Spec,
 	}
 
+	setHeartbeat(endpoint, time.Now())
+
 	return federator.Distribute(ctx, endpoint) //nolint:wrapcheck  // Let the caller wrap it
 }
+
+func (d *DatastoreSyncer) recordLocalClusterEvent(eventType, reason, messageFmt string, args ...interface{}) {
+	if d.statusReporter == nil || d.statusReporter.Recorder == nil {
+		return
+	}
+
+	cluster := &submarinerv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Cluster",
+			APIVersion: submarinerv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resource.EnsureValidName(d.localCluster.Spec.ClusterID),
+			Namespace: d.syncerConfig.LocalNamespace,
+		},
+	}
+
+	d.statusReporter.Recorder.Eventf(cluster, eventType, reason, messageFmt, args...)
+}
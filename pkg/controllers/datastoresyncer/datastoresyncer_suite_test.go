@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -36,6 +37,7 @@ import (
 	"github.com/submariner-io/admiral/pkg/syncer/test"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/controllers/datastoresyncer"
+	"github.com/submariner-io/submariner/pkg/peering"
 	"github.com/submariner-io/submariner/pkg/types"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -45,6 +47,7 @@ import (
 	"k8s.io/client-go/dynamic"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 )
 
 const (
@@ -90,6 +93,15 @@ type testDriver struct {
 	startCompleted   chan error
 	expectedStartErr error
 	doStart          bool
+	recorder         *record.FakeRecorder
+	conditionMutex   sync.Mutex
+	conditions       map[string]*metav1.Condition
+	peeringPolicy    *peering.Policy
+	transitEnabled   bool
+	transitClusters  []string
+	staleEndpointTTL time.Duration
+	excluded         []submarinerv1.EndpointSpec
+	transitRoutes    []submarinerv1.TransitRoute
 }
 
 func newTestDriver() *testDriver {
@@ -118,6 +130,14 @@ func newTestDriver() *testDriver {
 	BeforeEach(func() {
 		t.expectedStartErr = nil
 		t.doStart = true
+		t.recorder = record.NewFakeRecorder(10)
+		t.conditions = map[string]*metav1.Condition{}
+		t.peeringPolicy = nil
+		t.transitEnabled = false
+		t.transitClusters = nil
+		t.staleEndpointTTL = 0
+		t.excluded = nil
+		t.transitRoutes = nil
 
 		t.syncerScheme = runtime.NewScheme()
 		Expect(submarinerv1.AddToScheme(t.syncerScheme)).To(Succeed())
@@ -164,7 +184,28 @@ func (t *testDriver) run() {
 		BrokerNamespace: brokerNamespace,
 		RestMapper:      t.restMapper,
 		Scheme:          t.syncerScheme,
-	}, t.localCluster, t.localEndpoint)
+	}, t.localCluster, t.localEndpoint, t.peeringPolicy, t.transitEnabled, t.transitClusters, t.staleEndpointTTL,
+		&datastoresyncer.StatusReporter{
+			Recorder: t.recorder,
+			SetCondition: func(_ context.Context, condition *metav1.Condition) {
+				t.conditionMutex.Lock()
+				defer t.conditionMutex.Unlock()
+
+				t.conditions[condition.Type] = condition
+			},
+			SetExcludedEndpoints: func(_ context.Context, endpoints []submarinerv1.EndpointSpec) {
+				t.conditionMutex.Lock()
+				defer t.conditionMutex.Unlock()
+
+				t.excluded = endpoints
+			},
+			SetTransitRoutes: func(_ context.Context, routes []submarinerv1.TransitRoute) {
+				t.conditionMutex.Lock()
+				defer t.conditionMutex.Unlock()
+
+				t.transitRoutes = routes
+			},
+		})
 
 	if t.doStart {
 		var ctx context.Context
@@ -202,6 +243,35 @@ func (t *testDriver) stop() {
 	}
 }
 
+func (t *testDriver) getOverlapCondition() *metav1.Condition {
+	return t.getCondition(submarinerv1.GatewayRemoteCIDROverlap)
+}
+
+func (t *testDriver) getMaintenanceCondition() *metav1.Condition {
+	return t.getCondition(submarinerv1.GatewayMaintenance)
+}
+
+func (t *testDriver) getCondition(conditionType string) *metav1.Condition {
+	t.conditionMutex.Lock()
+	defer t.conditionMutex.Unlock()
+
+	return t.conditions[conditionType]
+}
+
+func (t *testDriver) getExcludedEndpoints() []submarinerv1.EndpointSpec {
+	t.conditionMutex.Lock()
+	defer t.conditionMutex.Unlock()
+
+	return t.excluded
+}
+
+func (t *testDriver) getTransitRoutes() []submarinerv1.TransitRoute {
+	t.conditionMutex.Lock()
+	defer t.conditionMutex.Unlock()
+
+	return t.transitRoutes
+}
+
 func newEndpoint(spec *submarinerv1.EndpointSpec) *submarinerv1.Endpoint {
 	return &submarinerv1.Endpoint{
 		ObjectMeta: metav1.ObjectMeta{
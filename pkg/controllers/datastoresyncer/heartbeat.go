@@ -0,0 +1,232 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastoresyncer
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/resource"
+	resourceSyncer "github.com/submariner-io/admiral/pkg/syncer"
+	"github.com/submariner-io/admiral/pkg/util"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+)
+
+const staleEndpointRemovedReason = "StaleEndpointRemoved"
+
+var (
+	heartbeatInterval   = time.Minute
+	minStaleEndpointTTL = 3 * heartbeatInterval
+)
+
+// startHeartbeat periodically renews the heartbeat of the local Endpoint on the broker and, if a stale Endpoint TTL is
+// configured, removes the remote Endpoints whose heartbeat expired from the broker.
+func (d *DatastoreSyncer) startHeartbeat(ctx context.Context) {
+	go func() {
+		// The local Endpoint was just published with a fresh heartbeat so the first renewal is due after an interval.
+		_ = wait.PollUntilContextCancel(ctx, heartbeatInterval, false, func(ctx context.Context) (bool, error) {
+			d.renewHeartbeat(ctx)
+			return false, nil
+		})
+	}()
+
+	if d.staleEndpointTTL == 0 {
+		return
+	}
+
+	if d.staleEndpointTTL < minStaleEndpointTTL {
+		logger.Warningf("The configured stale Endpoint TTL %v is too short - using %v", d.staleEndpointTTL, minStaleEndpointTTL)
+		d.staleEndpointTTL = minStaleEndpointTTL
+	}
+
+	logger.Infof("Removing remote Endpoints whose heartbeat is older than %v from the broker", d.staleEndpointTTL)
+
+	go wait.UntilWithContext(ctx, d.removeStaleEndpoints, heartbeatInterval)
+}
+
+// renewHeartbeat updates the heartbeat of the local Endpoint directly on the broker. The local copy isn't updated so the
+// local watchers aren't notified on each renewal. The local Endpoint is withdrawn from the broker while in maintenance mode
+// so it isn't renewed. If the local Endpoint no longer exists on the broker, eg it was removed as
+// stale by a remote cluster while the broker was unreachable, the local Cluster and Endpoint are published again.
+func (d *DatastoreSyncer) renewHeartbeat(ctx context.Context) {
+	if d.isInMaintenance() {
+		return
+	}
+
+	d.localEndpointMutex.Lock()
+	endpointName, err := d.localEndpoint.Spec.GenerateName()
+	d.localEndpointMutex.Unlock()
+
+	if err != nil {
+		logger.Errorf(err, "Error extracting the submariner Endpoint name from %#v", d.localEndpoint)
+		return
+	}
+
+	endpoint := &unstructured.Unstructured{}
+	endpoint.SetName(endpointName)
+
+	err = util.MustUpdate[*unstructured.Unstructured](ctx, resource.ForDynamic(d.brokerResource(endpointsGVR)), endpoint,
+		func(existing *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+			setHeartbeat(existing, time.Now())
+			return existing, nil
+		})
+	if apierrors.IsNotFound(err) {
+		logger.Warningf("The local Endpoint %q no longer exists on the broker - publishing it again", endpointName)
+
+		err = d.republishLocalResources(ctx)
+	}
+
+	if err != nil {
+		logger.Errorf(err, "Error renewing the heartbeat of Endpoint %q on the broker", endpointName)
+	}
+}
+
+// republishLocalResources distributes the local Cluster and Endpoint to the broker.
+func (d *DatastoreSyncer) republishLocalResources(ctx context.Context) error {
+	clusterName := resource.EnsureValidName(d.localCluster.Spec.ClusterID)
+
+	cluster, found, err := d.syncer.GetLocalResource(clusterName, d.syncerConfig.LocalNamespace, &submarinerv1.Cluster{})
+	if err != nil {
+		return errors.Wrapf(err, "error retrieving the local Cluster %q", clusterName)
+	}
+
+	if found {
+		err = d.syncer.GetBrokerFederator().Distribute(ctx, cluster)
+		if err != nil {
+			return errors.Wrapf(err, "error distributing the local Cluster %q to the broker", clusterName)
+		}
+	}
+
+	return d.publishLocalEndpoint(ctx, true)
+}
+
+// removeStaleEndpoints deletes the remote Endpoints whose heartbeat is older than the stale Endpoint TTL from the broker,
+// along with the Cluster of each remote cluster left without an Endpoint. Endpoints without a heartbeat, published by
+// gateways that don't renew it, are never considered stale.
+func (d *DatastoreSyncer) removeStaleEndpoints(ctx context.Context) {
+	endpoints, err := d.brokerResource(endpointsGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		logger.Errorf(err, "Error listing the Endpoints on the broker")
+		return
+	}
+
+	now := time.Now()
+	staleClusters := sets.New[string]()
+	liveClusters := sets.New[string]()
+
+	for i := range endpoints.Items {
+		endpoint := &endpoints.Items[i]
+
+		clusterID, _, _ := unstructured.NestedString(endpoint.Object, "spec", "cluster_id")
+		if clusterID == d.localCluster.Spec.ClusterID {
+			continue
+		}
+
+		heartbeat, found := getHeartbeat(endpoint)
+		age := now.Sub(heartbeat)
+
+		if !found || age <= d.staleEndpointTTL {
+			liveClusters.Insert(clusterID)
+			continue
+		}
+
+		err = d.brokerResource(endpointsGVR).Delete(ctx, endpoint.GetName(), metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			logger.Errorf(err, "Error deleting stale Endpoint %q from the broker", endpoint.GetName())
+			liveClusters.Insert(clusterID)
+
+			continue
+		}
+
+		logger.Infof("Deleted Endpoint %q of cluster %q from the broker as its last heartbeat was %v ago", endpoint.GetName(),
+			clusterID, age.Round(time.Second))
+
+		staleClusters.Insert(clusterID)
+		d.recordLocalClusterEvent(corev1.EventTypeNormal, staleEndpointRemovedReason,
+			"Endpoint %q of remote cluster %q was removed from the broker as its last heartbeat was %v ago",
+			endpoint.GetName(), clusterID, age.Round(time.Second))
+	}
+
+	for _, clusterID := range sets.List(staleClusters.Difference(liveClusters)) {
+		err = d.brokerResource(clustersGVR).Delete(ctx, resource.EnsureValidName(clusterID), metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			logger.Errorf(err, "Error deleting the Cluster of stale cluster %q from the broker", clusterID)
+			continue
+		}
+
+		logger.Infof("Deleted the Cluster of stale cluster %q from the broker", clusterID)
+	}
+}
+
+func (d *DatastoreSyncer) brokerResource(gvr schema.GroupVersionResource) dynamic.ResourceInterface {
+	return d.syncer.GetBrokerClient().Resource(gvr).Namespace(d.syncer.GetBrokerNamespace())
+}
+
+func setHeartbeat(obj metav1.Object, t time.Time) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[submarinerv1.EndpointHeartbeatAnnotation] = t.UTC().Format(time.RFC3339)
+	obj.SetAnnotations(annotations)
+}
+
+func getHeartbeat(obj metav1.Object) (time.Time, bool) {
+	value, found := obj.GetAnnotations()[submarinerv1.EndpointHeartbeatAnnotation]
+	if !found {
+		return time.Time{}, false
+	}
+
+	heartbeat, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		logger.Warningf("Ignoring the invalid heartbeat %q of Endpoint %q", value, obj.GetName())
+		return time.Time{}, false
+	}
+
+	return heartbeat, true
+}
+
+// areEndpointsEquivalent ignores the heartbeat so renewing it on the broker doesn't re-sync the remote Endpoints.
+func areEndpointsEquivalent(obj1, obj2 *unstructured.Unstructured) bool {
+	return resourceSyncer.DefaultResourcesEquivalent(withoutHeartbeat(obj1), withoutHeartbeat(obj2))
+}
+
+func withoutHeartbeat(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	annotations := obj.GetAnnotations()
+	if _, found := annotations[submarinerv1.EndpointHeartbeatAnnotation]; !found {
+		return obj
+	}
+
+	obj = obj.DeepCopy()
+
+	delete(annotations, submarinerv1.EndpointHeartbeatAnnotation)
+	obj.SetAnnotations(annotations)
+
+	return obj
+}
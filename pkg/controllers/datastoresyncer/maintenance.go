@@ -0,0 +1,155 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastoresyncer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/admiral/pkg/resource"
+	resourceSyncer "github.com/submariner-io/admiral/pkg/syncer"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	maintenanceEnabledReason  = "MaintenanceEnabled"
+	maintenanceDisabledReason = "MaintenanceDisabled"
+)
+
+// onLocalClusterSync withdraws the local Endpoint from the broker when the local Cluster enters maintenance mode and
+// publishes it again when it leaves it.
+func (d *DatastoreSyncer) onLocalClusterSync(obj runtime.Object, numRequeues int, op resourceSyncer.Operation,
+) (runtime.Object, bool) {
+	cluster := obj.(*submarinerv1.Cluster)
+
+	if op == resourceSyncer.Delete || cluster.Spec.ClusterID != d.localCluster.Spec.ClusterID {
+		return obj, false
+	}
+
+	inMaintenance := cluster.InMaintenance()
+
+	d.localEndpointMutex.Lock()
+	changed := d.inMaintenance != inMaintenance
+	d.inMaintenance = inMaintenance
+	d.localEndpointMutex.Unlock()
+
+	if !changed && numRequeues == 0 {
+		return obj, false
+	}
+
+	if inMaintenance {
+		logger.Infof("The local cluster %q entered maintenance mode - withdrawing the local Endpoint from the broker",
+			cluster.Spec.ClusterID)
+	} else {
+		logger.Infof("The local cluster %q left maintenance mode - publishing the local Endpoint to the broker",
+			cluster.Spec.ClusterID)
+	}
+
+	if err := d.publishLocalEndpoint(context.TODO(), !inMaintenance); err != nil {
+		logger.Errorf(err, "Error updating the local Endpoint on the broker")
+		return nil, true
+	}
+
+	d.reportMaintenance(context.TODO(), inMaintenance)
+
+	return obj, false
+}
+
+// shouldSyncLocalEndpoint prevents the local Endpoint from being synced to the broker while in maintenance mode.
+func (d *DatastoreSyncer) shouldSyncLocalEndpoint(obj runtime.Object, _ int, op resourceSyncer.Operation) (runtime.Object, bool) {
+	if op != resourceSyncer.Delete && d.isInMaintenance() {
+		logger.V(log.DEBUG).Infof("Not syncing the local Endpoint %q to the broker while in maintenance mode",
+			resource.MustToMeta(obj).GetName())
+		return nil, false
+	}
+
+	return obj, false
+}
+
+func (d *DatastoreSyncer) isInMaintenance() bool {
+	d.localEndpointMutex.Lock()
+	defer d.localEndpointMutex.Unlock()
+
+	return d.inMaintenance
+}
+
+// publishLocalEndpoint distributes the local Endpoint to, or deletes it from, the broker.
+func (d *DatastoreSyncer) publishLocalEndpoint(ctx context.Context, publish bool) error {
+	d.localEndpointMutex.Lock()
+	endpointName, err := d.localEndpoint.Spec.GenerateName()
+	d.localEndpointMutex.Unlock()
+
+	if err != nil {
+		return errors.Wrapf(err, "error extracting the submariner Endpoint name from %#v", d.localEndpoint)
+	}
+
+	if !publish {
+		err = d.brokerResource(endpointsGVR).Delete(ctx, endpointName, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "error deleting the local Endpoint %q from the broker", endpointName)
+		}
+
+		return nil
+	}
+
+	obj, found, err := d.syncer.GetLocalResource(endpointName, d.syncerConfig.LocalNamespace, &submarinerv1.Endpoint{})
+	if err != nil {
+		return errors.Wrapf(err, "error retrieving the local Endpoint %q", endpointName)
+	}
+
+	if !found {
+		// The local Endpoint will be synced, if allowed, once it's created.
+		return nil
+	}
+
+	endpoint := obj.(*submarinerv1.Endpoint)
+	setHeartbeat(endpoint, time.Now())
+
+	return errors.Wrapf(d.syncer.GetBrokerFederator().Distribute(ctx, endpoint), "error distributing the local Endpoint %q to the broker",
+		endpointName)
+}
+
+func (d *DatastoreSyncer) reportMaintenance(ctx context.Context, inMaintenance bool) {
+	if d.statusReporter == nil || d.statusReporter.SetCondition == nil {
+		return
+	}
+
+	condition := &metav1.Condition{
+		Type:    submarinerv1.GatewayMaintenance,
+		Status:  metav1.ConditionFalse,
+		Reason:  maintenanceDisabledReason,
+		Message: "The local cluster isn't in maintenance mode",
+	}
+
+	if inMaintenance {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = maintenanceEnabledReason
+		condition.Message = fmt.Sprintf("The local cluster is in maintenance mode: its Endpoint is withdrawn from the broker and "+
+			"no cables are installed. Remove the %q annotation from the local Cluster to restore connectivity",
+			submarinerv1.MaintenanceAnnotation)
+	}
+
+	d.statusReporter.SetCondition(ctx, condition)
+}
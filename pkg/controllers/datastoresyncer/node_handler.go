@@ -70,6 +70,9 @@ func (d *DatastoreSyncer) areNodesEquivalent(obj1, obj2 *unstructured.Unstructur
 }
 
 func (d *DatastoreSyncer) updateLocalEndpointIfNecessary(globalIPOfNode string) bool {
+	d.localEndpointMutex.Lock()
+	defer d.localEndpointMutex.Unlock()
+
 	if d.localEndpoint.Spec.HealthCheckIP != globalIPOfNode {
 		logger.Infof("Updating the endpoint HealthCheckIP to globalIP %q", globalIPOfNode)
 
@@ -0,0 +1,174 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastoresyncer
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/cidr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	remoteCIDROverlapReason = "RemoteCIDROverlap"
+	noOverlapReason         = "NoOverlap"
+)
+
+var remoteCIDROverlapsGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "submariner_remote_cidr_overlaps",
+		Help: "Number of remote subnets overlapping with the local subnets (by remote cluster)",
+	},
+	[]string{
+		"remote_cluster",
+	},
+)
+
+func init() {
+	prometheus.MustRegister(remoteCIDROverlapsGauge)
+}
+
+type remoteCIDROverlap struct {
+	clusterID string
+	conflicts []string
+}
+
+// overlappingSubnets returns a description of each remote subnet of the Endpoint that overlaps with a local subnet.
+func (d *DatastoreSyncer) overlappingSubnets(endpoint *submarinerv1.Endpoint) ([]string, error) {
+	var conflicts []string
+
+	for _, remoteSubnet := range endpoint.Spec.Subnets {
+		for _, localSubnet := range d.localEndpoint.Spec.Subnets {
+			overlap, err := cidr.IsOverlapping([]string{remoteSubnet}, localSubnet)
+			if err != nil {
+				return nil, err //nolint:wrapcheck  // Let the caller wrap it
+			}
+
+			if overlap {
+				conflicts = append(conflicts, fmt.Sprintf("%s overlaps %s", remoteSubnet, localSubnet))
+			}
+		}
+	}
+
+	return conflicts, nil
+}
+
+// setRemoteCIDROverlap records the subnet conflicts of the given remote Endpoint and, if they changed, reports them.
+func (d *DatastoreSyncer) setRemoteCIDROverlap(endpoint *submarinerv1.Endpoint, conflicts []string) {
+	d.statusMutex.Lock()
+	defer d.statusMutex.Unlock()
+
+	existing, found := d.remoteCIDROverlaps[endpoint.Name]
+	if (!found && len(conflicts) == 0) || (found && reflect.DeepEqual(existing.conflicts, conflicts)) {
+		return
+	}
+
+	if len(conflicts) == 0 {
+		delete(d.remoteCIDROverlaps, endpoint.Name)
+	} else {
+		d.remoteCIDROverlaps[endpoint.Name] = remoteCIDROverlap{clusterID: endpoint.Spec.ClusterID, conflicts: conflicts}
+		d.recordRemoteCIDROverlapEvent(endpoint, conflicts)
+	}
+
+	d.updateRemoteCIDROverlapMetric(endpoint.Spec.ClusterID)
+
+	if d.statusReporter != nil && d.statusReporter.SetCondition != nil {
+		d.statusReporter.SetCondition(context.TODO(), d.remoteCIDROverlapCondition())
+	}
+}
+
+func (d *DatastoreSyncer) recordRemoteCIDROverlapEvent(endpoint *submarinerv1.Endpoint, conflicts []string) {
+	d.recordLocalClusterEvent(corev1.EventTypeWarning, remoteCIDROverlapReason,
+		"Endpoint %q from remote cluster %q was rejected because its subnets overlap with the local subnets: %s. %s",
+		endpoint.Name, endpoint.Spec.ClusterID, strings.Join(conflicts, ", "), d.remoteCIDROverlapRemedy())
+}
+
+func (d *DatastoreSyncer) updateRemoteCIDROverlapMetric(clusterID string) {
+	conflicts := sets.New[string]()
+
+	for _, overlap := range d.remoteCIDROverlaps {
+		if overlap.clusterID == clusterID {
+			conflicts.Insert(overlap.conflicts...)
+		}
+	}
+
+	if conflicts.Len() == 0 {
+		remoteCIDROverlapsGauge.DeleteLabelValues(clusterID)
+		return
+	}
+
+	remoteCIDROverlapsGauge.WithLabelValues(clusterID).Set(float64(conflicts.Len()))
+}
+
+func (d *DatastoreSyncer) remoteCIDROverlapCondition() *metav1.Condition {
+	if len(d.remoteCIDROverlaps) == 0 {
+		return &metav1.Condition{
+			Type:    submarinerv1.GatewayRemoteCIDROverlap,
+			Status:  metav1.ConditionFalse,
+			Reason:  noOverlapReason,
+			Message: "No remote Endpoints were rejected due to overlapping subnets",
+		}
+	}
+
+	byCluster := map[string]sets.Set[string]{}
+
+	for _, overlap := range d.remoteCIDROverlaps {
+		if byCluster[overlap.clusterID] == nil {
+			byCluster[overlap.clusterID] = sets.New[string]()
+		}
+
+		byCluster[overlap.clusterID].Insert(overlap.conflicts...)
+	}
+
+	clusterIDs := make([]string, 0, len(byCluster))
+	for clusterID := range byCluster {
+		clusterIDs = append(clusterIDs, clusterID)
+	}
+
+	sort.Strings(clusterIDs)
+
+	details := make([]string, len(clusterIDs))
+	for i, clusterID := range clusterIDs {
+		details[i] = fmt.Sprintf("cluster %q (%s)", clusterID, strings.Join(sets.List(byCluster[clusterID]), ", "))
+	}
+
+	return &metav1.Condition{
+		Type:   submarinerv1.GatewayRemoteCIDROverlap,
+		Status: metav1.ConditionTrue,
+		Reason: remoteCIDROverlapReason,
+		Message: fmt.Sprintf("Endpoints from remote clusters were rejected because their subnets overlap with the local subnets: %s. %s",
+			strings.Join(details, "; "), d.remoteCIDROverlapRemedy()),
+	}
+}
+
+func (d *DatastoreSyncer) remoteCIDROverlapRemedy() string {
+	if len(d.localCluster.Spec.GlobalCIDR) == 0 {
+		return "Enable Globalnet to connect clusters with overlapping CIDRs."
+	}
+
+	return "Assign non-overlapping Globalnet CIDRs to the clusters."
+}
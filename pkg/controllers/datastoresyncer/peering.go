@@ -0,0 +1,151 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastoresyncer
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/submariner-io/admiral/pkg/resource"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var peeringResyncPeriod = 2 * time.Minute
+
+// maxClusterWaitRequeues bounds how many times a remote Endpoint is requeued while waiting for its Cluster to be synced.
+const maxClusterWaitRequeues = 10
+
+// isPeeringAllowed returns whether the peering policy allows connecting to the given remote cluster. If the policy selects
+// clusters by their labels and the remote Cluster hasn't been synced yet, found is false.
+func (d *DatastoreSyncer) isPeeringAllowed(clusterID string) (allowed, found bool) {
+	if !d.peeringPolicy.HasClusterSelector() {
+		return d.peeringPolicy.AllowsClusterID(clusterID), true
+	}
+
+	obj, found, err := d.syncer.GetLocalResource(resource.EnsureValidName(clusterID), d.syncerConfig.LocalNamespace,
+		&submarinerv1.Cluster{})
+	if err != nil {
+		logger.Errorf(err, "Error retrieving the Cluster for %q", clusterID)
+		return false, false
+	}
+
+	if !found {
+		return false, false
+	}
+
+	return d.peeringPolicy.Allows(clusterID, resource.MustToMeta(obj).GetLabels()), true
+}
+
+// areRemoteEndpointsEquivalent extends the Endpoint equivalence check to also re-process a remote Endpoint whose peering
+// status changed due to an update of its Cluster labels or that was still waiting for its Cluster to be synced.
+func (d *DatastoreSyncer) areRemoteEndpointsEquivalent(obj1, obj2 *unstructured.Unstructured) bool {
+	if !areEndpointsEquivalent(obj1, obj2) {
+		return false
+	}
+
+	if !d.peeringPolicy.HasClusterSelector() {
+		return true
+	}
+
+	clusterID, _, _ := unstructured.NestedString(obj2.Object, "spec", "cluster_id")
+
+	allowed, found := d.isPeeringAllowed(clusterID)
+	if !found {
+		return true
+	}
+
+	d.statusMutex.Lock()
+	defer d.statusMutex.Unlock()
+
+	if d.pendingEndpoints[obj2.GetName()] {
+		return false
+	}
+
+	_, excluded := d.excludedEndpoints[obj2.GetName()]
+
+	return allowed != excluded
+}
+
+// setPendingEndpoint records whether the given remote Endpoint is waiting for its Cluster to be synced.
+func (d *DatastoreSyncer) setPendingEndpoint(endpoint *submarinerv1.Endpoint, pending bool) {
+	d.statusMutex.Lock()
+	defer d.statusMutex.Unlock()
+
+	if pending {
+		d.pendingEndpoints[endpoint.Name] = true
+	} else {
+		delete(d.pendingEndpoints, endpoint.Name)
+	}
+}
+
+// setExcludedEndpoint records whether the given remote Endpoint is excluded by the peering policy and, if that changed,
+// reports the excluded Endpoints.
+func (d *DatastoreSyncer) setExcludedEndpoint(endpoint *submarinerv1.Endpoint, excluded bool) {
+	d.statusMutex.Lock()
+	defer d.statusMutex.Unlock()
+
+	if _, found := d.excludedEndpoints[endpoint.Name]; found == excluded {
+		return
+	}
+
+	if excluded {
+		d.excludedEndpoints[endpoint.Name] = endpoint.Spec
+	} else {
+		delete(d.excludedEndpoints, endpoint.Name)
+	}
+
+	if d.statusReporter == nil || d.statusReporter.SetExcludedEndpoints == nil {
+		return
+	}
+
+	names := make([]string, 0, len(d.excludedEndpoints))
+	for name := range d.excludedEndpoints {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	endpoints := make([]submarinerv1.EndpointSpec, len(names))
+	for i, name := range names {
+		endpoints[i] = d.excludedEndpoints[name]
+	}
+
+	d.statusReporter.SetExcludedEndpoints(context.TODO(), endpoints)
+}
+
+// deleteExcludedEndpoint deletes the local copy of a remote Endpoint that was previously synced before its cluster became
+// excluded by the peering policy.
+func (d *DatastoreSyncer) deleteExcludedEndpoint(endpoint *submarinerv1.Endpoint) error {
+	err := d.syncer.GetLocalFederator().Delete(context.TODO(), endpoint)
+	if err == nil {
+		logger.Infof("Deleted the local submariner Endpoint %q excluded by the peering policy", endpoint.Name)
+		return nil
+	}
+
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	logger.Errorf(err, "Error deleting the local submariner Endpoint %q excluded by the peering policy", endpoint.Name)
+
+	return err //nolint:wrapcheck  // Let the caller wrap it
+}
@@ -0,0 +1,295 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastoresyncer
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"k8s.io/utils/set"
+)
+
+// setRemoteEndpoint records whether the given remote Endpoint is synced locally, ie its cluster is connected directly, and
+// re-evaluates the transit routes that depend on it.
+func (d *DatastoreSyncer) setRemoteEndpoint(endpoint *submarinerv1.Endpoint, synced bool) error {
+	d.statusMutex.Lock()
+
+	_, found := d.remoteEndpoints[endpoint.Name]
+	if !found && !synced {
+		d.statusMutex.Unlock()
+		return nil
+	}
+
+	if synced {
+		d.remoteEndpoints[endpoint.Name] = endpoint.DeepCopy()
+		d.transitRoutes[endpoint.Name] = d.transitRoutesVia(endpoint)
+	} else {
+		delete(d.remoteEndpoints, endpoint.Name)
+		delete(d.transitRoutes, endpoint.Name)
+	}
+
+	d.statusMutex.Unlock()
+
+	return d.reconcileTransitRoutes(endpoint.Name)
+}
+
+// withTransitSubnets returns the given remote Endpoint with the subnets of the transit routes the local cluster uses through
+// it appended, so the cable drivers and route agents route them via its cable.
+func (d *DatastoreSyncer) withTransitSubnets(endpoint *submarinerv1.Endpoint) *submarinerv1.Endpoint {
+	d.statusMutex.Lock()
+	defer d.statusMutex.Unlock()
+
+	return withTransitSubnets(endpoint, d.transitRoutes[endpoint.Name])
+}
+
+func withTransitSubnets(endpoint *submarinerv1.Endpoint, routes []submarinerv1.TransitRoute) *submarinerv1.Endpoint {
+	if len(routes) == 0 {
+		return endpoint
+	}
+
+	endpoint = endpoint.DeepCopy()
+	subnets := set.New(endpoint.Spec.Subnets...)
+
+	for i := range routes {
+		for _, subnet := range routes[i].Subnets {
+			if !subnets.Has(subnet) {
+				subnets.Insert(subnet)
+				endpoint.Spec.Subnets = append(endpoint.Spec.Subnets, subnet)
+			}
+		}
+	}
+
+	return endpoint
+}
+
+// reconcileTransitRoutes re-evaluates the transit routes used through the synced remote Endpoints other than the given one,
+// updating their local copies as needed, and the transit routes advertised by the local Endpoint.
+func (d *DatastoreSyncer) reconcileTransitRoutes(skipEndpoint string) error {
+	var toUpdate []*submarinerv1.Endpoint
+
+	d.statusMutex.Lock()
+
+	for name, endpoint := range d.remoteEndpoints {
+		if name == skipEndpoint {
+			continue
+		}
+
+		routes := d.transitRoutesVia(endpoint)
+		if reflect.DeepEqual(routes, d.transitRoutes[name]) {
+			continue
+		}
+
+		d.transitRoutes[name] = routes
+		toUpdate = append(toUpdate, withTransitSubnets(endpoint, routes))
+	}
+
+	used := d.usedTransitRoutes()
+	reportUsed := !reflect.DeepEqual(used, d.reportedTransit)
+	d.reportedTransit = used
+	advertised := d.advertisedTransitRoutes()
+
+	d.statusMutex.Unlock()
+
+	for _, endpoint := range toUpdate {
+		logger.Infof("Updating the transit subnets of remote endpoint %q to %v", endpoint.Name, endpoint.Spec.Subnets)
+
+		err := d.syncer.GetLocalFederator().Distribute(context.TODO(), endpoint)
+		if err != nil {
+			logger.Errorf(err, "Error updating the local submariner Endpoint %q", endpoint.Name)
+		}
+	}
+
+	if reportUsed && d.statusReporter != nil && d.statusReporter.SetTransitRoutes != nil {
+		d.statusReporter.SetTransitRoutes(context.TODO(), used)
+	}
+
+	return d.advertiseTransitRoutes(advertised)
+}
+
+// transitRoutesVia returns the transit routes advertised by the given remote Endpoint that the local cluster uses. A route is
+// used if its path doesn't loop back to the local cluster, its cluster is configured to be reached through transit gateways
+// and there's no better route through another transit gateway. The statusMutex must be held.
+func (d *DatastoreSyncer) transitRoutesVia(endpoint *submarinerv1.Endpoint) []submarinerv1.TransitRoute {
+	var routes []submarinerv1.TransitRoute
+
+	for i := range endpoint.Spec.TransitRoutes {
+		route := &endpoint.Spec.TransitRoutes[i]
+
+		if !d.isValidTransitRoute(route, endpoint.Spec.ClusterID) {
+			continue
+		}
+
+		if !d.transitClusters.Has(route.ClusterID) {
+			continue
+		}
+
+		if d.hasBetterTransitRoute(route, endpoint.Spec.ClusterID) {
+			continue
+		}
+
+		conflicts, err := d.overlappingSubnets(&submarinerv1.Endpoint{Spec: submarinerv1.EndpointSpec{Subnets: route.Subnets}})
+		if err != nil || len(conflicts) > 0 {
+			logger.Warningf("Ignoring the transit route to cluster %q via %q as its subnets overlap with the local subnets: %s",
+				route.ClusterID, endpoint.Spec.ClusterID, strings.Join(conflicts, ", "))
+			continue
+		}
+
+		routes = append(routes, *route.DeepCopy())
+	}
+
+	return routes
+}
+
+func (d *DatastoreSyncer) isValidTransitRoute(route *submarinerv1.TransitRoute, advertiser string) bool {
+	if len(route.Path) == 0 || route.Path[0] != advertiser || route.ClusterID == d.localCluster.Spec.ClusterID {
+		return false
+	}
+
+	// A path through the local cluster would loop.
+	return !set.New(route.Path...).Has(d.localCluster.Spec.ClusterID)
+}
+
+// hasBetterTransitRoute returns whether another transit gateway advertises a shorter path to the route's cluster. Equal
+// length paths are broken by the lowest transit cluster ID so all clusters agree.
+func (d *DatastoreSyncer) hasBetterTransitRoute(route *submarinerv1.TransitRoute, advertiser string) bool {
+	for _, other := range d.remoteEndpoints {
+		if other.Spec.ClusterID == advertiser {
+			continue
+		}
+
+		for i := range other.Spec.TransitRoutes {
+			otherRoute := &other.Spec.TransitRoutes[i]
+			if otherRoute.ClusterID != route.ClusterID || !d.isValidTransitRoute(otherRoute, other.Spec.ClusterID) {
+				continue
+			}
+
+			if isShorterPath(otherRoute.Path, route.Path) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isShorterPath(path1, path2 []string) bool {
+	if len(path1) != len(path2) {
+		return len(path1) < len(path2)
+	}
+
+	return strings.Join(path1, ",") < strings.Join(path2, ",")
+}
+
+// usedTransitRoutes returns the transit routes used through all the synced remote Endpoints, sorted by cluster ID. The
+// statusMutex must be held.
+func (d *DatastoreSyncer) usedTransitRoutes() []submarinerv1.TransitRoute {
+	var routes []submarinerv1.TransitRoute
+
+	for _, r := range d.transitRoutes {
+		routes = append(routes, r...)
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].ClusterID < routes[j].ClusterID
+	})
+
+	return routes
+}
+
+// advertisedTransitRoutes returns, if transit is enabled, the routes to the directly connected remote clusters and to the
+// clusters reachable through them, with the local cluster prepended to the paths. The statusMutex must be held.
+func (d *DatastoreSyncer) advertisedTransitRoutes() []submarinerv1.TransitRoute {
+	if !d.transitEnabled {
+		return nil
+	}
+
+	localClusterID := d.localCluster.Spec.ClusterID
+	best := map[string]submarinerv1.TransitRoute{}
+
+	add := func(route submarinerv1.TransitRoute) {
+		existing, found := best[route.ClusterID]
+		if !found || isShorterPath(route.Path, existing.Path) {
+			best[route.ClusterID] = route
+		}
+	}
+
+	for _, endpoint := range d.remoteEndpoints {
+		add(submarinerv1.TransitRoute{
+			ClusterID: endpoint.Spec.ClusterID,
+			Subnets:   endpoint.Spec.Subnets,
+			Path:      []string{localClusterID},
+		})
+
+		for i := range endpoint.Spec.TransitRoutes {
+			route := &endpoint.Spec.TransitRoutes[i]
+			if !d.isValidTransitRoute(route, endpoint.Spec.ClusterID) {
+				continue
+			}
+
+			add(submarinerv1.TransitRoute{
+				ClusterID: route.ClusterID,
+				Subnets:   route.Subnets,
+				Path:      append([]string{localClusterID}, route.Path...),
+			})
+		}
+	}
+
+	clusterIDs := make([]string, 0, len(best))
+	for clusterID := range best {
+		clusterIDs = append(clusterIDs, clusterID)
+	}
+
+	sort.Strings(clusterIDs)
+
+	var routes []submarinerv1.TransitRoute
+
+	for _, clusterID := range clusterIDs {
+		route := best[clusterID]
+		routes = append(routes, *route.DeepCopy())
+	}
+
+	return routes
+}
+
+// advertiseTransitRoutes publishes the given transit routes in the local Endpoint if they changed.
+func (d *DatastoreSyncer) advertiseTransitRoutes(routes []submarinerv1.TransitRoute) error {
+	d.localEndpointMutex.Lock()
+	defer d.localEndpointMutex.Unlock()
+
+	if reflect.DeepEqual(routes, d.localEndpoint.Spec.TransitRoutes) {
+		return nil
+	}
+
+	logger.Infof("Advertising transit routes to %d remote clusters", len(routes))
+
+	prevRoutes := d.localEndpoint.Spec.TransitRoutes
+	d.localEndpoint.Spec.TransitRoutes = routes
+
+	err := d.createOrUpdateLocalEndpoint(context.TODO(), d.syncer.GetLocalFederator())
+	if err != nil {
+		logger.Errorf(err, "Error advertising the transit routes in the local submariner Endpoint")
+
+		d.localEndpoint.Spec.TransitRoutes = prevRoutes
+	}
+
+	return err
+}
@@ -19,19 +19,30 @@ limitations under the License.
 package tunnel
 
 import (
+	"context"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/admiral/pkg/resource"
 	"github.com/submariner-io/admiral/pkg/watcher"
 	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/cableengine"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 type controller struct {
-	engine cableengine.Engine
+	sync.Mutex
+	engine          cableengine.Engine
+	localClusterID  string
+	inMaintenance   bool
+	endpointWatcher watcher.Interface
 }
 
 var logger = log.Logger{Logger: logf.Log.WithName("Tunnel")}
@@ -39,7 +50,10 @@ var logger = log.Logger{Logger: logf.Log.WithName("Tunnel")}
 func StartController(engine cableengine.Engine, namespace string, config *watcher.Config, stopCh <-chan struct{}) error {
 	logger.Info("Starting the tunnel controller")
 
-	c := &controller{engine: engine}
+	c := &controller{
+		engine:         engine,
+		localClusterID: engine.GetLocalEndpoint().Spec.ClusterID,
+	}
 
 	config.ResourceConfigs = []watcher.ResourceConfig{
 		{
@@ -52,18 +66,33 @@ func StartController(engine cableengine.Engine, namespace string, config *watche
 			},
 			SourceNamespace: namespace,
 		},
+		{
+			Name:         "Tunnel Controller maintenance watcher",
+			ResourceType: &v1.Cluster{},
+			Handler: watcher.EventHandlerFuncs{
+				OnCreateFunc: c.handleCreatedOrUpdatedCluster,
+				OnUpdateFunc: c.handleCreatedOrUpdatedCluster,
+			},
+			SourceNamespace: namespace,
+		},
 	}
 
 	if config.ResyncPeriod == 0 {
 		config.ResyncPeriod = time.Second * 30
 	}
 
-	endpointWatcher, err := watcher.New(config)
+	// The maintenance mode must be known before the watcher starts so the existing Endpoints aren't connected meanwhile.
+	err := c.readMaintenanceMode(namespace, config)
+	if err != nil {
+		return err
+	}
+
+	c.endpointWatcher, err = watcher.New(config)
 	if err != nil {
 		return errors.Wrap(err, "error creating the Endpoint watcher")
 	}
 
-	err = endpointWatcher.Start(stopCh)
+	err = c.endpointWatcher.Start(stopCh)
 	if err != nil {
 		return errors.Wrap(err, "error starting the Endpoint watcher")
 	}
@@ -71,11 +100,45 @@ func StartController(engine cableengine.Engine, namespace string, config *watche
 	return nil
 }
 
+func (c *controller) readMaintenanceMode(namespace string, config *watcher.Config) error {
+	client := config.Client
+	if client == nil {
+		var err error
+
+		client, err = dynamic.NewForConfig(config.RestConfig)
+		if err != nil {
+			return errors.Wrap(err, "error creating the dynamic client")
+		}
+	}
+
+	name := resource.EnsureValidName(c.localClusterID)
+
+	obj, err := client.Resource(v1.SchemeGroupVersion.WithResource("clusters")).Namespace(namespace).Get(context.TODO(),
+		name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "error retrieving the local Cluster %q", name)
+	}
+
+	cluster := &v1.Cluster{ObjectMeta: metav1.ObjectMeta{Annotations: obj.GetAnnotations()}}
+	c.inMaintenance = cluster.InMaintenance()
+
+	return nil
+}
+
 func (c *controller) handleCreatedOrUpdatedEndpoint(obj runtime.Object, _ int) bool {
 	endpoint := obj.(*v1.Endpoint)
 
 	logger.V(log.TRACE).Infof("Tunnel controller processing added or updated submariner Endpoint object: %#v", endpoint)
 
+	if c.isInMaintenance() {
+		logger.V(log.DEBUG).Infof("Not installing cable %q while the local cluster is in maintenance mode", endpoint.Spec.CableName)
+		return false
+	}
+
 	err := c.engine.InstallCable(endpoint)
 	if err != nil {
 		logger.Errorf(err, "Error installing cable for Endpoint %#v", endpoint)
@@ -99,3 +162,48 @@ func (c *controller) handleRemovedEndpoint(obj runtime.Object, _ int) bool {
 
 	return false
 }
+
+// handleCreatedOrUpdatedCluster removes all the cables when the local cluster enters maintenance mode and re-installs them
+// when it leaves it.
+func (c *controller) handleCreatedOrUpdatedCluster(obj runtime.Object, numRequeues int) bool {
+	cluster := obj.(*v1.Cluster)
+	if cluster.Spec.ClusterID != c.localClusterID {
+		return false
+	}
+
+	inMaintenance := cluster.InMaintenance()
+
+	c.Lock()
+	changed := c.inMaintenance != inMaintenance
+	c.inMaintenance = inMaintenance
+	c.Unlock()
+
+	if !changed && numRequeues == 0 {
+		return false
+	}
+
+	if inMaintenance {
+		logger.Infof("The local cluster entered maintenance mode - removing all cables")
+	} else {
+		logger.Infof("The local cluster left maintenance mode - re-installing the cables")
+	}
+
+	requeue := false
+
+	for _, obj := range c.endpointWatcher.ListResources(&v1.Endpoint{}, labels.Everything()) {
+		if inMaintenance {
+			requeue = c.handleRemovedEndpoint(obj, numRequeues) || requeue
+		} else {
+			requeue = c.handleCreatedOrUpdatedEndpoint(obj, numRequeues) || requeue
+		}
+	}
+
+	return requeue
+}
+
+func (c *controller) isInMaintenance() bool {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.inMaintenance
+}
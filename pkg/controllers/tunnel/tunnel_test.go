@@ -44,7 +44,8 @@ import (
 )
 
 const (
-	namespace = "submariner"
+	namespace      = "submariner"
+	localClusterID = "west"
 )
 
 func init() {
@@ -64,6 +65,7 @@ var _ = Describe("Managing tunnels", func() {
 	var (
 		config    *watcher.Config
 		endpoints dynamic.ResourceInterface
+		clusters  dynamic.ResourceInterface
 		endpoint  *v1.Endpoint
 		stopCh    chan struct{}
 	)
@@ -81,6 +83,7 @@ var _ = Describe("Managing tunnels", func() {
 				ClusterID: "east",
 				Hostname:  "redsox",
 				PrivateIP: "192.68.1.2",
+				Backend:   fake.DriverName,
 			},
 		}
 
@@ -95,6 +98,7 @@ var _ = Describe("Managing tunnels", func() {
 		gvr := test.GetGroupVersionResourceFor(restMapper, &v1.Endpoint{})
 
 		endpoints = client.Resource(*gvr).Namespace(namespace)
+		clusters = client.Resource(*test.GetGroupVersionResourceFor(restMapper, &v1.Cluster{})).Namespace(namespace)
 
 		config = &watcher.Config{
 			RestMapper: restMapper,
@@ -104,9 +108,10 @@ var _ = Describe("Managing tunnels", func() {
 	})
 
 	JustBeforeEach(func() {
-		engine := cableengine.NewEngine(&types.SubmarinerCluster{}, &types.SubmarinerEndpoint{
+		engine := cableengine.NewEngine(&types.SubmarinerCluster{ID: localClusterID}, &types.SubmarinerEndpoint{
 			Spec: v1.EndpointSpec{
-				Backend: fake.DriverName,
+				ClusterID: localClusterID,
+				Backend:   fake.DriverName,
 			},
 		})
 
@@ -192,8 +197,62 @@ var _ = Describe("Managing tunnels", func() {
 			verifyDisconnectFromEndpoint()
 		})
 	})
+
+	When("the local cluster enters and leaves maintenance mode", func() {
+		var cluster *v1.Cluster
+
+		BeforeEach(func() {
+			cluster = newLocalClusterInMaintenance()
+		})
+
+		It("should remove the cables and re-install them", func() {
+			test.CreateResource(endpoints, endpoint)
+			verifyConnectToEndpoint()
+
+			test.CreateResource(clusters, cluster)
+			verifyDisconnectFromEndpoint()
+
+			cluster.Annotations = nil
+			test.UpdateResource(clusters, cluster)
+			verifyConnectToEndpoint()
+		})
+	})
+
+	When("an Endpoint is created while the local cluster is in maintenance mode", func() {
+		BeforeEach(func() {
+			test.CreateResource(clusters, newLocalClusterInMaintenance())
+		})
+
+		It("should not install the cable", func() {
+			time.Sleep(300 * time.Millisecond)
+			test.CreateResource(endpoints, endpoint)
+			fakeDriver.AwaitNoConnectToEndpoint()
+		})
+	})
+
+	When("the local cluster is in maintenance mode on startup", func() {
+		BeforeEach(func() {
+			test.CreateResource(clusters, newLocalClusterInMaintenance())
+			test.CreateResource(endpoints, endpoint)
+		})
+
+		It("should not install the cable for an existing Endpoint", func() {
+			fakeDriver.AwaitNoConnectToEndpoint()
+		})
+	})
 })
 
+func newLocalClusterInMaintenance() *v1.Cluster {
+	return &v1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        localClusterID,
+			Namespace:   namespace,
+			Annotations: map[string]string{v1.MaintenanceAnnotation: "true"},
+		},
+		Spec: v1.ClusterSpec{ClusterID: localClusterID},
+	}
+}
+
 func TestTunnelController(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Tunnel controller Suite")
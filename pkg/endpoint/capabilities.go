@@ -0,0 +1,80 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	submv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	natproto "github.com/submariner-io/submariner/pkg/natdiscovery/proto"
+	"github.com/submariner-io/submariner/pkg/types"
+)
+
+func getLocalCapabilities(submSpec *types.SubmarinerSpecification, globalnetEnabled bool) *submv1.EndpointCapabilities {
+	capabilities := &submv1.EndpointCapabilities{
+		NATDiscoveryVersion: natproto.Version,
+		Globalnet:           globalnetEnabled,
+	}
+
+	if submSpec.HealthCheckEnabled {
+		capabilities.HealthCheckProbes = []string{submv1.HealthCheckProbeICMP, submv1.HealthCheckProbePathMTU}
+	}
+
+	return capabilities
+}
+
+// CheckCompatibility returns an error explaining why the local and remote Endpoints are incompatible, if they are. The
+// optional features they have in common, such as the NAT discovery protocol version and the health check probes, are
+// negotiated by the components using them.
+func CheckCompatibility(local, remote *submv1.EndpointSpec) error {
+	localCaps := local.GetCapabilities()
+	remoteCaps := remote.GetCapabilities()
+
+	if remote.Backend != local.Backend {
+		if !contains(remoteCaps.Backends, local.Backend) {
+			return errors.Errorf("the remote gateway doesn't support the %q cable driver used locally, it supports: %s",
+				local.Backend, strings.Join(remoteCaps.Backends, ", "))
+		}
+
+		return errors.Errorf("the remote gateway uses the %q cable driver while the local gateway uses %q",
+			remote.Backend, local.Backend)
+	}
+
+	// Gateways predating capability publication don't advertise whether they use Globalnet.
+	if remote.Capabilities != nil && localCaps.Globalnet != remoteCaps.Globalnet {
+		if localCaps.Globalnet {
+			return errors.New("the local cluster uses Globalnet but the remote cluster doesn't")
+		}
+
+		return errors.New("the remote cluster uses Globalnet but the local cluster doesn't")
+	}
+
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for i := range list {
+		if list[i] == s {
+			return true
+		}
+	}
+
+	return false
+}
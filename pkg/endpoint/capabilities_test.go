@@ -0,0 +1,111 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoint_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/endpoint"
+)
+
+var _ = Describe("CheckCompatibility", func() {
+	var local, remote *v1.EndpointSpec
+
+	BeforeEach(func() {
+		local = &v1.EndpointSpec{
+			ClusterID: "east",
+			Backend:   "libreswan",
+			Subnets:   []string{"10.0.0.0/16"},
+			Capabilities: &v1.EndpointCapabilities{
+				Backends:            []string{"libreswan", "wireguard"},
+				NATDiscoveryVersion: 2,
+				HealthCheckProbes:   []string{v1.HealthCheckProbeICMP, v1.HealthCheckProbePathMTU},
+			},
+		}
+
+		remote = &v1.EndpointSpec{
+			ClusterID: "west",
+			Backend:   "libreswan",
+			Subnets:   []string{"10.1.0.0/16"},
+			Capabilities: &v1.EndpointCapabilities{
+				Backends:            []string{"libreswan", "wireguard"},
+				NATDiscoveryVersion: 1,
+				HealthCheckProbes:   []string{v1.HealthCheckProbeICMP},
+			},
+		}
+	})
+
+	It("should not return an error", func() {
+		Expect(endpoint.CheckCompatibility(local, remote)).To(Succeed())
+	})
+
+	When("the remote gateway doesn't support the local cable driver", func() {
+		It("should return an error", func() {
+			remote.Backend = "vxlan"
+			remote.Capabilities.Backends = []string{"vxlan"}
+
+			Expect(endpoint.CheckCompatibility(local, remote)).To(MatchError(ContainSubstring(`doesn't support the "libreswan" cable driver`)))
+		})
+	})
+
+	When("the remote gateway uses a different cable driver", func() {
+		It("should return an error", func() {
+			remote.Backend = "wireguard"
+
+			Expect(endpoint.CheckCompatibility(local, remote)).To(MatchError(ContainSubstring(`uses the "wireguard" cable driver`)))
+		})
+	})
+
+	When("only the remote cluster uses Globalnet", func() {
+		It("should return an error", func() {
+			remote.Capabilities.Globalnet = true
+
+			Expect(endpoint.CheckCompatibility(local, remote)).To(MatchError(ContainSubstring("the remote cluster uses Globalnet")))
+		})
+	})
+
+	When("the remote gateway has IPv6 subnets", func() {
+		It("should not return an error", func() {
+			remote.Subnets = append(remote.Subnets, "fd00::/64")
+
+			Expect(endpoint.CheckCompatibility(local, remote)).To(Succeed())
+		})
+	})
+
+	When("the remote gateway doesn't publish its capabilities", func() {
+		BeforeEach(func() {
+			remote.Capabilities = nil
+			remote.HealthCheckIP = "10.1.0.1"
+			remote.BackendConfig = map[string]string{v1.NATTDiscoveryPortConfig: "4490"}
+		})
+
+		It("should not return an error", func() {
+			Expect(endpoint.CheckCompatibility(local, remote)).To(Succeed())
+		})
+
+		Context("and the local cluster uses Globalnet", func() {
+			It("should not return an error", func() {
+				local.Capabilities.Globalnet = true
+
+				Expect(endpoint.CheckCompatibility(local, remote)).To(Succeed())
+			})
+		})
+	})
+})
@@ -88,6 +88,7 @@ func GetLocal(submSpec *types.SubmarinerSpecification, k8sClient kubernetes.Inte
 			Subnets:       localSubnets,
 			Backend:       submSpec.CableDriver,
 			BackendConfig: backendConfig,
+			Capabilities:  getLocalCapabilities(submSpec, globalnetEnabled),
 		},
 	}
 
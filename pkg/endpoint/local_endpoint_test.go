@@ -24,6 +24,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/submariner-io/submariner/pkg/endpoint"
+	natproto "github.com/submariner-io/submariner/pkg/natdiscovery/proto"
 	"github.com/submariner-io/submariner/pkg/types"
 	v1 "k8s.io/api/core/v1"
 	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -86,6 +87,9 @@ var _ = Describe("GetLocal", func() {
 		Expect(endpoint.Spec.Subnets).To(Equal(subnets))
 		Expect(endpoint.Spec.NATEnabled).To(BeFalse())
 		Expect(endpoint.Spec.BackendConfig[testUDPPortLabel]).To(Equal(testUDPPort))
+		Expect(endpoint.Spec.Capabilities).ToNot(BeNil())
+		Expect(endpoint.Spec.Capabilities.NATDiscoveryVersion).To(BeEquivalentTo(natproto.Version))
+		Expect(endpoint.Spec.Capabilities.Globalnet).To(BeFalse())
 	})
 
 	When("gateway node is not annotated with udp port", func() {
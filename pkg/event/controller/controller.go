@@ -23,6 +23,7 @@ import (
 	"os"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 	"github.com/pkg/errors"
@@ -32,6 +33,7 @@ import (
 	"github.com/submariner-io/submariner/pkg/event"
 	k8sv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes/scheme"
 	restclient "k8s.io/client-go/rest"
@@ -76,8 +78,9 @@ type Controller struct {
 	handlers     *event.Registry
 	handlerState handlerStateImpl
 
-	syncMutex sync.Mutex
-	hostname  string
+	syncMutex         sync.Mutex
+	hostname          string
+	reconcileInterval time.Duration
 }
 
 // If the handler cannot recover from a failure, even after retrying for maximum requeue attempts,
@@ -99,6 +102,10 @@ type Config struct {
 
 	// Client can be provided for unit testing. By default New will create its own dynamic client.
 	Client dynamic.Interface
+
+	// ReconcileInterval specifies how often the handlers are asked to reconcile their full state. If zero,
+	// periodic reconciliation is disabled.
+	ReconcileInterval time.Duration
 }
 
 var logger = log.Logger{Logger: logf.Log.WithName("EventController")}
@@ -110,8 +117,9 @@ func New(config *Config) (*Controller, error) {
 	}
 
 	ctl := Controller{
-		handlers: config.Registry,
-		hostname: hostname,
+		handlers:          config.Registry,
+		hostname:          hostname,
+		reconcileInterval: config.ReconcileInterval,
 	}
 
 	err = envconfig.Process("submariner", &ctl.env)
@@ -178,6 +186,19 @@ func (c *Controller) Start(stopCh <-chan struct{}) error {
 		return errors.Wrap(err, "error starting the resource watcher")
 	}
 
+	if c.reconcileInterval > 0 {
+		go func() {
+			// Give the initial events a chance to be processed before the first reconciliation.
+			select {
+			case <-time.After(c.reconcileInterval):
+			case <-stopCh:
+				return
+			}
+
+			wait.Until(c.reconcileHandlers, c.reconcileInterval, stopCh)
+		}()
+	}
+
 	logger.Info("Event controller started")
 
 	return nil
@@ -190,3 +211,14 @@ func (c *Controller) Stop() {
 		logger.Warningf("In Event Controller, StopHandlers returned error: %v", err)
 	}
 }
+
+func (c *Controller) reconcileHandlers() {
+	c.syncMutex.Lock()
+	defer c.syncMutex.Unlock()
+
+	logger.V(log.DEBUG).Info("Reconciling the event handlers")
+
+	if err := c.handlers.Reconcile(&c.handlerState); err != nil {
+		logger.Errorf(err, "Error reconciling the event handlers")
+	}
+}
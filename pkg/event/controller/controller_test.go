@@ -20,11 +20,13 @@ package controller_test
 
 import (
 	"sync/atomic"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	submV1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/event"
+	"github.com/submariner-io/submariner/pkg/event/controller"
 	"github.com/submariner-io/submariner/pkg/event/testing"
 )
 
@@ -75,6 +77,46 @@ var _ = Describe("Event controller", func() {
 			t.testRemoteEndpoints()
 		})
 	})
+
+	When("the state is requested", func() {
+		It("should report the role, remote Endpoints and handlers", func() {
+			t.CreateLocalHostEndpoint()
+			t.awaitEvent(testing.EvTransitionToGateway, nil)
+
+			endpoint := t.CreateEndpoint(testing.NewEndpoint("remote-cluster", "host", "10.1.0.0/16"))
+			t.awaitEvent(testing.EvRemoteEndpointCreated, endpoint)
+
+			state := t.Controller.State()
+			Expect(state.Role).To(Equal(controller.RoleGateway))
+			Expect(state.Node).To(Equal(t.Hostname))
+			Expect(state.RemoteEndpoints).To(Equal([]controller.RemoteEndpoint{{
+				Name:      endpoint.Name,
+				ClusterID: "remote-cluster",
+				Hostname:  "host",
+				Subnets:   []string{"10.1.0.0/16"},
+			}}))
+			Expect(state.Handlers).To(HaveLen(1))
+			Expect(state.Handlers[0].Name).To(Equal(testHandlerName))
+		})
+	})
+
+	When("a reconcile interval is configured", func() {
+		BeforeEach(func() {
+			t.ReconcileInterval = 100 * time.Millisecond
+		})
+
+		It("should periodically invoke the handler's Reconcile", func() {
+			endpoint := t.CreateEndpoint(testing.NewEndpoint("remote-cluster", "host"))
+			t.awaitEvent(testing.EvRemoteEndpointCreated, endpoint)
+
+			for i := 0; i < 2; i++ {
+				Eventually(t.testEvents).Should(Receive(And(HaveField("Name", testing.EvReconcile),
+					HaveField("Parameter", WithTransform(func(s event.HandlerState) []submV1.Endpoint {
+						return s.GetRemoteEndpoints()
+					}, Equal([]submV1.Endpoint{*endpoint}))))))
+			}
+		})
+	})
 })
 
 type testDriver struct {
@@ -89,6 +131,7 @@ func newTestDriver() *testDriver {
 	}
 
 	BeforeEach(func() {
+		t.ReconcileInterval = 0
 		t.testEvents = make(chan testing.TestEvent, 1000)
 		t.handler = &TestHandler{
 			TestHandler: &testing.TestHandler{
@@ -0,0 +1,90 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/submariner-io/submariner/pkg/event"
+)
+
+const (
+	RoleGateway    = "gateway"
+	RoleNonGateway = "non-gateway"
+)
+
+// RemoteEndpoint summarizes a remote Endpoint known to the handlers.
+type RemoteEndpoint struct {
+	Name      string   `json:"name"`
+	ClusterID string   `json:"clusterID"`
+	Hostname  string   `json:"hostname"`
+	Subnets   []string `json:"subnets"`
+}
+
+// State describes the node's role, the remote Endpoints known to the handlers and the state of each handler.
+type State struct {
+	Node            string                `json:"node"`
+	Role            string                `json:"role"`
+	RemoteEndpoints []RemoteEndpoint      `json:"remoteEndpoints"`
+	Handlers        []event.HandlerStatus `json:"handlers"`
+}
+
+// State returns a consistent snapshot of the controller and handler state.
+func (c *Controller) State() State {
+	c.syncMutex.Lock()
+	defer c.syncMutex.Unlock()
+
+	state := State{
+		Node:            c.hostname,
+		Role:            RoleNonGateway,
+		RemoteEndpoints: []RemoteEndpoint{},
+		Handlers:        c.handlers.HandlerStatuses(),
+	}
+
+	if c.handlerState.IsOnGateway() {
+		state.Role = RoleGateway
+	}
+
+	endpoints := c.handlerState.GetRemoteEndpoints()
+	for i := range endpoints {
+		state.RemoteEndpoints = append(state.RemoteEndpoints, RemoteEndpoint{
+			Name:      endpoints[i].Name,
+			ClusterID: endpoints[i].Spec.ClusterID,
+			Hostname:  endpoints[i].Spec.Hostname,
+			Subnets:   endpoints[i].Spec.Subnets,
+		})
+	}
+
+	sort.Slice(state.RemoteEndpoints, func(i, j int) bool {
+		return state.RemoteEndpoints[i].Name < state.RemoteEndpoints[j].Name
+	})
+
+	return state
+}
+
+// ServeHTTP writes the controller State as JSON.
+func (c *Controller) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(c.State()); err != nil {
+		logger.Errorf(err, "Error encoding the event controller state")
+	}
+}
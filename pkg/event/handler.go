@@ -91,6 +91,13 @@ type Handler interface {
 
 	// NodeRemoved indicates when a node has been removed from the cluster
 	NodeRemoved(node *k8sV1.Node) error
+
+	// Reconcile is called periodically to let the handler converge the data path it manages to the desired state,
+	// correcting any drift caused by missed events or external changes.
+	Reconcile(state HandlerState) error
+
+	// OwnedState returns the data path entries the handler believes it has programmed on the node, for introspection.
+	OwnedState() OwnedState
 }
 
 // Base structure for event handlers that stubs out methods considered to be optional.
@@ -165,3 +172,11 @@ func (ev *HandlerBase) NodeUpdated(_ *k8sV1.Node) error {
 func (ev *HandlerBase) NodeRemoved(_ *k8sV1.Node) error {
 	return nil
 }
+
+func (ev *HandlerBase) Reconcile(_ HandlerState) error {
+	return nil
+}
+
+func (ev *HandlerBase) OwnedState() OwnedState {
+	return OwnedState{}
+}
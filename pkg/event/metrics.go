@@ -0,0 +1,95 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+import "github.com/prometheus/client_golang/prometheus"
+
+const (
+	handlerLabel  = "handler"
+	resourceLabel = "resource"
+	eventLabel    = "event"
+)
+
+// Resource kinds reported by handlers when they correct drift during reconciliation.
+const (
+	DriftRoutes   = "routes"
+	DriftRules    = "rules"
+	DriftIPSets   = "ipsets"
+	DriftIPTables = "iptables"
+)
+
+var driftCorrectedCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "submariner_routeagent_drift_corrected_total",
+		Help: "Count of data path entries corrected by event handlers during periodic reconciliation",
+	},
+	[]string{
+		handlerLabel,
+		resourceLabel,
+	},
+)
+
+var handlerFailuresCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "submariner_routeagent_handler_failures_total",
+		Help: "Count of errors returned by event handlers while processing events",
+	},
+	[]string{
+		handlerLabel,
+		eventLabel,
+	},
+)
+
+var handlerHealthyGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "submariner_routeagent_handler_healthy",
+		Help: "Whether an event handler has processed all its events successfully (1) or has outstanding failures (0)",
+	},
+	[]string{
+		handlerLabel,
+	},
+)
+
+func init() {
+	prometheus.MustRegister(driftCorrectedCounter, handlerFailuresCounter, handlerHealthyGauge)
+}
+
+func recordHandlerFailure(handler, eventName string) {
+	handlerFailuresCounter.With(prometheus.Labels{handlerLabel: handler, eventLabel: eventName}).Inc()
+}
+
+func setHandlerHealthy(handler string, healthy bool) {
+	value := 0.0
+	if healthy {
+		value = 1
+	}
+
+	handlerHealthyGauge.With(prometheus.Labels{handlerLabel: handler}).Set(value)
+}
+
+// RecordDriftCorrected records the number of entries of the given resource kind that the named handler
+// had to add or remove to bring the data path back to its desired state.
+func RecordDriftCorrected(handler, resource string, count int) {
+	if count <= 0 {
+		return
+	}
+
+	logger.Infof("Event handler %q corrected %d drifted %s", handler, count, resource)
+	driftCorrectedCounter.With(prometheus.Labels{handlerLabel: handler, resourceLabel: resource}).Add(float64(count))
+}
@@ -19,7 +19,10 @@ limitations under the License.
 package event
 
 import (
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/log"
@@ -36,6 +39,16 @@ type Registry struct {
 	networkPlugin           string
 	eventHandlers           []Handler
 	remoteEndpointTimeStamp map[string]v1.Time
+	healthMutex             sync.Mutex
+	lastErrors              map[string]*HandlerError
+	pendingEvents           map[string]*pendingEvent
+}
+
+// pendingEvent tracks the handlers that failed to process a particular event so only those are invoked when the
+// event is retried.
+type pendingEvent struct {
+	version  string
+	handlers set.Set[string]
 }
 
 var logger = log.Logger{Logger: logf.Log.WithName("EventRegistry")}
@@ -49,6 +62,8 @@ func NewRegistry(name, networkPlugin string, eventHandlers ...Handler) (*Registr
 		networkPlugin:           strings.ToLower(networkPlugin),
 		eventHandlers:           []Handler{},
 		remoteEndpointTimeStamp: map[string]v1.Time{},
+		lastErrors:              map[string]*HandlerError{},
+		pendingEvents:           map[string]*pendingEvent{},
 	}
 
 	for _, eventHandler := range eventHandlers {
@@ -108,31 +123,43 @@ func (er *Registry) Uninstall() error {
 }
 
 func (er *Registry) TransitionToNonGateway() error {
-	return er.invokeHandlers("TransitionToNonGateway", func(h Handler) error {
+	er.forgetPendingEvents("", "TransitionToGateway")
+
+	return er.invokeTrackedHandlers("TransitionToNonGateway", nil, func(h Handler) error {
 		return h.TransitionToNonGateway() //nolint:wrapcheck  // Let the caller wrap it
 	})
 }
 
 func (er *Registry) TransitionToGateway() error {
-	return er.invokeHandlers("TransitionToGateway", func(h Handler) error {
+	er.forgetPendingEvents("", "TransitionToNonGateway")
+
+	return er.invokeTrackedHandlers("TransitionToGateway", nil, func(h Handler) error {
 		return h.TransitionToGateway() //nolint:wrapcheck  // Let the caller wrap it
 	})
 }
 
 func (er *Registry) LocalEndpointCreated(endpoint *submV1.Endpoint) error {
-	return er.invokeHandlers("LocalEndpointCreated", func(h Handler) error {
+	return er.invokeTrackedHandlers("LocalEndpointCreated", endpoint, func(h Handler) error {
 		return h.LocalEndpointCreated(endpoint) //nolint:wrapcheck  // Let the caller wrap it
 	})
 }
 
 func (er *Registry) LocalEndpointUpdated(endpoint *submV1.Endpoint) error {
-	return er.invokeHandlers("LocalEndpointUpdated", func(h Handler) error {
+	err := er.invokeTrackedHandlers("LocalEndpointUpdated", endpoint, func(h Handler) error {
 		return h.LocalEndpointUpdated(endpoint) //nolint:wrapcheck  // Let the caller wrap it
 	})
+
+	if err == nil {
+		er.forgetPendingEvents(endpoint.Name, "LocalEndpointCreated")
+	}
+
+	return err
 }
 
 func (er *Registry) LocalEndpointRemoved(endpoint *submV1.Endpoint) error {
-	return er.invokeHandlers("LocalEndpointRemoved", func(h Handler) error {
+	er.forgetPendingEvents(endpoint.Name, "LocalEndpointCreated", "LocalEndpointUpdated")
+
+	return er.invokeTrackedHandlers("LocalEndpointRemoved", endpoint, func(h Handler) error {
 		return h.LocalEndpointRemoved(endpoint) //nolint:wrapcheck  // Let the caller wrap it
 	})
 }
@@ -146,7 +173,7 @@ func (er *Registry) RemoteEndpointCreated(endpoint *submV1.Endpoint) error {
 		return nil
 	}
 
-	err := er.invokeHandlers("RemoteEndpointCreated", func(h Handler) error {
+	err := er.invokeTrackedHandlers("RemoteEndpointCreated", endpoint, func(h Handler) error {
 		return h.RemoteEndpointCreated(endpoint) //nolint:wrapcheck  // Let the caller wrap it
 	})
 
@@ -158,9 +185,15 @@ func (er *Registry) RemoteEndpointCreated(endpoint *submV1.Endpoint) error {
 }
 
 func (er *Registry) RemoteEndpointUpdated(endpoint *submV1.Endpoint) error {
-	return er.invokeHandlers("RemoteEndpointUpdated", func(h Handler) error {
+	err := er.invokeTrackedHandlers("RemoteEndpointUpdated", endpoint, func(h Handler) error {
 		return h.RemoteEndpointUpdated(endpoint) //nolint:wrapcheck  // Let the caller wrap it
 	})
+
+	if err == nil {
+		er.forgetPendingEvents(endpoint.Name, "RemoteEndpointCreated")
+	}
+
+	return err
 }
 
 func (er *Registry) RemoteEndpointRemoved(endpoint *submV1.Endpoint) error {
@@ -173,39 +206,194 @@ func (er *Registry) RemoteEndpointRemoved(endpoint *submV1.Endpoint) error {
 	}
 
 	delete(er.remoteEndpointTimeStamp, endpoint.Spec.ClusterID)
+	er.forgetPendingEvents(endpoint.Name, "RemoteEndpointCreated", "RemoteEndpointUpdated")
 
-	return er.invokeHandlers("RemoteEndpointRemoved", func(h Handler) error {
+	return er.invokeTrackedHandlers("RemoteEndpointRemoved", endpoint, func(h Handler) error {
 		return h.RemoteEndpointRemoved(endpoint) //nolint:wrapcheck  // Let the caller wrap it
 	})
 }
 
 func (er *Registry) NodeCreated(node *k8sV1.Node) error {
-	return er.invokeHandlers("NodeCreated", func(h Handler) error {
+	return er.invokeTrackedHandlers("NodeCreated", node, func(h Handler) error {
 		return h.NodeCreated(node) //nolint:wrapcheck  // Let the caller wrap it
 	})
 }
 
 func (er *Registry) NodeUpdated(node *k8sV1.Node) error {
-	return er.invokeHandlers("NodeUpdated", func(h Handler) error {
+	err := er.invokeTrackedHandlers("NodeUpdated", node, func(h Handler) error {
 		return h.NodeUpdated(node) //nolint:wrapcheck  // Let the caller wrap it
 	})
+
+	if err == nil {
+		er.forgetPendingEvents(node.Name, "NodeCreated")
+	}
+
+	return err
 }
 
 func (er *Registry) NodeRemoved(node *k8sV1.Node) error {
-	return er.invokeHandlers("NodeRemoved", func(h Handler) error {
+	er.forgetPendingEvents(node.Name, "NodeCreated", "NodeUpdated")
+
+	return er.invokeTrackedHandlers("NodeRemoved", node, func(h Handler) error {
 		return h.NodeRemoved(node) //nolint:wrapcheck  // Let the caller wrap it
 	})
 }
 
+func (er *Registry) Reconcile(handlerState HandlerState) error {
+	return er.invokeHandlers("Reconcile", func(h Handler) error {
+		return h.Reconcile(handlerState) //nolint:wrapcheck  // Let the caller wrap it
+	})
+}
+
 func (er *Registry) invokeHandlers(eventName string, invoke func(h Handler) error) error {
+	return er.invokeHandlersOf(eventName, er.eventHandlers, invoke)
+}
+
+// invokeTrackedHandlers invokes the handlers for an event, optionally associated with the given object, and records
+// the handlers that fail. If the same event, for the same version of the object, is subsequently retried, only the
+// handlers that previously failed are invoked. This prevents a failing handler from causing the event to be
+// re-processed by every other handler.
+func (er *Registry) invokeTrackedHandlers(eventName string, obj v1.Object, invoke func(h Handler) error) error {
+	key, version := pendingEventKey(eventName, obj)
+
+	handlers := er.eventHandlers
+
+	er.healthMutex.Lock()
+	pending, ok := er.pendingEvents[key]
+	er.healthMutex.Unlock()
+
+	if ok && pending.version == version {
+		handlers = []Handler{}
+
+		for _, h := range er.eventHandlers {
+			if pending.handlers.Has(h.GetName()) {
+				handlers = append(handlers, h)
+			}
+		}
+
+		logger.V(log.DEBUG).Infof("Retrying %q only for the previously failed handlers %v", key, pending.handlers.SortedList())
+	}
+
+	failed := set.New[string]()
+
+	err := er.invokeHandlersOf(eventName, handlers, func(h Handler) error {
+		err := invoke(h)
+		if err != nil {
+			failed.Insert(h.GetName())
+		}
+
+		return err
+	})
+
+	er.healthMutex.Lock()
+	defer er.healthMutex.Unlock()
+
+	if failed.Len() == 0 {
+		delete(er.pendingEvents, key)
+	} else {
+		er.pendingEvents[key] = &pendingEvent{version: version, handlers: failed}
+	}
+
+	er.updateHealthMetrics()
+
+	return err
+}
+
+// forgetPendingEvents discards the failed handlers tracked for the given events, associated with the named object,
+// as they're superseded by the event being processed. An Updated event that all the handlers processed successfully
+// supersedes the Created event for the same object.
+func (er *Registry) forgetPendingEvents(name string, eventNames ...string) {
+	er.healthMutex.Lock()
+	defer er.healthMutex.Unlock()
+
+	for _, eventName := range eventNames {
+		key := eventName
+		if name != "" {
+			key += "/" + name
+		}
+
+		delete(er.pendingEvents, key)
+	}
+
+	er.updateHealthMetrics()
+}
+
+func pendingEventKey(eventName string, obj v1.Object) (string, string) {
+	if obj == nil {
+		return eventName, ""
+	}
+
+	return eventName + "/" + obj.GetName(), obj.GetResourceVersion()
+}
+
+func (er *Registry) invokeHandlersOf(eventName string, handlers []Handler, invoke func(h Handler) error) error {
 	var errs []error
 
-	for _, h := range er.eventHandlers {
+	for _, h := range handlers {
 		err := invoke(h)
 		if err != nil {
 			errs = append(errs, errors.Wrapf(err, "%q returned error", h.GetName()))
+			er.recordError(h, eventName, err)
 		}
 	}
 
 	return errors.Wrapf(k8serrors.NewAggregate(errs), "%s failed", eventName)
 }
+
+func (er *Registry) recordError(h Handler, eventName string, err error) {
+	er.healthMutex.Lock()
+	defer er.healthMutex.Unlock()
+
+	er.lastErrors[h.GetName()] = &HandlerError{
+		Event: eventName,
+		Error: err.Error(),
+		Time:  time.Now(),
+	}
+
+	recordHandlerFailure(h.GetName(), eventName)
+}
+
+// pendingEventsFor returns the sorted keys of the events the named handler has failed to process. The healthMutex
+// must be held by the caller.
+func (er *Registry) pendingEventsFor(name string) []string {
+	var events []string
+
+	for key, pending := range er.pendingEvents {
+		if pending.handlers.Has(name) {
+			events = append(events, key)
+		}
+	}
+
+	sort.Strings(events)
+
+	return events
+}
+
+func (er *Registry) updateHealthMetrics() {
+	for _, h := range er.eventHandlers {
+		setHandlerHealthy(h.GetName(), len(er.pendingEventsFor(h.GetName())) == 0)
+	}
+}
+
+// HandlerStatuses returns the state owned by each registered handler along with its health. A handler is considered
+// unhealthy while it has events it failed to process.
+func (er *Registry) HandlerStatuses() []HandlerStatus {
+	er.healthMutex.Lock()
+	defer er.healthMutex.Unlock()
+
+	statuses := make([]HandlerStatus, 0, len(er.eventHandlers))
+
+	for _, h := range er.eventHandlers {
+		pending := er.pendingEventsFor(h.GetName())
+
+		statuses = append(statuses, HandlerStatus{
+			Name:         h.GetName(),
+			Healthy:      len(pending) == 0,
+			FailedEvents: pending,
+			Owned:        h.OwnedState(),
+			LastError:    er.lastErrors[h.GetName()],
+		})
+	}
+
+	return statuses
+}
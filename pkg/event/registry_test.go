@@ -104,6 +104,94 @@ var _ = Describe("Event Registry", func() {
 					}
 				}
 			})
+
+			It("should report the last error for the handler", func() {
+				matchingHandlers[0].FailOnEvent(testing.EvTransitionToGateway)
+				Expect(registry.TransitionToGateway()).ToNot(Succeed())
+
+				statuses := registry.HandlerStatuses()
+				Expect(statuses).To(HaveLen(len(matchingHandlers) + 1))
+
+				for i := range statuses {
+					if statuses[i].Name != matchingHandlers[0].Name {
+						Expect(statuses[i].Healthy).To(BeTrue())
+						Expect(statuses[i].LastError).To(BeNil())
+						continue
+					}
+
+					Expect(statuses[i].Healthy).To(BeFalse())
+					Expect(statuses[i].FailedEvents).To(Equal([]string{testing.EvTransitionToGateway}))
+					Expect(statuses[i].LastError).ToNot(BeNil())
+					Expect(statuses[i].LastError.Event).To(Equal(testing.EvTransitionToGateway))
+					Expect(statuses[i].LastError.Error).To(ContainSubstring("mock handler error"))
+				}
+			})
+		})
+
+		When("an event that a handler previously failed is retried", func() {
+			var endpoint *submV1.Endpoint
+
+			BeforeEach(func() {
+				endpoint = &submV1.Endpoint{ObjectMeta: v1meta.ObjectMeta{Name: "endpoint1", ResourceVersion: "1"}}
+
+				matchingHandlers[0].FailOnEvent(testing.EvRemoteEndpointCreated)
+				Expect(registry.RemoteEndpointCreated(endpoint)).ToNot(Succeed())
+
+				for i := 1; i < len(matchingHandlers); i++ {
+					Expect(allTestEvents).To(Receive())
+				}
+			})
+
+			It("should only invoke the failed handler", func() {
+				Expect(registry.RemoteEndpointCreated(endpoint)).To(Succeed())
+				Expect(allTestEvents).To(Receive(Equal(testing.TestEvent{
+					Handler:   matchingHandlers[0].Name,
+					Name:      testing.EvRemoteEndpointCreated,
+					Parameter: endpoint,
+				})))
+				Expect(allTestEvents).ToNot(Receive())
+
+				for _, status := range registry.HandlerStatuses() {
+					Expect(status.Healthy).To(BeTrue(), "Handler %q is not healthy", status.Name)
+				}
+			})
+
+			Context("with a new version of the object", func() {
+				It("should invoke all handlers", func() {
+					endpoint = endpoint.DeepCopy()
+					endpoint.ResourceVersion = "2"
+
+					Expect(registry.RemoteEndpointCreated(endpoint)).To(Succeed())
+
+					for _, h := range matchingHandlers {
+						Expect(allTestEvents).To(Receive(HaveField("Handler", h.Name)))
+					}
+				})
+			})
+
+			Context("after an update of the object was processed by all handlers", func() {
+				It("should no longer report the handler as unhealthy", func() {
+					endpoint = endpoint.DeepCopy()
+					endpoint.ResourceVersion = "2"
+
+					Expect(registry.RemoteEndpointUpdated(endpoint)).To(Succeed())
+
+					for _, status := range registry.HandlerStatuses() {
+						Expect(status.Healthy).To(BeTrue(), "Handler %q is not healthy", status.Name)
+						Expect(status.FailedEvents).To(BeEmpty())
+					}
+				})
+			})
+
+			Context("after the object was removed", func() {
+				It("should no longer report the handler as unhealthy", func() {
+					Expect(registry.RemoteEndpointRemoved(endpoint)).To(Succeed())
+
+					for _, status := range registry.HandlerStatuses() {
+						Expect(status.Healthy).To(BeTrue(), "Handler %q is not healthy", status.Name)
+					}
+				})
+			})
 		})
 
 		When("the RemoteEndpointCreated notification is fired out of order", func() {
@@ -165,6 +253,7 @@ var _ = Describe("Event Registry", func() {
 func allEvents(registry *event.Registry) map[testing.TestEvent]func() error {
 	endpoint := &submV1.Endpoint{ObjectMeta: v1meta.ObjectMeta{Name: "endpoint1"}}
 	node := &k8sV1.Node{ObjectMeta: v1meta.ObjectMeta{Name: "node1"}}
+	state := &testing.TestHandlerState{}
 
 	return map[testing.TestEvent]func() error{
 		{Name: testing.EvStop}:                                       func() error { return registry.StopHandlers() },
@@ -180,5 +269,6 @@ func allEvents(registry *event.Registry) map[testing.TestEvent]func() error {
 		{Name: testing.EvRemoteEndpointCreated, Parameter: endpoint}: func() error { return registry.RemoteEndpointCreated(endpoint) },
 		{Name: testing.EvRemoteEndpointUpdated, Parameter: endpoint}: func() error { return registry.RemoteEndpointUpdated(endpoint) },
 		{Name: testing.EvRemoteEndpointRemoved, Parameter: endpoint}: func() error { return registry.RemoteEndpointRemoved(endpoint) },
+		{Name: testing.EvReconcile, Parameter: state}:                func() error { return registry.Reconcile(state) },
 	}
 }
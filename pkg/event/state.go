@@ -0,0 +1,45 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+import "time"
+
+// OwnedState describes the data path entries a handler believes it has programmed on the node.
+type OwnedState struct {
+	Routes       []string            `json:"routes,omitempty"`
+	Rules        []string            `json:"rules,omitempty"`
+	IPSetEntries map[string][]string `json:"ipsetEntries,omitempty"`
+}
+
+// HandlerError records the last error returned by a handler and the event that triggered it.
+type HandlerError struct {
+	Event string    `json:"event"`
+	Error string    `json:"error"`
+	Time  time.Time `json:"time"`
+}
+
+// HandlerStatus reports the state and health of a registered handler. A handler is healthy if it has no
+// outstanding FailedEvents.
+type HandlerStatus struct {
+	Name         string        `json:"name"`
+	Healthy      bool          `json:"healthy"`
+	FailedEvents []string      `json:"failedEvents,omitempty"`
+	Owned        OwnedState    `json:"owned"`
+	LastError    *HandlerError `json:"lastError,omitempty"`
+}
@@ -21,6 +21,7 @@ package testing
 import (
 	"context"
 	"os"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -47,9 +48,11 @@ func init() {
 }
 
 type ControllerSupport struct {
-	Hostname  string
-	endpoints dynamic.ResourceInterface
-	nodes     dynamic.ResourceInterface
+	Hostname          string
+	ReconcileInterval time.Duration
+	Controller        *controller.Controller
+	endpoints         dynamic.ResourceInterface
+	nodes             dynamic.ResourceInterface
 }
 
 func NewControllerSupport() *ControllerSupport {
@@ -66,9 +69,10 @@ func (c *ControllerSupport) Start(handler event.Handler) {
 	Expect(err).To(Succeed())
 
 	config := controller.Config{
-		RestMapper: test.GetRESTMapperFor(&corev1.Node{}, &submV1.Endpoint{}),
-		Client:     dynamicfake.NewSimpleDynamicClient(scheme.Scheme),
-		Registry:   registry,
+		RestMapper:        test.GetRESTMapperFor(&corev1.Node{}, &submV1.Endpoint{}),
+		Client:            dynamicfake.NewSimpleDynamicClient(scheme.Scheme),
+		Registry:          registry,
+		ReconcileInterval: c.ReconcileInterval,
 	}
 
 	os.Setenv("SUBMARINER_NAMESPACE", Namespace)
@@ -77,14 +81,14 @@ func (c *ControllerSupport) Start(handler event.Handler) {
 	c.nodes = config.Client.Resource(*test.GetGroupVersionResourceFor(config.RestMapper, &corev1.Node{}))
 	c.endpoints = config.Client.Resource(*test.GetGroupVersionResourceFor(config.RestMapper, &submV1.Endpoint{})).Namespace(Namespace)
 
-	eventController, err := controller.New(&config)
+	c.Controller, err = controller.New(&config)
 
 	Expect(err).To(Succeed())
-	Expect(eventController.Start(stopCh)).To(Succeed())
+	Expect(c.Controller.Start(stopCh)).To(Succeed())
 
 	DeferCleanup(func() {
 		close(stopCh)
-		eventController.Stop()
+		c.Controller.Stop()
 	})
 }
 
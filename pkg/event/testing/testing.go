@@ -35,13 +35,18 @@ type TestEvent struct {
 
 type TestHandlerState struct {
 	event.DefaultHandlerState
-	Gateway bool
+	Gateway         bool
+	RemoteEndpoints []v1.Endpoint
 }
 
 func (c *TestHandlerState) IsOnGateway() bool {
 	return c.Gateway
 }
 
+func (c *TestHandlerState) GetRemoteEndpoints() []v1.Endpoint {
+	return c.RemoteEndpoints
+}
+
 type TestHandler struct {
 	event.HandlerBase
 	Name          string
@@ -111,6 +116,7 @@ const (
 	EvNodeRemoved            = "NodeRemoved"
 	EvStop                   = "Stop"
 	EvUninstall              = "Uninstall"
+	EvReconcile              = "Reconcile"
 )
 
 func (t *TestHandler) Stop() error {
@@ -164,3 +170,7 @@ func (t *TestHandler) NodeUpdated(node *v12.Node) error {
 func (t *TestHandler) NodeRemoved(node *v12.Node) error {
 	return t.addEvent(EvNodeRemoved, node)
 }
+
+func (t *TestHandler) Reconcile(state event.HandlerState) error {
+	return t.addEvent(EvReconcile, state)
+}
@@ -37,10 +37,12 @@ import (
 	"github.com/submariner-io/submariner/pkg/cableengine/syncer"
 	"github.com/submariner-io/submariner/pkg/cidr"
 	submclientset "github.com/submariner-io/submariner/pkg/client/clientset/versioned"
+	"github.com/submariner-io/submariner/pkg/controllers/connectivity"
 	"github.com/submariner-io/submariner/pkg/controllers/datastoresyncer"
 	"github.com/submariner-io/submariner/pkg/controllers/tunnel"
 	"github.com/submariner-io/submariner/pkg/endpoint"
 	"github.com/submariner-io/submariner/pkg/natdiscovery"
+	"github.com/submariner-io/submariner/pkg/peering"
 	"github.com/submariner-io/submariner/pkg/pod"
 	"github.com/submariner-io/submariner/pkg/types"
 	"github.com/submariner-io/submariner/pkg/versions"
@@ -48,6 +50,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"k8s.io/client-go/tools/record"
@@ -140,6 +143,13 @@ func New(config *Config) (Interface, error) {
 
 	g.Spec.CableDriver = strings.ToLower(g.Spec.CableDriver)
 
+	// The libreswan IPsec policies only cover the subnets of the clusters at each end of a cable, so traffic relayed through a
+	// transit gateway would be dropped. Creating child SAs for every pair of transit subnets isn't supported.
+	if (g.Spec.TransitEnabled || len(g.Spec.TransitClusters) > 0) && g.Spec.CableDriver == "libreswan" {
+		return nil, errors.New("transit routing isn't supported by the libreswan cable driver whose IPsec policies only cover" +
+			" the local subnets - use a route-based cable driver such as vxlan or wireguard")
+	}
+
 	g.airGapped = os.Getenv("AIR_GAPPED_DEPLOYMENT") == "true"
 	logger.Infof("AIR_GAPPED_DEPLOYMENT is set to %t", g.airGapped)
 
@@ -148,7 +158,15 @@ func New(config *Config) (Interface, error) {
 		return nil, errors.Wrap(err, "error creating local endpoint object")
 	}
 
+	g.localEndpoint.Spec.Capabilities.Backends = cable.GetDriverNames()
+
+	peeringPolicy, err := peering.NewPolicy(g.Spec.PeeringAllowedClusters, g.Spec.PeeringDeniedClusters, g.Spec.PeeringClusterSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the peering policy")
+	}
+
 	g.cableEngine = g.NewCableEngine(localCluster, g.localEndpoint)
+	g.cableEngine.SetPeeringPolicy(peeringPolicy)
 
 	g.natDiscovery, err = g.NewNATDiscovery(g.localEndpoint)
 	if err != nil {
@@ -159,8 +177,6 @@ func New(config *Config) (Interface, error) {
 
 	g.SyncerConfig.LocalNamespace = g.Spec.Namespace
 
-	g.datastoreSyncer = datastoresyncer.New(&g.SyncerConfig, localCluster, g.localEndpoint)
-
 	g.initCableHealthChecker()
 
 	g.cableEngineSyncer = syncer.NewGatewaySyncer(
@@ -170,8 +186,18 @@ func New(config *Config) (Interface, error) {
 
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartLogging(logger.V(log.DEBUG).Infof)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: g.KubeClient.CoreV1().Events("")})
 	g.recorder = eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "submariner-controller"})
 
+	g.datastoreSyncer = datastoresyncer.New(&g.SyncerConfig, localCluster, g.localEndpoint, peeringPolicy, g.Spec.TransitEnabled,
+		g.Spec.TransitClusters, time.Duration(g.Spec.StaleEndpointTTL)*time.Second,
+		&datastoresyncer.StatusReporter{
+			Recorder:             g.recorder,
+			SetCondition:         g.cableEngineSyncer.SetCondition,
+			SetExcludedEndpoints: g.cableEngineSyncer.SetExcludedEndpoints,
+			SetTransitRoutes:     g.cableEngineSyncer.SetTransitRoutes,
+		})
+
 	return g, nil
 }
 
@@ -203,6 +229,15 @@ func (g *gatewayType) Run(ctx context.Context) error {
 		g.cableEngineSyncer.Run(ctx.Done())
 	})
 
+	// Every gateway pod maintains the connectivity summary so it still reflects the loss of the active gateway.
+	watcherConfig := g.WatcherConfig
+
+	err = connectivity.StartController(g.SubmarinerClient.SubmarinerV1().ConnectivitySummaries(), g.Spec.ClusterID,
+		g.Spec.Namespace, &watcherConfig, ctx.Done())
+	if err != nil {
+		return errors.Wrap(err, "error starting the connectivity summary controller")
+	}
+
 	if !g.airGapped {
 		g.initPublicIPWatcher()
 	}
@@ -371,6 +406,7 @@ func (g *gatewayType) initCableHealthChecker() {
 			ClusterID:          g.Spec.ClusterID,
 			PingInterval:       g.Spec.HealthCheckInterval,
 			MaxPacketLossCount: g.Spec.HealthCheckMaxPacketLossCount,
+			PathMTUInterval:    g.Spec.PathMTUDiscoveryInterval,
 		})
 		if err != nil {
 			logger.Errorf(err, "Error creating healthChecker")
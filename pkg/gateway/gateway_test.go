@@ -391,7 +391,7 @@ func (t *testDriver) newRemoteEndpoint() *submarinerv1.Endpoint {
 			Subnets:   []string{"169.254.3.0/24"},
 			PrivateIP: "11.1.2.3",
 			PublicIP:  "ipv4:12.1.2.3",
-			Backend:   "libreswan",
+			Backend:   fakecable.DriverName,
 		},
 	}
 
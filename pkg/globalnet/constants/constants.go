@@ -25,15 +25,21 @@ const (
 	SmGlobalnetIngressChain = "SUBMARINER-GN-INGRESS"
 	SmGlobalnetEgressChain  = "SUBMARINER-GN-EGRESS"
 	SmGlobalnetMarkChain    = "SUBMARINER-GN-MARK"
+	SmGlobalnetForwardChain = "SUBMARINER-GN-FORWARD"
 
 	// The following chains are added as part of GN 2.0 implementation.
 	SmGlobalnetEgressChainForPods            = "SM-GN-EGRESS-PODS"
 	SmGlobalnetEgressChainForHeadlessSvcPods = "SM-GN-EGRESS-HDLS-PODS"
 	SmGlobalnetEgressChainForHeadlessSvcEPs  = "SM-GN-EGRESS-HDLS-EPS"
 	SmGlobalnetEgressChainForNamespace       = "SM-GN-EGRESS-NS"
+	SmGlobalnetEgressChainForSelectors       = "SM-GN-EGRESS-SEL"
 	SmGlobalnetEgressChainForCluster         = "SM-GN-EGRESS-CLUSTER"
 
-	NATTable = "nat"
+	// Chain in the filter table used for the per GlobalEgressIP accounting and limits.
+	SmGlobalnetEgressChainForLimits = "SM-GN-EGRESS-LIMIT"
+
+	NATTable    = "nat"
+	FilterTable = "filter"
 
 	SmGlobalIP = "submariner.io/globalIp"
 )
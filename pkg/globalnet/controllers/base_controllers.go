@@ -41,6 +41,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 )
 
 const maxRequeues = 20
@@ -61,11 +62,13 @@ func newBaseSyncerController() *baseSyncerController {
 	}
 }
 
-func newBaseIPAllocationController(pool *ipam.IPPool, iptIface iptiface.Interface) *baseIPAllocationController {
+func newBaseIPAllocationController(pool *ipam.IPPool, iptIface iptiface.Interface, recorder record.EventRecorder,
+) *baseIPAllocationController {
 	return &baseIPAllocationController{
 		baseSyncerController: newBaseSyncerController(),
 		pool:                 pool,
 		iptIface:             iptIface,
+		recorder:             recorder,
 	}
 }
 
@@ -185,6 +188,50 @@ func (c *baseIPAllocationController) flushRulesAndReleaseIPs(key string, numRequ
 	return false
 }
 
+// checkPoolUsage raises a Warning Event and sets the PoolExhaustion condition, if conditions is non-nil, on the given
+// object when its global IP allocation failed or left the pool usage at or above a configured threshold. The condition
+// is cleared once an allocation succeeds with the pool usage below all thresholds.
+func (c *baseIPAllocationController) checkPoolUsage(obj runtime.Object, conditions *[]metav1.Condition, allocErr error) {
+	var reason, message string
+
+	if allocErr != nil {
+		reason = poolExhaustedReason
+		message = fmt.Sprintf("The global IP pool %s is exhausted: %v", c.pool.GetCIDR(), allocErr)
+	} else if threshold := c.pool.ReachedThreshold(); threshold > 0 {
+		reason = poolThresholdReachedReason
+		message = fmt.Sprintf("The global IP pool %s is %d%% allocated, reaching the %d%% threshold", c.pool.GetCIDR(),
+			c.pool.Usage(), threshold)
+	}
+
+	if reason == "" {
+		if conditions != nil && meta.FindStatusCondition(*conditions, string(submarinerv1.GlobalIPPoolExhaustion)) != nil {
+			meta.SetStatusCondition(conditions, metav1.Condition{
+				Type:    string(submarinerv1.GlobalIPPoolExhaustion),
+				Status:  metav1.ConditionFalse,
+				Reason:  "PoolAvailable",
+				Message: fmt.Sprintf("The global IP pool %s is below the usage thresholds", c.pool.GetCIDR()),
+			})
+		}
+
+		return
+	}
+
+	logger.Warningf("%s", message)
+
+	if c.recorder != nil {
+		c.recorder.Event(obj, corev1.EventTypeWarning, reason, message)
+	}
+
+	if conditions != nil {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:    string(submarinerv1.GlobalIPPoolExhaustion),
+			Status:  metav1.ConditionTrue,
+			Reason:  reason,
+			Message: message,
+		})
+	}
+}
+
 func shouldRequeue(numRequeues int) bool {
 	return numRequeues < maxRequeues
 }
@@ -271,6 +318,13 @@ func GetInternalSvcName(name string) string {
 	return strings.ToLower(svcName)
 }
 
+func getIPSetName(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	encoded := base32.StdEncoding.EncodeToString(hash[:])
+	// Max length of IPSet name can be 31
+	return IPSetPrefix + encoded[:25]
+}
+
 func deleteEndpoints(namespace, name string,
 	client dynamic.NamespaceableResourceInterface,
 ) error {
@@ -27,36 +27,52 @@ import (
 	"github.com/submariner-io/admiral/pkg/log"
 	"github.com/submariner-io/admiral/pkg/syncer"
 	"github.com/submariner-io/admiral/pkg/util"
+	"github.com/submariner-io/admiral/pkg/watcher"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/globalnet/constants"
 	"github.com/submariner-io/submariner/pkg/globalnet/controllers/iptables"
 	"github.com/submariner-io/submariner/pkg/globalnet/metrics"
 	"github.com/submariner-io/submariner/pkg/ipam"
+	"github.com/submariner-io/submariner/pkg/ipset"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 )
 
 func NewClusterGlobalEgressIPController(config *syncer.ResourceSyncerConfig, localSubnets []string,
-	pool *ipam.IPPool,
+	pool *ipam.IPPool, recorder record.EventRecorder, dataPathBackend string,
 ) (Interface, error) {
 	// We'll panic if config is nil, this is intentional
 	var err error
 
 	logger.Info("Creating ClusterGlobalEgressIP controller")
 
-	iptIface, err := iptables.New()
+	iptIface, err := iptables.New(dataPathBackend)
 	if err != nil {
 		return nil, errors.WithMessage(err, "error creating the IPTablesInterface handler")
 	}
 
 	controller := &clusterGlobalEgressIPController{
-		baseIPAllocationController: newBaseIPAllocationController(pool, iptIface),
+		baseIPAllocationController: newBaseIPAllocationController(pool, iptIface, recorder),
 		localSubnets:               localSubnets,
+		podWatchers:                map[string]*egressPodWatcher{},
+		watcherConfig: watcher.Config{
+			RestMapper: config.RestMapper,
+			Client:     config.SourceClient,
+			Scheme:     config.Scheme,
+		},
+	}
+
+	controller.ipSetIface, err = iptables.NewIPSet(dataPathBackend)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error creating the IPSet handler")
 	}
 
 	federator := federate.NewUpdateStatusFederator(config.SourceClient, config.RestMapper, corev1.NamespaceAll)
@@ -99,6 +115,11 @@ func NewClusterGlobalEgressIPController(config *syncer.ResourceSyncerConfig, loc
 		}
 	}
 
+	err = controller.reserveSelectorAllocatedIPs(federator, client)
+	if err != nil {
+		return nil, err
+	}
+
 	controller.resourceSyncer, err = syncer.NewResourceSyncer(&syncer.ResourceSyncerConfig{
 		Name:                "ClusterGlobalEgressIP syncer",
 		ResourceType:        &submarinerv1.ClusterGlobalEgressIP{},
@@ -118,6 +139,46 @@ func NewClusterGlobalEgressIPController(config *syncer.ResourceSyncerConfig, loc
 	return controller, nil
 }
 
+// reserveSelectorAllocatedIPs reserves the IPs previously allocated for the ClusterGlobalEgressIPs with selectors and
+// reprograms their rules.
+func (c *clusterGlobalEgressIPController) reserveSelectorAllocatedIPs(federator federate.Federator,
+	client dynamic.ResourceInterface,
+) error {
+	list, err := client.List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "error listing the resources")
+	}
+
+	for i := range list.Items {
+		if list.Items[i].GetName() == constants.ClusterGlobalEgressIPName {
+			continue
+		}
+
+		key, _ := cache.MetaNamespaceKeyFunc(&list.Items[i])
+
+		err = c.reserveAllocatedIPs(federator, &list.Items[i], func(reservedIPs []string) error {
+			metrics.RecordAllocateClusterGlobalEgressIPs(c.pool.GetCIDR(), len(reservedIPs))
+			return c.programSelectorEgressRules(key, reservedIPs, c.newNamedIPSet(key))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *clusterGlobalEgressIPController) Stop() {
+	c.baseController.Stop()
+
+	c.Lock()
+	defer c.Unlock()
+
+	for _, podWatcher := range c.podWatchers {
+		close(podWatcher.stopCh)
+	}
+}
+
 func (c *clusterGlobalEgressIPController) process(from runtime.Object, numRequeues int, op syncer.Operation) (runtime.Object, bool) {
 	clusterGlobalEgressIP := from.(*submarinerv1.ClusterGlobalEgressIP)
 
@@ -126,8 +187,9 @@ func (c *clusterGlobalEgressIPController) process(from runtime.Object, numRequeu
 		numberOfIPs = *clusterGlobalEgressIP.Spec.NumberOfIPs
 	}
 
-	logger.Infof("Processing %sd ClusterGlobalEgressIP %q, Spec.NumberOfIPs: %d, Status: %#v", op, clusterGlobalEgressIP.Name,
-		numberOfIPs, clusterGlobalEgressIP.Status)
+	logger.Infof("Processing %sd ClusterGlobalEgressIP %q, Spec.NumberOfIPs: %d, PodSelector: %#v, NamespaceSelector: %#v, "+
+		"Status: %#v", op, clusterGlobalEgressIP.Name, numberOfIPs, clusterGlobalEgressIP.Spec.PodSelector,
+		clusterGlobalEgressIP.Spec.NamespaceSelector, clusterGlobalEgressIP.Status)
 
 	key, _ := cache.MetaNamespaceKeyFunc(clusterGlobalEgressIP)
 
@@ -138,13 +200,35 @@ func (c *clusterGlobalEgressIPController) process(from runtime.Object, numRequeu
 		trimAllocatedStatusCondition(&clusterGlobalEgressIP.Status.Conditions)
 
 		if !c.validate(numberOfIPs, clusterGlobalEgressIP) {
-			return checkStatusChanged(&prevStatus, &clusterGlobalEgressIP.Status, clusterGlobalEgressIP), false
+			var requeue bool
+
+			// Instances other than the well-known one are only programmed with their selectors so, if an update invalidated
+			// them, e.g. by removing the selectors, the previously allocated resources are released.
+			if clusterGlobalEgressIP.Name != constants.ClusterGlobalEgressIPName {
+				requeue = c.onSelectorDelete(key, clusterGlobalEgressIP, numRequeues)
+				if !requeue {
+					clusterGlobalEgressIP.Status.AllocatedIPs = nil
+				}
+			}
+
+			return checkStatusChanged(&prevStatus, &clusterGlobalEgressIP.Status, clusterGlobalEgressIP), requeue
 		}
 
-		requeue := c.onCreateOrUpdate(key, numberOfIPs, &clusterGlobalEgressIP.Status, numRequeues)
+		var requeue bool
+
+		if hasSelectors(clusterGlobalEgressIP) {
+			requeue = c.onSelectorCreateOrUpdate(key, numberOfIPs, clusterGlobalEgressIP, numRequeues)
+		} else {
+			requeue = c.onCreateOrUpdate(key, numberOfIPs, clusterGlobalEgressIP, numRequeues)
+		}
 
 		return checkStatusChanged(&prevStatus, &clusterGlobalEgressIP.Status, clusterGlobalEgressIP), requeue
 	case syncer.Delete:
+		// The selectors may have been removed by a prior update so the well-known name determines how it was programmed.
+		if clusterGlobalEgressIP.Name != constants.ClusterGlobalEgressIPName {
+			return nil, c.onSelectorDelete(key, clusterGlobalEgressIP, numRequeues)
+		}
+
 		return nil, c.onDelete(key, &clusterGlobalEgressIP.Status, numRequeues)
 	}
 
@@ -152,18 +236,42 @@ func (c *clusterGlobalEgressIPController) process(from runtime.Object, numRequeu
 }
 
 func (c *clusterGlobalEgressIPController) validate(numberOfIPs int, egressIP *submarinerv1.ClusterGlobalEgressIP) bool {
-	if egressIP.Name != constants.ClusterGlobalEgressIPName {
+	if egressIP.Name != constants.ClusterGlobalEgressIPName && !hasSelectors(egressIP) {
 		meta.SetStatusCondition(&egressIP.Status.Conditions, metav1.Condition{
 			Type:   string(submarinerv1.GlobalEgressIPAllocated),
 			Status: metav1.ConditionFalse,
 			Reason: "InvalidInstance",
-			Message: fmt.Sprintf("Only the ClusterGlobalEgressIP instance with the well-known name %q is supported",
-				constants.ClusterGlobalEgressIPName),
+			Message: fmt.Sprintf("Only the ClusterGlobalEgressIP instance with the well-known name %q is supported "+
+				"without a PodSelector or NamespaceSelector", constants.ClusterGlobalEgressIPName),
+		})
+
+		return false
+	}
+
+	if egressIP.Name == constants.ClusterGlobalEgressIPName && hasSelectors(egressIP) {
+		meta.SetStatusCondition(&egressIP.Status.Conditions, metav1.Condition{
+			Type:    string(submarinerv1.GlobalEgressIPAllocated),
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidInput",
+			Message: "The PodSelector and NamespaceSelector are not supported for the well-known instance",
 		})
 
 		return false
 	}
 
+	for _, selector := range []*metav1.LabelSelector{egressIP.Spec.PodSelector, egressIP.Spec.NamespaceSelector} {
+		if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+			meta.SetStatusCondition(&egressIP.Status.Conditions, metav1.Condition{
+				Type:    string(submarinerv1.GlobalEgressIPAllocated),
+				Status:  metav1.ConditionFalse,
+				Reason:  "InvalidInput",
+				Message: fmt.Sprintf("Invalid label selector: %v", err),
+			})
+
+			return false
+		}
+	}
+
 	if numberOfIPs < 0 {
 		meta.SetStatusCondition(&egressIP.Status.Conditions, metav1.Condition{
 			Type:    string(submarinerv1.GlobalEgressIPAllocated),
@@ -187,9 +295,11 @@ func (c *clusterGlobalEgressIPController) validate(numberOfIPs int, egressIP *su
 	return true
 }
 
-func (c *clusterGlobalEgressIPController) onCreateOrUpdate(key string, numberOfIPs int, status *submarinerv1.GlobalEgressIPStatus,
-	numRequeues int,
+func (c *clusterGlobalEgressIPController) onCreateOrUpdate(key string, numberOfIPs int,
+	egressIP *submarinerv1.ClusterGlobalEgressIP, numRequeues int,
 ) bool {
+	status := &egressIP.Status
+
 	if numberOfIPs == len(status.AllocatedIPs) {
 		logger.V(log.DEBUG).Infof("Update called for %q, but numberOfIPs %d are already allocated", key, numberOfIPs)
 		return false
@@ -200,7 +310,7 @@ func (c *clusterGlobalEgressIPController) onCreateOrUpdate(key string, numberOfI
 		return true
 	}
 
-	return c.allocateGlobalIPs(key, numberOfIPs, status)
+	return c.allocateGlobalIPs(key, numberOfIPs, egressIP, c.programClusterGlobalEgressRules)
 }
 
 func (c *clusterGlobalEgressIPController) onDelete(key string, status *submarinerv1.GlobalEgressIPStatus, numRequeues int) bool {
@@ -240,9 +350,148 @@ func (c *clusterGlobalEgressIPController) programClusterGlobalEgressRules(alloca
 	return nil
 }
 
-func (c *clusterGlobalEgressIPController) allocateGlobalIPs(key string, numberOfIPs int, status *submarinerv1.GlobalEgressIPStatus) bool {
+func (c *clusterGlobalEgressIPController) onSelectorCreateOrUpdate(key string, numberOfIPs int,
+	egressIP *submarinerv1.ClusterGlobalEgressIP, numRequeues int,
+) bool {
+	namedIPSet := c.newNamedIPSet(key)
+
+	if numberOfIPs != len(egressIP.Status.AllocatedIPs) {
+		if requeue := c.flushRulesAndReleaseIPs(key, numRequeues, c.selectorEgressRulesFlusher(key, namedIPSet.Name()),
+			egressIP.Status.AllocatedIPs...); requeue {
+			return true
+		}
+
+		if requeue := c.allocateGlobalIPs(key, numberOfIPs, egressIP, func(allocatedIPs []string) error {
+			return c.programSelectorEgressRules(key, allocatedIPs, namedIPSet)
+		}); requeue {
+			return true
+		}
+	}
+
+	return !c.createPodWatcher(key, namedIPSet, numberOfIPs, egressIP)
+}
+
+func (c *clusterGlobalEgressIPController) onSelectorDelete(key string, egressIP *submarinerv1.ClusterGlobalEgressIP,
+	numRequeues int,
+) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	podWatcher, found := c.podWatchers[key]
+	if found {
+		close(podWatcher.stopCh)
+		delete(c.podWatchers, key)
+
+		if len(egressIP.Status.AllocatedIPs) == 0 {
+			egressIP.Status.AllocatedIPs = podWatcher.allocatedIPs
+		}
+	}
+
+	namedIPSet := c.newNamedIPSet(key)
+
+	if requeue := c.flushRulesAndReleaseIPs(key, numRequeues, c.selectorEgressRulesFlusher(key, namedIPSet.Name()),
+		egressIP.Status.AllocatedIPs...); requeue {
+		return true
+	}
+
+	if err := namedIPSet.Destroy(); err != nil {
+		logger.Errorf(err, "Error destroying the ipSet %q for %q", namedIPSet.Name(), key)
+		return shouldRequeue(numRequeues)
+	}
+
+	return false
+}
+
+func (c *clusterGlobalEgressIPController) selectorEgressRulesFlusher(key, ipSetName string) func(allocatedIPs []string) error {
+	return func(allocatedIPs []string) error {
+		metrics.RecordDeallocateClusterGlobalEgressIPs(c.pool.GetCIDR(), len(allocatedIPs))
+
+		//nolint:wrapcheck  // Let the caller wrap it
+		return c.iptIface.RemoveEgressRulesForSelectors(key, ipSetName, getTargetSNATIPaddress(allocatedIPs), globalNetIPTableMark)
+	}
+}
+
+func (c *clusterGlobalEgressIPController) programSelectorEgressRules(key string, allocatedIPs []string, namedIPSet ipset.Named) error {
+	if err := namedIPSet.Create(true); err != nil {
+		return errors.Wrapf(err, "error creating the IP set chain %q", namedIPSet.Name())
+	}
+
+	snatIP := getTargetSNATIPaddress(allocatedIPs)
+
+	if err := c.iptIface.AddEgressRulesForSelectors(key, namedIPSet.Name(), snatIP, globalNetIPTableMark); err != nil {
+		_ = c.iptIface.RemoveEgressRulesForSelectors(key, namedIPSet.Name(), snatIP, globalNetIPTableMark)
+		return err //nolint:wrapcheck  // Let the caller wrap it
+	}
+
+	return nil
+}
+
+func (c *clusterGlobalEgressIPController) createPodWatcher(key string, namedIPSet ipset.Named, numberOfIPs int,
+	egressIP *submarinerv1.ClusterGlobalEgressIP,
+) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	prevPodWatcher, found := c.podWatchers[key]
+	if found {
+		if equality.Semantic.DeepEqual(prevPodWatcher.podSelector, egressIP.Spec.PodSelector) &&
+			equality.Semantic.DeepEqual(prevPodWatcher.namespaceSelector, egressIP.Spec.NamespaceSelector) {
+			prevPodWatcher.allocatedIPs = egressIP.Status.AllocatedIPs
+			return true
+		}
+
+		logger.Infof("The selectors for %q were updated - restarting its pod watcher", key)
+
+		// The IP set is flushed once the previous watcher is stopped so the new watcher only adds the pods now selected.
+		if err := prevPodWatcher.stop(namedIPSet.Flush); err != nil {
+			logger.Errorf(err, "Error flushing the IP set %q for %q", namedIPSet.Name(), key)
+			return false
+		}
+
+		delete(c.podWatchers, key)
+	}
+
+	if numberOfIPs == 0 {
+		return true
+	}
+
+	podWatcher, err := startEgressPodWatcher(key, corev1.NamespaceAll, namedIPSet, &c.watcherConfig,
+		egressIP.Spec.PodSelector, egressIP.Spec.NamespaceSelector)
+	if err != nil {
+		logger.Errorf(err, "Error starting pod watcher for %q", key)
+		return false
+	}
+
+	c.podWatchers[key] = podWatcher
+	podWatcher.podSelector = egressIP.Spec.PodSelector
+	podWatcher.namespaceSelector = egressIP.Spec.NamespaceSelector
+	podWatcher.allocatedIPs = egressIP.Status.AllocatedIPs
+
+	logger.Infof("Started pod watcher for %q", key)
+
+	return true
+}
+
+func (c *clusterGlobalEgressIPController) newNamedIPSet(key string) ipset.Named {
+	return ipset.NewNamed(&ipset.IPSet{
+		Name:    getIPSetName(key),
+		SetType: ipset.HashIP,
+	}, c.ipSetIface)
+}
+
+// hasSelectors returns whether the ClusterGlobalEgressIP selects a subset of the pods in the cluster. Such instances
+// are programmed in a chain that takes precedence over the cluster-wide chain of the well-known instance.
+func hasSelectors(egressIP *submarinerv1.ClusterGlobalEgressIP) bool {
+	return egressIP.Spec.PodSelector != nil || egressIP.Spec.NamespaceSelector != nil
+}
+
+func (c *clusterGlobalEgressIPController) allocateGlobalIPs(key string, numberOfIPs int, egressIP *submarinerv1.ClusterGlobalEgressIP,
+	programRules func(allocatedIPs []string) error,
+) bool {
 	logger.Infof("Allocating %d global IP(s) for %q", numberOfIPs, key)
 
+	status := &egressIP.Status
+
 	status.AllocatedIPs = nil
 
 	if numberOfIPs == 0 {
@@ -260,10 +509,12 @@ func (c *clusterGlobalEgressIPController) allocateGlobalIPs(key string, numberOf
 			Message: fmt.Sprintf("Error allocating %d global IP(s) from the pool: %v", numberOfIPs, err),
 		})
 
+		c.checkPoolUsage(egressIP, &status.Conditions, err)
+
 		return true
 	}
 
-	err = c.programClusterGlobalEgressRules(allocatedIPs)
+	err = programRules(allocatedIPs)
 	if err != nil {
 		logger.Errorf(err, "Error programming egress IP table rules for %q", key)
 
@@ -288,6 +539,8 @@ func (c *clusterGlobalEgressIPController) allocateGlobalIPs(key string, numberOf
 		Message: fmt.Sprintf("Allocated %d global IP(s)", numberOfIPs),
 	})
 
+	c.checkPoolUsage(egressIP, &status.Conditions, nil)
+
 	status.AllocatedIPs = allocatedIPs
 
 	return false
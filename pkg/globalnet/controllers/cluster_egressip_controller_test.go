@@ -29,7 +29,9 @@ import (
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/globalnet/constants"
 	"github.com/submariner-io/submariner/pkg/globalnet/controllers"
+	iptiface "github.com/submariner-io/submariner/pkg/globalnet/controllers/iptables"
 	"github.com/submariner-io/submariner/pkg/ipam"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -336,6 +338,146 @@ var _ = Describe("ClusterGlobalEgressIP controller", func() {
 		})
 	})
 
+	When("a ClusterGlobalEgressIP with selectors is created", func() {
+		var (
+			egressIP *submarinerv1.ClusterGlobalEgressIP
+			pod      *corev1.Pod
+			ipSet    string
+		)
+
+		BeforeEach(func() {
+			egressIP = newClusterGlobalEgressIP("payments", 1)
+			egressIP.Spec.PodSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}}
+
+			pod = newPod("ns1")
+			pod.Labels = map[string]string{"team": "payments"}
+		})
+
+		JustBeforeEach(func() {
+			t.createClusterGlobalEgressIP(egressIP)
+			t.awaitEgressIPStatusAllocated(t.clusterGlobalEgressIPs, egressIP.Name, 1)
+			ipSet = t.awaitSelectorIPTableRules(getGlobalEgressIPStatus(t.clusterGlobalEgressIPs, egressIP.Name).AllocatedIPs...)
+			t.createPod(pod)
+		})
+
+		Context("and a Pod in any namespace matches the Pod selector", func() {
+			It("should add the Pod IP to the IP set", func() {
+				t.ipSet.AwaitEntry(ipSet, pod.Status.PodIP)
+			})
+		})
+
+		Context("and a Pod does not match the Pod selector", func() {
+			BeforeEach(func() {
+				pod.Labels = nil
+			})
+
+			It("should not add the Pod IP to the IP set", func() {
+				t.ipSet.AwaitNoEntry(ipSet, pod.Status.PodIP)
+			})
+		})
+
+		Context("with a Namespace selector", func() {
+			BeforeEach(func() {
+				egressIP.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"billing": "true"}}
+			})
+
+			Context("and the Pod's Namespace matches", func() {
+				BeforeEach(func() {
+					t.createNamespace(pod.Namespace, egressIP.Spec.NamespaceSelector.MatchLabels)
+				})
+
+				It("should add the Pod IP to the IP set", func() {
+					t.ipSet.AwaitEntry(ipSet, pod.Status.PodIP)
+				})
+			})
+
+			Context("and the Pod's Namespace does not match", func() {
+				BeforeEach(func() {
+					t.createNamespace(pod.Namespace, nil)
+				})
+
+				It("should not add the Pod IP to the IP set", func() {
+					t.ipSet.AwaitNoEntry(ipSet, pod.Status.PodIP)
+				})
+			})
+
+			Context("and the Pod's Namespace is created to match afterwards", func() {
+				It("should add the Pod IP to the IP set", func() {
+					t.ipSet.AwaitNoEntry(ipSet, pod.Status.PodIP)
+					t.createNamespace(pod.Namespace, egressIP.Spec.NamespaceSelector.MatchLabels)
+					t.ipSet.AwaitEntry(ipSet, pod.Status.PodIP)
+				})
+			})
+		})
+
+		Context("and then deleted", func() {
+			It("should release the allocated IPs and remove the IP table rules and IP set", func() {
+				allocatedIPs := getGlobalEgressIPStatus(t.clusterGlobalEgressIPs, egressIP.Name).AllocatedIPs
+
+				Expect(t.clusterGlobalEgressIPs.Delete(context.TODO(), egressIP.Name, metav1.DeleteOptions{})).To(Succeed())
+
+				t.awaitIPsReleasedFromPool(allocatedIPs...)
+				t.ipt.AwaitNoRule("nat", constants.SmGlobalnetEgressChainForSelectors, ContainSubstring(getSNATAddress(allocatedIPs...)))
+				t.ipSet.AwaitSetDeleted(ipSet)
+			})
+		})
+
+		Context("and its Pod selector is updated", func() {
+			It("should update the IP set with the Pods now selected", func() {
+				t.ipSet.AwaitEntry(ipSet, pod.Status.PodIP)
+
+				other := newPod("ns2")
+				other.Name = "other"
+				other.Labels = map[string]string{"team": "billing"}
+				other.Status.PodIP = "1.2.3.5"
+				t.createPod(other)
+
+				egressIP.Status = *getGlobalEgressIPStatus(t.clusterGlobalEgressIPs, egressIP.Name)
+				egressIP.Spec.PodSelector = &metav1.LabelSelector{MatchLabels: other.Labels}
+				test.UpdateResource(t.clusterGlobalEgressIPs, egressIP)
+
+				t.ipSet.AwaitEntry(ipSet, other.Status.PodIP)
+				t.ipSet.AwaitNoEntry(ipSet, pod.Status.PodIP)
+			})
+		})
+
+		Context("and its selectors are removed", func() {
+			It("should release the allocated IPs and remove the IP table rules and IP set", func() {
+				t.ipSet.AwaitEntry(ipSet, pod.Status.PodIP)
+
+				egressIP.Status = *getGlobalEgressIPStatus(t.clusterGlobalEgressIPs, egressIP.Name)
+				allocatedIPs := egressIP.Status.AllocatedIPs
+
+				egressIP.Spec.PodSelector = nil
+				test.UpdateResource(t.clusterGlobalEgressIPs, egressIP)
+
+				Eventually(func() []string {
+					return getGlobalEgressIPStatus(t.clusterGlobalEgressIPs, egressIP.Name).AllocatedIPs
+				}).Should(BeEmpty())
+
+				t.awaitIPsReleasedFromPool(allocatedIPs...)
+				t.ipt.AwaitNoRule("nat", constants.SmGlobalnetEgressChainForSelectors, ContainSubstring(getSNATAddress(allocatedIPs...)))
+				t.ipSet.AwaitSetDeleted(ipSet)
+			})
+		})
+	})
+
+	When("the well-known ClusterGlobalEgressIP has selectors", func() {
+		BeforeEach(func() {
+			existing := newClusterGlobalEgressIP(constants.ClusterGlobalEgressIPName, 1)
+			existing.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"billing": "true"}}
+			t.createClusterGlobalEgressIP(existing)
+		})
+
+		It("should add an appropriate Status condition", func() {
+			t.awaitEgressIPStatus(t.clusterGlobalEgressIPs, constants.ClusterGlobalEgressIPName, 0, metav1.Condition{
+				Type:   string(submarinerv1.GlobalEgressIPAllocated),
+				Status: metav1.ConditionFalse,
+				Reason: "InvalidInput",
+			})
+		})
+	})
+
 	When("a ClusterGlobalEgressIP is created without the well-known name", func() {
 		JustBeforeEach(func() {
 			t.createClusterGlobalEgressIP(newClusterGlobalEgressIP("other name", 1))
@@ -387,7 +529,7 @@ func (t *clusterGlobalEgressIPControllerTestDriver) start() {
 		SourceClient: t.dynClient,
 		RestMapper:   t.restMapper,
 		Scheme:       t.scheme,
-	}, t.localSubnets, t.pool)
+	}, t.localSubnets, t.pool, t.recorder, iptiface.IPTablesBackend)
 
 	Expect(err).To(Succeed())
 	Expect(t.controller.Start()).To(Succeed())
@@ -401,6 +543,14 @@ func (t *clusterGlobalEgressIPControllerTestDriver) awaitIPTableRules(ips ...str
 	}
 }
 
+func (t *clusterGlobalEgressIPControllerTestDriver) awaitSelectorIPTableRules(ips ...string) string {
+	set := t.ipSet.AwaitOneSet(HavePrefix(controllers.IPSetPrefix))
+	t.ipt.AwaitRule("nat", constants.SmGlobalnetEgressChainForSelectors, And(ContainSubstring(set),
+		ContainSubstring(getSNATAddress(ips...))))
+
+	return set
+}
+
 func (t *clusterGlobalEgressIPControllerTestDriver) awaitNoIPTableRules(ips ...string) {
 	t.ipt.AwaitNoRule("nat", constants.SmGlobalnetEgressChainForCluster, ContainSubstring(getSNATAddress(ips...)))
 }
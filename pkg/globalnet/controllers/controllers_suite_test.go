@@ -53,6 +53,7 @@ import (
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
 	mcsv1a1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
 )
 
@@ -100,19 +101,22 @@ type testDriverBase struct {
 	serviceExports         dynamic.ResourceInterface
 	endpoints              dynamic.ResourceInterface
 	pods                   dynamic.NamespaceableResourceInterface
+	namespaces             dynamic.ResourceInterface
 	nodes                  dynamic.ResourceInterface
 	watches                *fakeDynClient.WatchReactor
+	recorder               *record.FakeRecorder
 }
 
 func newTestDriverBase() *testDriverBase {
 	t := &testDriverBase{
 		restMapper: test.GetRESTMapperFor(&submarinerv1.Endpoint{}, &corev1.Service{}, &corev1.Node{}, &corev1.Pod{}, &corev1.Endpoints{},
-			&submarinerv1.GlobalEgressIP{}, &submarinerv1.ClusterGlobalEgressIP{}, &submarinerv1.GlobalIngressIP{}, &mcsv1a1.ServiceExport{}),
+			&corev1.Namespace{}, &submarinerv1.GlobalEgressIP{}, &submarinerv1.ClusterGlobalEgressIP{}, &submarinerv1.GlobalIngressIP{}, &mcsv1a1.ServiceExport{}),
 		scheme:       runtime.NewScheme(),
 		ipt:          fakeIPT.New(),
 		ipSet:        fakeIPSet.New(),
 		globalCIDR:   localCIDR,
 		localSubnets: []string{},
+		recorder:     record.NewFakeRecorder(1000),
 	}
 
 	Expect(mcsv1a1.AddToScheme(t.scheme)).To(Succeed())
@@ -132,6 +136,8 @@ func newTestDriverBase() *testDriverBase {
 
 	t.pods = t.dynClient.Resource(*test.GetGroupVersionResourceFor(t.restMapper, &corev1.Pod{}))
 
+	t.namespaces = t.dynClient.Resource(*test.GetGroupVersionResourceFor(t.restMapper, &corev1.Namespace{}))
+
 	t.endpoints = t.dynClient.Resource(*test.GetGroupVersionResourceFor(t.restMapper, &corev1.Endpoints{})).Namespace(namespace)
 
 	t.services = t.dynClient.Resource(*test.GetGroupVersionResourceFor(t.restMapper, &corev1.Service{})).Namespace(namespace)
@@ -202,6 +208,15 @@ func (t *testDriverBase) deletePod(p *corev1.Pod) {
 	Expect(t.pods.Namespace(p.Namespace).Delete(context.TODO(), p.Name, metav1.DeleteOptions{})).To(Succeed())
 }
 
+func (t *testDriverBase) createNamespace(name string, labels map[string]string) *corev1.Namespace {
+	return test.CreateResource(t.namespaces, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+	})
+}
+
 func (t *testDriverBase) createEndpoints(ep *corev1.Endpoints) *corev1.Endpoints {
 	test.CreateResource(t.endpoints, ep)
 	return ep
@@ -283,6 +298,11 @@ func (t *testDriverBase) awaitNoNodeGlobalIP() {
 	}, 500*time.Millisecond).Should(BeEmpty())
 }
 
+func (t *testDriverBase) awaitWarningEvent(reason string) {
+	Eventually(t.recorder.Events, 5).Should(Receive(HavePrefix(corev1.EventTypeWarning+" "+reason)),
+		"Warning Event with reason %q", reason)
+}
+
 func addAnnotation(obj metav1.Object, key, value string) {
 	if value == "" {
 		return
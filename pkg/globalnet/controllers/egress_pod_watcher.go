@@ -28,12 +28,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/cache"
 )
 
 func startEgressPodWatcher(name, namespace string, namedIPSet ipset.Named, config *watcher.Config,
-	podSelector *metav1.LabelSelector,
+	podSelector, namespaceSelector *metav1.LabelSelector,
 ) (*egressPodWatcher, error) {
 	pw := &egressPodWatcher{
 		stopCh:     make(chan struct{}),
@@ -47,30 +48,54 @@ func startEgressPodWatcher(name, namespace string, namedIPSet ipset.Named, confi
 
 	labelSelector := sel.String()
 
-	w, err := watcher.New(&watcher.Config{
-		RestMapper: config.RestMapper,
-		Client:     config.Client,
-		Scheme:     config.Scheme,
-		ResourceConfigs: []watcher.ResourceConfig{
-			{
-				Name:         fmt.Sprintf("Pod watcher %s", name),
-				ResourceType: &corev1.Pod{},
-				Handler: watcher.EventHandlerFuncs{
-					OnCreateFunc: pw.onCreateOrUpdate,
-					OnUpdateFunc: pw.onCreateOrUpdate,
-					OnDeleteFunc: pw.onDelete,
-				},
-				ResourcesEquivalent: pw.arePodsEquivalent,
-				SourceNamespace:     namespace,
-				SourceLabelSelector: labelSelector,
+	resourceConfigs := []watcher.ResourceConfig{
+		{
+			Name:         fmt.Sprintf("Pod watcher %s", name),
+			ResourceType: &corev1.Pod{},
+			Handler: watcher.EventHandlerFuncs{
+				OnCreateFunc: pw.onCreateOrUpdate,
+				OnUpdateFunc: pw.onCreateOrUpdate,
+				OnDeleteFunc: pw.onDelete,
 			},
+			ResourcesEquivalent: pw.arePodsEquivalent,
+			SourceNamespace:     namespace,
+			SourceLabelSelector: labelSelector,
 		},
+	}
+
+	// With a NamespaceSelector, pods are watched in all namespaces but only those in the namespaces currently matching
+	// the selector are added to the IP set. Namespaces that start or stop matching add or remove their pods.
+	if namespaceSelector != nil {
+		nsSel, err := metav1.LabelSelectorAsSelector(namespaceSelector)
+		if err != nil {
+			return nil, errors.Wrap(err, "error getting namespace label selector")
+		}
+
+		pw.namespaces = map[string]bool{}
+
+		resourceConfigs = append(resourceConfigs, watcher.ResourceConfig{
+			Name:         fmt.Sprintf("Namespace watcher %s", name),
+			ResourceType: &corev1.Namespace{},
+			Handler: watcher.EventHandlerFuncs{
+				OnCreateFunc: pw.onNamespaceCreateOrUpdate,
+				OnUpdateFunc: pw.onNamespaceCreateOrUpdate,
+				OnDeleteFunc: pw.onNamespaceDelete,
+			},
+			SourceLabelSelector: nsSel.String(),
+		})
+	}
+
+	pw.watcher, err = watcher.New(&watcher.Config{
+		RestMapper:      config.RestMapper,
+		Client:          config.Client,
+		Scheme:          config.Scheme,
+		ResourceConfigs: resourceConfigs,
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating resource watcher")
 	}
 
-	err = w.Start(pw.stopCh)
+	err = pw.watcher.Start(pw.stopCh)
 	if err != nil {
 		return nil, errors.Wrap(err, "error starting resource watcher")
 	}
@@ -78,6 +103,27 @@ func startEgressPodWatcher(name, namespace string, namedIPSet ipset.Named, confi
 	return pw, nil
 }
 
+// stop stops the watcher and then invokes the given function, after which the IP set is no longer added to.
+func (w *egressPodWatcher) stop(then func() error) error {
+	w.Lock()
+	defer w.Unlock()
+
+	if !w.stopped() {
+		close(w.stopCh)
+	}
+
+	return then()
+}
+
+func (w *egressPodWatcher) stopped() bool {
+	select {
+	case <-w.stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
 func (w *egressPodWatcher) arePodsEquivalent(oldObj, newObj *unstructured.Unstructured) bool {
 	oldPodIP, _, _ := unstructured.NestedString(oldObj.Object, "status", "podIP")
 	newPodIP, _, _ := unstructured.NestedString(newObj.Object, "status", "podIP")
@@ -93,6 +139,13 @@ func (w *egressPodWatcher) onCreateOrUpdate(obj runtime.Object, _ int) bool {
 		return false
 	}
 
+	w.Lock()
+	defer w.Unlock()
+
+	if w.stopped() || !w.namespaceSelected(pod.Namespace) {
+		return false
+	}
+
 	logger.V(log.DEBUG).Infof("Pod %q with IP %s created/updated", key, pod.Status.PodIP)
 
 	if err := w.namedIPSet.AddEntry(pod.Status.PodIP, true); err != nil {
@@ -107,6 +160,13 @@ func (w *egressPodWatcher) onDelete(obj runtime.Object, _ int) bool {
 	pod := obj.(*corev1.Pod)
 	key, _ := cache.MetaNamespaceKeyFunc(pod)
 
+	w.Lock()
+	defer w.Unlock()
+
+	if !w.namespaceSelected(pod.Namespace) {
+		return false
+	}
+
 	logger.V(log.DEBUG).Infof("Pod %q removed", key)
 
 	if err := w.namedIPSet.DelEntry(pod.Status.PodIP); err != nil {
@@ -116,3 +176,72 @@ func (w *egressPodWatcher) onDelete(obj runtime.Object, _ int) bool {
 
 	return false
 }
+
+func (w *egressPodWatcher) onNamespaceCreateOrUpdate(obj runtime.Object, _ int) bool {
+	namespace := obj.(*corev1.Namespace)
+
+	w.Lock()
+	defer w.Unlock()
+
+	if w.stopped() || w.namespaces[namespace.Name] {
+		return false
+	}
+
+	logger.V(log.DEBUG).Infof("Namespace %q selected", namespace.Name)
+
+	w.namespaces[namespace.Name] = true
+
+	for _, pod := range w.podsInNamespace(namespace.Name) {
+		if err := w.namedIPSet.AddEntry(pod.Status.PodIP, true); err != nil {
+			logger.Errorf(err, "Error adding pod IP %q to IP set %q", pod.Status.PodIP, w.ipSetName)
+
+			delete(w.namespaces, namespace.Name)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+func (w *egressPodWatcher) onNamespaceDelete(obj runtime.Object, _ int) bool {
+	namespace := obj.(*corev1.Namespace)
+
+	w.Lock()
+	defer w.Unlock()
+
+	if !w.namespaces[namespace.Name] {
+		return false
+	}
+
+	logger.V(log.DEBUG).Infof("Namespace %q no longer selected", namespace.Name)
+
+	delete(w.namespaces, namespace.Name)
+
+	// The pods may already be gone if the namespace was deleted so errors are logged and not retried.
+	for _, pod := range w.podsInNamespace(namespace.Name) {
+		if err := w.namedIPSet.DelEntry(pod.Status.PodIP); err != nil {
+			logger.Errorf(err, "Error deleting pod IP %q from IP set %q", pod.Status.PodIP, w.ipSetName)
+		}
+	}
+
+	return false
+}
+
+// namespaceSelected returns whether pods in the given namespace are selected. It must be called with the lock held.
+func (w *egressPodWatcher) namespaceSelected(namespace string) bool {
+	return w.namespaces == nil || w.namespaces[namespace]
+}
+
+func (w *egressPodWatcher) podsInNamespace(namespace string) []*corev1.Pod {
+	var pods []*corev1.Pod
+
+	for _, obj := range w.watcher.ListResources(&corev1.Pod{}, labels.Everything()) {
+		pod := obj.(*corev1.Pod)
+		if pod.Namespace == namespace && pod.Status.PodIP != "" {
+			pods = append(pods, pod)
+		}
+	}
+
+	return pods
+}
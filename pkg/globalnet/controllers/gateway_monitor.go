@@ -21,7 +21,6 @@ package controllers
 import (
 	"context"
 	"os"
-	"strings"
 	"sync/atomic"
 	"time"
 
@@ -33,19 +32,19 @@ import (
 	"github.com/submariner-io/admiral/pkg/watcher"
 	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/cidr"
-	"github.com/submariner-io/submariner/pkg/globalnet/constants"
+	iptiface "github.com/submariner-io/submariner/pkg/globalnet/controllers/iptables"
 	"github.com/submariner-io/submariner/pkg/ipam"
-	"github.com/submariner-io/submariner/pkg/iptables"
 	"github.com/submariner-io/submariner/pkg/netlink"
-	routeAgent "github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/tools/record"
 	k8snet "k8s.io/utils/net"
 	"k8s.io/utils/set"
 )
@@ -93,7 +92,7 @@ func NewGatewayMonitor(config *GatewayMonitorConfig) (Interface, error) {
 
 	var err error
 
-	gatewayMonitor.ipt, err = iptables.New()
+	gatewayMonitor.ipt, err = iptiface.New(config.Spec.DataPathBackend)
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating IP tables")
 	}
@@ -114,6 +113,12 @@ func NewGatewayMonitor(config *GatewayMonitorConfig) (Interface, error) {
 		config.Scheme = scheme.Scheme
 	}
 
+	gatewayMonitor.eventBroadcaster = record.NewBroadcaster()
+	gatewayMonitor.eventBroadcaster.StartLogging(logger.V(log.DEBUG).Infof)
+	gatewayMonitor.eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: config.KubeClient.CoreV1().Events("")})
+	gatewayMonitor.recorder = gatewayMonitor.eventBroadcaster.NewRecorder(config.Scheme,
+		corev1.EventSource{Component: "submariner-globalnet"})
+
 	config.ResourceConfigs = []watcher.ResourceConfig{
 		{
 			Name:         "IPAM GatewayMonitor",
@@ -154,8 +159,8 @@ func NewGatewayMonitor(config *GatewayMonitorConfig) (Interface, error) {
 func (g *gatewayMonitor) Start() error {
 	logger.Info("Starting GatewayMonitor to monitor the active Gateway node in the cluster.")
 
-	if err := g.createGlobalNetMarkingChain(); err != nil {
-		return errors.Wrap(err, "error while calling createGlobalNetMarkingChain")
+	if err := g.ipt.CreateGlobalNetMarkingChain(); err != nil {
+		return errors.Wrap(err, "error while calling CreateGlobalNetMarkingChain")
 	}
 
 	err := g.endpointWatcher.Start(g.stopCh)
@@ -179,6 +184,8 @@ func (g *gatewayMonitor) Stop() {
 	defer cancel()
 
 	g.stopControllers(ctx, true)
+
+	g.eventBroadcaster.Shutdown()
 }
 
 func (g *gatewayMonitor) handleCreatedOrUpdatedEndpoint(obj runtime.Object, _ int) bool {
@@ -355,9 +362,9 @@ func (g *gatewayMonitor) startControllers() error {
 
 	logger.Info("Starting controllers")
 
-	err := g.createGlobalnetChains()
+	err := g.ipt.CreateGlobalnetChains(globalNetIPTableMark)
 	if err != nil {
-		return err
+		return err //nolint:wrapcheck  // Let the caller wrap it
 	}
 
 	pool, err := ipam.NewIPPool(g.spec.GlobalCIDR[0])
@@ -365,23 +372,25 @@ func (g *gatewayMonitor) startControllers() error {
 		return errors.Wrap(err, "error creating the IP pool")
 	}
 
+	pool.SetUsageThresholds(g.spec.PoolUsageThresholds...)
+
 	g.controllers = nil
 
-	c, err := NewNodeController(g.syncerConfig, pool, g.nodeName)
+	c, err := NewNodeController(g.syncerConfig, pool, g.nodeName, g.recorder, g.spec.DataPathBackend)
 	if err != nil {
 		return errors.Wrap(err, "error creating the Node controller")
 	}
 
 	g.controllers = append(g.controllers, c)
 
-	c, err = NewClusterGlobalEgressIPController(g.syncerConfig, g.localSubnets, pool)
+	c, err = NewClusterGlobalEgressIPController(g.syncerConfig, g.localSubnets, pool, g.recorder, g.spec.DataPathBackend)
 	if err != nil {
 		return errors.Wrap(err, "error creating the ClusterGlobalEgressIP controller")
 	}
 
 	g.controllers = append(g.controllers, c)
 
-	c, err = NewGlobalEgressIPController(g.syncerConfig, pool)
+	c, err = NewGlobalEgressIPController(g.syncerConfig, pool, g.recorder, g.spec.DataPathBackend)
 	if err != nil {
 		return errors.Wrap(err, "error creating the GlobalEgressIP controller")
 	}
@@ -401,13 +410,20 @@ func (g *gatewayMonitor) startControllers() error {
 
 	// The GlobalIngressIP controller needs to be started before the ServiceExport and Service controllers to ensure
 	// reconciliation works properly.
-	c, err = NewGlobalIngressIPController(g.syncerConfig, pool)
+	c, err = NewGlobalIngressIPController(g.syncerConfig, pool, g.recorder, g.spec.DataPathBackend)
 	if err != nil {
 		return errors.Wrap(err, "error creating the GlobalIngressIP controller")
 	}
 
 	g.controllers = append(g.controllers, c)
 
+	c, err = NewGlobalIngressPodController(g.syncerConfig)
+	if err != nil {
+		return errors.Wrap(err, "error creating the GlobalIngress Pod controller")
+	}
+
+	g.controllers = append(g.controllers, c)
+
 	podControllers, err := NewIngressPodControllers(g.syncerConfig)
 	if err != nil {
 		return errors.Wrap(err, "error creating the IngressPodControllers")
@@ -424,7 +440,7 @@ func (g *gatewayMonitor) startControllers() error {
 	}
 
 	seController, err := NewServiceExportController(g.syncerConfig, podControllers, endpointsControllers,
-		ingressEndpointsControllers)
+		ingressEndpointsControllers, g.spec.DataPathBackend)
 	if err != nil {
 		return errors.Wrap(err, "error creating the ServiceExport controller")
 	}
@@ -438,6 +454,13 @@ func (g *gatewayMonitor) startControllers() error {
 
 	g.controllers = append(g.controllers, c)
 
+	c, err = NewLeakedIPSweeper(g.syncerConfig, pool, g.spec.LeakedIPSweepInterval)
+	if err != nil {
+		return errors.Wrap(err, "error creating the leaked global IP sweeper")
+	}
+
+	g.controllers = append(g.controllers, c)
+
 	for _, c := range g.controllers {
 		err = c.Start()
 		if err != nil {
@@ -476,143 +499,17 @@ func (g *gatewayMonitor) stopControllers(ctx context.Context, clearGlobalnetChai
 	logger.Info("Controllers stopped")
 }
 
-func (g *gatewayMonitor) createGlobalNetMarkingChain() error {
-	logger.V(log.DEBUG).Infof("Install/ensure %s chain exists", constants.SmGlobalnetMarkChain)
-
-	if err := g.ipt.CreateChainIfNotExists("nat", constants.SmGlobalnetMarkChain); err != nil {
-		return errors.Wrapf(err, "error creating iptables chain %s", constants.SmGlobalnetMarkChain)
-	}
-
-	return nil
-}
-
-//nolint:gocyclo // Lots of error checks, but simple logic
-func (g *gatewayMonitor) createGlobalnetChains() error {
-	logger.V(log.DEBUG).Infof("Install/ensure %s chain exists", constants.SmGlobalnetIngressChain)
-
-	if err := g.ipt.CreateChainIfNotExists("nat", constants.SmGlobalnetIngressChain); err != nil {
-		return errors.Wrapf(err, "error creating iptables chain %s", constants.SmGlobalnetIngressChain)
-	}
-
-	forwardToSubGlobalNetChain := []string{"-j", constants.SmGlobalnetIngressChain}
-	if err := g.ipt.PrependUnique("nat", "PREROUTING", forwardToSubGlobalNetChain); err != nil {
-		logger.Errorf(err, "Error appending iptables rule %q", strings.Join(forwardToSubGlobalNetChain, " "))
-	}
-
-	logger.V(log.DEBUG).Infof("Install/ensure %s chain exists", constants.SmGlobalnetEgressChain)
-
-	if err := g.ipt.CreateChainIfNotExists("nat", constants.SmGlobalnetEgressChain); err != nil {
-		return errors.Wrapf(err, "error creating iptables chain %s", constants.SmGlobalnetEgressChain)
-	}
-
-	logger.V(log.DEBUG).Infof("Install/ensure %s chain exists", routeAgent.SmPostRoutingChain)
-
-	if err := g.ipt.CreateChainIfNotExists("nat", routeAgent.SmPostRoutingChain); err != nil {
-		return errors.Wrapf(err, "error creating iptables chain %s", routeAgent.SmPostRoutingChain)
-	}
-
-	forwardToSubGlobalNetChain = []string{"-j", constants.SmGlobalnetEgressChain}
-	if err := g.ipt.PrependUnique("nat", routeAgent.SmPostRoutingChain, forwardToSubGlobalNetChain); err != nil {
-		logger.Errorf(err, "Error inserting iptables rule %q", strings.Join(forwardToSubGlobalNetChain, " "))
-	}
-
-	if err := g.createGlobalNetMarkingChain(); err != nil {
-		return err
-	}
-
-	forwardToSubGlobalNetChain = []string{"-j", constants.SmGlobalnetMarkChain}
-	if err := g.ipt.PrependUnique("nat", constants.SmGlobalnetEgressChain, forwardToSubGlobalNetChain); err != nil {
-		logger.Errorf(err, "Error inserting iptables rule %q", strings.Join(forwardToSubGlobalNetChain, " "))
-	}
-
-	logger.V(log.DEBUG).Infof("Install/ensure %s chain exists", constants.SmGlobalnetEgressChainForPods)
-
-	if err := g.ipt.CreateChainIfNotExists("nat", constants.SmGlobalnetEgressChainForPods); err != nil {
-		return errors.Wrapf(err, "error creating iptables chain %s", constants.SmGlobalnetEgressChainForPods)
-	}
-
-	logger.V(log.DEBUG).Infof("Install/ensure %s chain exists", constants.SmGlobalnetEgressChainForHeadlessSvcPods)
-
-	if err := g.ipt.CreateChainIfNotExists("nat", constants.SmGlobalnetEgressChainForHeadlessSvcPods); err != nil {
-		return errors.Wrapf(err, "error creating iptables chain %s", constants.SmGlobalnetEgressChainForHeadlessSvcPods)
-	}
-
-	logger.V(log.DEBUG).Infof("Install/ensure %s chain exists", constants.SmGlobalnetEgressChainForHeadlessSvcEPs)
-
-	if err := g.ipt.CreateChainIfNotExists("nat", constants.SmGlobalnetEgressChainForHeadlessSvcEPs); err != nil {
-		return errors.Wrapf(err, "error creating iptables chain %s", constants.SmGlobalnetEgressChainForHeadlessSvcEPs)
-	}
-
-	logger.V(log.DEBUG).Infof("Install/ensure %s chain exists", constants.SmGlobalnetEgressChainForNamespace)
-
-	if err := g.ipt.CreateChainIfNotExists("nat", constants.SmGlobalnetEgressChainForNamespace); err != nil {
-		return errors.Wrapf(err, "error creating iptables chain %s", constants.SmGlobalnetEgressChainForNamespace)
-	}
-
-	logger.V(log.DEBUG).Infof("Install/ensure %s chain exists", constants.SmGlobalnetEgressChainForCluster)
-
-	if err := g.ipt.CreateChainIfNotExists("nat", constants.SmGlobalnetEgressChainForCluster); err != nil {
-		return errors.Wrapf(err, "error creating iptables chain %s", constants.SmGlobalnetEgressChainForCluster)
-	}
-
-	forwardToSubGlobalNetChain = []string{"-j", constants.SmGlobalnetEgressChainForPods}
-	if err := g.ipt.InsertUnique("nat", constants.SmGlobalnetEgressChain, 2, forwardToSubGlobalNetChain); err != nil {
-		logger.Errorf(err, "Error inserting iptables rule %q", strings.Join(forwardToSubGlobalNetChain, " "))
-	}
-
-	forwardToSubGlobalNetChain = []string{"-j", constants.SmGlobalnetEgressChainForHeadlessSvcPods}
-	if err := g.ipt.InsertUnique("nat", constants.SmGlobalnetEgressChain, 3, forwardToSubGlobalNetChain); err != nil {
-		logger.Errorf(err, "Error inserting iptables rule %q", strings.Join(forwardToSubGlobalNetChain, " "))
-	}
-
-	forwardToSubGlobalNetChain = []string{"-j", constants.SmGlobalnetEgressChainForHeadlessSvcEPs}
-	if err := g.ipt.InsertUnique("nat", constants.SmGlobalnetEgressChain, 4, forwardToSubGlobalNetChain); err != nil {
-		logger.Errorf(err, "Error inserting iptables rule %q", strings.Join(forwardToSubGlobalNetChain, " "))
-	}
-
-	forwardToSubGlobalNetChain = []string{"-j", constants.SmGlobalnetEgressChainForNamespace}
-	if err := g.ipt.InsertUnique("nat", constants.SmGlobalnetEgressChain, 5, forwardToSubGlobalNetChain); err != nil {
-		logger.Errorf(err, "Error inserting iptables rule %q", strings.Join(forwardToSubGlobalNetChain, " "))
-	}
-
-	forwardToSubGlobalNetChain = []string{"-j", constants.SmGlobalnetEgressChainForCluster}
-	if err := g.ipt.InsertUnique("nat", constants.SmGlobalnetEgressChain, 6, forwardToSubGlobalNetChain); err != nil {
-		logger.Errorf(err, "Error inserting iptables rule %q", strings.Join(forwardToSubGlobalNetChain, " "))
-	}
-
-	return nil
-}
-
 func (g *gatewayMonitor) clearGlobalnetChains() {
 	logger.Info("Active gateway migrated, flushing Globalnet chains.")
 
-	if err := g.ipt.ClearChain("nat", constants.SmGlobalnetIngressChain); err != nil {
-		logger.Errorf(err, "Error while flushing rules in %s chain", constants.SmGlobalnetIngressChain)
-	}
-
-	if err := g.ipt.ClearChain("nat", constants.SmGlobalnetEgressChain); err != nil {
-		logger.Errorf(err, "Error while flushing rules in %s chain", constants.SmGlobalnetEgressChain)
-	}
-
-	if err := g.ipt.ClearChain("nat", constants.SmGlobalnetMarkChain); err != nil {
-		logger.Errorf(err, "Error while flushing rules in %s chain", constants.SmGlobalnetMarkChain)
+	if err := g.ipt.ClearGlobalnetChains(); err != nil {
+		logger.Errorf(err, "Error while flushing the Globalnet chains")
 	}
 }
 
 func (g *gatewayMonitor) markRemoteClusterTraffic(remoteCidr string, addRules bool) {
-	ruleSpec := []string{"-d", remoteCidr, "-j", "MARK", "--set-mark", globalNetIPTableMark}
-
-	if addRules {
-		logger.V(log.DEBUG).Infof("Marking traffic destined to remote cluster: %s", strings.Join(ruleSpec, " "))
-
-		if err := g.ipt.AppendUnique("nat", constants.SmGlobalnetMarkChain, ruleSpec...); err != nil {
-			logger.Errorf(err, "Error appending iptables rule \"%s\"", strings.Join(ruleSpec, " "))
-		}
-	} else {
-		logger.V(log.DEBUG).Infof("Deleting rule that marks remote cluster traffic: %s", strings.Join(ruleSpec, " "))
-		if err := g.ipt.Delete("nat", constants.SmGlobalnetMarkChain, ruleSpec...); err != nil {
-			logger.Errorf(err, "Error deleting iptables rule \"%s\"", strings.Join(ruleSpec, " "))
-		}
+	if err := g.ipt.MarkRemoteClusterTraffic(remoteCidr, globalNetIPTableMark, addRules); err != nil {
+		logger.Errorf(err, "Error updating the rules that mark traffic destined to remote cluster %q", remoteCidr)
 	}
 }
 
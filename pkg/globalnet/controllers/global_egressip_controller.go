@@ -20,8 +20,6 @@ package controllers
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/base32"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -39,23 +37,26 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
-	utilexec "k8s.io/utils/exec"
+	"k8s.io/client-go/tools/record"
 )
 
-func NewGlobalEgressIPController(config *syncer.ResourceSyncerConfig, pool *ipam.IPPool) (Interface, error) {
+func NewGlobalEgressIPController(config *syncer.ResourceSyncerConfig, pool *ipam.IPPool, recorder record.EventRecorder,
+	dataPathBackend string,
+) (Interface, error) {
 	// We'll panic if config is nil, this is intentional
 	var err error
 
 	logger.Info("Creating GlobalEgressIP controller")
 
-	iptIface, err := iptables.New()
+	iptIface, err := iptables.New(dataPathBackend)
 	if err != nil {
 		return nil, errors.WithMessage(err, "error creating the IPTablesInterface handler")
 	}
 
 	controller := &globalEgressIPController{
-		baseIPAllocationController: newBaseIPAllocationController(pool, iptIface),
+		baseIPAllocationController: newBaseIPAllocationController(pool, iptIface, recorder),
 		podWatchers:                map[string]*egressPodWatcher{},
 		watcherConfig: watcher.Config{
 			RestMapper: config.RestMapper,
@@ -64,7 +65,10 @@ func NewGlobalEgressIPController(config *syncer.ResourceSyncerConfig, pool *ipam
 		},
 	}
 
-	controller.ipSetIface = ipset.New(utilexec.New())
+	controller.ipSetIface, err = iptables.NewIPSet(dataPathBackend)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error creating the IPSet handler")
+	}
 
 	_, gvr, err := util.ToUnstructuredResource(&submarinerv1.GlobalEgressIP{}, config.RestMapper)
 	if err != nil {
@@ -114,6 +118,12 @@ func NewGlobalEgressIPController(config *syncer.ResourceSyncerConfig, pool *ipam
 	return controller, nil
 }
 
+func (c *globalEgressIPController) Start() error {
+	go wait.Until(c.recordEgressCounters, egressCountersInterval, c.stopCh)
+
+	return c.baseSyncerController.Start()
+}
+
 func (c *globalEgressIPController) Stop() {
 	c.baseController.Stop()
 
@@ -167,10 +177,72 @@ func (c *globalEgressIPController) onCreateOrUpdate(key string, numberOfIPs int,
 		requeue = c.flushGlobalEgressRulesAndReleaseIPs(key, namedIPSet.Name(), numRequeues, globalEgressIP)
 	}
 
-	return requeue || c.allocateGlobalIPs(key, numberOfIPs, globalEgressIP, namedIPSet) ||
+	if requeue || c.allocateGlobalIPs(key, numberOfIPs, globalEgressIP, namedIPSet) {
+		return true
+	}
+
+	return !c.programEgressLimitRules(key, namedIPSet.Name(), globalEgressIP) ||
 		!c.createPodWatcher(key, namedIPSet, numberOfIPs, globalEgressIP)
 }
 
+func (c *globalEgressIPController) programEgressLimitRules(key, ipSetName string, globalEgressIP *submarinerv1.GlobalEgressIP) bool {
+	if len(globalEgressIP.Status.AllocatedIPs) == 0 {
+		return true
+	}
+
+	limits := iptables.EgressLimits{}
+
+	if globalEgressIP.Spec.BandwidthLimit != nil {
+		limits.BandwidthLimit = globalEgressIP.Spec.BandwidthLimit.Value()
+	}
+
+	if globalEgressIP.Spec.MaxConnections != nil {
+		limits.MaxConnections = *globalEgressIP.Spec.MaxConnections
+	}
+
+	err := c.iptIface.UpdateEgressRulesForLimits(key, ipSetName, limits)
+	if err != nil {
+		logger.Errorf(err, "Error programming egress limit IP table rules for %q", key)
+
+		meta.SetStatusCondition(&globalEgressIP.Status.Conditions, metav1.Condition{
+			Type:    string(submarinerv1.GlobalEgressIPUpdated),
+			Status:  metav1.ConditionFalse,
+			Reason:  "ProgramLimitRulesFailed",
+			Message: fmt.Sprintf("Error programming egress limit rules: %v", err),
+		})
+
+		return false
+	}
+
+	cond := meta.FindStatusCondition(globalEgressIP.Status.Conditions, string(submarinerv1.GlobalEgressIPUpdated))
+	if cond != nil && cond.Reason == "ProgramLimitRulesFailed" {
+		meta.RemoveStatusCondition(&globalEgressIP.Status.Conditions, string(submarinerv1.GlobalEgressIPUpdated))
+	}
+
+	return true
+}
+
+func (c *globalEgressIPController) recordEgressCounters() {
+	counters, err := c.iptIface.GetEgressCounters()
+	if err != nil {
+		logger.Errorf(err, "Error retrieving the egress counters")
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	for key, podWatcher := range c.podWatchers {
+		counter, found := counters[podWatcher.namedIPSet.Name()]
+		if !found {
+			continue
+		}
+
+		namespace, name, _ := cache.SplitMetaNamespaceKey(key)
+		metrics.RecordGlobalEgressIPCounters(namespace, name, counter.Packets, counter.Bytes, counter.Connections)
+	}
+}
+
 //nolint:wrapcheck  // No need to wrap these errors.
 func (c *globalEgressIPController) programGlobalEgressRules(key string, allocatedIPs []string, podSelector *metav1.LabelSelector,
 	namedIPSet ipset.Named,
@@ -231,6 +303,8 @@ func (c *globalEgressIPController) allocateGlobalIPs(key string, numberOfIPs int
 			Message: fmt.Sprintf("Error allocating %d global IP(s) from the pool: %v", numberOfIPs, err),
 		})
 
+		c.checkPoolUsage(globalEgressIP, &globalEgressIP.Status.Conditions, err)
+
 		return true
 	}
 
@@ -259,6 +333,8 @@ func (c *globalEgressIPController) allocateGlobalIPs(key string, numberOfIPs int
 		Message: fmt.Sprintf("Allocated %d global IP(s)", numberOfIPs),
 	})
 
+	c.checkPoolUsage(globalEgressIP, &globalEgressIP.Status.Conditions, nil)
+
 	globalEgressIP.Status.AllocatedIPs = allocatedIPs
 
 	logger.Infof("Allocated %v global IP(s) for %q", globalEgressIP.Status.AllocatedIPs, key)
@@ -278,6 +354,28 @@ func (c *globalEgressIPController) validate(numberOfIPs int, egressIP *submarine
 		return false
 	}
 
+	if egressIP.Spec.BandwidthLimit != nil && egressIP.Spec.BandwidthLimit.Sign() < 0 {
+		meta.SetStatusCondition(&egressIP.Status.Conditions, metav1.Condition{
+			Type:    string(submarinerv1.GlobalEgressIPAllocated),
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidInput",
+			Message: "The BandwidthLimit cannot be negative",
+		})
+
+		return false
+	}
+
+	if egressIP.Spec.MaxConnections != nil && *egressIP.Spec.MaxConnections < 1 {
+		meta.SetStatusCondition(&egressIP.Status.Conditions, metav1.Condition{
+			Type:    string(submarinerv1.GlobalEgressIPAllocated),
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidInput",
+			Message: "The MaxConnections must be greater than 0",
+		})
+
+		return false
+	}
+
 	return true
 }
 
@@ -295,6 +393,8 @@ func (c *globalEgressIPController) onDelete(numRequeues int, globalEgressIP *sub
 
 	namedIPSet := c.newNamedIPSet(key)
 
+	metrics.DeleteGlobalEgressIPCounters(globalEgressIP.Namespace, globalEgressIP.Name)
+
 	if len(globalEgressIP.Status.AllocatedIPs) == 0 && len(podWatcher.allocatedIPs) > 0 {
 		// Refer to issue for more details: https://github.com/submariner-io/submariner/issues/2388
 		logger.Warningf("Using the cached allocatedIPs %q to delete the iptables rules for key %q", podWatcher.allocatedIPs, key)
@@ -323,13 +423,6 @@ func (c *globalEgressIPController) onDelete(numRequeues int, globalEgressIP *sub
 	return false
 }
 
-func (c *globalEgressIPController) getIPSetName(key string) string {
-	hash := sha256.Sum256([]byte(key))
-	encoded := base32.StdEncoding.EncodeToString(hash[:])
-	// Max length of IPSet name can be 31
-	return IPSetPrefix + encoded[:25]
-}
-
 func (c *globalEgressIPController) createPodWatcher(key string, namedIPSet ipset.Named, numberOfIPs int,
 	globalEgressIP *submarinerv1.GlobalEgressIP,
 ) bool {
@@ -356,7 +449,8 @@ func (c *globalEgressIPController) createPodWatcher(key string, namedIPSet ipset
 		return true
 	}
 
-	podWatcher, err := startEgressPodWatcher(key, globalEgressIP.Namespace, namedIPSet, &c.watcherConfig, globalEgressIP.Spec.PodSelector)
+	podWatcher, err := startEgressPodWatcher(key, globalEgressIP.Namespace, namedIPSet, &c.watcherConfig,
+		globalEgressIP.Spec.PodSelector, nil)
 	if err != nil {
 		logger.Errorf(err, "Error starting pod watcher for %q", key)
 		return false
@@ -377,6 +471,11 @@ func (c *globalEgressIPController) flushGlobalEgressRulesAndReleaseIPs(key, ipSe
 ) bool {
 	return c.flushRulesAndReleaseIPs(key, numRequeues, func(allocatedIPs []string) error {
 		metrics.RecordDeallocateGlobalEgressIPs(c.pool.GetCIDR(), len(allocatedIPs))
+
+		if err := c.iptIface.RemoveEgressRulesForLimits(key, ipSetName); err != nil {
+			return err
+		}
+
 		if globalEgressIP.Spec.PodSelector != nil {
 			return c.iptIface.RemoveEgressRulesForPods(key, ipSetName,
 				getTargetSNATIPaddress(allocatedIPs), globalNetIPTableMark)
@@ -388,7 +487,7 @@ func (c *globalEgressIPController) flushGlobalEgressRulesAndReleaseIPs(key, ipSe
 
 func (c *globalEgressIPController) newNamedIPSet(key string) ipset.Named {
 	return ipset.NewNamed(&ipset.IPSet{
-		Name:    c.getIPSetName(key),
+		Name:    getIPSetName(key),
 		SetType: ipset.HashIP,
 	}, c.ipSetIface)
 }
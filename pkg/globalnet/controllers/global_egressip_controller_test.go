@@ -30,8 +30,10 @@ import (
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/globalnet/constants"
 	"github.com/submariner-io/submariner/pkg/globalnet/controllers"
+	iptiface "github.com/submariner-io/submariner/pkg/globalnet/controllers/iptables"
 	"github.com/submariner-io/submariner/pkg/ipam"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -66,6 +68,10 @@ var _ = Describe("GlobalEgressIP controller", func() {
 	When("a Pod associated with a GlobalEgressIP is created", func() {
 		testEgressPodEvents(t)
 	})
+
+	When("a GlobalEgressIP with egress limits is created", func() {
+		testGlobalEgressIPLimits(t)
+	})
 })
 
 func testGlobalEgressIPCreated(t *globalEgressIPControllerTestDriver, podSelector *metav1.LabelSelector) {
@@ -193,6 +199,48 @@ func testGlobalEgressIPCreated(t *globalEgressIPControllerTestDriver, podSelecto
 
 			t.watches.AwaitWatchStarted("pods")
 		})
+
+		It("should raise a Warning Event and set the PoolExhaustion condition until the pool is available", func() {
+			t.awaitWarningEvent("PoolExhausted")
+
+			t.awaitStatusConditions(t.globalEgressIPs, globalEgressIPName, metav1.Condition{
+				Type:   string(submarinerv1.GlobalIPPoolExhaustion),
+				Status: metav1.ConditionTrue,
+				Reason: "PoolExhausted",
+			})
+
+			_ = t.pool.Release(ips...)
+
+			t.awaitStatusConditions(t.globalEgressIPs, globalEgressIPName, metav1.Condition{
+				Type:   string(submarinerv1.GlobalIPPoolExhaustion),
+				Status: metav1.ConditionTrue,
+				Reason: "PoolExhausted",
+			}, metav1.Condition{
+				Type:   string(submarinerv1.GlobalIPPoolExhaustion),
+				Status: metav1.ConditionFalse,
+				Reason: "PoolAvailable",
+			})
+		})
+	})
+
+	Context("with the IP pool usage reaching a threshold", func() {
+		BeforeEach(func() {
+			t.pool.SetUsageThresholds(50, 90)
+
+			_, err := t.pool.Allocate(t.pool.Size() * 6 / 10)
+			Expect(err).To(Succeed())
+		})
+
+		It("should allocate the global IP and raise a Warning Event and the PoolExhaustion condition", func() {
+			t.awaitGlobalEgressIPStatusAllocated(globalEgressIPName, 1)
+			t.awaitWarningEvent("PoolUsageThresholdReached")
+
+			t.awaitStatusConditions(t.globalEgressIPs, globalEgressIPName, metav1.Condition{
+				Type:   string(submarinerv1.GlobalIPPoolExhaustion),
+				Status: metav1.ConditionTrue,
+				Reason: "PoolUsageThresholdReached",
+			})
+		})
 	})
 
 	Context("and then deleted", func() {
@@ -579,6 +627,104 @@ func testEgressPodEvents(t *globalEgressIPControllerTestDriver) {
 	})
 }
 
+func testGlobalEgressIPLimits(t *globalEgressIPControllerTestDriver) {
+	var (
+		egressIP *submarinerv1.GlobalEgressIP
+		ipSet    string
+	)
+
+	BeforeEach(func() {
+		maxConnections := 10
+		bandwidthLimit := resource.MustParse("1Mi")
+
+		egressIP = newGlobalEgressIP(globalEgressIPName, nil, nil)
+		egressIP.Spec.MaxConnections = &maxConnections
+		egressIP.Spec.BandwidthLimit = &bandwidthLimit
+	})
+
+	JustBeforeEach(func() {
+		t.createGlobalEgressIP(egressIP)
+	})
+
+	Context("", func() {
+		JustBeforeEach(func() {
+			t.awaitGlobalEgressIPStatusAllocated(globalEgressIPName, 1)
+			ipSet = t.awaitIPTableRules(constants.SmGlobalnetEgressChainForNamespace,
+				getGlobalEgressIPStatus(t.globalEgressIPs, globalEgressIPName).AllocatedIPs...)
+		})
+
+		It("should program the limit and accounting IP table rules", func() {
+			t.ipt.AwaitRule(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits,
+				And(ContainSubstring(ipSet), ContainSubstring("--connlimit-above 10"), HaveSuffix("-j DROP")))
+			t.ipt.AwaitRule(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits,
+				And(ContainSubstring(ipSet), ContainSubstring("--hashlimit-above 1024kb/s"), HaveSuffix("-j DROP")))
+			t.ipt.AwaitRule(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits,
+				Equal("-m set --match-set "+ipSet+" src"))
+			t.ipt.AwaitRule(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits,
+				Equal("-m set --match-set "+ipSet+" src -m conntrack --ctstate NEW"))
+		})
+
+		Context("and then updated to remove the limits", func() {
+			JustBeforeEach(func() {
+				t.ipt.AwaitRule(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits, ContainSubstring("--connlimit-above"))
+
+				egressIP.Spec.MaxConnections = nil
+				egressIP.Spec.BandwidthLimit = nil
+				test.UpdateResource(t.globalEgressIPs, egressIP)
+			})
+
+			It("should remove the limit IP table rules", func() {
+				t.ipt.AwaitNoRule(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits, ContainSubstring("--connlimit-above"))
+				t.ipt.AwaitNoRule(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits, ContainSubstring("--hashlimit-above"))
+				t.ipt.AwaitRule(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits,
+					Equal("-m set --match-set "+ipSet+" src"))
+			})
+		})
+
+		Context("and then deleted", func() {
+			JustBeforeEach(func() {
+				t.ipt.AwaitRule(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits, ContainSubstring(ipSet))
+				Expect(t.globalEgressIPs.Delete(context.TODO(), globalEgressIPName, metav1.DeleteOptions{})).To(Succeed())
+			})
+
+			It("should remove the limit and accounting IP table rules", func() {
+				t.ipt.AwaitNoRule(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits, ContainSubstring(ipSet))
+			})
+		})
+	})
+
+	Context("with MaxConnections negative", func() {
+		BeforeEach(func() {
+			n := -1
+			egressIP.Spec.MaxConnections = &n
+		})
+
+		It("should add an appropriate Status condition", func() {
+			t.awaitEgressIPStatus(t.globalEgressIPs, globalEgressIPName, 0, metav1.Condition{
+				Type:   string(submarinerv1.GlobalEgressIPAllocated),
+				Status: metav1.ConditionFalse,
+				Reason: "InvalidInput",
+			})
+		})
+	})
+
+	Context("with MaxConnections zero", func() {
+		BeforeEach(func() {
+			n := 0
+			egressIP.Spec.MaxConnections = &n
+		})
+
+		It("should add an appropriate Status condition", func() {
+			t.awaitEgressIPStatus(t.globalEgressIPs, globalEgressIPName, 0, metav1.Condition{
+				Type:   string(submarinerv1.GlobalEgressIPAllocated),
+				Status: metav1.ConditionFalse,
+				Reason: "InvalidInput",
+			})
+			t.ipt.AwaitNoRule(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits, ContainSubstring("-m set"))
+		})
+	})
+}
+
 type globalEgressIPControllerTestDriver struct {
 	*testDriverBase
 }
@@ -615,7 +761,7 @@ func (t *globalEgressIPControllerTestDriver) start() {
 		SourceClient: t.dynClient,
 		RestMapper:   t.restMapper,
 		Scheme:       t.scheme,
-	}, t.pool)
+	}, t.pool, t.recorder, iptiface.IPTablesBackend)
 
 	Expect(err).To(Succeed())
 	Expect(t.controller.Start()).To(Succeed())
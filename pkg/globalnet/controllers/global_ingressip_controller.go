@@ -28,24 +28,30 @@ import (
 	"github.com/submariner-io/admiral/pkg/resource"
 	"github.com/submariner-io/admiral/pkg/syncer"
 	"github.com/submariner-io/admiral/pkg/util"
+	"github.com/submariner-io/admiral/pkg/watcher"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/globalnet/controllers/iptables"
 	"github.com/submariner-io/submariner/pkg/globalnet/metrics"
 	"github.com/submariner-io/submariner/pkg/ipam"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 )
 
-func NewGlobalIngressIPController(config *syncer.ResourceSyncerConfig, pool *ipam.IPPool) (Interface, error) {
+func NewGlobalIngressIPController(config *syncer.ResourceSyncerConfig, pool *ipam.IPPool, recorder record.EventRecorder,
+	dataPathBackend string,
+) (Interface, error) {
 	// We'll panic if config is nil, this is intentional
 	var err error
 
 	logger.Info("Creating GlobalIngressIP controller")
 
-	iptIface, err := iptables.New()
+	iptIface, err := iptables.New(dataPathBackend)
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating the IPTablesInterface handler")
 	}
@@ -56,11 +62,19 @@ func NewGlobalIngressIPController(config *syncer.ResourceSyncerConfig, pool *ipa
 	}
 
 	controller := &globalIngressIPController{
-		baseIPAllocationController: newBaseIPAllocationController(pool, iptIface),
+		baseIPAllocationController: newBaseIPAllocationController(pool, iptIface, recorder),
 		services:                   config.SourceClient.Resource(*gvr),
 		scheme:                     config.Scheme,
+		podTargets:                 map[string]*podIngressTarget{},
 	}
 
+	_, gvr, err = util.ToUnstructuredResource(&corev1.Pod{}, config.RestMapper)
+	if err != nil {
+		return nil, errors.Wrap(err, "error converting resource")
+	}
+
+	controller.pods = config.SourceClient.Resource(*gvr)
+
 	_, gvr, err = util.ToUnstructuredResource(&submarinerv1.GlobalIngressIP{}, config.RestMapper)
 	if err != nil {
 		return nil, errors.Wrap(err, "error converting resource")
@@ -87,8 +101,12 @@ func NewGlobalIngressIPController(config *syncer.ResourceSyncerConfig, pool *ipa
 
 			metrics.RecordAllocateGlobalIngressIPs(pool.GetCIDR(), len(reservedIPs))
 
+			key, _ := cache.MetaNamespaceKeyFunc(obj)
+
 			if gip.Spec.Target == submarinerv1.ClusterIPService {
 				return controller.ensureInternalServiceExists(gip)
+			} else if gip.Spec.Target == submarinerv1.Pod {
+				return controller.addPodTarget(key, gip, reservedIPs[0])
 			} else if gip.Spec.Target == submarinerv1.HeadlessServicePod {
 				target = gip.GetAnnotations()[headlessSvcPodIP]
 				tType = iptables.PodTarget
@@ -104,7 +122,6 @@ func NewGlobalIngressIPController(config *syncer.ResourceSyncerConfig, pool *ipa
 				return err
 			}
 
-			key, _ := cache.MetaNamespaceKeyFunc(obj)
 			return controller.iptIface.AddEgressRulesForHeadlessSvc(key, target, reservedIPs[0], globalNetIPTableMark, tType)
 		})
 
@@ -129,9 +146,39 @@ func NewGlobalIngressIPController(config *syncer.ResourceSyncerConfig, pool *ipa
 		return nil, errors.Wrap(err, "error creating the syncer")
 	}
 
+	controller.podWatcher, err = watcher.New(&watcher.Config{
+		RestMapper: config.RestMapper,
+		Client:     config.SourceClient,
+		Scheme:     config.Scheme,
+		ResourceConfigs: []watcher.ResourceConfig{
+			{
+				Name:         "GlobalIngressIP Pod watcher",
+				ResourceType: &corev1.Pod{},
+				Handler: watcher.EventHandlerFuncs{
+					OnCreateFunc: controller.onPodCreateOrUpdate,
+					OnUpdateFunc: controller.onPodCreateOrUpdate,
+					OnDeleteFunc: controller.onPodDelete,
+				},
+				ResourcesEquivalent: arePodsEqual,
+				SourceNamespace:     corev1.NamespaceAll,
+			},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the Pod watcher")
+	}
+
 	return controller, nil
 }
 
+func (c *globalIngressIPController) Start() error {
+	if err := c.podWatcher.Start(c.stopCh); err != nil {
+		return errors.Wrap(err, "error starting the Pod watcher")
+	}
+
+	return c.baseSyncerController.Start()
+}
+
 func (c *globalIngressIPController) process(from runtime.Object, numRequeues int, op syncer.Operation) (runtime.Object, bool) {
 	ingressIP := from.(*submarinerv1.GlobalIngressIP)
 
@@ -163,6 +210,17 @@ func (c *globalIngressIPController) onCreate(ingressIP *submarinerv1.GlobalIngre
 
 	key, _ := cache.MetaNamespaceKeyFunc(ingressIP)
 
+	if ingressIP.Spec.Target == submarinerv1.Pod && ingressIP.Spec.PodRef == nil {
+		meta.SetStatusCondition(&ingressIP.Status.Conditions, metav1.Condition{
+			Type:    string(submarinerv1.GlobalEgressIPAllocated),
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidInput",
+			Message: "The PodRef must be specified for the Pod target",
+		})
+
+		return false
+	}
+
 	ips, err := c.pool.Allocate(1)
 	if err != nil {
 		logger.Errorf(err, "Error allocating IP for %q", key)
@@ -174,6 +232,8 @@ func (c *globalIngressIPController) onCreate(ingressIP *submarinerv1.GlobalIngre
 			Message: fmt.Sprintf("Error allocating a global IP from the pool: %v", err),
 		})
 
+		c.checkPoolUsage(ingressIP, &ingressIP.Status.Conditions, err)
+
 		return true
 	}
 
@@ -229,6 +289,21 @@ func (c *globalIngressIPController) onCreate(ingressIP *submarinerv1.GlobalIngre
 
 			return false
 		}
+	} else if ingressIP.Spec.Target == submarinerv1.Pod {
+		if err := c.addPodTarget(key, ingressIP, ips[0]); err != nil {
+			logger.Errorf(err, "Error while programming the ingress rules for %q", key)
+
+			_ = c.pool.Release(ips...)
+
+			meta.SetStatusCondition(&ingressIP.Status.Conditions, metav1.Condition{
+				Type:    string(submarinerv1.GlobalEgressIPAllocated),
+				Status:  metav1.ConditionFalse,
+				Reason:  "ProgramIPTableRulesFailed",
+				Message: err.Error(),
+			})
+
+			return true
+		}
 	} else {
 		var annotationKey string
 		var tType iptables.TargetType
@@ -286,6 +361,8 @@ func (c *globalIngressIPController) onCreate(ingressIP *submarinerv1.GlobalIngre
 		Message: "Allocated global IP",
 	})
 
+	c.checkPoolUsage(ingressIP, &ingressIP.Status.Conditions, nil)
+
 	return false
 }
 
@@ -334,6 +411,10 @@ func (c *globalIngressIPController) onDelete(ingressIP *submarinerv1.GlobalIngre
 
 		metrics.RecordDeallocateGlobalIngressIPs(c.pool.GetCIDR(), len(allocatedIPs))
 
+		if ingressIP.Spec.Target == submarinerv1.Pod {
+			return c.removePodTarget(key)
+		}
+
 		if ingressIP.Spec.Target == submarinerv1.HeadlessServicePod {
 			target = ingressIP.GetAnnotations()[headlessSvcPodIP]
 			tType = iptables.PodTarget
@@ -393,9 +474,133 @@ func (c *globalIngressIPController) getServiceExternalIP(service *corev1.Service
 func (c *globalIngressIPController) getTargetReference(giip *submarinerv1.GlobalIngressIP) string {
 	if giip.Spec.Target == submarinerv1.ClusterIPService {
 		return giip.Spec.ServiceRef.Name
-	} else if giip.Spec.Target == submarinerv1.HeadlessServicePod {
+	} else if (giip.Spec.Target == submarinerv1.HeadlessServicePod || giip.Spec.Target == submarinerv1.Pod) &&
+		giip.Spec.PodRef != nil {
 		return giip.Spec.PodRef.Name
 	}
 
 	return ""
 }
+
+// addPodTarget programs the rules for a GlobalIngressIP targeting a Pod, if the Pod has an IP, and tracks the Pod so the
+// rules follow changes to its IP.
+func (c *globalIngressIPController) addPodTarget(key string, ingressIP *submarinerv1.GlobalIngressIP, globalIP string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	target := &podIngressTarget{
+		podKey:   ingressIP.Namespace + "/" + ingressIP.Spec.PodRef.Name,
+		globalIP: globalIP,
+	}
+
+	obj, err := c.pods.Namespace(ingressIP.Namespace).Get(context.TODO(), ingressIP.Spec.PodRef.Name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "error retrieving Pod %q", target.podKey)
+	}
+
+	if err == nil {
+		podIP, _, _ := unstructured.NestedString(obj.Object, "status", "podIP")
+
+		if err := c.updatePodTarget(key, target, podIP); err != nil {
+			return err
+		}
+	}
+
+	c.podTargets[key] = target
+
+	return nil
+}
+
+func (c *globalIngressIPController) removePodTarget(key string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	target, found := c.podTargets[key]
+	if !found {
+		return nil
+	}
+
+	if err := c.updatePodTarget(key, target, ""); err != nil {
+		return err
+	}
+
+	delete(c.podTargets, key)
+
+	return nil
+}
+
+// updatePodTarget reprograms the rules for the target from its current Pod IP to the given Pod IP. It must be called
+// with the lock held.
+//
+//nolint:wrapcheck  // No need to wrap these errors.
+func (c *globalIngressIPController) updatePodTarget(key string, target *podIngressTarget, podIP string) error {
+	if target.podIP == podIP {
+		return nil
+	}
+
+	if target.podIP != "" {
+		logger.Infof("Removing the rules for GlobalIngressIP %q from Pod IP %q", key, target.podIP)
+
+		if err := c.iptIface.RemoveIngressRulesForHeadlessSvc(target.globalIP, target.podIP, iptables.PodTarget); err != nil {
+			return err
+		}
+
+		if err := c.iptIface.RemoveEgressRulesForHeadlessSvc(key, target.podIP, target.globalIP, globalNetIPTableMark,
+			iptables.PodTarget); err != nil {
+			return err
+		}
+
+		target.podIP = ""
+	}
+
+	if podIP == "" {
+		return nil
+	}
+
+	logger.Infof("Programming the rules for GlobalIngressIP %q to Pod IP %q", key, podIP)
+
+	if err := c.iptIface.AddIngressRulesForHeadlessSvc(target.globalIP, podIP, iptables.PodTarget); err != nil {
+		return err
+	}
+
+	if err := c.iptIface.AddEgressRulesForHeadlessSvc(key, podIP, target.globalIP, globalNetIPTableMark, iptables.PodTarget); err != nil {
+		_ = c.iptIface.RemoveIngressRulesForHeadlessSvc(target.globalIP, podIP, iptables.PodTarget)
+		return err
+	}
+
+	target.podIP = podIP
+
+	return nil
+}
+
+func (c *globalIngressIPController) onPodCreateOrUpdate(obj runtime.Object, _ int) bool {
+	pod := obj.(*corev1.Pod)
+	return c.onPodIPChanged(pod, pod.Status.PodIP)
+}
+
+func (c *globalIngressIPController) onPodDelete(obj runtime.Object, _ int) bool {
+	return c.onPodIPChanged(obj.(*corev1.Pod), "")
+}
+
+func (c *globalIngressIPController) onPodIPChanged(pod *corev1.Pod, podIP string) bool {
+	podKey, _ := cache.MetaNamespaceKeyFunc(pod)
+
+	c.Lock()
+	defer c.Unlock()
+
+	requeue := false
+
+	for key, target := range c.podTargets {
+		if target.podKey != podKey {
+			continue
+		}
+
+		if err := c.updatePodTarget(key, target, podIP); err != nil {
+			logger.Errorf(err, "Error updating the rules for GlobalIngressIP %q for Pod IP %q", key, podIP)
+
+			requeue = true
+		}
+	}
+
+	return requeue
+}
@@ -25,9 +25,11 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/submariner-io/admiral/pkg/syncer"
+	"github.com/submariner-io/admiral/pkg/syncer/test"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/globalnet/constants"
 	"github.com/submariner-io/submariner/pkg/globalnet/controllers"
+	iptiface "github.com/submariner-io/submariner/pkg/globalnet/controllers/iptables"
 	"github.com/submariner-io/submariner/pkg/ipam"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -125,6 +127,10 @@ var _ = Describe("GlobalIngressIP controller", func() {
 		testGlobalIngressIPCreatedHeadlessSvc(t, headlessServiceWithoutSelectorIngress, awaitHeadlessServiceEndpointsRules,
 			awaitNoHeadlessServiceEndpointsRules, endpointsIP)
 	})
+
+	When("a GlobalIngressIP for a Pod is created", func() {
+		testGlobalIngressIPCreatedPod(t)
+	})
 })
 
 func testGlobalIngressIPCreatedClusterIPSvc(t *globalIngressIPControllerTestDriver, ingressIP *submarinerv1.GlobalIngressIP) {
@@ -162,6 +168,14 @@ func testGlobalIngressIPCreatedClusterIPSvc(t *globalIngressIPControllerTestDriv
 				Status: metav1.ConditionFalse,
 				Reason: "IPPoolAllocationFailed",
 			})
+
+			t.awaitStatusConditions(t.globalIngressIPs, globalIngressIPName, metav1.Condition{
+				Type:   string(submarinerv1.GlobalIPPoolExhaustion),
+				Status: metav1.ConditionTrue,
+				Reason: "PoolExhausted",
+			})
+
+			t.awaitWarningEvent("PoolExhausted")
 		})
 	})
 
@@ -492,6 +506,110 @@ func testExistingGlobalIngressIPHeadlessSvc(t *globalIngressIPControllerTestDriv
 	})
 }
 
+func testGlobalIngressIPCreatedPod(t *globalIngressIPControllerTestDriver) {
+	var (
+		pod       *corev1.Pod
+		ingressIP *submarinerv1.GlobalIngressIP
+	)
+
+	BeforeEach(func() {
+		pod = newPod(namespace)
+
+		ingressIP = &submarinerv1.GlobalIngressIP{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: globalIngressIPName,
+			},
+			Spec: submarinerv1.GlobalIngressIPSpec{
+				Target: submarinerv1.Pod,
+				PodRef: &corev1.LocalObjectReference{Name: pod.Name},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		t.createGlobalIngressIP(ingressIP)
+	})
+
+	Context("and the Pod exists", func() {
+		var allocatedIP string
+
+		BeforeEach(func() {
+			t.createPod(pod)
+		})
+
+		JustBeforeEach(func() {
+			t.awaitIngressIPStatusAllocated(globalIngressIPName)
+			allocatedIP = t.getGlobalIngressIPStatus(globalIngressIPName).AllocatedIP
+		})
+
+		It("should program the rules for the Pod IP", func() {
+			t.awaitPodIngressRules(pod.Status.PodIP, allocatedIP)
+			t.awaitPodEgressRules(pod.Status.PodIP, allocatedIP)
+		})
+
+		Context("and the Pod IP changes", func() {
+			It("should reprogram the rules for the new Pod IP and retain the global IP", func() {
+				oldPodIP := pod.Status.PodIP
+				t.awaitPodIngressRules(oldPodIP, allocatedIP)
+
+				pod.Status.PodIP = "1.2.3.5"
+				test.UpdateResource(t.pods.Namespace(pod.Namespace), pod)
+
+				t.awaitPodIngressRules(pod.Status.PodIP, allocatedIP)
+				t.awaitPodEgressRules(pod.Status.PodIP, allocatedIP)
+				t.ipt.AwaitNoRule("nat", constants.SmGlobalnetIngressChain, ContainSubstring(oldPodIP))
+				Expect(t.getGlobalIngressIPStatus(globalIngressIPName).AllocatedIP).To(Equal(allocatedIP))
+			})
+		})
+
+		Context("and the Pod is deleted", func() {
+			It("should remove the rules and retain the global IP", func() {
+				t.awaitPodIngressRules(pod.Status.PodIP, allocatedIP)
+				t.deletePod(pod)
+
+				t.awaitNoPodIngressRules(pod.Status.PodIP, allocatedIP)
+				t.awaitNoPodEgressRules(pod.Status.PodIP, allocatedIP)
+				t.verifyIPsReservedInPool(allocatedIP)
+			})
+		})
+
+		Context("and the GlobalIngressIP is then removed", func() {
+			It("should release the allocated global IP and remove the rules", func() {
+				t.awaitPodIngressRules(pod.Status.PodIP, allocatedIP)
+				Expect(t.globalIngressIPs.Delete(context.TODO(), globalIngressIPName, metav1.DeleteOptions{})).To(Succeed())
+
+				t.awaitIPsReleasedFromPool(allocatedIP)
+				t.awaitNoPodIngressRules(pod.Status.PodIP, allocatedIP)
+				t.awaitNoPodEgressRules(pod.Status.PodIP, allocatedIP)
+			})
+		})
+	})
+
+	Context("and the Pod doesn't exist yet", func() {
+		It("should allocate a global IP and program the rules once the Pod is created", func() {
+			t.awaitIngressIPStatusAllocated(globalIngressIPName)
+			allocatedIP := t.getGlobalIngressIPStatus(globalIngressIPName).AllocatedIP
+
+			t.createPod(pod)
+			t.awaitPodIngressRules(pod.Status.PodIP, allocatedIP)
+		})
+	})
+
+	Context("without a PodRef", func() {
+		BeforeEach(func() {
+			ingressIP.Spec.PodRef = nil
+		})
+
+		It("should add an appropriate Status condition", func() {
+			t.awaitStatusConditions(t.globalIngressIPs, globalIngressIPName, metav1.Condition{
+				Type:   string(submarinerv1.GlobalEgressIPAllocated),
+				Status: metav1.ConditionFalse,
+				Reason: "InvalidInput",
+			})
+		})
+	})
+}
+
 type globalIngressIPControllerTestDriver struct {
 	*testDriverBase
 }
@@ -526,7 +644,7 @@ func (t *globalIngressIPControllerTestDriver) start() {
 		SourceClient: t.dynClient,
 		RestMapper:   t.restMapper,
 		Scheme:       t.scheme,
-	}, t.pool)
+	}, t.pool, t.recorder, iptiface.IPTablesBackend)
 
 	Expect(err).To(Succeed())
 	Expect(t.controller.Start()).To(Succeed())
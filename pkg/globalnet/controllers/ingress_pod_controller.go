@@ -19,6 +19,9 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -27,6 +30,7 @@ import (
 	"github.com/submariner-io/admiral/pkg/util"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
@@ -144,6 +148,156 @@ func (c *ingressPodController) process(from runtime.Object, _ int, op syncer.Ope
 	return ingressIP, false
 }
 
+// NewGlobalIngressPodController creates a controller that maintains a GlobalIngressIP targeting each Pod, in any
+// namespace, annotated with GlobalIngressPodAnnotation. The GlobalIngressIP is deleted, and thus its global IP released,
+// when the Pod is deleted or the annotation is removed.
+func NewGlobalIngressPodController(config *syncer.ResourceSyncerConfig) (Interface, error) {
+	// We'll panic if config is nil, this is intentional
+	var err error
+
+	logger.Info("Creating GlobalIngress Pod controller")
+
+	_, gvr, err := util.ToUnstructuredResource(&submarinerv1.GlobalIngressIP{}, config.RestMapper)
+	if err != nil {
+		return nil, errors.Wrap(err, "error converting resource")
+	}
+
+	controller := &ingressPodController{
+		baseSyncerController: newBaseSyncerController(),
+		ingressIPMap:         set.New[string](),
+		ingressIPs:           config.SourceClient.Resource(*gvr),
+	}
+
+	ingressIPSelector := labels.SelectorFromSet(map[string]string{GlobalIngressPodLabel: "true"}).String()
+
+	// Seed the GlobalIngressIPs previously created so they're deleted if the annotation was removed in the meantime.
+	list, err := controller.ingressIPs.Namespace(corev1.NamespaceAll).List(context.TODO(),
+		metav1.ListOptions{LabelSelector: ingressIPSelector})
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing the GlobalIngressIPs")
+	}
+
+	for i := range list.Items {
+		controller.ingressIPMap.Insert(list.Items[i].GetNamespace() + "/" + list.Items[i].GetName())
+	}
+
+	controller.resourceSyncer, err = syncer.NewResourceSyncer(&syncer.ResourceSyncerConfig{
+		Name:                "GlobalIngress Pod syncer",
+		ResourceType:        &corev1.Pod{},
+		SourceClient:        config.SourceClient,
+		SourceNamespace:     corev1.NamespaceAll,
+		RestMapper:          config.RestMapper,
+		Federator:           federate.NewCreateFederator(config.SourceClient, config.RestMapper, corev1.NamespaceAll),
+		Scheme:              config.Scheme,
+		Transform:           controller.processAnnotatedPod,
+		ResourcesEquivalent: areAnnotatedPodsEqual,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the syncer")
+	}
+
+	controller.reconcile(controller.ingressIPs.Namespace(corev1.NamespaceAll), ingressIPSelector, "", /* fieldSelector*/
+		func(obj *unstructured.Unstructured) runtime.Object {
+			podName, exists, _ := unstructured.NestedString(obj.Object, "spec", "podRef", "name")
+			if exists {
+				return &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        podName,
+						Namespace:   obj.GetNamespace(),
+						Annotations: map[string]string{GlobalIngressPodAnnotation: "true"},
+					},
+				}
+			}
+
+			return nil
+		})
+
+	return controller, nil
+}
+
+// podIngressIPName returns the name of the GlobalIngressIP for a Pod with the global ingress annotation. If the name has to be
+// truncated, a hash of the Pod name is appended so Pods whose names share a long prefix don't map to the same GlobalIngressIP.
+func podIngressIPName(podName string) string {
+	const maxLen = 63
+
+	name := "pod-ingress-" + podName
+	if len(name) <= maxLen {
+		return name
+	}
+
+	hash := sha256.Sum256([]byte(podName))
+	suffix := hex.EncodeToString(hash[:])[:10]
+
+	return name[:maxLen-len(suffix)-1] + "-" + suffix
+}
+
+func (c *ingressPodController) processAnnotatedPod(from runtime.Object, _ int, op syncer.Operation) (runtime.Object, bool) {
+	pod := from.(*corev1.Pod)
+	key, _ := cache.MetaNamespaceKeyFunc(pod)
+
+	ingressIP := &submarinerv1.GlobalIngressIP{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      podIngressIPName(pod.Name),
+			Namespace: pod.Namespace,
+			Labels: map[string]string{
+				GlobalIngressPodLabel: "true",
+			},
+		},
+	}
+
+	ingressIPKey := ingressIP.Namespace + "/" + ingressIP.Name
+	annotated := pod.GetAnnotations()[GlobalIngressPodAnnotation] == "true"
+
+	if op == syncer.Delete {
+		if !annotated && !c.ingressIPMap.Has(ingressIPKey) {
+			return nil, false
+		}
+
+		c.ingressIPMap.Delete(ingressIPKey)
+		logger.Infof("Global ingress Pod %s deleted", key)
+
+		return ingressIP, false
+	}
+
+	if !annotated {
+		if !c.ingressIPMap.Has(ingressIPKey) {
+			return nil, false
+		}
+
+		logger.Infof("Global ingress annotation removed from Pod %s", key)
+
+		err := c.ingressIPs.Namespace(ingressIP.Namespace).Delete(context.TODO(), ingressIP.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			logger.Errorf(err, "Error deleting GlobalIngressIP %q", ingressIPKey)
+			return nil, true
+		}
+
+		c.ingressIPMap.Delete(ingressIPKey)
+
+		return nil, false
+	}
+
+	if c.ingressIPMap.Has(ingressIPKey) {
+		return nil, false
+	}
+
+	logger.Infof("%q global ingress Pod %s", op, key)
+
+	ingressIP.Spec = submarinerv1.GlobalIngressIPSpec{
+		Target: submarinerv1.Pod,
+		PodRef: &corev1.LocalObjectReference{Name: pod.Name},
+	}
+
+	c.ingressIPMap.Insert(ingressIPKey)
+
+	return ingressIP, false
+}
+
+// areAnnotatedPodsEqual compares the global ingress annotation. The GlobalIngressIP controller tracks the Pod IP itself.
+func areAnnotatedPodsEqual(obj1, obj2 *unstructured.Unstructured) bool {
+	return obj1.GetAnnotations()[GlobalIngressPodAnnotation] == obj2.GetAnnotations()[GlobalIngressPodAnnotation]
+}
+
 func arePodsEqual(obj1, obj2 *unstructured.Unstructured) bool {
 	phase1, _, _ := unstructured.NestedString(obj1.Object, "status", "phase")
 	phase2, _, _ := unstructured.NestedString(obj2.Object, "status", "phase")
@@ -0,0 +1,164 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/admiral/pkg/syncer"
+	"github.com/submariner-io/admiral/pkg/syncer/test"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/globalnet/controllers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+var _ = Describe("GlobalIngress Pod controller", func() {
+	t := newGlobalIngressPodControllerTestDriver()
+
+	var pod *corev1.Pod
+
+	ingressIPName := "pod-ingress-nginx"
+
+	BeforeEach(func() {
+		pod = newPod("ns1")
+	})
+
+	JustBeforeEach(func() {
+		t.createPod(pod)
+	})
+
+	When("a Pod with the global ingress annotation is created", func() {
+		BeforeEach(func() {
+			addAnnotation(pod, controllers.GlobalIngressPodAnnotation, "true")
+		})
+
+		It("should create a GlobalIngressIP targeting the Pod", func() {
+			ingressIP := t.awaitPodGlobalIngressIP(pod.Namespace, ingressIPName)
+			Expect(ingressIP.Spec.Target).To(Equal(submarinerv1.Pod))
+			Expect(ingressIP.Spec.PodRef).To(Equal(&corev1.LocalObjectReference{Name: pod.Name}))
+			Expect(ingressIP.Labels).To(HaveKeyWithValue(controllers.GlobalIngressPodLabel, "true"))
+		})
+
+		Context("and then deleted", func() {
+			It("should delete the GlobalIngressIP", func() {
+				t.awaitPodGlobalIngressIP(pod.Namespace, ingressIPName)
+				t.deletePod(pod)
+				test.AwaitNoResource(t.ingressIPs.Namespace(pod.Namespace), ingressIPName)
+			})
+		})
+
+		Context("with a long name", func() {
+			BeforeEach(func() {
+				pod.Name = strings.Repeat("a", 60) + "-1"
+			})
+
+			It("should create a distinct GlobalIngressIP for another Pod with the same name prefix", func() {
+				other := newPod(pod.Namespace)
+				other.Name = strings.Repeat("a", 60) + "-2"
+				addAnnotation(other, controllers.GlobalIngressPodAnnotation, "true")
+				t.createPod(other)
+
+				Eventually(func() []string {
+					list, err := t.ingressIPs.Namespace(pod.Namespace).List(context.TODO(), metav1.ListOptions{})
+					Expect(err).To(Succeed())
+
+					var podRefs []string
+
+					for i := range list.Items {
+						Expect(len(list.Items[i].GetName())).To(BeNumerically("<=", 63))
+
+						name, _, _ := unstructured.NestedString(list.Items[i].Object, "spec", "podRef", "name")
+						podRefs = append(podRefs, name)
+					}
+
+					return podRefs
+				}).Should(ConsistOf(pod.Name, other.Name))
+			})
+		})
+
+		Context("and then the annotation is removed", func() {
+			It("should delete the GlobalIngressIP", func() {
+				t.awaitPodGlobalIngressIP(pod.Namespace, ingressIPName)
+
+				pod.Annotations = nil
+				test.UpdateResource(t.pods.Namespace(pod.Namespace), pod)
+
+				test.AwaitNoResource(t.ingressIPs.Namespace(pod.Namespace), ingressIPName)
+			})
+		})
+	})
+
+	When("a Pod without the global ingress annotation is created", func() {
+		It("should not create a GlobalIngressIP", func() {
+			Consistently(func() error {
+				_, err := t.ingressIPs.Namespace(pod.Namespace).Get(context.TODO(), ingressIPName, metav1.GetOptions{})
+				return err
+			}, 300*time.Millisecond).ShouldNot(Succeed())
+		})
+	})
+})
+
+type globalIngressPodControllerTestDriver struct {
+	*testDriverBase
+	ingressIPs dynamic.NamespaceableResourceInterface
+}
+
+func newGlobalIngressPodControllerTestDriver() *globalIngressPodControllerTestDriver {
+	t := &globalIngressPodControllerTestDriver{}
+
+	BeforeEach(func() {
+		t.testDriverBase = newTestDriverBase()
+		t.ingressIPs = t.dynClient.Resource(*test.GetGroupVersionResourceFor(t.restMapper, &submarinerv1.GlobalIngressIP{}))
+	})
+
+	JustBeforeEach(func() {
+		var err error
+
+		t.controller, err = controllers.NewGlobalIngressPodController(&syncer.ResourceSyncerConfig{
+			SourceClient: t.dynClient,
+			RestMapper:   t.restMapper,
+			Scheme:       t.scheme,
+		})
+
+		Expect(err).To(Succeed())
+		Expect(t.controller.Start()).To(Succeed())
+	})
+
+	AfterEach(func() {
+		t.testDriverBase.afterEach()
+	})
+
+	return t
+}
+
+func (t *globalIngressPodControllerTestDriver) awaitPodGlobalIngressIP(namespace, name string) *submarinerv1.GlobalIngressIP {
+	obj := test.AwaitResource(t.ingressIPs.Namespace(namespace), name)
+
+	ingressIP := &submarinerv1.GlobalIngressIP{}
+	Expect(t.scheme.Convert(obj, ingressIP, nil)).To(Succeed())
+
+	return ingressIP
+}
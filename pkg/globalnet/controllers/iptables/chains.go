@@ -0,0 +1,178 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iptables
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/submariner/pkg/globalnet/constants"
+	routeAgent "github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
+	k8serrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+func (i *ipTables) CreateGlobalNetMarkingChain() error {
+	logger.V(log.DEBUG).Infof("Install/ensure %s chain exists", constants.SmGlobalnetMarkChain)
+
+	if err := i.ipt.CreateChainIfNotExists("nat", constants.SmGlobalnetMarkChain); err != nil {
+		return errors.Wrapf(err, "error creating iptables chain %s", constants.SmGlobalnetMarkChain)
+	}
+
+	// The forwarding chains in the filter table are used to apply the per GlobalEgressIP accounting and limits to
+	// traffic destined to remote clusters.
+	logger.V(log.DEBUG).Infof("Install/ensure %s chain exists", constants.SmGlobalnetForwardChain)
+
+	if err := i.ipt.CreateChainIfNotExists(constants.FilterTable, constants.SmGlobalnetForwardChain); err != nil {
+		return errors.Wrapf(err, "error creating iptables chain %s", constants.SmGlobalnetForwardChain)
+	}
+
+	logger.V(log.DEBUG).Infof("Install/ensure %s chain exists", constants.SmGlobalnetEgressChainForLimits)
+
+	if err := i.ipt.CreateChainIfNotExists(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits); err != nil {
+		return errors.Wrapf(err, "error creating iptables chain %s", constants.SmGlobalnetEgressChainForLimits)
+	}
+
+	forwardToSubGlobalNetChain := []string{"-j", constants.SmGlobalnetForwardChain}
+	if err := i.ipt.PrependUnique(constants.FilterTable, routeAgent.ForwardChain, forwardToSubGlobalNetChain); err != nil {
+		logger.Errorf(err, "Error inserting iptables rule %q", strings.Join(forwardToSubGlobalNetChain, " "))
+	}
+
+	return nil
+}
+
+//nolint:gocyclo // Lots of error checks, but simple logic
+func (i *ipTables) CreateGlobalnetChains(_ string) error {
+	logger.V(log.DEBUG).Infof("Install/ensure %s chain exists", constants.SmGlobalnetIngressChain)
+
+	if err := i.ipt.CreateChainIfNotExists("nat", constants.SmGlobalnetIngressChain); err != nil {
+		return errors.Wrapf(err, "error creating iptables chain %s", constants.SmGlobalnetIngressChain)
+	}
+
+	forwardToSubGlobalNetChain := []string{"-j", constants.SmGlobalnetIngressChain}
+	if err := i.ipt.PrependUnique("nat", "PREROUTING", forwardToSubGlobalNetChain); err != nil {
+		logger.Errorf(err, "Error appending iptables rule %q", strings.Join(forwardToSubGlobalNetChain, " "))
+	}
+
+	logger.V(log.DEBUG).Infof("Install/ensure %s chain exists", constants.SmGlobalnetEgressChain)
+
+	if err := i.ipt.CreateChainIfNotExists("nat", constants.SmGlobalnetEgressChain); err != nil {
+		return errors.Wrapf(err, "error creating iptables chain %s", constants.SmGlobalnetEgressChain)
+	}
+
+	logger.V(log.DEBUG).Infof("Install/ensure %s chain exists", routeAgent.SmPostRoutingChain)
+
+	if err := i.ipt.CreateChainIfNotExists("nat", routeAgent.SmPostRoutingChain); err != nil {
+		return errors.Wrapf(err, "error creating iptables chain %s", routeAgent.SmPostRoutingChain)
+	}
+
+	forwardToSubGlobalNetChain = []string{"-j", constants.SmGlobalnetEgressChain}
+	if err := i.ipt.PrependUnique("nat", routeAgent.SmPostRoutingChain, forwardToSubGlobalNetChain); err != nil {
+		logger.Errorf(err, "Error inserting iptables rule %q", strings.Join(forwardToSubGlobalNetChain, " "))
+	}
+
+	if err := i.CreateGlobalNetMarkingChain(); err != nil {
+		return err
+	}
+
+	forwardToSubGlobalNetChain = []string{"-j", constants.SmGlobalnetMarkChain}
+	if err := i.ipt.PrependUnique("nat", constants.SmGlobalnetEgressChain, forwardToSubGlobalNetChain); err != nil {
+		logger.Errorf(err, "Error inserting iptables rule %q", strings.Join(forwardToSubGlobalNetChain, " "))
+	}
+
+	// The position of each sub-chain's jump rule in the egress chain, the marking chain being the first.
+	subChains := []string{
+		constants.SmGlobalnetEgressChainForPods,
+		constants.SmGlobalnetEgressChainForHeadlessSvcPods,
+		constants.SmGlobalnetEgressChainForHeadlessSvcEPs,
+		constants.SmGlobalnetEgressChainForNamespace,
+		constants.SmGlobalnetEgressChainForSelectors,
+		constants.SmGlobalnetEgressChainForCluster,
+	}
+
+	for _, chain := range subChains {
+		logger.V(log.DEBUG).Infof("Install/ensure %s chain exists", chain)
+
+		if err := i.ipt.CreateChainIfNotExists("nat", chain); err != nil {
+			return errors.Wrapf(err, "error creating iptables chain %s", chain)
+		}
+	}
+
+	for index, chain := range subChains {
+		forwardToSubGlobalNetChain = []string{"-j", chain}
+		if err := i.ipt.InsertUnique("nat", constants.SmGlobalnetEgressChain, index+2, forwardToSubGlobalNetChain); err != nil {
+			logger.Errorf(err, "Error inserting iptables rule %q", strings.Join(forwardToSubGlobalNetChain, " "))
+		}
+	}
+
+	return nil
+}
+
+func (i *ipTables) ClearGlobalnetChains() error {
+	var errs []error
+
+	if err := i.ipt.ClearChain("nat", constants.SmGlobalnetIngressChain); err != nil {
+		errs = append(errs, errors.Wrapf(err, "error flushing rules in %s chain", constants.SmGlobalnetIngressChain))
+	}
+
+	if err := i.ipt.ClearChain("nat", constants.SmGlobalnetEgressChain); err != nil {
+		errs = append(errs, errors.Wrapf(err, "error flushing rules in %s chain", constants.SmGlobalnetEgressChain))
+	}
+
+	if err := i.ipt.ClearChain("nat", constants.SmGlobalnetMarkChain); err != nil {
+		errs = append(errs, errors.Wrapf(err, "error flushing rules in %s chain", constants.SmGlobalnetMarkChain))
+	}
+
+	if err := i.ipt.ClearChain(constants.FilterTable, constants.SmGlobalnetForwardChain); err != nil {
+		errs = append(errs, errors.Wrapf(err, "error flushing rules in %s chain", constants.SmGlobalnetForwardChain))
+	}
+
+	return k8serrors.NewAggregate(errs)
+}
+
+func (i *ipTables) MarkRemoteClusterTraffic(remoteCidr, globalNetIPTableMark string, addRules bool) error {
+	ruleSpec := []string{"-d", remoteCidr, "-j", "MARK", "--set-mark", globalNetIPTableMark}
+	limitRuleSpec := []string{"-d", remoteCidr, "-j", constants.SmGlobalnetEgressChainForLimits}
+
+	if addRules {
+		logger.V(log.DEBUG).Infof("Marking traffic destined to remote cluster: %s", strings.Join(ruleSpec, " "))
+
+		if err := i.ipt.AppendUnique("nat", constants.SmGlobalnetMarkChain, ruleSpec...); err != nil {
+			return errors.Wrapf(err, "error appending iptables rule \"%s\"", strings.Join(ruleSpec, " "))
+		}
+
+		if err := i.ipt.AppendUnique(constants.FilterTable, constants.SmGlobalnetForwardChain, limitRuleSpec...); err != nil {
+			return errors.Wrapf(err, "error appending iptables rule \"%s\"", strings.Join(limitRuleSpec, " "))
+		}
+
+		return nil
+	}
+
+	logger.V(log.DEBUG).Infof("Deleting rule that marks remote cluster traffic: %s", strings.Join(ruleSpec, " "))
+
+	if err := i.ipt.Delete("nat", constants.SmGlobalnetMarkChain, ruleSpec...); err != nil {
+		return errors.Wrapf(err, "error deleting iptables rule \"%s\"", strings.Join(ruleSpec, " "))
+	}
+
+	if err := i.ipt.Delete(constants.FilterTable, constants.SmGlobalnetForwardChain, limitRuleSpec...); err != nil {
+		return errors.Wrapf(err, "error deleting iptables rule \"%s\"", strings.Join(limitRuleSpec, " "))
+	}
+
+	return nil
+}
@@ -0,0 +1,548 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iptables_test
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner/pkg/globalnet/constants"
+	"github.com/submariner-io/submariner/pkg/globalnet/controllers/iptables"
+	"github.com/submariner-io/submariner/pkg/ipset"
+	fakeIPSet "github.com/submariner-io/submariner/pkg/ipset/fake"
+	pkgiptables "github.com/submariner-io/submariner/pkg/iptables"
+	fakeIPT "github.com/submariner-io/submariner/pkg/iptables/fake"
+	"github.com/submariner-io/submariner/pkg/nftables"
+	fakeNFT "github.com/submariner-io/submariner/pkg/nftables/fake"
+	routeAgent "github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
+)
+
+const (
+	mark       = "0xC0000/0xC0000"
+	localCIDR  = "10.1.0.0/16"
+	remoteCIDR = "10.2.0.0/16"
+	globalIP   = "169.254.1.10"
+	globalIP2  = "169.254.1.11"
+	podIP      = "10.1.1.5"
+	ipSetName  = "SM-GN-ABCDEFGHIJKLMNO"
+)
+
+// dataPath verifies the data path programmed by a backend. The same specs are run against each backend so they're
+// expressed in terms of the observable translations rather than the rules that implement them.
+type dataPath interface {
+	awaitChain(chain string)
+	awaitNoChain(chain string)
+	awaitEgressSNAT(chain, source, snatIP string)
+	awaitNoEgressSNAT(chain, source, snatIP string)
+	awaitNoEgressSNATFor(chain, ipSetName, member string)
+	awaitIngressDNAT(globalIP, target string)
+	awaitNoIngressDNAT(globalIP, target string)
+	awaitHealthCheckDNAT(globalIP, target string)
+	awaitNoHealthCheckDNAT(globalIP, target string)
+	awaitRemoteTrafficMarked(cidr string)
+	awaitNoRemoteTrafficMarked(cidr string)
+	awaitEgressLimits(ipSetName string, limits iptables.EgressLimits)
+	awaitNoEgressLimits(ipSetName string)
+}
+
+var _ = Describe("Interface conformance", func() {
+	testConformance(iptables.IPTablesBackend, newIPTablesDataPath)
+	testConformance(iptables.NFTablesBackend, newNFTablesDataPath)
+})
+
+func testConformance(backend string, newDataPath func() dataPath) {
+	Context(fmt.Sprintf("with the %s backend", backend), func() {
+		var (
+			dp    dataPath
+			iface iptables.Interface
+		)
+
+		BeforeEach(func() {
+			iptables.ResetNFTEgressSNATBindings()
+
+			dp = newDataPath()
+
+			var err error
+
+			iface, err = iptables.New(backend)
+			Expect(err).To(Succeed())
+
+			Expect(iface.CreateGlobalnetChains(mark)).To(Succeed())
+		})
+
+		createEgressSet := func(entries ...string) (ipset.Interface, *ipset.IPSet) {
+			sets, err := iptables.NewIPSet(backend)
+			Expect(err).To(Succeed())
+
+			set := &ipset.IPSet{Name: ipSetName, SetType: ipset.HashIP}
+			Expect(sets.CreateSet(set, true)).To(Succeed())
+
+			for _, entry := range entries {
+				Expect(sets.AddEntry(entry, set, true)).To(Succeed())
+			}
+
+			return sets, set
+		}
+
+		AfterEach(func() {
+			pkgiptables.NewFunc = nil
+			ipset.NewFunc = nil
+			nftables.NewFunc = nil
+		})
+
+		It("should create the Globalnet chains", func() {
+			for _, chain := range []string{
+				constants.SmGlobalnetIngressChain, constants.SmGlobalnetEgressChain, constants.SmGlobalnetMarkChain,
+				constants.SmGlobalnetEgressChainForPods, constants.SmGlobalnetEgressChainForHeadlessSvcPods,
+				constants.SmGlobalnetEgressChainForHeadlessSvcEPs, constants.SmGlobalnetEgressChainForNamespace,
+				constants.SmGlobalnetEgressChainForSelectors, constants.SmGlobalnetEgressChainForCluster,
+				constants.SmGlobalnetForwardChain, constants.SmGlobalnetEgressChainForLimits,
+			} {
+				dp.awaitChain(chain)
+			}
+
+			By("Creating them again")
+
+			Expect(iface.CreateGlobalnetChains(mark)).To(Succeed())
+		})
+
+		It("should add and remove the cluster egress rules", func() {
+			Expect(iface.AddClusterEgressRules(localCIDR, globalIP, mark)).To(Succeed())
+			dp.awaitEgressSNAT(constants.SmGlobalnetEgressChainForCluster, localCIDR, globalIP)
+
+			Expect(iface.RemoveClusterEgressRules(localCIDR, globalIP, mark)).To(Succeed())
+			dp.awaitNoEgressSNAT(constants.SmGlobalnetEgressChainForCluster, localCIDR, globalIP)
+		})
+
+		It("should add and remove the egress rules for a set of Pods", func() {
+			snatIP := globalIP + "-" + globalIP2
+			createEgressSet(podIP)
+
+			Expect(iface.AddEgressRulesForPods("ns/egress", ipSetName, snatIP, mark)).To(Succeed())
+			dp.awaitEgressSNAT(constants.SmGlobalnetEgressChainForPods, "@"+ipSetName, snatIP)
+
+			Expect(iface.RemoveEgressRulesForPods("ns/egress", ipSetName, snatIP, mark)).To(Succeed())
+			dp.awaitNoEgressSNAT(constants.SmGlobalnetEgressChainForPods, "@"+ipSetName, snatIP)
+		})
+
+		It("should add and remove the egress rules for a Namespace", func() {
+			createEgressSet(podIP)
+
+			Expect(iface.AddEgressRulesForNamespace("ns", ipSetName, globalIP, mark)).To(Succeed())
+			dp.awaitEgressSNAT(constants.SmGlobalnetEgressChainForNamespace, "@"+ipSetName, globalIP)
+
+			Expect(iface.RemoveEgressRulesForNamespace("ns", ipSetName, globalIP, mark)).To(Succeed())
+			dp.awaitNoEgressSNAT(constants.SmGlobalnetEgressChainForNamespace, "@"+ipSetName, globalIP)
+		})
+
+		It("should add and remove the egress rules for cluster-wide selectors", func() {
+			createEgressSet(podIP)
+
+			Expect(iface.AddEgressRulesForSelectors("payments", ipSetName, globalIP, mark)).To(Succeed())
+			dp.awaitEgressSNAT(constants.SmGlobalnetEgressChainForSelectors, "@"+ipSetName, globalIP)
+
+			Expect(iface.RemoveEgressRulesForSelectors("payments", ipSetName, globalIP, mark)).To(Succeed())
+			dp.awaitNoEgressSNAT(constants.SmGlobalnetEgressChainForSelectors, "@"+ipSetName, globalIP)
+		})
+
+		It("should translate the Pods added to and removed from an egress set", func() {
+			sets, set := createEgressSet()
+
+			Expect(iface.AddEgressRulesForPods("ns/egress", ipSetName, globalIP, mark)).To(Succeed())
+
+			Expect(sets.AddEntry(podIP, set, true)).To(Succeed())
+			dp.awaitEgressSNAT(constants.SmGlobalnetEgressChainForPods, "@"+ipSetName, globalIP)
+
+			Expect(sets.DelEntry(podIP, ipSetName)).To(Succeed())
+			dp.awaitNoEgressSNATFor(constants.SmGlobalnetEgressChainForPods, ipSetName, podIP)
+		})
+
+		It("should add and remove the egress rules for headless Service Pods and Endpoints", func() {
+			Expect(iface.AddEgressRulesForHeadlessSvc("ns/svc", podIP, globalIP, mark, iptables.PodTarget)).To(Succeed())
+			dp.awaitEgressSNAT(constants.SmGlobalnetEgressChainForHeadlessSvcPods, podIP, globalIP)
+
+			Expect(iface.AddEgressRulesForHeadlessSvc("ns/svc", podIP, globalIP2, mark, iptables.EndpointsTarget)).To(Succeed())
+			dp.awaitEgressSNAT(constants.SmGlobalnetEgressChainForHeadlessSvcEPs, podIP, globalIP2)
+
+			Expect(iface.RemoveEgressRulesForHeadlessSvc("ns/svc", podIP, globalIP, mark, iptables.PodTarget)).To(Succeed())
+			dp.awaitNoEgressSNAT(constants.SmGlobalnetEgressChainForHeadlessSvcPods, podIP, globalIP)
+			dp.awaitEgressSNAT(constants.SmGlobalnetEgressChainForHeadlessSvcEPs, podIP, globalIP2)
+
+			Expect(iface.RemoveEgressRulesForHeadlessSvc("ns/svc", podIP, globalIP2, mark, iptables.EndpointsTarget)).To(Succeed())
+			dp.awaitNoEgressSNAT(constants.SmGlobalnetEgressChainForHeadlessSvcEPs, podIP, globalIP2)
+		})
+
+		It("should add and remove the ingress rules for a headless Service", func() {
+			Expect(iface.AddIngressRulesForHeadlessSvc(globalIP, podIP, iptables.PodTarget)).To(Succeed())
+			dp.awaitIngressDNAT(globalIP, podIP)
+
+			Expect(iface.RemoveIngressRulesForHeadlessSvc(globalIP, podIP, iptables.PodTarget)).To(Succeed())
+			dp.awaitNoIngressDNAT(globalIP, podIP)
+		})
+
+		It("should reject empty ingress addresses for a headless Service", func() {
+			Expect(iface.AddIngressRulesForHeadlessSvc("", podIP, iptables.PodTarget)).ToNot(Succeed())
+			Expect(iface.RemoveIngressRulesForHeadlessSvc(globalIP, "", iptables.PodTarget)).ToNot(Succeed())
+		})
+
+		It("should add and remove the ingress rules for the health check", func() {
+			Expect(iface.AddIngressRulesForHealthCheck(podIP, globalIP)).To(Succeed())
+			dp.awaitHealthCheckDNAT(globalIP, podIP)
+
+			Expect(iface.RemoveIngressRulesForHealthCheck(podIP, globalIP)).To(Succeed())
+			dp.awaitNoHealthCheckDNAT(globalIP, podIP)
+		})
+
+		It("should mark and unmark the traffic destined to a remote cluster", func() {
+			Expect(iface.MarkRemoteClusterTraffic(remoteCIDR, mark, true)).To(Succeed())
+			dp.awaitRemoteTrafficMarked(remoteCIDR)
+
+			Expect(iface.MarkRemoteClusterTraffic(remoteCIDR, mark, false)).To(Succeed())
+			dp.awaitNoRemoteTrafficMarked(remoteCIDR)
+		})
+
+		It("should update and remove the egress limit rules and report their counters", func() {
+			limits := iptables.EgressLimits{BandwidthLimit: 1024 * 1024, MaxConnections: 100}
+
+			Expect(iface.UpdateEgressRulesForLimits("ns/egress", ipSetName, limits)).To(Succeed())
+			dp.awaitEgressLimits(ipSetName, limits)
+
+			counters, err := iface.GetEgressCounters()
+			Expect(err).To(Succeed())
+			Expect(counters).To(HaveKeyWithValue(ipSetName, iptables.EgressCounters{}))
+
+			limits = iptables.EgressLimits{MaxConnections: 50}
+
+			Expect(iface.UpdateEgressRulesForLimits("ns/egress", ipSetName, limits)).To(Succeed())
+			dp.awaitEgressLimits(ipSetName, limits)
+
+			Expect(iface.RemoveEgressRulesForLimits("ns/egress", ipSetName)).To(Succeed())
+			dp.awaitNoEgressLimits(ipSetName)
+
+			counters, err = iface.GetEgressCounters()
+			Expect(err).To(Succeed())
+			Expect(counters).ToNot(HaveKey(ipSetName))
+		})
+
+		It("should clear the ingress and marking rules when the Globalnet chains are cleared", func() {
+			Expect(iface.AddIngressRulesForHeadlessSvc(globalIP, podIP, iptables.PodTarget)).To(Succeed())
+			Expect(iface.AddIngressRulesForHealthCheck(podIP, globalIP2)).To(Succeed())
+			Expect(iface.MarkRemoteClusterTraffic(remoteCIDR, mark, true)).To(Succeed())
+
+			Expect(iface.ClearGlobalnetChains()).To(Succeed())
+
+			dp.awaitNoIngressDNAT(globalIP, podIP)
+			dp.awaitNoHealthCheckDNAT(globalIP2, podIP)
+			dp.awaitNoRemoteTrafficMarked(remoteCIDR)
+
+			By("Recreating the chains")
+
+			Expect(iface.CreateGlobalnetChains(mark)).To(Succeed())
+			Expect(iface.AddIngressRulesForHeadlessSvc(globalIP, podIP, iptables.PodTarget)).To(Succeed())
+			dp.awaitIngressDNAT(globalIP, podIP)
+		})
+
+		It("should maintain the sets referenced by the egress rules", func() {
+			sets, _ := createEgressSet(podIP)
+
+			Expect(sets.ListSets()).To(ContainElement(ipSetName))
+			Expect(sets.ListEntries(ipSetName)).To(ConsistOf(podIP))
+			Expect(sets.TestEntry(podIP, ipSetName)).To(BeTrue())
+
+			Expect(sets.DelEntry(podIP, ipSetName)).To(Succeed())
+			Expect(sets.TestEntry(podIP, ipSetName)).To(BeFalse())
+
+			Expect(sets.DestroySet(ipSetName)).To(Succeed())
+			Expect(sets.ListSets()).ToNot(ContainElement(ipSetName))
+		})
+
+		It("should remove the Globalnet chains on uninstall", func() {
+			natChains := []string{
+				constants.SmGlobalnetEgressChainForCluster, constants.SmGlobalnetEgressChainForSelectors,
+				constants.SmGlobalnetEgressChainForHeadlessSvcPods,
+				constants.SmGlobalnetEgressChainForHeadlessSvcEPs, constants.SmGlobalnetEgressChainForNamespace,
+				constants.SmGlobalnetEgressChainForPods, constants.SmGlobalnetIngressChain, constants.SmGlobalnetMarkChain,
+				constants.SmGlobalnetEgressChain,
+			}
+
+			filterChains := []string{constants.SmGlobalnetForwardChain, constants.SmGlobalnetEgressChainForLimits}
+
+			for _, chain := range natChains {
+				Expect(iface.FlushIPTableChain(constants.NATTable, chain)).To(Succeed())
+			}
+
+			Expect(iface.FlushIPTableChain(constants.NATTable, routeAgent.SmPostRoutingChain)).To(Succeed())
+			Expect(iface.DeleteIPTableRule(constants.NATTable, "PREROUTING", constants.SmGlobalnetIngressChain)).To(Succeed())
+
+			for _, chain := range natChains {
+				Expect(iface.DeleteIPTableChain(constants.NATTable, chain)).To(Succeed())
+			}
+
+			for _, chain := range filterChains {
+				Expect(iface.FlushIPTableChain(constants.FilterTable, chain)).To(Succeed())
+			}
+
+			Expect(iface.DeleteIPTableRule(constants.FilterTable, routeAgent.ForwardChain, constants.SmGlobalnetForwardChain)).To(Succeed())
+
+			for _, chain := range filterChains {
+				Expect(iface.DeleteIPTableChain(constants.FilterTable, chain)).To(Succeed())
+			}
+
+			for _, chain := range append(natChains, filterChains...) {
+				dp.awaitNoChain(chain)
+			}
+		})
+	})
+}
+
+type iptablesDataPath struct {
+	ipt   *fakeIPT.IPTables
+	ipSet *fakeIPSet.IPSet
+}
+
+func newIPTablesDataPath() dataPath {
+	dp := &iptablesDataPath{ipt: fakeIPT.New(), ipSet: fakeIPSet.New()}
+
+	pkgiptables.NewFunc = func() (pkgiptables.Interface, error) {
+		return dp.ipt, nil
+	}
+
+	ipset.NewFunc = func() ipset.Interface {
+		return dp.ipSet
+	}
+
+	return dp
+}
+
+func (d *iptablesDataPath) awaitChain(chain string) {
+	d.ipt.AwaitChain(tableFor(chain), chain)
+}
+
+func (d *iptablesDataPath) awaitNoChain(chain string) {
+	d.ipt.AwaitNoChain(tableFor(chain), chain)
+}
+
+func (d *iptablesDataPath) snatRuleMatcher(source, snatIP string) interface{} {
+	sourceMatch := "-s " + source + " "
+	if strings.HasPrefix(source, "@") {
+		sourceMatch = "--match-set " + strings.TrimPrefix(source, "@") + " src "
+	}
+
+	return And(ContainSubstring(sourceMatch), ContainSubstring("--mark "+mark+" "), HaveSuffix("-j SNAT --to "+snatIP))
+}
+
+func (d *iptablesDataPath) awaitEgressSNAT(chain, source, snatIP string) {
+	d.ipt.AwaitRule(constants.NATTable, chain, d.snatRuleMatcher(source, snatIP))
+}
+
+func (d *iptablesDataPath) awaitNoEgressSNAT(chain, source, snatIP string) {
+	d.ipt.AwaitNoRule(constants.NATTable, chain, d.snatRuleMatcher(source, snatIP))
+}
+
+// The set match rule remains, so a member is no longer translated once it's removed from the set.
+func (d *iptablesDataPath) awaitNoEgressSNATFor(_, ipSetName, member string) {
+	d.ipSet.AwaitNoEntry(ipSetName, member)
+}
+
+func (d *iptablesDataPath) awaitIngressDNAT(globalIP, target string) {
+	d.ipt.AwaitRule(constants.NATTable, constants.SmGlobalnetIngressChain, "-d "+globalIP+" -j DNAT --to "+target)
+}
+
+func (d *iptablesDataPath) awaitNoIngressDNAT(globalIP, target string) {
+	d.ipt.AwaitNoRule(constants.NATTable, constants.SmGlobalnetIngressChain, "-d "+globalIP+" -j DNAT --to "+target)
+}
+
+func (d *iptablesDataPath) awaitHealthCheckDNAT(globalIP, target string) {
+	d.ipt.AwaitRule(constants.NATTable, constants.SmGlobalnetIngressChain, "-p icmp -d "+globalIP+" -j DNAT --to "+target)
+}
+
+func (d *iptablesDataPath) awaitNoHealthCheckDNAT(globalIP, target string) {
+	d.ipt.AwaitNoRule(constants.NATTable, constants.SmGlobalnetIngressChain, "-p icmp -d "+globalIP+" -j DNAT --to "+target)
+}
+
+func (d *iptablesDataPath) awaitRemoteTrafficMarked(cidr string) {
+	d.ipt.AwaitRule(constants.NATTable, constants.SmGlobalnetMarkChain, "-d "+cidr+" -j MARK --set-mark "+mark)
+	d.ipt.AwaitRule(constants.FilterTable, constants.SmGlobalnetForwardChain, "-d "+cidr+" -j "+constants.SmGlobalnetEgressChainForLimits)
+}
+
+func (d *iptablesDataPath) awaitNoRemoteTrafficMarked(cidr string) {
+	d.ipt.AwaitNoRule(constants.NATTable, constants.SmGlobalnetMarkChain, ContainSubstring("-d "+cidr+" "))
+	d.ipt.AwaitNoRule(constants.FilterTable, constants.SmGlobalnetForwardChain, ContainSubstring("-d "+cidr+" "))
+}
+
+func (d *iptablesDataPath) awaitEgressLimits(ipSetName string, limits iptables.EgressLimits) {
+	matchSet := ContainSubstring("--match-set " + ipSetName + " src")
+	connLimit := And(matchSet, ContainSubstring("--connlimit-above"))
+	bwLimit := And(matchSet, ContainSubstring("--hashlimit-above"))
+
+	d.ipt.AwaitRule(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits, "-m set --match-set "+ipSetName+" src")
+
+	if limits.MaxConnections > 0 {
+		d.ipt.AwaitRule(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits,
+			And(matchSet, ContainSubstring(fmt.Sprintf("--connlimit-above %d ", limits.MaxConnections))))
+	} else {
+		d.ipt.AwaitNoRule(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits, connLimit)
+	}
+
+	if limits.BandwidthLimit > 0 {
+		d.ipt.AwaitRule(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits, bwLimit)
+	} else {
+		d.ipt.AwaitNoRule(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits, bwLimit)
+	}
+}
+
+func (d *iptablesDataPath) awaitNoEgressLimits(ipSetName string) {
+	d.ipt.AwaitNoRule(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits,
+		ContainSubstring("--match-set "+ipSetName+" src"))
+}
+
+type nftablesDataPath struct {
+	nft *fakeNFT.NFTables
+}
+
+func newNFTablesDataPath() dataPath {
+	dp := &nftablesDataPath{nft: fakeNFT.New()}
+
+	nftables.NewFunc = func() (nftables.Interface, error) {
+		return dp.nft, nil
+	}
+
+	return dp
+}
+
+func (d *nftablesDataPath) awaitChain(chain string) {
+	d.nft.AwaitChain(iptables.NFTablesTableName, chain)
+}
+
+func (d *nftablesDataPath) awaitNoChain(chain string) {
+	d.nft.AwaitNoChain(iptables.NFTablesTableName, chain)
+}
+
+func (d *nftablesDataPath) snatMap(chain string) string {
+	switch chain {
+	case constants.SmGlobalnetEgressChainForHeadlessSvcPods:
+		return "SM-GN-HDLS-PODS-SNAT"
+	case constants.SmGlobalnetEgressChainForHeadlessSvcEPs:
+		return "SM-GN-HDLS-EPS-SNAT"
+	case constants.SmGlobalnetEgressChainForPods:
+		return "SM-GN-EGRESS-PODS-SNAT"
+	case constants.SmGlobalnetEgressChainForNamespace:
+		return "SM-GN-EGRESS-NS-SNAT"
+	case constants.SmGlobalnetEgressChainForSelectors:
+		return "SM-GN-EGRESS-SEL-SNAT"
+	}
+
+	return ""
+}
+
+// The members of the egress sets are mapped to their SNAT IPs, so the specs verify the mapping of the Pod they add.
+func (d *nftablesDataPath) awaitEgressSNAT(chain, source, snatIP string) {
+	if m := d.snatMap(chain); m != "" {
+		if strings.HasPrefix(source, "@") {
+			source = podIP
+		}
+
+		d.nft.AwaitRule(iptables.NFTablesTableName, chain, "map @"+m)
+		d.nft.AwaitMapElement(iptables.NFTablesTableName, m, source, snatIP)
+
+		return
+	}
+
+	d.nft.AwaitRule(iptables.NFTablesTableName, chain, "snat "+source+" "+snatIP)
+}
+
+func (d *nftablesDataPath) awaitNoEgressSNAT(chain, source, snatIP string) {
+	if m := d.snatMap(chain); m != "" {
+		if strings.HasPrefix(source, "@") {
+			source = podIP
+		}
+
+		d.nft.AwaitNoMapElement(iptables.NFTablesTableName, m, source)
+
+		return
+	}
+
+	d.nft.AwaitNoRule(iptables.NFTablesTableName, chain, "snat "+source+" "+snatIP)
+}
+
+func (d *nftablesDataPath) awaitNoEgressSNATFor(chain, _, member string) {
+	d.nft.AwaitNoMapElement(iptables.NFTablesTableName, d.snatMap(chain), member)
+}
+
+func (d *nftablesDataPath) awaitIngressDNAT(globalIP, target string) {
+	d.nft.AwaitRule(iptables.NFTablesTableName, constants.SmGlobalnetIngressChain, "map @SM-GN-INGRESS-DNAT")
+	d.nft.AwaitMapElement(iptables.NFTablesTableName, "SM-GN-INGRESS-DNAT", globalIP, target)
+}
+
+func (d *nftablesDataPath) awaitNoIngressDNAT(globalIP, _ string) {
+	d.nft.AwaitNoMapElement(iptables.NFTablesTableName, "SM-GN-INGRESS-DNAT", globalIP)
+}
+
+func (d *nftablesDataPath) awaitHealthCheckDNAT(globalIP, target string) {
+	d.nft.AwaitRule(iptables.NFTablesTableName, constants.SmGlobalnetIngressChain, "dnat-icmp "+globalIP+" "+target)
+}
+
+func (d *nftablesDataPath) awaitNoHealthCheckDNAT(globalIP, target string) {
+	d.nft.AwaitNoRule(iptables.NFTablesTableName, constants.SmGlobalnetIngressChain, "dnat-icmp "+globalIP+" "+target)
+}
+
+func (d *nftablesDataPath) awaitRemoteTrafficMarked(cidr string) {
+	d.nft.AwaitRule(iptables.NFTablesTableName, constants.SmGlobalnetMarkChain, "mark "+cidr)
+	d.nft.AwaitRule(iptables.NFTablesTableName, constants.SmGlobalnetForwardChain, "limit "+cidr)
+}
+
+func (d *nftablesDataPath) awaitNoRemoteTrafficMarked(cidr string) {
+	d.nft.AwaitNoRule(iptables.NFTablesTableName, constants.SmGlobalnetMarkChain, "mark "+cidr)
+	d.nft.AwaitNoRule(iptables.NFTablesTableName, constants.SmGlobalnetForwardChain, "limit "+cidr)
+}
+
+func (d *nftablesDataPath) awaitEgressLimits(ipSetName string, limits iptables.EgressLimits) {
+	chain := constants.SmGlobalnetEgressChainForLimits
+	connLimit := HavePrefix("conn-limit ")
+	bwLimit := HavePrefix("bw-limit ")
+
+	d.nft.AwaitRule(iptables.NFTablesTableName, chain, "acct @"+ipSetName)
+	d.nft.AwaitRule(iptables.NFTablesTableName, chain, "acct-new @"+ipSetName)
+
+	if limits.MaxConnections > 0 {
+		d.nft.AwaitRule(iptables.NFTablesTableName, chain, fmt.Sprintf("conn-limit %d @%s", limits.MaxConnections, ipSetName))
+	} else {
+		d.nft.AwaitNoRule(iptables.NFTablesTableName, chain, connLimit)
+	}
+
+	if limits.BandwidthLimit > 0 {
+		d.nft.AwaitRule(iptables.NFTablesTableName, chain, fmt.Sprintf("bw-limit %d @%s", limits.BandwidthLimit, ipSetName))
+	} else {
+		d.nft.AwaitNoRule(iptables.NFTablesTableName, chain, bwLimit)
+	}
+}
+
+func (d *nftablesDataPath) awaitNoEgressLimits(ipSetName string) {
+	d.nft.AwaitNoRule(iptables.NFTablesTableName, constants.SmGlobalnetEgressChainForLimits, HaveSuffix("@"+ipSetName))
+}
+
+func tableFor(chain string) string {
+	if chain == constants.SmGlobalnetForwardChain || chain == constants.SmGlobalnetEgressChainForLimits {
+		return constants.FilterTable
+	}
+
+	return constants.NATTable
+}
@@ -27,8 +27,10 @@ import (
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/log"
 	"github.com/submariner-io/submariner/pkg/globalnet/constants"
+	"github.com/submariner-io/submariner/pkg/ipset"
 	"github.com/submariner-io/submariner/pkg/iptables"
 	corev1 "k8s.io/api/core/v1"
+	utilexec "k8s.io/utils/exec"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -46,6 +48,15 @@ type Interface interface {
 	RemoveEgressRulesForPods(namespace, ipSetName, snatIP, globalNetIPTableMark string) error
 	AddEgressRulesForNamespace(namespace, ipSetName, snatIP, globalNetIPTableMark string) error
 	RemoveEgressRulesForNamespace(namespace, ipSetName, snatIP, globalNetIPTableMark string) error
+	AddEgressRulesForSelectors(key, ipSetName, snatIP, globalNetIPTableMark string) error
+	RemoveEgressRulesForSelectors(key, ipSetName, snatIP, globalNetIPTableMark string) error
+	UpdateEgressRulesForLimits(key, ipSetName string, limits EgressLimits) error
+	RemoveEgressRulesForLimits(key, ipSetName string) error
+	GetEgressCounters() (map[string]EgressCounters, error)
+	CreateGlobalNetMarkingChain() error
+	CreateGlobalnetChains(globalNetIPTableMark string) error
+	ClearGlobalnetChains() error
+	MarkRemoteClusterTraffic(remoteCidr, globalNetIPTableMark string, addRules bool) error
 	FlushIPTableChain(table, chainName string) error
 	DeleteIPTableChain(table, chainName string) error
 	DeleteIPTableRule(table, chainName, jumpTarget string) error
@@ -62,9 +73,48 @@ const (
 	EndpointsTarget TargetType = "Endpoints"
 )
 
+// The supported data path backends.
+const (
+	IPTablesBackend = "iptables"
+	NFTablesBackend = "nftables"
+)
+
 var logger = log.Logger{Logger: logf.Log.WithName("IPTables")}
 
-func New() (Interface, error) {
+// ValidateBackend checks that the given data path backend is supported. An empty name selects the iptables backend.
+func ValidateBackend(backend string) error {
+	switch backend {
+	case "", IPTablesBackend, NFTablesBackend:
+		return nil
+	default:
+		return fmt.Errorf("unsupported data path backend %q", backend)
+	}
+}
+
+// NewIPSet returns the ipset.Interface used to maintain the sets referenced by the egress rules for the given data path
+// backend. With the nftables backend, the sets are nftables named sets in the Globalnet table.
+func NewIPSet(backend string) (ipset.Interface, error) {
+	if err := ValidateBackend(backend); err != nil {
+		return nil, err
+	}
+
+	if backend == NFTablesBackend {
+		return newNFTSets()
+	}
+
+	return ipset.New(utilexec.New()), nil
+}
+
+// New returns the Interface that programs the Globalnet data path with the given backend.
+func New(backend string) (Interface, error) {
+	if err := ValidateBackend(backend); err != nil {
+		return nil, err
+	}
+
+	if backend == NFTablesBackend {
+		return newNFTables()
+	}
+
 	iptableHandler, err := iptables.New()
 	if err != nil {
 		return nil, err //nolint:wrapcheck  // Let the caller wrap it
@@ -280,6 +330,34 @@ func (i *ipTables) RemoveEgressRulesForNamespace(namespace, ipSetName, snatIP, g
 	return nil
 }
 
+func (i *ipTables) AddEgressRulesForSelectors(key, ipSetName, snatIP, globalNetIPTableMark string) error {
+	ruleSpec := []string{
+		"-p", "all", "-m", "set", "--match-set", ipSetName, "src", "-m", "mark",
+		"--mark", globalNetIPTableMark, "-j", "SNAT", "--to", snatIP,
+	}
+	logger.V(log.DEBUG).Infof("Installing iptable egress rules for selectors %q: %s", key, strings.Join(ruleSpec, " "))
+
+	if err := i.ipt.AppendUnique("nat", constants.SmGlobalnetEgressChainForSelectors, ruleSpec...); err != nil {
+		return errors.Wrapf(err, "error appending iptables rule \"%s\"", strings.Join(ruleSpec, " "))
+	}
+
+	return nil
+}
+
+func (i *ipTables) RemoveEgressRulesForSelectors(key, ipSetName, snatIP, globalNetIPTableMark string) error {
+	ruleSpec := []string{
+		"-p", "all", "-m", "set", "--match-set", ipSetName, "src", "-m", "mark",
+		"--mark", globalNetIPTableMark, "-j", "SNAT", "--to", snatIP,
+	}
+	logger.V(log.DEBUG).Infof("Deleting iptable egress rules for selectors %q: %s", key, strings.Join(ruleSpec, " "))
+
+	if err := i.ipt.Delete("nat", constants.SmGlobalnetEgressChainForSelectors, ruleSpec...); err != nil {
+		return errors.Wrapf(err, "error deleting iptables rule \"%s\"", strings.Join(ruleSpec, " "))
+	}
+
+	return nil
+}
+
 func (i *ipTables) FlushIPTableChain(table, chainName string) error {
 	logger.Infof("Flushing iptable rules in %q chain of table %q", chainName, table)
 
@@ -0,0 +1,256 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iptables
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/submariner/pkg/globalnet/constants"
+)
+
+// The hashlimit match restricts the name of its hash table to 15 characters.
+const maxHashLimitNameLen = 15
+
+type EgressLimits struct {
+	// BandwidthLimit is the maximum egress rate in bytes per second. Zero means unlimited.
+	BandwidthLimit int64
+	// MaxConnections is the maximum number of concurrent egress connections. Zero means unlimited, i.e. not specified, as
+	// a specified limit is at least 1.
+	MaxConnections int
+}
+
+type EgressCounters struct {
+	Packets     uint64
+	Bytes       uint64
+	Connections uint64
+}
+
+func (i *ipTables) UpdateEgressRulesForLimits(key, ipSetName string, limits EgressLimits) error {
+	existing, err := i.listEgressLimitRules(ipSetName)
+	if err != nil {
+		return err
+	}
+
+	desired := egressLimitRules(ipSetName, limits)
+
+	installed, err := i.egressLimitRulesInstalled(existing, desired)
+	if err != nil || installed {
+		return err
+	}
+
+	logger.V(log.DEBUG).Infof("Updating iptable egress limit rules for %q: %+v", key, limits)
+
+	// The DROP rules have to precede the accounting rules, so rather than patching the chain we replace all the rules for the ipset.
+	for _, ruleSpec := range existing {
+		if err := i.ipt.Delete(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits, ruleSpec...); err != nil {
+			return errors.Wrapf(err, "error deleting iptables rule \"%s\"", strings.Join(ruleSpec, " "))
+		}
+	}
+
+	for _, ruleSpec := range desired {
+		if err := i.ipt.Append(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits, ruleSpec...); err != nil {
+			return errors.Wrapf(err, "error appending iptables rule \"%s\"", strings.Join(ruleSpec, " "))
+		}
+	}
+
+	return nil
+}
+
+func (i *ipTables) RemoveEgressRulesForLimits(key, ipSetName string) error {
+	existing, err := i.listEgressLimitRules(ipSetName)
+	if err != nil {
+		return err
+	}
+
+	logger.V(log.DEBUG).Infof("Deleting iptable egress limit rules for %q", key)
+
+	for _, ruleSpec := range existing {
+		if err := i.ipt.Delete(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits, ruleSpec...); err != nil {
+			return errors.Wrapf(err, "error deleting iptables rule \"%s\"", strings.Join(ruleSpec, " "))
+		}
+	}
+
+	return nil
+}
+
+// GetEgressCounters returns the counters of the accounting rules in the egress limit chain keyed by ipset name.
+func (i *ipTables) GetEgressCounters() (map[string]EgressCounters, error) {
+	rules, err := i.ipt.ListWithCounters(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listing the rules in chain %q", constants.SmGlobalnetEgressChainForLimits)
+	}
+
+	result := map[string]EgressCounters{}
+
+	for _, rule := range rules {
+		ruleSpec, packets, bytes, ok := parseRuleCounters(rule)
+		if !ok || indexOf(ruleSpec, "-j") >= 0 {
+			continue
+		}
+
+		ipSetName := matchSetName(ruleSpec)
+		if ipSetName == "" {
+			continue
+		}
+
+		counters := result[ipSetName]
+
+		if indexOf(ruleSpec, "--ctstate") >= 0 {
+			counters.Connections = packets
+		} else {
+			counters.Packets = packets
+			counters.Bytes = bytes
+		}
+
+		result[ipSetName] = counters
+	}
+
+	return result, nil
+}
+
+func (i *ipTables) listEgressLimitRules(ipSetName string) ([][]string, error) {
+	rules, err := i.ipt.List(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listing the rules in chain %q", constants.SmGlobalnetEgressChainForLimits)
+	}
+
+	var ruleSpecs [][]string
+
+	for _, rule := range rules {
+		ruleSpec := toRuleSpec(rule)
+		if matchSetName(ruleSpec) == ipSetName {
+			ruleSpecs = append(ruleSpecs, ruleSpec)
+		}
+	}
+
+	return ruleSpecs, nil
+}
+
+func egressLimitRules(ipSetName string, limits EgressLimits) [][]string {
+	matchSet := func(extra ...string) []string {
+		return append([]string{"-m", "set", "--match-set", ipSetName, "src"}, extra...)
+	}
+
+	var rules [][]string
+
+	if limits.MaxConnections > 0 {
+		rules = append(rules, matchSet("-m", "conntrack", "--ctstate", "NEW", "-m", "connlimit", "--connlimit-above",
+			strconv.Itoa(limits.MaxConnections), "--connlimit-mask", "0", "-j", "DROP"))
+	}
+
+	if limits.BandwidthLimit > 0 {
+		kbps := (limits.BandwidthLimit + 1023) / 1024
+		rules = append(rules, matchSet("-m", "hashlimit", "--hashlimit-above", fmt.Sprintf("%dkb/s", kbps),
+			"--hashlimit-name", hashLimitName(ipSetName), "-j", "DROP"))
+	}
+
+	// Rules without a target only update their counters. The first accounts for all the egress traffic and the second
+	// for the new connections.
+	return append(rules, matchSet(), matchSet("-m", "conntrack", "--ctstate", "NEW"))
+}
+
+func hashLimitName(ipSetName string) string {
+	if len(ipSetName) <= maxHashLimitNameLen {
+		return ipSetName
+	}
+
+	// The tail of the ipset name is the hash of its key, so it's unique enough.
+	return ipSetName[len(ipSetName)-maxHashLimitNameLen:]
+}
+
+// toRuleSpec converts a rule as returned by List, e.g. "-A CHAIN -m set ...", to a rule spec that can be passed to Delete.
+func toRuleSpec(rule string) []string {
+	ruleSpec := strings.Fields(rule)
+	if len(ruleSpec) > 0 && ruleSpec[0] == "-N" {
+		return nil
+	}
+
+	if len(ruleSpec) >= 2 && ruleSpec[0] == "-A" {
+		ruleSpec = ruleSpec[2:]
+	}
+
+	return ruleSpec
+}
+
+// parseRuleCounters extracts the "-c <packets> <bytes>" counters from a rule as returned by ListWithCounters.
+func parseRuleCounters(rule string) (ruleSpec []string, packets, bytes uint64, ok bool) {
+	ruleSpec = toRuleSpec(rule)
+
+	idx := indexOf(ruleSpec, "-c")
+	if idx < 0 || idx+2 >= len(ruleSpec) {
+		return nil, 0, 0, false
+	}
+
+	packets, err := strconv.ParseUint(ruleSpec[idx+1], 10, 64)
+	if err != nil {
+		return nil, 0, 0, false
+	}
+
+	bytes, err = strconv.ParseUint(ruleSpec[idx+2], 10, 64)
+	if err != nil {
+		return nil, 0, 0, false
+	}
+
+	return append(ruleSpec[:idx:idx], ruleSpec[idx+3:]...), packets, bytes, true
+}
+
+func matchSetName(ruleSpec []string) string {
+	idx := indexOf(ruleSpec, "--match-set")
+	if idx < 0 || idx+1 >= len(ruleSpec) {
+		return ""
+	}
+
+	return ruleSpec[idx+1]
+}
+
+// egressLimitRulesInstalled checks if the existing rules match the desired ones. iptables normalizes the options of some
+// matches when listing them, e.g. it adds the connlimit and hashlimit defaults, so the listed rules can't be compared with
+// the desired ones verbatim. Instead each desired rule is checked with Exists.
+func (i *ipTables) egressLimitRulesInstalled(existing, desired [][]string) (bool, error) {
+	if len(existing) != len(desired) {
+		return false, nil
+	}
+
+	for _, ruleSpec := range desired {
+		exists, err := i.ipt.Exists(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits, ruleSpec...)
+		if err != nil {
+			return false, errors.Wrapf(err, "error checking for iptables rule \"%s\"", strings.Join(ruleSpec, " "))
+		}
+
+		if !exists {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func indexOf(ruleSpec []string, s string) int {
+	for i := range ruleSpec {
+		if ruleSpec[i] == s {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,82 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iptables_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner/pkg/globalnet/constants"
+	"github.com/submariner-io/submariner/pkg/globalnet/controllers/iptables"
+	pkgiptables "github.com/submariner-io/submariner/pkg/iptables"
+	fakeIPT "github.com/submariner-io/submariner/pkg/iptables/fake"
+)
+
+var _ = Describe("Egress counters", func() {
+	const (
+		accountingRule = "-m set --match-set " + ipSetName + " src"
+		newConnRule    = "-m set --match-set " + ipSetName + " src -m conntrack --ctstate NEW"
+	)
+
+	var (
+		ipt   *fakeIPT.IPTables
+		iface iptables.Interface
+	)
+
+	BeforeEach(func() {
+		ipt = fakeIPT.New()
+
+		pkgiptables.NewFunc = func() (pkgiptables.Interface, error) {
+			return ipt, nil
+		}
+
+		var err error
+
+		iface, err = iptables.New(iptables.IPTablesBackend)
+		Expect(err).To(Succeed())
+
+		Expect(iface.CreateGlobalnetChains(mark)).To(Succeed())
+		Expect(iface.UpdateEgressRulesForLimits("ns/egress", ipSetName, iptables.EgressLimits{MaxConnections: 100})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		pkgiptables.NewFunc = nil
+	})
+
+	It("should parse the counters of the accounting rules", func() {
+		ipt.SetRuleCounters(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits, accountingRule, 120, 98765)
+		ipt.SetRuleCounters(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits, newConnRule, 7, 420)
+
+		counters, err := iface.GetEgressCounters()
+		Expect(err).To(Succeed())
+		Expect(counters).To(HaveLen(1))
+		Expect(counters).To(HaveKeyWithValue(ipSetName, iptables.EgressCounters{Packets: 120, Bytes: 98765, Connections: 7}))
+	})
+
+	When("the egress limit rules are updated with unchanged limits", func() {
+		It("should not reinstall the rules", func() {
+			ipt.SetRuleCounters(constants.FilterTable, constants.SmGlobalnetEgressChainForLimits, accountingRule, 10, 1000)
+
+			Expect(iface.UpdateEgressRulesForLimits("ns/egress", ipSetName, iptables.EgressLimits{MaxConnections: 100})).To(Succeed())
+
+			counters, err := iface.GetEgressCounters()
+			Expect(err).To(Succeed())
+			Expect(counters[ipSetName].Packets).To(BeEquivalentTo(10))
+		})
+	})
+})
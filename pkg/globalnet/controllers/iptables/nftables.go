@@ -0,0 +1,787 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iptables
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	nft "github.com/google/nftables"
+	"github.com/google/nftables/expr"
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/submariner/pkg/globalnet/constants"
+	"github.com/submariner-io/submariner/pkg/iptables"
+	"github.com/submariner-io/submariner/pkg/nftables"
+	routeAgent "github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NFTablesTableName is the nftables table that holds all the Globalnet chains, sets and maps.
+const NFTablesTableName = "submariner-globalnet"
+
+// The base chains hooked into netfilter. Their priorities place them just ahead of the iptables chains they replace so
+// the Globalnet NAT is applied before kube-proxy's or the CNI's.
+const (
+	nftPreRoutingChain  = "PREROUTING"
+	nftPostRoutingChain = "POSTROUTING"
+	nftForwardChain     = "FORWARD"
+)
+
+// The maps used for the 1:1 address translations, which would otherwise require a rule per address.
+const (
+	nftIngressDNATMap       = "SM-GN-INGRESS-DNAT"
+	nftHeadlessSvcPodsSNAT  = "SM-GN-HDLS-PODS-SNAT"
+	nftHeadlessSvcEPsSNAT   = "SM-GN-HDLS-EPS-SNAT"
+	nftLimitsCommentPrefix  = "acct @"
+	nftNewConnCommentPrefix = "acct-new @"
+)
+
+// The egress sub-chains in the order they're jumped to from the egress chain.
+var nftEgressSubChains = []string{
+	constants.SmGlobalnetEgressChainForPods,
+	constants.SmGlobalnetEgressChainForHeadlessSvcPods,
+	constants.SmGlobalnetEgressChainForHeadlessSvcEPs,
+	constants.SmGlobalnetEgressChainForNamespace,
+	constants.SmGlobalnetEgressChainForSelectors,
+	constants.SmGlobalnetEgressChainForCluster,
+}
+
+type nfTables struct {
+	mutex sync.Mutex
+	nft   nftables.Interface
+	table *nft.Table
+	ipt   iptables.Interface
+}
+
+func newNFTables() (Interface, error) {
+	conn, err := nftables.New()
+	if err != nil {
+		return nil, err //nolint:wrapcheck  // Let the caller wrap it
+	}
+
+	return &nfTables{
+		nft:   conn,
+		table: nftTable(),
+	}, nil
+}
+
+func nftTable() *nft.Table {
+	return &nft.Table{Family: nft.TableFamilyIPv4, Name: NFTablesTableName}
+}
+
+func (n *nfTables) chain(name string) *nft.Chain {
+	return &nft.Chain{Name: name, Table: n.table}
+}
+
+func (n *nfTables) baseChain(name string, chainType nft.ChainType, hook nft.ChainHook, priority nft.ChainPriority) *nft.Chain {
+	return &nft.Chain{
+		Name:     name,
+		Table:    n.table,
+		Type:     chainType,
+		Hooknum:  hook,
+		Priority: priority,
+	}
+}
+
+func (n *nfTables) flush(op string) error {
+	return errors.Wrapf(n.nft.Flush(), "error %s", op)
+}
+
+func (n *nfTables) AddClusterEgressRules(subnet, snatIP, globalNetIPTableMark string) error {
+	return n.addSNATRule(constants.SmGlobalnetEgressChainForCluster, subnet, snatIP, globalNetIPTableMark)
+}
+
+func (n *nfTables) RemoveClusterEgressRules(subnet, snatIP, _ string) error {
+	return n.deleteRule(constants.SmGlobalnetEgressChainForCluster, snatComment(subnet, snatIP))
+}
+
+func (n *nfTables) AddIngressRulesForHeadlessSvc(globalIP, ip string, targetType TargetType) error {
+	if globalIP == "" || ip == "" {
+		return fmt.Errorf("globalIP %q or %s IP %q cannot be empty", globalIP, targetType, ip)
+	}
+
+	logger.V(log.DEBUG).Infof("Mapping Headless SVC global IP %s to %s %s", globalIP, targetType, ip)
+
+	return n.addMapElement(nftIngressDNATMap, globalIP, ip)
+}
+
+func (n *nfTables) RemoveIngressRulesForHeadlessSvc(globalIP, ip string, targetType TargetType) error {
+	if globalIP == "" || ip == "" {
+		return fmt.Errorf("globalIP %q or %s IP %q cannot be empty", globalIP, targetType, ip)
+	}
+
+	logger.V(log.DEBUG).Infof("Unmapping Headless SVC global IP %s from %s %s", globalIP, targetType, ip)
+
+	return n.deleteMapElement(nftIngressDNATMap, globalIP)
+}
+
+// GetKubeProxyClusterIPServiceChainName looks up the kube-proxy service chain, which is an iptables chain regardless of
+// the backend used by Globalnet.
+func (n *nfTables) GetKubeProxyClusterIPServiceChainName(service *corev1.Service,
+	kubeProxyServiceChainPrefix string,
+) (string, bool, error) {
+	n.mutex.Lock()
+
+	if n.ipt == nil {
+		ipt, err := iptables.New()
+		if err != nil {
+			n.mutex.Unlock()
+			return "", false, errors.Wrap(err, "error creating IP tables")
+		}
+
+		n.ipt = ipt
+	}
+
+	n.mutex.Unlock()
+
+	return (&ipTables{ipt: n.ipt}).GetKubeProxyClusterIPServiceChainName(service, kubeProxyServiceChainPrefix)
+}
+
+func (n *nfTables) AddIngressRulesForHealthCheck(cniIfaceIP, globalIP string) error {
+	exprs, err := newExprBuilder().matchICMP().matchAddr(daddrOffset, globalIP).natTo(expr.NATTypeDestNAT, cniIfaceIP).build()
+	if err != nil {
+		return err
+	}
+
+	logger.V(log.DEBUG).Infof("Installing nftables ingress rule for Node: icmp %s -> %s", globalIP, cniIfaceIP)
+
+	return n.appendUniqueRule(constants.SmGlobalnetIngressChain, healthCheckComment(cniIfaceIP, globalIP), exprs)
+}
+
+func (n *nfTables) RemoveIngressRulesForHealthCheck(cniIfaceIP, globalIP string) error {
+	return n.deleteRule(constants.SmGlobalnetIngressChain, healthCheckComment(cniIfaceIP, globalIP))
+}
+
+func (n *nfTables) AddEgressRulesForHeadlessSvc(key, sourceIP, snatIP, _ string, targetType TargetType) error {
+	logger.V(log.DEBUG).Infof("Mapping egress for HDLS SVC %q for %s: %s -> %s", key, targetType, sourceIP, snatIP)
+
+	return n.addMapElement(headlessSvcSNATMap(targetType), sourceIP, snatIP)
+}
+
+func (n *nfTables) RemoveEgressRulesForHeadlessSvc(key, sourceIP, snatIP, _ string, targetType TargetType) error {
+	logger.V(log.DEBUG).Infof("Unmapping egress for HDLS SVC %q for %s: %s -> %s", key, targetType, sourceIP, snatIP)
+
+	return n.deleteMapElement(headlessSvcSNATMap(targetType), sourceIP)
+}
+
+func (n *nfTables) AddEgressRulesForPods(key, ipSetName, snatIP, _ string) error {
+	return n.bindEgressSet(constants.SmGlobalnetEgressChainForPods, key, ipSetName, snatIP)
+}
+
+func (n *nfTables) RemoveEgressRulesForPods(key, ipSetName, _, _ string) error {
+	return n.unbindEgressSet(constants.SmGlobalnetEgressChainForPods, key, ipSetName)
+}
+
+func (n *nfTables) AddEgressRulesForNamespace(key, ipSetName, snatIP, _ string) error {
+	return n.bindEgressSet(constants.SmGlobalnetEgressChainForNamespace, key, ipSetName, snatIP)
+}
+
+func (n *nfTables) RemoveEgressRulesForNamespace(key, ipSetName, _, _ string) error {
+	return n.unbindEgressSet(constants.SmGlobalnetEgressChainForNamespace, key, ipSetName)
+}
+
+func (n *nfTables) AddEgressRulesForSelectors(key, ipSetName, snatIP, _ string) error {
+	return n.bindEgressSet(constants.SmGlobalnetEgressChainForSelectors, key, ipSetName, snatIP)
+}
+
+func (n *nfTables) RemoveEgressRulesForSelectors(key, ipSetName, _, _ string) error {
+	return n.unbindEgressSet(constants.SmGlobalnetEgressChainForSelectors, key, ipSetName)
+}
+
+func (n *nfTables) UpdateEgressRulesForLimits(key, ipSetName string, limits EgressLimits) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	existing, err := n.limitRules(ipSetName)
+	if err != nil {
+		return err
+	}
+
+	desired, err := egressLimitNFTRules(ipSetName, limits)
+	if err != nil {
+		return err
+	}
+
+	if len(existing) == len(desired) {
+		equal := true
+
+		for i := range desired {
+			equal = equal && nftables.CommentFrom(existing[i].UserData) == nftables.CommentFrom(desired[i].UserData)
+		}
+
+		if equal {
+			return nil
+		}
+	}
+
+	logger.V(log.DEBUG).Infof("Updating nftables egress limit rules for %q: %+v", key, limits)
+
+	for _, r := range existing {
+		if err := n.nft.DelRule(r); err != nil {
+			return errors.Wrapf(err, "error deleting nftables rule %q", nftables.CommentFrom(r.UserData))
+		}
+	}
+
+	for _, r := range desired {
+		n.nft.AddRule(r)
+	}
+
+	return n.flush("updating the egress limit rules")
+}
+
+func (n *nfTables) RemoveEgressRulesForLimits(key, ipSetName string) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	existing, err := n.limitRules(ipSetName)
+	if err != nil {
+		return err
+	}
+
+	logger.V(log.DEBUG).Infof("Deleting nftables egress limit rules for %q", key)
+
+	for _, r := range existing {
+		if err := n.nft.DelRule(r); err != nil {
+			return errors.Wrapf(err, "error deleting nftables rule %q", nftables.CommentFrom(r.UserData))
+		}
+	}
+
+	return n.flush("deleting the egress limit rules")
+}
+
+func (n *nfTables) GetEgressCounters() (map[string]EgressCounters, error) {
+	rules, err := n.nft.GetRules(n.table, n.chain(constants.SmGlobalnetEgressChainForLimits))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listing the rules in chain %q", constants.SmGlobalnetEgressChainForLimits)
+	}
+
+	result := map[string]EgressCounters{}
+
+	for _, r := range rules {
+		comment := nftables.CommentFrom(r.UserData)
+
+		var counter *expr.Counter
+
+		for _, e := range r.Exprs {
+			if c, ok := e.(*expr.Counter); ok {
+				counter = c
+			}
+		}
+
+		if counter == nil {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(comment, nftLimitsCommentPrefix):
+			ipSetName := strings.TrimPrefix(comment, nftLimitsCommentPrefix)
+			counters := result[ipSetName]
+			counters.Packets = counter.Packets
+			counters.Bytes = counter.Bytes
+			result[ipSetName] = counters
+		case strings.HasPrefix(comment, nftNewConnCommentPrefix):
+			ipSetName := strings.TrimPrefix(comment, nftNewConnCommentPrefix)
+			counters := result[ipSetName]
+			counters.Connections = counter.Packets
+			result[ipSetName] = counters
+		}
+	}
+
+	return result, nil
+}
+
+func (n *nfTables) CreateGlobalNetMarkingChain() error {
+	n.mutex.Lock()
+
+	logger.V(log.DEBUG).Infof("Install/ensure nftables table %s exists", NFTablesTableName)
+
+	n.nft.AddTable(n.table)
+
+	for _, name := range []string{
+		constants.SmGlobalnetMarkChain, constants.SmGlobalnetForwardChain,
+		constants.SmGlobalnetEgressChainForLimits,
+	} {
+		logger.V(log.DEBUG).Infof("Install/ensure %s chain exists", name)
+		n.nft.AddChain(n.chain(name))
+	}
+
+	n.nft.AddChain(n.baseChain(nftForwardChain, nft.ChainTypeFilter, nft.ChainHookForward, nft.ChainPriorityFilter-1))
+
+	err := n.flush("creating the Globalnet marking chains")
+
+	n.mutex.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	return n.ensureJumpRules(nftForwardChain, constants.SmGlobalnetForwardChain)
+}
+
+func (n *nfTables) CreateGlobalnetChains(globalNetIPTableMark string) error {
+	if err := n.CreateGlobalNetMarkingChain(); err != nil {
+		return err
+	}
+
+	if err := n.createGlobalnetChains(); err != nil {
+		return err
+	}
+
+	if err := n.ensureJumpRules(nftPreRoutingChain, constants.SmGlobalnetIngressChain); err != nil {
+		return err
+	}
+
+	if err := n.ensureJumpRules(nftPostRoutingChain, constants.SmGlobalnetEgressChain); err != nil {
+		return err
+	}
+
+	// The marking chain has to be the first in the egress chain.
+	if err := n.ensureJumpRules(constants.SmGlobalnetEgressChain, append([]string{constants.SmGlobalnetMarkChain}, nftEgressSubChains...)...); err != nil {
+		return err
+	}
+
+	if err := n.ensureMapRule(constants.SmGlobalnetIngressChain, nftIngressDNATMap, daddrOffset, expr.NATTypeDestNAT, ""); err != nil {
+		return err
+	}
+
+	if err := n.ensureMapRule(constants.SmGlobalnetEgressChainForHeadlessSvcPods, nftHeadlessSvcPodsSNAT, saddrOffset,
+		expr.NATTypeSourceNAT, globalNetIPTableMark); err != nil {
+		return err
+	}
+
+	if err := n.ensureMapRule(constants.SmGlobalnetEgressChainForHeadlessSvcEPs, nftHeadlessSvcEPsSNAT, saddrOffset,
+		expr.NATTypeSourceNAT, globalNetIPTableMark); err != nil {
+		return err
+	}
+
+	for _, chain := range []string{
+		constants.SmGlobalnetEgressChainForPods, constants.SmGlobalnetEgressChainForNamespace,
+		constants.SmGlobalnetEgressChainForSelectors,
+	} {
+		if err := n.ensureRangeMapRule(chain, nftEgressSNATMaps[chain], globalNetIPTableMark); err != nil {
+			return err
+		}
+	}
+
+	return nftEgressSNATBindings.syncAll(n.nft, n.table)
+}
+
+func (n *nfTables) createGlobalnetChains() error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.nft.AddChain(n.baseChain(nftPreRoutingChain, nft.ChainTypeNAT, nft.ChainHookPrerouting, nft.ChainPriorityNATDest-1))
+	n.nft.AddChain(n.baseChain(nftPostRoutingChain, nft.ChainTypeNAT, nft.ChainHookPostrouting, nft.ChainPriorityNATSource-1))
+
+	for _, name := range append([]string{constants.SmGlobalnetIngressChain, constants.SmGlobalnetEgressChain}, nftEgressSubChains...) {
+		logger.V(log.DEBUG).Infof("Install/ensure %s chain exists", name)
+		n.nft.AddChain(n.chain(name))
+	}
+
+	for _, name := range []string{nftIngressDNATMap, nftHeadlessSvcPodsSNAT, nftHeadlessSvcEPsSNAT} {
+		if err := n.nft.AddSet(&nft.Set{Table: n.table, Name: name, IsMap: true, KeyType: nft.TypeIPAddr,
+			DataType: nft.TypeIPAddr}, nil); err != nil {
+			return errors.Wrapf(err, "error creating nftables map %s", name)
+		}
+	}
+
+	for _, name := range []string{nftEgressPodsSNAT, nftEgressNamespaceSNAT, nftEgressSelectorsSNAT} {
+		if err := n.nft.AddSet(egressSNATMap(n.table, name), nil); err != nil {
+			return errors.Wrapf(err, "error creating nftables map %s", name)
+		}
+	}
+
+	return n.flush("creating the Globalnet chains")
+}
+
+func (n *nfTables) ClearGlobalnetChains() error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	for _, name := range []string{
+		constants.SmGlobalnetIngressChain, constants.SmGlobalnetEgressChain, constants.SmGlobalnetMarkChain,
+		constants.SmGlobalnetForwardChain,
+	} {
+		n.nft.FlushChain(n.chain(name))
+	}
+
+	// With the iptables backend, the ingress DNAT translations are rules in the ingress chain whereas here they're entries in
+	// the ingress DNAT map so it's flushed as well. The headless Service and egress SNAT maps and the egress limit chain aren't
+	// flushed, as with the iptables backend.
+	n.nft.FlushSet(&nft.Set{Table: n.table, Name: nftIngressDNATMap, IsMap: true})
+
+	return n.flush("flushing the Globalnet chains")
+}
+
+func (n *nfTables) MarkRemoteClusterTraffic(remoteCidr, globalNetIPTableMark string, addRules bool) error {
+	if !addRules {
+		logger.V(log.DEBUG).Infof("Deleting rules that mark remote cluster traffic to %s", remoteCidr)
+
+		if err := n.deleteRule(constants.SmGlobalnetMarkChain, "mark "+remoteCidr); err != nil {
+			return err
+		}
+
+		return n.deleteRule(constants.SmGlobalnetForwardChain, "limit "+remoteCidr)
+	}
+
+	logger.V(log.DEBUG).Infof("Marking traffic destined to remote cluster %s", remoteCidr)
+
+	exprs, err := newExprBuilder().matchAddr(daddrOffset, remoteCidr).setMark(globalNetIPTableMark).build()
+	if err != nil {
+		return err
+	}
+
+	if err := n.appendUniqueRule(constants.SmGlobalnetMarkChain, "mark "+remoteCidr, exprs); err != nil {
+		return err
+	}
+
+	exprs, err = newExprBuilder().matchAddr(daddrOffset, remoteCidr).jumpTo(constants.SmGlobalnetEgressChainForLimits).build()
+	if err != nil {
+		return err
+	}
+
+	return n.appendUniqueRule(constants.SmGlobalnetForwardChain, "limit "+remoteCidr, exprs)
+}
+
+func (n *nfTables) FlushIPTableChain(_, chainName string) error {
+	logger.Infof("Flushing nftables rules in %q chain", chainName)
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if exists, err := n.chainExists(nftChainName(chainName)); err != nil || !exists {
+		return err
+	}
+
+	n.nft.FlushChain(n.chain(nftChainName(chainName)))
+
+	return n.flush(fmt.Sprintf("flushing nftables chain %q", chainName))
+}
+
+// DeleteIPTableChain deletes the given chain. Once only the base chains remain, the Globalnet table is deleted along
+// with them and the sets and maps.
+func (n *nfTables) DeleteIPTableChain(_, chainName string) error {
+	logger.Infof("Deleting nftables chain %q", chainName)
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if exists, err := n.chainExists(nftChainName(chainName)); err != nil || !exists {
+		return err
+	}
+
+	n.nft.DelChain(n.chain(nftChainName(chainName)))
+
+	if err := n.flush(fmt.Sprintf("deleting nftables chain %q", chainName)); err != nil {
+		return err
+	}
+
+	chains, err := n.nft.ListChains()
+	if err != nil {
+		return errors.Wrap(err, "error listing the nftables chains")
+	}
+
+	for _, c := range chains {
+		if c.Table.Name == NFTablesTableName && c.Type == "" {
+			return nil
+		}
+	}
+
+	logger.Infof("Deleting nftables table %q", NFTablesTableName)
+
+	n.nft.DelTable(n.table)
+
+	return n.flush("deleting the Globalnet table")
+}
+
+func (n *nfTables) DeleteIPTableRule(_, chainName, jumpTarget string) error {
+	n.mutex.Lock()
+	exists, err := n.chainExists(nftChainName(chainName))
+	n.mutex.Unlock()
+
+	if err != nil || !exists {
+		return err
+	}
+
+	return n.deleteRule(nftChainName(chainName), "jump "+jumpTarget)
+}
+
+func (n *nfTables) addSNATRule(chain, source, snatIP, globalNetIPTableMark string) error {
+	exprs, err := newExprBuilder().matchAddr(saddrOffset, source).matchMark(globalNetIPTableMark).
+		natTo(expr.NATTypeSourceNAT, snatIP).build()
+	if err != nil {
+		return err
+	}
+
+	logger.V(log.DEBUG).Infof("Installing nftables egress rule in %s: %s", chain, snatComment(source, snatIP))
+
+	return n.appendUniqueRule(chain, snatComment(source, snatIP), exprs)
+}
+
+func (n *nfTables) appendUniqueRule(chain, comment string, exprs []expr.Any) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	existing, err := n.findRule(chain, comment)
+	if err != nil || existing != nil {
+		return err
+	}
+
+	n.nft.AddRule(&nft.Rule{
+		Table:    n.table,
+		Chain:    n.chain(chain),
+		Exprs:    exprs,
+		UserData: nftables.Comment(comment),
+	})
+
+	return n.flush(fmt.Sprintf("appending nftables rule %q", comment))
+}
+
+func (n *nfTables) deleteRule(chain, comment string) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	existing, err := n.findRule(chain, comment)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return fmt.Errorf("nftables rule %q does not exist in chain %q", comment, chain)
+	}
+
+	if err := n.nft.DelRule(existing); err != nil {
+		return errors.Wrapf(err, "error deleting nftables rule %q", comment)
+	}
+
+	return n.flush(fmt.Sprintf("deleting nftables rule %q", comment))
+}
+
+func (n *nfTables) findRule(chain, comment string) (*nft.Rule, error) {
+	rules, err := n.nft.GetRules(n.table, n.chain(chain))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listing the rules in chain %q", chain)
+	}
+
+	for _, r := range rules {
+		if nftables.CommentFrom(r.UserData) == comment {
+			return r, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ensureJumpRules appends a jump to each target chain, in order, that doesn't already have one.
+func (n *nfTables) ensureJumpRules(chain string, targets ...string) error {
+	for _, target := range targets {
+		exprs, err := newExprBuilder().jumpTo(target).build()
+		if err != nil {
+			return err
+		}
+
+		if err := n.appendUniqueRule(chain, "jump "+target, exprs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureMapRule installs the rule that translates the source or destination address of the packets through the given map.
+func (n *nfTables) ensureMapRule(chain, mapName string, offset uint32, natType expr.NATType, mark string) error {
+	builder := newExprBuilder()
+
+	if mark != "" {
+		builder.matchMark(mark)
+	}
+
+	exprs, err := builder.natThroughMap(natType, offset, mapName).build()
+	if err != nil {
+		return err
+	}
+
+	return n.appendUniqueRule(chain, "map @"+mapName, exprs)
+}
+
+// ensureRangeMapRule installs the rule that translates the source address of the marked packets to the range it's mapped
+// to in the given egress SNAT map.
+func (n *nfTables) ensureRangeMapRule(chain, mapName, mark string) error {
+	exprs, err := newExprBuilder().matchMark(mark).natRangeThroughMap(expr.NATTypeSourceNAT, saddrOffset, mapName).build()
+	if err != nil {
+		return err
+	}
+
+	return n.appendUniqueRule(chain, "map @"+mapName, exprs)
+}
+
+// bindEgressSet maps the members of the given set to the given SNAT IP in the chain's egress SNAT map.
+func (n *nfTables) bindEgressSet(chain, key, ipSetName, snatIP string) error {
+	logger.V(log.DEBUG).Infof("Mapping the members of set %q for %q to %s in nftables map %s", ipSetName, key, snatIP,
+		nftEgressSNATMaps[chain])
+
+	return nftEgressSNATBindings.bind(n.nft, n.table, nftEgressSNATMaps[chain], ipSetName, snatIP)
+}
+
+func (n *nfTables) unbindEgressSet(chain, key, ipSetName string) error {
+	logger.V(log.DEBUG).Infof("Unmapping the members of set %q for %q from nftables map %s", ipSetName, key,
+		nftEgressSNATMaps[chain])
+
+	return nftEgressSNATBindings.unbind(n.nft, n.table, nftEgressSNATMaps[chain], ipSetName)
+}
+
+func (n *nfTables) addMapElement(mapName, key, value string) error {
+	element, err := mapElement(key, value)
+	if err != nil {
+		return err
+	}
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if err := n.nft.SetAddElements(&nft.Set{Table: n.table, Name: mapName, IsMap: true, KeyType: nft.TypeIPAddr,
+		DataType: nft.TypeIPAddr}, []nft.SetElement{element}); err != nil {
+		return errors.Wrapf(err, "error adding %s -> %s to nftables map %s", key, value, mapName)
+	}
+
+	return n.flush(fmt.Sprintf("adding %s -> %s to nftables map %s", key, value, mapName))
+}
+
+func (n *nfTables) deleteMapElement(mapName, key string) error {
+	element, err := mapElement(key, "")
+	if err != nil {
+		return err
+	}
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if err := n.nft.SetDeleteElements(&nft.Set{Table: n.table, Name: mapName, IsMap: true, KeyType: nft.TypeIPAddr,
+		DataType: nft.TypeIPAddr}, []nft.SetElement{element}); err != nil {
+		return errors.Wrapf(err, "error deleting %s from nftables map %s", key, mapName)
+	}
+
+	return n.flush(fmt.Sprintf("deleting %s from nftables map %s", key, mapName))
+}
+
+func (n *nfTables) limitRules(ipSetName string) ([]*nft.Rule, error) {
+	rules, err := n.nft.GetRules(n.table, n.chain(constants.SmGlobalnetEgressChainForLimits))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listing the rules in chain %q", constants.SmGlobalnetEgressChainForLimits)
+	}
+
+	var result []*nft.Rule
+
+	for _, r := range rules {
+		if strings.HasSuffix(nftables.CommentFrom(r.UserData), " @"+ipSetName) {
+			result = append(result, r)
+		}
+	}
+
+	return result, nil
+}
+
+func (n *nfTables) chainExists(name string) (bool, error) {
+	chains, err := n.nft.ListChains()
+	if err != nil {
+		return false, errors.Wrap(err, "error listing the nftables chains")
+	}
+
+	for _, c := range chains {
+		if c.Table.Name == NFTablesTableName && c.Name == name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func egressLimitNFTRules(ipSetName string, limits EgressLimits) ([]*nft.Rule, error) {
+	table := nftTable()
+	chain := &nft.Chain{Name: constants.SmGlobalnetEgressChainForLimits, Table: table}
+
+	var buildErr error
+
+	rule := func(comment string, builder *exprBuilder) *nft.Rule {
+		exprs, err := builder.build()
+		if err != nil && buildErr == nil {
+			buildErr = errors.Wrapf(err, "error building the nftables rule %q", comment)
+		}
+
+		return &nft.Rule{
+			Table:    table,
+			Chain:    chain,
+			Exprs:    exprs,
+			UserData: nftables.Comment(comment),
+		}
+	}
+
+	matchSet := func() *exprBuilder {
+		return newExprBuilder().matchSet(saddrOffset, ipSetName)
+	}
+
+	var rules []*nft.Rule
+
+	if limits.MaxConnections > 0 {
+		rules = append(rules, rule(fmt.Sprintf("conn-limit %d @%s", limits.MaxConnections, ipSetName),
+			matchSet().matchCtStateNew().connLimitAbove(limits.MaxConnections).drop()))
+	}
+
+	if limits.BandwidthLimit > 0 {
+		rules = append(rules, rule(fmt.Sprintf("bw-limit %d @%s", limits.BandwidthLimit, ipSetName),
+			matchSet().rateAbove(limits.BandwidthLimit).drop()))
+	}
+
+	// Rules without a verdict only update their counters. The first accounts for all the egress traffic and the second
+	// for the new connections.
+	rules = append(rules, rule(nftLimitsCommentPrefix+ipSetName, matchSet().counter()),
+		rule(nftNewConnCommentPrefix+ipSetName, matchSet().matchCtStateNew().counter()))
+
+	return rules, buildErr
+}
+
+func headlessSvcSNATMap(targetType TargetType) string {
+	if targetType == PodTarget {
+		return nftHeadlessSvcPodsSNAT
+	}
+
+	return nftHeadlessSvcEPsSNAT
+}
+
+// nftChainName maps the iptables chain names used by the uninstall code to the nftables chains.
+func nftChainName(chainName string) string {
+	switch chainName {
+	case routeAgent.SmPostRoutingChain:
+		return nftPostRoutingChain
+	case routeAgent.ForwardChain:
+		return nftForwardChain
+	default:
+		return chainName
+	}
+}
+
+func snatComment(source, snatIP string) string {
+	return "snat " + source + " " + snatIP
+}
+
+func healthCheckComment(cniIfaceIP, globalIP string) string {
+	return "dnat-icmp " + globalIP + " " + cniIfaceIP
+}
@@ -0,0 +1,231 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iptables
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	nft "github.com/google/nftables"
+	"github.com/google/nftables/binaryutil"
+	"github.com/google/nftables/expr"
+	"golang.org/x/sys/unix"
+)
+
+// The offsets of the source and destination addresses in the IPv4 header.
+const (
+	saddrOffset = 12
+	daddrOffset = 16
+)
+
+// The connlimit flag that inverts the match, i.e. "ct count over".
+const connlimitFlagOver = 1
+
+// exprBuilder accumulates the expressions of a rule. The first error encountered is returned by build.
+type exprBuilder struct {
+	exprs []expr.Any
+	err   error
+}
+
+func newExprBuilder() *exprBuilder {
+	return &exprBuilder{}
+}
+
+func (b *exprBuilder) build() ([]expr.Any, error) {
+	return b.exprs, b.err
+}
+
+func (b *exprBuilder) add(exprs ...expr.Any) *exprBuilder {
+	b.exprs = append(b.exprs, exprs...)
+	return b
+}
+
+func (b *exprBuilder) fail(err error) *exprBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+
+	return b
+}
+
+// matchAddr matches the address at the given offset against an IP or a CIDR.
+func (b *exprBuilder) matchAddr(offset uint32, addr string) *exprBuilder {
+	if !strings.Contains(addr, "/") {
+		addr += "/32"
+	}
+
+	_, ipNet, err := net.ParseCIDR(addr)
+	if err != nil || ipNet.IP.To4() == nil {
+		return b.fail(fmt.Errorf("invalid IPv4 address or CIDR %q", addr))
+	}
+
+	b.add(&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader, Offset: offset, Len: net.IPv4len})
+
+	if ones, _ := ipNet.Mask.Size(); ones < 32 {
+		b.add(&expr.Bitwise{SourceRegister: 1, DestRegister: 1, Len: net.IPv4len, Mask: ipNet.Mask, Xor: make([]byte, net.IPv4len)})
+	}
+
+	return b.add(&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: ipNet.IP.To4()})
+}
+
+// matchSet matches the address at the given offset against the members of the named set.
+func (b *exprBuilder) matchSet(offset uint32, name string) *exprBuilder {
+	return b.add(&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader, Offset: offset, Len: net.IPv4len},
+		&expr.Lookup{SourceRegister: 1, SetName: name})
+}
+
+func (b *exprBuilder) matchMark(mark string) *exprBuilder {
+	value, mask, err := parseMark(mark)
+	if err != nil {
+		return b.fail(err)
+	}
+
+	return b.add(&expr.Meta{Key: expr.MetaKeyMARK, Register: 1},
+		&expr.Bitwise{SourceRegister: 1, DestRegister: 1, Len: 4, Mask: binaryutil.NativeEndian.PutUint32(mask),
+			Xor: binaryutil.NativeEndian.PutUint32(0)},
+		&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: binaryutil.NativeEndian.PutUint32(value)})
+}
+
+// setMark sets the masked bits of the packet mark, like the iptables MARK target.
+func (b *exprBuilder) setMark(mark string) *exprBuilder {
+	value, mask, err := parseMark(mark)
+	if err != nil {
+		return b.fail(err)
+	}
+
+	return b.add(&expr.Meta{Key: expr.MetaKeyMARK, Register: 1},
+		&expr.Bitwise{SourceRegister: 1, DestRegister: 1, Len: 4, Mask: binaryutil.NativeEndian.PutUint32(^mask),
+			Xor: binaryutil.NativeEndian.PutUint32(value)},
+		&expr.Meta{Key: expr.MetaKeyMARK, SourceRegister: true, Register: 1})
+}
+
+func (b *exprBuilder) matchICMP() *exprBuilder {
+	return b.add(&expr.Meta{Key: expr.MetaKeyL4PROTO, Register: 1},
+		&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{unix.IPPROTO_ICMP}})
+}
+
+func (b *exprBuilder) matchCtStateNew() *exprBuilder {
+	return b.add(&expr.Ct{Register: 1, Key: expr.CtKeySTATE},
+		&expr.Bitwise{SourceRegister: 1, DestRegister: 1, Len: 4, Mask: binaryutil.NativeEndian.PutUint32(expr.CtStateBitNEW),
+			Xor: binaryutil.NativeEndian.PutUint32(0)},
+		&expr.Cmp{Op: expr.CmpOpNeq, Register: 1, Data: binaryutil.NativeEndian.PutUint32(0)})
+}
+
+// natTo translates the address to the given IP or to an IP in the given "<first>-<last>" range.
+func (b *exprBuilder) natTo(natType expr.NATType, to string) *exprBuilder {
+	first, last, isRange := strings.Cut(to, "-")
+
+	firstIP := net.ParseIP(first).To4()
+	if firstIP == nil {
+		return b.fail(fmt.Errorf("invalid IPv4 address %q", to))
+	}
+
+	b.add(&expr.Immediate{Register: 1, Data: firstIP})
+
+	nat := &expr.NAT{Type: natType, Family: uint32(nft.TableFamilyIPv4), RegAddrMin: 1}
+
+	if isRange {
+		lastIP := net.ParseIP(last).To4()
+		if lastIP == nil {
+			return b.fail(fmt.Errorf("invalid IPv4 address range %q", to))
+		}
+
+		b.add(&expr.Immediate{Register: 2, Data: lastIP})
+		nat.RegAddrMax = 2
+	}
+
+	return b.add(nat)
+}
+
+// natThroughMap translates the address at the given offset to the value it's mapped to in the named map.
+func (b *exprBuilder) natThroughMap(natType expr.NATType, offset uint32, mapName string) *exprBuilder {
+	return b.add(&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader, Offset: offset, Len: net.IPv4len},
+		&expr.Lookup{SourceRegister: 1, DestRegister: 1, IsDestRegSet: true, SetName: mapName},
+		&expr.NAT{Type: natType, Family: uint32(nft.TableFamilyIPv4), RegAddrMin: 1})
+}
+
+// natRangeThroughMap translates the address at the given offset to the "<first> . <last>" range it's mapped to in the
+// named map. The range is loaded in the first register, so the last address is in the second 32-bit register.
+func (b *exprBuilder) natRangeThroughMap(natType expr.NATType, offset uint32, mapName string) *exprBuilder {
+	return b.add(&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader, Offset: offset, Len: net.IPv4len},
+		&expr.Lookup{SourceRegister: 1, DestRegister: 1, IsDestRegSet: true, SetName: mapName},
+		&expr.NAT{Type: natType, Family: uint32(nft.TableFamilyIPv4), RegAddrMin: 1, RegAddrMax: unix.NFT_REG32_01})
+}
+
+func (b *exprBuilder) connLimitAbove(count int) *exprBuilder {
+	return b.add(&expr.Connlimit{Count: uint32(count), Flags: connlimitFlagOver})
+}
+
+// rateAbove matches once the rate exceeds the given number of bytes per second.
+func (b *exprBuilder) rateAbove(bytesPerSecond int64) *exprBuilder {
+	return b.add(&expr.Limit{Type: expr.LimitTypePktBytes, Rate: uint64(bytesPerSecond), Over: true, Unit: expr.LimitTimeSecond})
+}
+
+func (b *exprBuilder) counter() *exprBuilder {
+	return b.add(&expr.Counter{})
+}
+
+func (b *exprBuilder) drop() *exprBuilder {
+	return b.add(&expr.Verdict{Kind: expr.VerdictDrop})
+}
+
+func (b *exprBuilder) jumpTo(chain string) *exprBuilder {
+	return b.add(&expr.Verdict{Kind: expr.VerdictJump, Chain: chain})
+}
+
+// parseMark parses an iptables style "<value>[/<mask>]" mark.
+func parseMark(mark string) (value, mask uint32, err error) {
+	valueStr, maskStr, hasMask := strings.Cut(mark, "/")
+
+	v, err := strconv.ParseUint(valueStr, 0, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid mark %q: %w", mark, err)
+	}
+
+	if !hasMask {
+		return uint32(v), ^uint32(0), nil
+	}
+
+	m, err := strconv.ParseUint(maskStr, 0, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid mark %q: %w", mark, err)
+	}
+
+	return uint32(v), uint32(m), nil
+}
+
+func mapElement(key, value string) (nft.SetElement, error) {
+	keyIP := net.ParseIP(key).To4()
+	if keyIP == nil {
+		return nft.SetElement{}, fmt.Errorf("invalid IPv4 address %q", key)
+	}
+
+	element := nft.SetElement{Key: keyIP}
+
+	if value != "" {
+		element.Val = net.ParseIP(value).To4()
+		if element.Val == nil {
+			return nft.SetElement{}, fmt.Errorf("invalid IPv4 address %q", value)
+		}
+	}
+
+	return element, nil
+}
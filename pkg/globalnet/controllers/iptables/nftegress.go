@@ -0,0 +1,273 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iptables
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	nft "github.com/google/nftables"
+	"github.com/pkg/errors"
+	"github.com/submariner-io/submariner/pkg/globalnet/constants"
+	"github.com/submariner-io/submariner/pkg/nftables"
+)
+
+// The egress SNAT maps of the sub-chains whose sources are sets of Pods. Each maps the IP of a Pod to the SNAT IP, or
+// range, of the egress IP whose set the Pod is a member of, so each sub-chain has a single rule regardless of the number
+// of egress IPs.
+const (
+	nftEgressPodsSNAT      = "SM-GN-EGRESS-PODS-SNAT"
+	nftEgressNamespaceSNAT = "SM-GN-EGRESS-NS-SNAT"
+	nftEgressSelectorsSNAT = "SM-GN-EGRESS-SEL-SNAT"
+)
+
+var nftEgressSNATMaps = map[string]string{
+	constants.SmGlobalnetEgressChainForPods:      nftEgressPodsSNAT,
+	constants.SmGlobalnetEgressChainForNamespace: nftEgressNamespaceSNAT,
+	constants.SmGlobalnetEgressChainForSelectors: nftEgressSelectorsSNAT,
+}
+
+// The values of the egress SNAT maps are "<first IP> . <last IP>" ranges.
+var nftSNATRangeType = nft.MustConcatSetType(nft.TypeIPAddr, nft.TypeIPAddr)
+
+// egressSNATBindings binds the egress sets to the SNAT IPs their members are mapped to in the egress SNAT maps. The rules
+// and the sets are maintained through different interfaces, nfTables and nftSets, so the bindings are shared. A Pod in
+// several sets bound to the same map is mapped to the SNAT IP of the first bound set, as the first matching rule would
+// apply with the iptables backend.
+type egressSNATBindings struct {
+	mutex   sync.Mutex
+	bySet   map[string]*egressSNATBinding
+	nextSeq uint64
+}
+
+type egressSNATBinding struct {
+	mapName string
+	snatIP  string
+	seq     uint64
+}
+
+var nftEgressSNATBindings = newEgressSNATBindings()
+
+func newEgressSNATBindings() *egressSNATBindings {
+	return &egressSNATBindings{bySet: map[string]*egressSNATBinding{}}
+}
+
+func egressSNATMap(table *nft.Table, name string) *nft.Set {
+	return &nft.Set{Table: table, Name: name, IsMap: true, KeyType: nft.TypeIPAddr, DataType: nftSNATRangeType}
+}
+
+// bind maps the members of the given set to the given SNAT IP in the given map.
+func (b *egressSNATBindings) bind(conn nftables.Interface, table *nft.Table, mapName, setName, snatIP string) error {
+	if _, err := snatRange(snatIP); err != nil {
+		return err
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if existing := b.bySet[setName]; existing != nil && existing.mapName == mapName && existing.snatIP == snatIP {
+		return b.syncMap(conn, table, mapName)
+	}
+
+	previous := b.bySet[setName]
+
+	b.nextSeq++
+	b.bySet[setName] = &egressSNATBinding{mapName: mapName, snatIP: snatIP, seq: b.nextSeq}
+
+	if previous != nil && previous.mapName != mapName {
+		if err := b.syncMap(conn, table, previous.mapName); err != nil {
+			return err
+		}
+	}
+
+	return b.syncMap(conn, table, mapName)
+}
+
+// unbind removes the members of the given set from the given map.
+func (b *egressSNATBindings) unbind(conn nftables.Interface, table *nft.Table, mapName, setName string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if existing := b.bySet[setName]; existing == nil || existing.mapName != mapName {
+		return nil
+	}
+
+	delete(b.bySet, setName)
+
+	return b.syncMap(conn, table, mapName)
+}
+
+// membersChanged updates the map the given set is bound to, if any, after its members changed.
+func (b *egressSNATBindings) membersChanged(conn nftables.Interface, table *nft.Table, setName string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	binding := b.bySet[setName]
+	if binding == nil {
+		return nil
+	}
+
+	return b.syncMap(conn, table, binding.mapName)
+}
+
+// syncAll updates all the egress SNAT maps from the bindings, removing stale elements such as those left behind by a
+// previous instance.
+func (b *egressSNATBindings) syncAll(conn nftables.Interface, table *nft.Table) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, mapName := range []string{nftEgressPodsSNAT, nftEgressNamespaceSNAT, nftEgressSelectorsSNAT} {
+		if err := b.syncMap(conn, table, mapName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncMap updates the elements of the given map to match the members of the sets bound to it. The mutex must be held.
+func (b *egressSNATBindings) syncMap(conn nftables.Interface, table *nft.Table, mapName string) error {
+	desired, err := b.desiredElements(conn, table, mapName)
+	if err != nil {
+		return err
+	}
+
+	snatMap := egressSNATMap(table, mapName)
+
+	current, err := conn.GetSetElements(snatMap)
+	if err != nil {
+		return errors.Wrapf(err, "error listing the elements of nftables map %s", mapName)
+	}
+
+	var stale, missing []nft.SetElement
+
+	for i := range current {
+		if value, found := desired[string(current[i].Key)]; !found || string(current[i].Val) != string(value) {
+			stale = append(stale, nft.SetElement{Key: current[i].Key})
+		}
+	}
+
+	for key, value := range desired {
+		if !hasElement(current, []byte(key), value) {
+			missing = append(missing, nft.SetElement{Key: []byte(key), Val: value})
+		}
+	}
+
+	if len(stale) == 0 && len(missing) == 0 {
+		return nil
+	}
+
+	if len(stale) > 0 {
+		if err := conn.SetDeleteElements(snatMap, stale); err != nil {
+			return errors.Wrapf(err, "error deleting stale elements from nftables map %s", mapName)
+		}
+	}
+
+	if len(missing) > 0 {
+		if err := conn.SetAddElements(snatMap, missing); err != nil {
+			return errors.Wrapf(err, "error adding elements to nftables map %s", mapName)
+		}
+	}
+
+	return errors.Wrapf(conn.Flush(), "error updating nftables map %s", mapName)
+}
+
+// desiredElements returns the elements of the given map, keyed by source IP, derived from the members of the sets bound
+// to it. Sets that don't exist are ignored.
+func (b *egressSNATBindings) desiredElements(conn nftables.Interface, table *nft.Table, mapName string,
+) (map[string][]byte, error) {
+	var setNames []string
+
+	for setName, binding := range b.bySet {
+		if binding.mapName == mapName {
+			setNames = append(setNames, setName)
+		}
+	}
+
+	desired := map[string][]byte{}
+
+	if len(setNames) == 0 {
+		return desired, nil
+	}
+
+	sort.Slice(setNames, func(i, j int) bool {
+		return b.bySet[setNames[i]].seq < b.bySet[setNames[j]].seq
+	})
+
+	sets, err := conn.GetSets(table)
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing the nftables sets")
+	}
+
+	existing := map[string]bool{}
+	for _, s := range sets {
+		existing[s.Name] = !s.IsMap
+	}
+
+	for _, setName := range setNames {
+		if !existing[setName] {
+			continue
+		}
+
+		members, err := conn.GetSetElements(&nft.Set{Table: table, Name: setName, KeyType: nft.TypeIPAddr})
+		if err != nil {
+			return nil, errors.Wrapf(err, "error listing the entries of nftables set %q", setName)
+		}
+
+		value, _ := snatRange(b.bySet[setName].snatIP)
+
+		for i := range members {
+			if _, found := desired[string(members[i].Key)]; !found {
+				desired[string(members[i].Key)] = value
+			}
+		}
+	}
+
+	return desired, nil
+}
+
+// snatRange encodes the given SNAT IP or "<first>-<last>" range as a map value.
+func snatRange(snatIP string) ([]byte, error) {
+	first, last, isRange := strings.Cut(snatIP, "-")
+	if !isRange {
+		last = first
+	}
+
+	firstIP := net.ParseIP(first).To4()
+	lastIP := net.ParseIP(last).To4()
+
+	if firstIP == nil || lastIP == nil {
+		return nil, fmt.Errorf("invalid IPv4 address or range %q", snatIP)
+	}
+
+	return append(append([]byte{}, firstIP...), lastIP...), nil
+}
+
+func hasElement(elements []nft.SetElement, key, value []byte) bool {
+	for i := range elements {
+		if string(elements[i].Key) == string(key) {
+			return string(elements[i].Val) == string(value)
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,254 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iptables
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	nft "github.com/google/nftables"
+	"github.com/pkg/errors"
+	"github.com/submariner-io/submariner/pkg/ipset"
+	"github.com/submariner-io/submariner/pkg/nftables"
+)
+
+// nftSets implements ipset.Interface with nftables named sets of IPv4 addresses in the Globalnet table.
+type nftSets struct {
+	mutex sync.Mutex
+	nft   nftables.Interface
+	table *nft.Table
+}
+
+var _ ipset.Interface = &nftSets{}
+
+func newNFTSets() (ipset.Interface, error) {
+	conn, err := nftables.New()
+	if err != nil {
+		return nil, err //nolint:wrapcheck  // Let the caller wrap it
+	}
+
+	return &nftSets{
+		nft:   conn,
+		table: nftTable(),
+	}, nil
+}
+
+func (s *nftSets) set(name string) *nft.Set {
+	return &nft.Set{Table: s.table, Name: name, KeyType: nft.TypeIPAddr}
+}
+
+func (s *nftSets) FlushSet(set string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.nft.FlushSet(s.set(set))
+
+	if err := s.nft.Flush(); err != nil {
+		return errors.Wrapf(err, "error flushing nftables set %q", set)
+	}
+
+	return nftEgressSNATBindings.membersChanged(s.nft, s.table, set)
+}
+
+func (s *nftSets) DestroySet(set string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.nft.DelSet(s.set(set))
+
+	if err := s.nft.Flush(); err != nil {
+		return errors.Wrapf(err, "error destroying nftables set %q", set)
+	}
+
+	return nftEgressSNATBindings.membersChanged(s.nft, s.table, set)
+}
+
+func (s *nftSets) DestroyAllSets() error {
+	sets, err := s.ListSets()
+	if err != nil {
+		return err
+	}
+
+	for _, set := range sets {
+		if err := s.DestroySet(set); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *nftSets) CreateSet(set *ipset.IPSet, _ bool) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// The table may not have been created yet by the controllers.
+	s.nft.AddTable(s.table)
+
+	if err := s.nft.AddSet(s.set(set.Name), nil); err != nil {
+		return errors.Wrapf(err, "error creating nftables set %q", set.Name)
+	}
+
+	return errors.Wrapf(s.nft.Flush(), "error creating nftables set %q", set.Name)
+}
+
+func (s *nftSets) AddEntry(entry string, set *ipset.IPSet, _ bool) error {
+	key, err := setKey(entry)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.nft.SetAddElements(s.set(set.Name), []nft.SetElement{{Key: key}}); err != nil {
+		return errors.Wrapf(err, "error adding entry %q to nftables set %q", entry, set.Name)
+	}
+
+	if err := s.nft.Flush(); err != nil {
+		return errors.Wrapf(err, "error adding entry %q to nftables set %q", entry, set.Name)
+	}
+
+	return nftEgressSNATBindings.membersChanged(s.nft, s.table, set.Name)
+}
+
+func (s *nftSets) DelEntry(entry, set string) error {
+	key, err := setKey(entry)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.nft.SetDeleteElements(s.set(set), []nft.SetElement{{Key: key}}); err != nil {
+		return errors.Wrapf(err, "error deleting entry %q from nftables set %q", entry, set)
+	}
+
+	if err := s.nft.Flush(); err != nil {
+		return errors.Wrapf(err, "error deleting entry %q from nftables set %q", entry, set)
+	}
+
+	return nftEgressSNATBindings.membersChanged(s.nft, s.table, set)
+}
+
+func (s *nftSets) TestEntry(entry, set string) (bool, error) {
+	entries, err := s.ListEntries(set)
+	if err != nil {
+		return false, err
+	}
+
+	for _, e := range entries {
+		if e == entry {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (s *nftSets) ListEntries(set string) ([]string, error) {
+	elements, err := s.nft.GetSetElements(s.set(set))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listing the entries of nftables set %q", set)
+	}
+
+	entries := make([]string, 0, len(elements))
+	for i := range elements {
+		entries = append(entries, net.IP(elements[i].Key).String())
+	}
+
+	return entries, nil
+}
+
+// ListSets lists the named sets in the Globalnet table, excluding the maps used by the data path.
+func (s *nftSets) ListSets() ([]string, error) {
+	tables, err := s.nft.ListTables()
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing the nftables tables")
+	}
+
+	found := false
+
+	for _, t := range tables {
+		found = found || (t.Name == NFTablesTableName && t.Family == nft.TableFamilyIPv4)
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	sets, err := s.nft.GetSets(s.table)
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing the nftables sets")
+	}
+
+	var names []string
+
+	for _, set := range sets {
+		if !set.IsMap {
+			names = append(names, set.Name)
+		}
+	}
+
+	return names, nil
+}
+
+func (s *nftSets) GetVersion() (string, error) {
+	return "nftables", nil
+}
+
+func (s *nftSets) AddEntryWithOptions(entry *ipset.Entry, set *ipset.IPSet, ignoreExistErr bool) error {
+	return s.AddEntry(entry.IP, set, ignoreExistErr)
+}
+
+func (s *nftSets) DelEntryWithOptions(set, entry string, _ ...string) error {
+	return s.DelEntry(entry, set)
+}
+
+func (s *nftSets) ListAllSetInfo() (string, error) {
+	sets, err := s.ListSets()
+	if err != nil {
+		return "", err
+	}
+
+	info := make([]string, 0, len(sets))
+
+	for _, set := range sets {
+		entries, err := s.ListEntries(set)
+		if err != nil {
+			return "", err
+		}
+
+		info = append(info, fmt.Sprintf("Name: %s\nMembers:\n%s", set, strings.Join(entries, "\n")))
+	}
+
+	return strings.Join(info, "\n\n"), nil
+}
+
+func setKey(entry string) ([]byte, error) {
+	key := net.ParseIP(entry).To4()
+	if key == nil {
+		return nil, fmt.Errorf("invalid IPv4 entry %q", entry)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,245 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/syncer"
+	"github.com/submariner-io/admiral/pkg/util"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/globalnet/constants"
+	"github.com/submariner-io/submariner/pkg/globalnet/metrics"
+	"github.com/submariner-io/submariner/pkg/ipam"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/utils/set"
+)
+
+// NewLeakedIPSweeper returns a controller that periodically reclaims the global IPs held by GlobalIngressIPs whose
+// target no longer exists, and the IPs allocated from the pool that are no longer held by any object.
+func NewLeakedIPSweeper(config *syncer.ResourceSyncerConfig, pool *ipam.IPPool, interval time.Duration) (Interface, error) {
+	// We'll panic if config is nil, this is intentional
+	logger.Info("Creating leaked global IP sweeper")
+
+	sweeper := &leakedIPSweeper{
+		baseController:  newBaseController(),
+		pool:            pool,
+		interval:        interval,
+		staleIngressIPs: set.New[string](),
+		unheldIPs:       set.New[string](),
+	}
+
+	for _, r := range []struct {
+		obj    runtime.Object
+		client *dynamic.NamespaceableResourceInterface
+	}{
+		{&submarinerv1.GlobalIngressIP{}, &sweeper.ingressIPs},
+		{&submarinerv1.GlobalEgressIP{}, &sweeper.egressIPs},
+		{&submarinerv1.ClusterGlobalEgressIP{}, &sweeper.clusterEgressIPs},
+		{&corev1.Service{}, &sweeper.services},
+		{&corev1.Pod{}, &sweeper.pods},
+		{&corev1.Node{}, &sweeper.nodes},
+	} {
+		_, gvr, err := util.ToUnstructuredResource(r.obj, config.RestMapper)
+		if err != nil {
+			return nil, errors.Wrap(err, "error converting resource")
+		}
+
+		*r.client = config.SourceClient.Resource(*gvr)
+	}
+
+	return sweeper, nil
+}
+
+func (s *leakedIPSweeper) Start() error {
+	if s.interval > 0 {
+		go wait.Until(s.sweep, s.interval, s.stopCh)
+	}
+
+	return nil
+}
+
+func (s *leakedIPSweeper) sweep() {
+	heldIPs, err := s.reclaimStaleIngressIPs()
+	if err != nil {
+		logger.Errorf(err, "Error reclaiming the global IPs of stale GlobalIngressIPs")
+		return
+	}
+
+	for _, r := range []struct {
+		client    dynamic.NamespaceableResourceInterface
+		getHeldIP func(obj *unstructured.Unstructured) []string
+	}{
+		{s.egressIPs, allocatedIPsOf},
+		{s.clusterEgressIPs, allocatedIPsOf},
+		{s.nodes, func(obj *unstructured.Unstructured) []string {
+			return []string{obj.GetAnnotations()[constants.SmGlobalIP]}
+		}},
+	} {
+		list, err := r.client.List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			logger.Errorf(err, "Error listing resources for the leaked global IP sweep")
+			return
+		}
+
+		for i := range list.Items {
+			heldIPs.Insert(r.getHeldIP(&list.Items[i])...)
+		}
+	}
+
+	s.releaseUnheldIPs(heldIPs)
+}
+
+// reclaimStaleIngressIPs deletes the GlobalIngressIPs whose target was missing on the previous sweep and still is,
+// which releases their global IP. It returns the global IPs held by the remaining GlobalIngressIPs.
+func (s *leakedIPSweeper) reclaimStaleIngressIPs() (set.Set[string], error) {
+	list, err := s.ingressIPs.List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing the GlobalIngressIPs")
+	}
+
+	heldIPs := set.New[string]()
+	staleIngressIPs := set.New[string]()
+
+	for i := range list.Items {
+		obj := &list.Items[i]
+		key := obj.GetNamespace() + "/" + obj.GetName()
+
+		heldIPs.Insert(allocatedIPsOf(obj)...)
+
+		exists, err := s.targetExists(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		if exists {
+			continue
+		}
+
+		if !s.staleIngressIPs.Has(key) {
+			staleIngressIPs.Insert(key)
+			continue
+		}
+
+		logger.Infof("Reclaiming the global IP of GlobalIngressIP %q whose target no longer exists", key)
+
+		err = s.ingressIPs.Namespace(obj.GetNamespace()).Delete(context.TODO(), obj.GetName(), metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "error deleting GlobalIngressIP %q", key)
+		}
+
+		metrics.RecordReclaimedGlobalIPs(s.pool.GetCIDR(), len(allocatedIPsOf(obj)))
+	}
+
+	s.staleIngressIPs = staleIngressIPs
+
+	return heldIPs, nil
+}
+
+func (s *leakedIPSweeper) targetExists(obj *unstructured.Unstructured) (bool, error) {
+	target, _, _ := unstructured.NestedString(obj.Object, "spec", "target")
+	serviceName, _, _ := unstructured.NestedString(obj.Object, "spec", "serviceRef", "name")
+	podName, _, _ := unstructured.NestedString(obj.Object, "spec", "podRef", "name")
+
+	var (
+		client dynamic.NamespaceableResourceInterface
+		name   string
+	)
+
+	switch submarinerv1.TargetType(target) {
+	case submarinerv1.ClusterIPService, submarinerv1.HeadlessServiceEndpoints:
+		client, name = s.services, serviceName
+	case submarinerv1.HeadlessServicePod:
+		client, name = s.pods, podName
+	case submarinerv1.Pod:
+		// The GlobalIP of a user-created Pod target is intentionally retained while the Pod doesn't exist.
+		if obj.GetLabels()[GlobalIngressPodLabel] == "" {
+			return true, nil
+		}
+
+		client, name = s.pods, podName
+	default:
+		return true, nil
+	}
+
+	if name == "" {
+		return true, nil
+	}
+
+	_, err := client.Namespace(obj.GetNamespace()).Get(context.TODO(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+
+	return err == nil, errors.Wrapf(err, "error retrieving the target %q of GlobalIngressIP %s/%s", name,
+		obj.GetNamespace(), obj.GetName())
+}
+
+// releaseUnheldIPs releases the IPs allocated from the pool that weren't held by any object on the previous sweep and
+// still aren't.
+func (s *leakedIPSweeper) releaseUnheldIPs(heldIPs set.Set[string]) {
+	unheldIPs := set.New[string]()
+
+	var leakedIPs []string
+
+	for _, ip := range s.pool.AllocatedIPs() {
+		if heldIPs.Has(ip) {
+			continue
+		}
+
+		if s.unheldIPs.Has(ip) {
+			leakedIPs = append(leakedIPs, ip)
+		} else {
+			unheldIPs.Insert(ip)
+		}
+	}
+
+	s.unheldIPs = unheldIPs
+
+	if len(leakedIPs) == 0 {
+		return
+	}
+
+	logger.Infof("Reclaiming leaked global IPs %v", leakedIPs)
+
+	if err := s.pool.Release(leakedIPs...); err != nil {
+		logger.Errorf(err, "Error releasing leaked global IPs %v", leakedIPs)
+		return
+	}
+
+	metrics.RecordReclaimedGlobalIPs(s.pool.GetCIDR(), len(leakedIPs))
+}
+
+func allocatedIPsOf(obj *unstructured.Unstructured) []string {
+	ips, _, _ := unstructured.NestedStringSlice(obj.Object, "status", "allocatedIPs")
+
+	if ip, _, _ := unstructured.NestedString(obj.Object, "status", "allocatedIP"); ip != "" {
+		ips = append(ips, ip)
+	}
+
+	return ips
+}
@@ -0,0 +1,157 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/admiral/pkg/syncer"
+	"github.com/submariner-io/admiral/pkg/syncer/test"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/globalnet/controllers"
+	"github.com/submariner-io/submariner/pkg/ipam"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const sweepInterval = 100 * time.Millisecond
+
+var _ = Describe("Leaked global IP sweeper", func() {
+	t := newLeakedIPSweeperTestDriver()
+
+	When("a GlobalIngressIP's target Service doesn't exist", func() {
+		It("should delete the GlobalIngressIP", func() {
+			t.createGlobalIngressIP(newIngressIPWithStatus(globalIngressIPName, submarinerv1.ClusterIPService, "169.254.1.10"))
+			test.AwaitNoResource(t.globalIngressIPs, globalIngressIPName)
+		})
+	})
+
+	When("a GlobalIngressIP's target Service exists", func() {
+		It("should not delete the GlobalIngressIP", func() {
+			t.createService(newClusterIPService())
+			t.createGlobalIngressIP(newIngressIPWithStatus(globalIngressIPName, submarinerv1.ClusterIPService, "169.254.1.10"))
+			t.ensureGlobalIngressIP(globalIngressIPName)
+		})
+	})
+
+	When("a user-created GlobalIngressIP's target Pod doesn't exist", func() {
+		It("should not delete the GlobalIngressIP", func() {
+			t.createGlobalIngressIP(newIngressIPWithStatus(globalIngressIPName, submarinerv1.Pod, "169.254.1.10"))
+			t.ensureGlobalIngressIP(globalIngressIPName)
+		})
+	})
+
+	When("the target Pod of a GlobalIngressIP created for an annotated Pod doesn't exist", func() {
+		It("should delete the GlobalIngressIP", func() {
+			ingressIP := newIngressIPWithStatus(globalIngressIPName, submarinerv1.Pod, "169.254.1.10")
+			ingressIP.Labels = map[string]string{controllers.GlobalIngressPodLabel: "true"}
+
+			t.createGlobalIngressIP(ingressIP)
+			test.AwaitNoResource(t.globalIngressIPs, globalIngressIPName)
+		})
+	})
+
+	When("an allocated IP isn't held by any object", func() {
+		It("should release it", func() {
+			Expect(t.pool.Reserve("169.254.1.20")).To(Succeed())
+			t.awaitIPsReleasedFromPool("169.254.1.20")
+		})
+	})
+
+	When("allocated IPs are held by objects", func() {
+		It("should not release them", func() {
+			t.createGlobalIngressIP(newIngressIPWithStatus(globalIngressIPName, submarinerv1.Pod, "169.254.1.10"))
+
+			egressIP := newGlobalEgressIP(globalEgressIPName, nil, nil)
+			egressIP.Status.AllocatedIPs = []string{"169.254.1.11", "169.254.1.12"}
+			t.createGlobalEgressIP(egressIP)
+
+			t.createNode(nodeName, cniInterfaceIP, "169.254.1.13")
+
+			Expect(t.pool.Reserve("169.254.1.10", "169.254.1.11", "169.254.1.12", "169.254.1.13")).To(Succeed())
+
+			Consistently(func() []string {
+				return t.pool.AllocatedIPs()
+			}, sweepInterval*5).Should(ConsistOf("169.254.1.10", "169.254.1.11", "169.254.1.12", "169.254.1.13"))
+		})
+	})
+})
+
+type leakedIPSweeperTestDriver struct {
+	*testDriverBase
+}
+
+func newLeakedIPSweeperTestDriver() *leakedIPSweeperTestDriver {
+	t := &leakedIPSweeperTestDriver{}
+
+	BeforeEach(func() {
+		t.testDriverBase = newTestDriverBase()
+
+		var err error
+
+		t.pool, err = ipam.NewIPPool(t.globalCIDR)
+		Expect(err).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		var err error
+
+		t.controller, err = controllers.NewLeakedIPSweeper(&syncer.ResourceSyncerConfig{
+			SourceClient: t.dynClient,
+			RestMapper:   t.restMapper,
+			Scheme:       t.scheme,
+		}, t.pool, sweepInterval)
+
+		Expect(err).To(Succeed())
+		Expect(t.controller.Start()).To(Succeed())
+	})
+
+	AfterEach(func() {
+		t.testDriverBase.afterEach()
+	})
+
+	return t
+}
+
+func (t *leakedIPSweeperTestDriver) ensureGlobalIngressIP(name string) {
+	Consistently(func() error {
+		_, err := t.globalIngressIPs.Get(context.TODO(), name, metav1.GetOptions{})
+		return err
+	}, sweepInterval*5).Should(Succeed())
+}
+
+func newIngressIPWithStatus(name string, target submarinerv1.TargetType, allocatedIP string) *submarinerv1.GlobalIngressIP {
+	return &submarinerv1.GlobalIngressIP{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: submarinerv1.GlobalIngressIPSpec{
+			Target:     target,
+			ServiceRef: &corev1.LocalObjectReference{Name: serviceName},
+			PodRef:     &corev1.LocalObjectReference{Name: "nginx"},
+		},
+		Status: submarinerv1.GlobalIngressIPStatus{
+			AllocatedIP: allocatedIP,
+		},
+	}
+}
@@ -34,21 +34,24 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 )
 
-func NewNodeController(config *syncer.ResourceSyncerConfig, pool *ipam.IPPool, nodeName string) (Interface, error) {
+func NewNodeController(config *syncer.ResourceSyncerConfig, pool *ipam.IPPool, nodeName string, recorder record.EventRecorder,
+	dataPathBackend string,
+) (Interface, error) {
 	// We'll panic if config is nil, this is intentional
 	var err error
 
 	logger.Info("Creating Node controller")
 
-	iptIface, err := iptables.New()
+	iptIface, err := iptables.New(dataPathBackend)
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating the IPTablesInterface handler")
 	}
 
 	controller := &nodeController{
-		baseIPAllocationController: newBaseIPAllocationController(pool, iptIface),
+		baseIPAllocationController: newBaseIPAllocationController(pool, iptIface, recorder),
 		nodeName:                   nodeName,
 	}
 
@@ -139,12 +142,16 @@ func (n *nodeController) allocateIP(node *corev1.Node, op syncer.Operation) (run
 		ips, err := n.pool.Allocate(1)
 		if err != nil {
 			logger.Errorf(err, "Error allocating IPs for node %q", node.Name)
+			n.checkPoolUsage(node, nil, err)
+
 			return nil, true
 		}
 
 		globalIP = ips[0]
 
 		logger.Infof("Allocated global IP %s for node %q", globalIP, node.Name)
+
+		n.checkPoolUsage(node, nil, nil)
 	}
 
 	logger.Infof("Adding ingress rules for node %q with global IP %s, CNI IP %s", node.Name, globalIP, cniIfaceIP)
@@ -28,6 +28,7 @@ import (
 	"github.com/submariner-io/admiral/pkg/syncer/test"
 	"github.com/submariner-io/submariner/pkg/globalnet/constants"
 	"github.com/submariner-io/submariner/pkg/globalnet/controllers"
+	iptiface "github.com/submariner-io/submariner/pkg/globalnet/controllers/iptables"
 	"github.com/submariner-io/submariner/pkg/ipam"
 	routeAgent "github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
 	corev1 "k8s.io/api/core/v1"
@@ -62,6 +63,10 @@ var _ = Describe("Node controller", func() {
 
 					t.awaitNodeGlobalIP("")
 				})
+
+				It("should raise a Warning Event", func() {
+					t.awaitWarningEvent("PoolExhausted")
+				})
 			})
 		})
 
@@ -198,7 +203,7 @@ func (t *nodeControllerTestDriver) start() {
 		SourceClient: t.dynClient,
 		RestMapper:   t.restMapper,
 		Scheme:       t.scheme,
-	}, t.pool, nodeName)
+	}, t.pool, nodeName, t.recorder, iptiface.IPTablesBackend)
 
 	Expect(err).To(Succeed())
 	Expect(t.controller.Start()).To(Succeed())
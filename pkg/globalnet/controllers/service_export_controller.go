@@ -36,7 +36,7 @@ import (
 //nolint:revive // Ignore "unexported-return:... which can be annoying to use"; it's only used by unit tests.
 func NewServiceExportController(config *syncer.ResourceSyncerConfig, podControllers *IngressPodControllers,
 	endpointsControllers *ServiceExportEndpointsControllers,
-	ingressEndpointsControllers *IngressEndpointsControllers,
+	ingressEndpointsControllers *IngressEndpointsControllers, dataPathBackend string,
 ) (*serviceExportController, error) {
 	// We'll panic if config is nil, this is intentional
 	var err error
@@ -72,7 +72,7 @@ func NewServiceExportController(config *syncer.ResourceSyncerConfig, podControll
 		return nil, errors.Wrap(err, "error creating the syncer")
 	}
 
-	iptIface, err := iptables.New()
+	iptIface, err := iptables.New(dataPathBackend)
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating the IPTablesInterface handler")
 	}
@@ -30,11 +30,11 @@ import (
 	iptiface "github.com/submariner-io/submariner/pkg/globalnet/controllers/iptables"
 	"github.com/submariner-io/submariner/pkg/ipam"
 	"github.com/submariner-io/submariner/pkg/ipset"
-	"github.com/submariner-io/submariner/pkg/iptables"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/set"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -55,6 +55,13 @@ const (
 
 	ServiceRefLabel = "submariner.io/serviceRef"
 
+	// GlobalIngressPodAnnotation is set to "true" on a Pod to request a GlobalIngressIP that makes it reachable from
+	// remote clusters via its own global IP.
+	GlobalIngressPodAnnotation = "submariner.io/global-ingress"
+
+	// GlobalIngressPodLabel is applied on the GlobalIngressIPs created for Pods with the GlobalIngressPodAnnotation.
+	GlobalIngressPodLabel = "submariner.io/global-ingress-pod"
+
 	// InternalServicePrefix is a prefix used for internal services.
 	InternalServicePrefix = "submariner-"
 
@@ -75,6 +82,13 @@ const (
 	DefaultNumberOfClusterEgressIPs = 8
 
 	LeaderElectionLockName = "submariner-globalnet-lock"
+
+	// The interval at which the per GlobalEgressIP traffic counters are exported as metrics.
+	egressCountersInterval = 30 * time.Second
+
+	// The reasons of the Warning Events and PoolExhaustion conditions raised when the global IP pool runs low.
+	poolThresholdReachedReason = "PoolUsageThresholdReached"
+	poolExhaustedReason        = "PoolExhausted"
 )
 
 type Interface interface {
@@ -88,6 +102,13 @@ type Specification struct {
 	GlobalCIDR  []string
 	MetricsPort string `default:"32781"`
 	Uninstall   bool
+	// DataPathBackend selects how the Globalnet data path is programmed, either "iptables" or "nftables".
+	DataPathBackend string `default:"iptables"`
+	// PoolUsageThresholds are the percentages of allocated global IPs at which Warning Events and the PoolExhaustion
+	// condition are raised on the objects being allocated.
+	PoolUsageThresholds []int `default:"80,95"`
+	// LeakedIPSweepInterval is the interval at which global IPs held for targets that no longer exist are reclaimed.
+	LeakedIPSweepInterval time.Duration `default:"5m"`
 }
 
 type LeaderElectionConfig struct {
@@ -122,7 +143,7 @@ type gatewayMonitor struct {
 	kubeClient              kubernetes.Interface
 	remoteEndpointTimeStamp map[string]metav1.Time
 	spec                    Specification
-	ipt                     iptables.Interface
+	ipt                     iptiface.Interface
 	isGatewayNode           atomic.Bool
 	shuttingDown            atomic.Bool
 	leaderElectionInfo      atomic.Pointer[LeaderElectionInfo]
@@ -132,6 +153,8 @@ type gatewayMonitor struct {
 	remoteSubnets           set.Set[string]
 	controllersMutex        sync.Mutex // Protects controllers
 	controllers             []Interface
+	eventBroadcaster        record.EventBroadcaster
+	recorder                record.EventRecorder
 }
 
 type baseSyncerController struct {
@@ -143,6 +166,7 @@ type baseIPAllocationController struct {
 	*baseSyncerController
 	pool     *ipam.IPPool
 	iptIface iptiface.Interface
+	recorder record.EventRecorder
 }
 
 type globalEgressIPController struct {
@@ -154,22 +178,59 @@ type globalEgressIPController struct {
 }
 
 type egressPodWatcher struct {
-	stopCh       chan struct{}
-	ipSetName    string
-	namedIPSet   ipset.Named
-	podSelector  *metav1.LabelSelector
-	allocatedIPs []string
+	sync.Mutex
+	stopCh            chan struct{}
+	ipSetName         string
+	namedIPSet        ipset.Named
+	podSelector       *metav1.LabelSelector
+	namespaceSelector *metav1.LabelSelector
+	allocatedIPs      []string
+	watcher           watcher.Interface
+	// The names of the namespaces matching the namespaceSelector. Nil if there's no namespaceSelector.
+	namespaces map[string]bool
 }
 
 type clusterGlobalEgressIPController struct {
 	*baseIPAllocationController
-	localSubnets []string
+	sync.Mutex
+	localSubnets  []string
+	podWatchers   map[string]*egressPodWatcher
+	ipSetIface    ipset.Interface
+	watcherConfig watcher.Config
 }
 
 type globalIngressIPController struct {
 	*baseIPAllocationController
-	services dynamic.NamespaceableResourceInterface
-	scheme   *runtime.Scheme
+	sync.Mutex
+	services   dynamic.NamespaceableResourceInterface
+	pods       dynamic.NamespaceableResourceInterface
+	scheme     *runtime.Scheme
+	podWatcher watcher.Interface
+	// The GlobalIngressIPs targeting a Pod, keyed by GlobalIngressIP.
+	podTargets map[string]*podIngressTarget
+}
+
+type podIngressTarget struct {
+	podKey   string
+	globalIP string
+	// The Pod IP the ingress rules are currently programmed for, if any.
+	podIP string
+}
+
+type leakedIPSweeper struct {
+	*baseController
+	pool             *ipam.IPPool
+	interval         time.Duration
+	ingressIPs       dynamic.NamespaceableResourceInterface
+	egressIPs        dynamic.NamespaceableResourceInterface
+	clusterEgressIPs dynamic.NamespaceableResourceInterface
+	services         dynamic.NamespaceableResourceInterface
+	pods             dynamic.NamespaceableResourceInterface
+	nodes            dynamic.NamespaceableResourceInterface
+	// The GlobalIngressIPs whose target was missing and the pool IPs held by no object on the previous sweep. They're
+	// reclaimed if still found on the next sweep to avoid racing with in-flight allocations.
+	staleIngressIPs set.Set[string]
+	unheldIPs       set.Set[string]
 }
 
 type serviceExportController struct {
@@ -202,6 +263,7 @@ type ingressPodController struct {
 	svcName                  string
 	namespace                string
 	ingressIPMap             set.Set[string]
+	ingressIPs               dynamic.NamespaceableResourceInterface
 }
 
 type IngressPodControllers struct {
@@ -33,7 +33,6 @@ import (
 	versioned "github.com/submariner-io/submariner/pkg/client/clientset/versioned"
 	"github.com/submariner-io/submariner/pkg/globalnet/constants"
 	"github.com/submariner-io/submariner/pkg/globalnet/controllers/iptables"
-	"github.com/submariner-io/submariner/pkg/ipset"
 	routeAgent "github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -44,16 +43,16 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	rest "k8s.io/client-go/rest"
 	"k8s.io/client-go/util/retry"
-	utilexec "k8s.io/utils/exec"
 )
 
-func UninstallDataPath() {
-	ipt, err := iptables.New()
+func UninstallDataPath(dataPathBackend string) {
+	ipt, err := iptables.New(dataPathBackend)
 	logger.FatalOnError(err, "Error initializing IP tables")
 
 	natTableChains := []string{
 		// The chains have to be deleted in a specific order.
 		constants.SmGlobalnetEgressChainForCluster,
+		constants.SmGlobalnetEgressChainForSelectors,
 		constants.SmGlobalnetEgressChainForHeadlessSvcPods,
 		constants.SmGlobalnetEgressChainForHeadlessSvcEPs,
 		constants.SmGlobalnetEgressChainForNamespace,
@@ -87,7 +86,31 @@ func UninstallDataPath() {
 		}
 	}
 
-	ipsetIface := ipset.New(utilexec.New())
+	filterTableChains := []string{
+		constants.SmGlobalnetForwardChain,
+		constants.SmGlobalnetEgressChainForLimits,
+	}
+
+	for _, chain := range filterTableChains {
+		err = ipt.FlushIPTableChain(constants.FilterTable, chain)
+		if err != nil {
+			logger.Errorf(err, "Error flushing iptables chain %q", chain)
+		}
+	}
+
+	if err := ipt.DeleteIPTableRule(constants.FilterTable, routeAgent.ForwardChain, constants.SmGlobalnetForwardChain); err != nil {
+		logger.Errorf(err, "Error deleting iptables rule for %q in FORWARD chain", constants.SmGlobalnetForwardChain)
+	}
+
+	for _, chain := range filterTableChains {
+		err = ipt.DeleteIPTableChain(constants.FilterTable, chain)
+		if err != nil {
+			logger.Errorf(err, "Error deleting iptables chain %q", chain)
+		}
+	}
+
+	ipsetIface, err := iptables.NewIPSet(dataPathBackend)
+	logger.FatalOnError(err, "Error initializing IP sets")
 
 	ipSetList, err := ipsetIface.ListSets()
 	if err != nil {
@@ -38,6 +38,7 @@ import (
 	"github.com/submariner-io/submariner/pkg/cidr"
 	submarinerClientset "github.com/submariner-io/submariner/pkg/client/clientset/versioned"
 	"github.com/submariner-io/submariner/pkg/globalnet/controllers"
+	"github.com/submariner-io/submariner/pkg/globalnet/controllers/iptables"
 	"github.com/submariner-io/submariner/pkg/versions"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -74,6 +75,9 @@ func main() {
 	err := envconfig.Process("submariner", &spec)
 	logger.FatalOnError(err, "Error processing env config")
 
+	err = iptables.ValidateBackend(spec.DataPathBackend)
+	logger.FatalOnError(err, "Error selecting the data path backend")
+
 	cfg, err := clientcmd.BuildConfigFromFlags(masterURL, kubeconfig)
 	logger.FatalOnError(err, "Error building kube config")
 
@@ -82,7 +86,7 @@ func main() {
 
 	if spec.Uninstall {
 		logger.Info("Uninstalling submariner-globalnet")
-		controllers.UninstallDataPath()
+		controllers.UninstallDataPath(spec.DataPathBackend)
 		controllers.DeleteGlobalnetObjects(submarinerClient, cfg)
 		controllers.RemoveGlobalIPAnnotationOnNode(cfg)
 
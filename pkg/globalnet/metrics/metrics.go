@@ -18,10 +18,16 @@ limitations under the License.
 
 package metrics
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
 
 const (
-	cidrLabel = "cidr"
+	cidrLabel      = "cidr"
+	namespaceLabel = "namespace"
+	nameLabel      = "name"
 )
 
 var (
@@ -70,11 +76,56 @@ var (
 			cidrLabel,
 		},
 	)
+	globalIPsReclaimedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "submariner_global_IP_reclaimed",
+			Help: "Count of leaked global IPs reclaimed per CIDR",
+		},
+		[]string{
+			cidrLabel,
+		},
+	)
+	globalEgressIPPacketsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "submariner_global_egress_IP_tx_packets_total",
+			Help: "Count of packets sent to remote clusters per GlobalEgressIP",
+		},
+		[]string{
+			namespaceLabel,
+			nameLabel,
+		},
+	)
+	globalEgressIPBytesCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "submariner_global_egress_IP_tx_bytes_total",
+			Help: "Count of bytes sent to remote clusters per GlobalEgressIP",
+		},
+		[]string{
+			namespaceLabel,
+			nameLabel,
+		},
+	)
+	globalEgressIPConnectionsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "submariner_global_egress_IP_connections_total",
+			Help: "Count of connections initiated to remote clusters per GlobalEgressIP",
+		},
+		[]string{
+			namespaceLabel,
+			nameLabel,
+		},
+	)
+)
+
+var (
+	egressCountersMutex sync.Mutex
+	lastEgressCounters  = map[string][3]uint64{}
 )
 
 func init() {
 	prometheus.MustRegister(globalIPsAvailabilityGauge, globalIPsAllocatedGauge, globalEgressIPsAllocatedGauge,
-		clusterGlobalEgressIPsAllocatedGauge, globalIngressIPsAllocatedGauge)
+		clusterGlobalEgressIPsAllocatedGauge, globalIngressIPsAllocatedGauge, globalIPsReclaimedCounter, globalEgressIPPacketsCounter,
+		globalEgressIPBytesCounter, globalEgressIPConnectionsCounter)
 }
 
 func RecordAllocateGlobalIP(cidr string) {
@@ -116,6 +167,50 @@ func RecordDeallocateGlobalIngressIPs(cidr string, count int) {
 	globalIngressIPsAllocatedGauge.With(prometheus.Labels{cidrLabel: cidr}).Sub(float64(count))
 }
 
+func RecordReclaimedGlobalIPs(cidr string, count int) {
+	globalIPsReclaimedCounter.With(prometheus.Labels{cidrLabel: cidr}).Add(float64(count))
+}
+
 func RecordAvailability(cidr string, count int) {
 	globalIPsAvailabilityGauge.With(prometheus.Labels{cidrLabel: cidr}).Set(float64(count))
 }
+
+// RecordGlobalEgressIPCounters records the current values of the data path counters for a GlobalEgressIP. The data path
+// counters are absolute so the delta since the last recorded values is added. A value lower than the last one means the
+// data path counter was reset, in which case the whole value is added.
+func RecordGlobalEgressIPCounters(namespace, name string, packets, bytes, connections uint64) {
+	labels := prometheus.Labels{namespaceLabel: namespace, nameLabel: name}
+	key := namespace + "/" + name
+
+	egressCountersMutex.Lock()
+	defer egressCountersMutex.Unlock()
+
+	last := lastEgressCounters[key]
+
+	globalEgressIPPacketsCounter.With(labels).Add(float64(counterDelta(last[0], packets)))
+	globalEgressIPBytesCounter.With(labels).Add(float64(counterDelta(last[1], bytes)))
+	globalEgressIPConnectionsCounter.With(labels).Add(float64(counterDelta(last[2], connections)))
+
+	lastEgressCounters[key] = [3]uint64{packets, bytes, connections}
+}
+
+func DeleteGlobalEgressIPCounters(namespace, name string) {
+	labels := prometheus.Labels{namespaceLabel: namespace, nameLabel: name}
+
+	egressCountersMutex.Lock()
+	defer egressCountersMutex.Unlock()
+
+	delete(lastEgressCounters, namespace+"/"+name)
+
+	globalEgressIPPacketsCounter.Delete(labels)
+	globalEgressIPBytesCounter.Delete(labels)
+	globalEgressIPConnectionsCounter.Delete(labels)
+}
+
+func counterDelta(last, current uint64) uint64 {
+	if current < last {
+		return current
+	}
+
+	return current - last
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"sort"
 	"sync"
 
 	"github.com/emirpasic/gods/maps/treemap"
@@ -35,7 +36,9 @@ type IPPool struct {
 	network   *net.IPNet
 	size      int
 	available *treemap.Map // int IP is the key, string IP is the value
-	mutex     sync.RWMutex
+	// The percentages of allocated IPs, in ascending order, at which the pool is considered to be running low.
+	thresholds []int
+	mutex      sync.RWMutex
 }
 
 func NewIPPool(cidr string) (*IPPool, error) {
@@ -218,3 +221,59 @@ func (p *IPPool) Size() int {
 func (p *IPPool) GetCIDR() string {
 	return p.cidr
 }
+
+// SetUsageThresholds sets the percentages of allocated IPs at which the pool is considered to be running low.
+func (p *IPPool) SetUsageThresholds(thresholds ...int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.thresholds = append([]int{}, thresholds...)
+	sort.Ints(p.thresholds)
+}
+
+// Usage returns the percentage of the pool's IPs that are allocated.
+func (p *IPPool) Usage() int {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.usage()
+}
+
+func (p *IPPool) usage() int {
+	return (p.size - p.available.Size()) * 100 / p.size
+}
+
+// ReachedThreshold returns the highest usage threshold reached by the pool, or 0 if none is reached.
+func (p *IPPool) ReachedThreshold() int {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	usage := p.usage()
+	reached := 0
+
+	for _, t := range p.thresholds {
+		if usage >= t {
+			reached = t
+		}
+	}
+
+	return reached
+}
+
+// AllocatedIPs returns the IPs that are currently allocated or reserved from the pool.
+func (p *IPPool) AllocatedIPs() []string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	allocated := make([]string, 0, p.size-p.available.Size())
+	startingIP := ipToInt(p.network.IP) + 1
+
+	for i := 0; i < p.size; i++ {
+		intIP := startingIP + i
+		if _, found := p.available.Get(intIP); !found {
+			allocated = append(allocated, intToIP(intIP).String())
+		}
+	}
+
+	return allocated
+}
@@ -34,6 +34,7 @@ var (
 	_ = Describe("IP Pool allocation", testPoolAllocation)
 	_ = Describe("IP Pool release", testPoolRelease)
 	_ = Describe("IP Pool reserve", testPoolReserve)
+	_ = Describe("IP Pool usage", testPoolUsage)
 	_ = Describe("isContiguous", testIsContiguous)
 )
 
@@ -286,6 +287,44 @@ func testPoolReserve() {
 	})
 }
 
+func testPoolUsage() {
+	t := newTestDriver()
+
+	JustBeforeEach(func() {
+		t.pool.SetUsageThresholds(95, 80)
+	})
+
+	When("no usage threshold is reached", func() {
+		It("should return 0", func() {
+			t.allocate(10)
+			Expect(t.pool.Usage()).To(Equal(3))
+			Expect(t.pool.ReachedThreshold()).To(Equal(0))
+		})
+	})
+
+	When("usage thresholds are reached", func() {
+		It("should return the highest one reached", func() {
+			t.allocate(204)
+			Expect(t.pool.ReachedThreshold()).To(Equal(80))
+
+			t.allocate(40)
+			Expect(t.pool.ReachedThreshold()).To(Equal(95))
+		})
+	})
+
+	When("IPs are allocated and reserved", func() {
+		It("should return them as allocated", func() {
+			Expect(t.pool.Reserve("169.254.1.10")).To(Succeed())
+			ips := t.allocate(2)
+
+			Expect(t.pool.AllocatedIPs()).To(ConsistOf(append(ips, "169.254.1.10")))
+
+			Expect(t.pool.Release(ips...)).To(Succeed())
+			Expect(t.pool.AllocatedIPs()).To(ConsistOf("169.254.1.10"))
+		})
+	})
+}
+
 func testIsContiguous() {
 	When("contiguous", func() {
 		It("should return true", func() {
@@ -20,6 +20,7 @@ package fake
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"sync"
 
@@ -32,6 +33,7 @@ type basicType struct {
 	mutex                    sync.Mutex
 	chainRules               map[string]set.Set[string]
 	tableChains              map[string]set.Set[string]
+	ruleCounters             map[string][2]uint64
 	failOnAppendRuleMatchers []interface{}
 	failOnDeleteRuleMatchers []interface{}
 }
@@ -44,8 +46,9 @@ func New() *IPTables {
 	return &IPTables{
 		Adapter: iptables.Adapter{
 			Basic: &basicType{
-				chainRules:  map[string]set.Set[string]{},
-				tableChains: map[string]set.Set[string]{},
+				chainRules:   map[string]set.Set[string]{},
+				tableChains:  map[string]set.Set[string]{},
+				ruleCounters: map[string][2]uint64{},
 			},
 		},
 	}
@@ -77,9 +80,20 @@ func (i *basicType) Delete(table, chain string, rulespec ...string) error {
 		ruleSet.Delete(strings.Join(rulespec, " "))
 	}
 
+	delete(i.ruleCounters, table+"/"+chain+"/"+strings.Join(rulespec, " "))
+
 	return nil
 }
 
+func (i *basicType) Exists(table, chain string, rulespec ...string) (bool, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	ruleSet := i.chainRules[table+"/"+chain]
+
+	return ruleSet != nil && ruleSet.Has(strings.Join(rulespec, " ")), nil
+}
+
 func (i *basicType) addRule(table, chain string, rulespec ...string) error {
 	i.mutex.Lock()
 	defer i.mutex.Unlock()
@@ -118,6 +132,20 @@ func (i *basicType) List(table, chain string) ([]string, error) {
 	return i.listRules(table, chain), nil
 }
 
+func (i *basicType) ListWithCounters(table, chain string) ([]string, error) {
+	rules := i.listRules(table, chain)
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	for idx := range rules {
+		counters := i.ruleCounters[table+"/"+chain+"/"+rules[idx]]
+		rules[idx] = fmt.Sprintf("-A %s %s -c %d %d", chain, rules[idx], counters[0], counters[1])
+	}
+
+	return rules, nil
+}
+
 func (i *basicType) listRules(table, chain string) []string {
 	i.mutex.Lock()
 	defer i.mutex.Unlock()
@@ -160,6 +188,8 @@ func (i *basicType) ClearChain(table, chain string) error {
 		chainSet.Delete(chain)
 	}
 
+	delete(i.chainRules, table+"/"+chain)
+
 	return nil
 }
 
@@ -238,6 +268,15 @@ func (i *IPTables) AddFailOnDeleteRuleMatcher(stringOrMatcher interface{}) {
 	i.basic().failOnDeleteRuleMatchers = append(i.basic().failOnDeleteRuleMatchers, stringOrMatcher)
 }
 
+// SetRuleCounters sets the packet and byte counters returned by ListWithCounters for the given rule. The counters are reset
+// when the rule is deleted.
+func (i *IPTables) SetRuleCounters(table, chain, rule string, packets, bytes uint64) {
+	i.basic().mutex.Lock()
+	defer i.basic().mutex.Unlock()
+
+	i.basic().ruleCounters[table+"/"+chain+"/"+rule] = [2]uint64{packets, bytes}
+}
+
 func (i *IPTables) basic() *basicType {
 	return i.Adapter.Basic.(*basicType)
 }
@@ -29,8 +29,11 @@ type Basic interface {
 	Append(table, chain string, rulespec ...string) error
 	AppendUnique(table, chain string, rulespec ...string) error
 	Delete(table, chain string, rulespec ...string) error
+	Exists(table, chain string, rulespec ...string) (bool, error)
 	Insert(table, chain string, pos int, rulespec ...string) error
 	List(table, chain string) ([]string, error)
+	// ListWithCounters is like List but each rule also includes its packet and byte counters in the form "-c <packets> <bytes>".
+	ListWithCounters(table, chain string) ([]string, error)
 	ListChains(table string) ([]string, error)
 	NewChain(table, chain string) error
 	ChainExists(table, chain string) (bool, error)
@@ -30,6 +30,7 @@ import (
 	"github.com/submariner-io/admiral/pkg/log"
 	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/endpoint"
+	natproto "github.com/submariner-io/submariner/pkg/natdiscovery/proto"
 	"github.com/submariner-io/submariner/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -151,9 +152,12 @@ func (nd *natDiscovery) AddEndpoint(endPoint *v1.Endpoint) {
 	remoteNAT := newRemoteEndpointNAT(endPoint)
 
 	// support nat discovery disabled or a remote cluster endpoint which still hasn't implemented this protocol
-	if _, err := extractNATDiscoveryPort(&endPoint.Spec); err != nil || nd.serverPort == 0 {
-		if !errors.Is(err, errNoNATDiscoveryPort) {
+	if _, err := extractNATDiscoveryPort(&endPoint.Spec); err != nil || nd.serverPort == 0 ||
+		remoteNAT.protocolVersion < natproto.MinVersion {
+		if err != nil && !errors.Is(err, errNoNATDiscoveryPort) {
 			logger.Errorf(err, "Error extracting NATT discovery port from endpoint %q", endPoint.Spec.CableName)
+		} else if err == nil && remoteNAT.protocolVersion < natproto.MinVersion {
+			logger.Infof("Endpoint %q doesn't support a common NAT discovery protocol version", endPoint.Spec.CableName)
 		}
 
 		remoteNAT.useLegacyNATSettings()
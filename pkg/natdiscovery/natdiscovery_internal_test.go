@@ -26,6 +26,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	natproto "github.com/submariner-io/submariner/pkg/natdiscovery/proto"
 )
 
 const (
@@ -268,6 +269,29 @@ var _ = When("a remote Endpoint is added", func() {
 		})
 	})
 
+	Context("advertising no common NAT discovery protocol version", func() {
+		BeforeEach(func() {
+			t.remoteEndpoint.Spec.PublicIP = testRemotePublicIP
+			t.remoteEndpoint.Spec.Capabilities = &submarinerv1.EndpointCapabilities{NATDiscoveryVersion: 0}
+		})
+
+		It("should notify with the legacy NATEndpointInfo settings", func() {
+			Eventually(t.readyChannel, 5).Should(Receive(Equal(&NATEndpointInfo{
+				Endpoint: t.remoteEndpoint,
+				UseNAT:   true,
+				UseIP:    t.remoteEndpoint.Spec.PublicIP,
+			})))
+		})
+	})
+
+	Context("advertising a higher NAT discovery protocol version", func() {
+		BeforeEach(func() {
+			t.remoteEndpoint.Spec.Capabilities = &submarinerv1.EndpointCapabilities{NATDiscoveryVersion: natproto.Version + 1}
+		})
+
+		t.testRemoteEndpointAdded(testRemotePrivateIP, natNotExpected)
+	})
+
 	Context("and the remote process doesn't respond", func() {
 		BeforeEach(func() {
 			forwardHowManyFromLocal = 0
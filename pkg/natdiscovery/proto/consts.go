@@ -20,5 +20,8 @@ package proto
 
 const (
 	DefaultPort = 4490
-	Version     = 1
+	// Version is the highest protocol version supported.
+	Version = 1
+	// MinVersion is the lowest protocol version supported.
+	MinVersion = 1
 )
@@ -24,6 +24,7 @@ import (
 
 	"github.com/submariner-io/admiral/pkg/log"
 	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	natproto "github.com/submariner-io/submariner/pkg/natdiscovery/proto"
 )
 
 type endpointState int
@@ -54,6 +55,7 @@ type remoteEndpointNAT struct {
 	lastPrivateIPRequestID uint64
 	useNAT                 bool
 	usingLoadBalancer      bool
+	protocolVersion        int32
 }
 
 type NATEndpointInfo struct {
@@ -72,10 +74,11 @@ func (rn *remoteEndpointNAT) toNATEndpointInfo() *NATEndpointInfo {
 
 func newRemoteEndpointNAT(endpoint *v1.Endpoint) *remoteEndpointNAT {
 	rnat := &remoteEndpointNAT{
-		endpoint:       *endpoint,
-		state:          testingPrivateAndPublicIPs,
-		started:        time.Now(),
-		lastTransition: time.Now(),
+		endpoint:        *endpoint,
+		state:           testingPrivateAndPublicIPs,
+		started:         time.Now(),
+		lastTransition:  time.Now(),
+		protocolVersion: negotiateProtocolVersion(&endpoint.Spec),
 	}
 
 	// Due to a network load balancer issue in the AWS implementation https://github.com/submariner-io/submariner/issues/1410
@@ -208,6 +211,17 @@ func (rn *remoteEndpointNAT) transitionToPrivateIP(remoteEndpointID string, useN
 	return false
 }
 
+// negotiateProtocolVersion returns the highest NAT discovery protocol version supported by both the local and the given
+// remote endpoint.
+func negotiateProtocolVersion(remote *v1.EndpointSpec) int32 {
+	version := remote.GetCapabilities().NATDiscoveryVersion
+	if version > natproto.Version {
+		version = natproto.Version
+	}
+
+	return version
+}
+
 func toDuration(v *int64) time.Duration {
 	return time.Duration(atomic.LoadInt64(v))
 }
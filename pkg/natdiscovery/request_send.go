@@ -98,7 +98,7 @@ func (nd *natDiscovery) sendCheckRequestToTargetIP(remoteNAT *remoteEndpointNAT,
 	}
 
 	message := natproto.SubmarinerNATDiscoveryMessage{
-		Version: natproto.Version,
+		Version: remoteNAT.protocolVersion,
 		Message: msgRequest,
 	}
 
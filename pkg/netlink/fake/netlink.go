@@ -221,6 +221,12 @@ func (n *basicType) RouteAdd(route *netlink.Route) error {
 	n.mutex.Lock()
 	defer n.mutex.Unlock()
 
+	for i := range n.routes[route.LinkIndex] {
+		if reflect.DeepEqual(n.routes[route.LinkIndex][i], *route) {
+			return os.ErrExist
+		}
+	}
+
 	n.routes[route.LinkIndex] = append(n.routes[route.LinkIndex], *route)
 
 	return nil
@@ -242,6 +248,24 @@ func (n *basicType) RouteDel(route *netlink.Route) error {
 	return nil
 }
 
+func (n *basicType) RouteReplace(route *netlink.Route) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	routes := n.routes[route.LinkIndex]
+
+	for i := range routes {
+		if reflect.DeepEqual(routes[i].Dst, route.Dst) && routes[i].Table == route.Table {
+			routes[i] = *route
+			return nil
+		}
+	}
+
+	n.routes[route.LinkIndex] = append(routes, *route)
+
+	return nil
+}
+
 func (n *basicType) FlushRouteTable(table int) error {
 	n.mutex.Lock()
 	defer n.mutex.Unlock()
@@ -288,6 +312,23 @@ func (n *basicType) RouteList(link netlink.Link, _ int) ([]netlink.Route, error)
 	return to, nil
 }
 
+func (n *basicType) RouteListTable(tableID, _ int) ([]netlink.Route, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	to := []netlink.Route{}
+
+	for _, routes := range n.routes {
+		for i := range routes {
+			if routes[i].Table == tableID {
+				to = append(to, routes[i])
+			}
+		}
+	}
+
+	return to, nil
+}
+
 //nolint:gocritic // Ignore hugeParam.
 func ruleKey(r netlink.Rule) string {
 	k := ""
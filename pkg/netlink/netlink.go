@@ -48,8 +48,10 @@ type Basic interface {
 	NeighDel(neigh *netlink.Neigh) error
 	RouteAdd(route *netlink.Route) error
 	RouteDel(route *netlink.Route) error
+	RouteReplace(route *netlink.Route) error
 	RouteGet(destination net.IP) ([]netlink.Route, error)
 	RouteList(link netlink.Link, family int) ([]netlink.Route, error)
+	RouteListTable(tableID, family int) ([]netlink.Route, error)
 	FlushRouteTable(tableID int) error
 	RuleAdd(rule *netlink.Rule) error
 	RuleDel(rule *netlink.Rule) error
@@ -140,6 +142,10 @@ func (n *netlinkType) RouteDel(route *netlink.Route) error {
 	return netlink.RouteDel(route)
 }
 
+func (n *netlinkType) RouteReplace(route *netlink.Route) error {
+	return netlink.RouteReplace(route)
+}
+
 func (n *netlinkType) RouteGet(destination net.IP) ([]netlink.Route, error) {
 	return netlink.RouteGet(destination)
 }
@@ -148,6 +154,10 @@ func (n *netlinkType) RouteList(link netlink.Link, family int) ([]netlink.Route,
 	return netlink.RouteList(link, family)
 }
 
+func (n *netlinkType) RouteListTable(tableID, family int) ([]netlink.Route, error) {
+	return netlink.RouteListFiltered(family, &netlink.Route{Table: tableID}, netlink.RT_FILTER_TABLE)
+}
+
 func (n *netlinkType) RuleAdd(rule *netlink.Rule) error {
 	return netlink.RuleAdd(rule)
 }
@@ -0,0 +1,402 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/google/nftables"
+	. "github.com/onsi/gomega"
+	nftablesAPI "github.com/submariner-io/submariner/pkg/nftables"
+)
+
+type setType struct {
+	set      *nftables.Set
+	elements []nftables.SetElement
+}
+
+type NFTables struct {
+	mutex      sync.Mutex
+	tables     map[string]*nftables.Table
+	chains     map[string]*nftables.Chain
+	rules      map[string][]*nftables.Rule
+	sets       map[string]*setType
+	nextHandle uint64
+}
+
+var _ nftablesAPI.Interface = &NFTables{}
+
+func New() *NFTables {
+	return &NFTables{
+		tables: map[string]*nftables.Table{},
+		chains: map[string]*nftables.Chain{},
+		rules:  map[string][]*nftables.Rule{},
+		sets:   map[string]*setType{},
+	}
+}
+
+func (n *NFTables) AddTable(t *nftables.Table) *nftables.Table {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if _, exists := n.tables[t.Name]; !exists {
+		n.tables[t.Name] = t
+	}
+
+	return t
+}
+
+func (n *NFTables) DelTable(t *nftables.Table) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	delete(n.tables, t.Name)
+
+	for key, c := range n.chains {
+		if c.Table.Name == t.Name {
+			delete(n.chains, key)
+			delete(n.rules, key)
+		}
+	}
+
+	for key, s := range n.sets {
+		if s.set.Table.Name == t.Name {
+			delete(n.sets, key)
+		}
+	}
+}
+
+func (n *NFTables) ListTables() ([]*nftables.Table, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	tables := make([]*nftables.Table, 0, len(n.tables))
+	for _, t := range n.tables {
+		tables = append(tables, t)
+	}
+
+	return tables, nil
+}
+
+func (n *NFTables) AddChain(c *nftables.Chain) *nftables.Chain {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	key := c.Table.Name + "/" + c.Name
+	if _, exists := n.chains[key]; !exists {
+		n.chains[key] = c
+	}
+
+	return c
+}
+
+func (n *NFTables) DelChain(c *nftables.Chain) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	key := c.Table.Name + "/" + c.Name
+	delete(n.chains, key)
+	delete(n.rules, key)
+}
+
+func (n *NFTables) FlushChain(c *nftables.Chain) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	delete(n.rules, c.Table.Name+"/"+c.Name)
+}
+
+func (n *NFTables) ListChains() ([]*nftables.Chain, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	chains := make([]*nftables.Chain, 0, len(n.chains))
+	for _, c := range n.chains {
+		chains = append(chains, c)
+	}
+
+	return chains, nil
+}
+
+func (n *NFTables) AddRule(r *nftables.Rule) *nftables.Rule {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.nextHandle++
+
+	rule := *r
+	rule.Handle = n.nextHandle
+
+	key := r.Table.Name + "/" + r.Chain.Name
+	n.rules[key] = append(n.rules[key], &rule)
+
+	return &rule
+}
+
+func (n *NFTables) DelRule(r *nftables.Rule) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	key := r.Table.Name + "/" + r.Chain.Name
+	rules := n.rules[key]
+
+	for i := range rules {
+		if rules[i].Handle == r.Handle {
+			n.rules[key] = append(rules[:i:i], rules[i+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("rule with handle %d not found in chain %q", r.Handle, key)
+}
+
+func (n *NFTables) GetRules(t *nftables.Table, c *nftables.Chain) ([]*nftables.Rule, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	key := t.Name + "/" + c.Name
+	if _, exists := n.chains[key]; !exists {
+		return nil, fmt.Errorf("chain %q does not exist", key)
+	}
+
+	rules := make([]*nftables.Rule, len(n.rules[key]))
+	for i := range n.rules[key] {
+		rule := *n.rules[key][i]
+		rules[i] = &rule
+	}
+
+	return rules, nil
+}
+
+func (n *NFTables) AddSet(s *nftables.Set, vals []nftables.SetElement) error {
+	n.mutex.Lock()
+
+	key := s.Table.Name + "/" + s.Name
+	if _, exists := n.sets[key]; !exists {
+		n.sets[key] = &setType{set: s}
+	}
+
+	n.mutex.Unlock()
+
+	return n.SetAddElements(s, vals)
+}
+
+func (n *NFTables) DelSet(s *nftables.Set) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	delete(n.sets, s.Table.Name+"/"+s.Name)
+}
+
+func (n *NFTables) FlushSet(s *nftables.Set) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if set, exists := n.sets[s.Table.Name+"/"+s.Name]; exists {
+		set.elements = nil
+	}
+}
+
+func (n *NFTables) GetSets(t *nftables.Table) ([]*nftables.Set, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	var sets []*nftables.Set
+
+	for _, s := range n.sets {
+		if s.set.Table.Name == t.Name {
+			sets = append(sets, s.set)
+		}
+	}
+
+	return sets, nil
+}
+
+func (n *NFTables) GetSetByName(t *nftables.Table, name string) (*nftables.Set, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	s, exists := n.sets[t.Name+"/"+name]
+	if !exists {
+		return nil, fmt.Errorf("set %q does not exist", name)
+	}
+
+	return s.set, nil
+}
+
+func (n *NFTables) GetSetElements(s *nftables.Set) ([]nftables.SetElement, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	set, exists := n.sets[s.Table.Name+"/"+s.Name]
+	if !exists {
+		return nil, fmt.Errorf("set %q does not exist", s.Name)
+	}
+
+	return append([]nftables.SetElement{}, set.elements...), nil
+}
+
+func (n *NFTables) SetAddElements(s *nftables.Set, vals []nftables.SetElement) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	set, exists := n.sets[s.Table.Name+"/"+s.Name]
+	if !exists {
+		return fmt.Errorf("set %q does not exist", s.Name)
+	}
+
+	for _, val := range vals {
+		if indexOfElement(set.elements, val.Key) < 0 {
+			set.elements = append(set.elements, val)
+		}
+	}
+
+	return nil
+}
+
+func (n *NFTables) SetDeleteElements(s *nftables.Set, vals []nftables.SetElement) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	set, exists := n.sets[s.Table.Name+"/"+s.Name]
+	if !exists {
+		return fmt.Errorf("set %q does not exist", s.Name)
+	}
+
+	for _, val := range vals {
+		i := indexOfElement(set.elements, val.Key)
+		if i < 0 {
+			return fmt.Errorf("element %v does not exist in set %q", val.Key, s.Name)
+		}
+
+		set.elements = append(set.elements[:i:i], set.elements[i+1:]...)
+	}
+
+	return nil
+}
+
+func (n *NFTables) Flush() error {
+	return nil
+}
+
+func (n *NFTables) AwaitChain(table, chain string) {
+	Eventually(func() bool {
+		return n.hasChain(table, chain)
+	}, 5).Should(BeTrue(), "nftables chain %q in table %q", chain, table)
+}
+
+func (n *NFTables) AwaitNoChain(table, chain string) {
+	Eventually(func() bool {
+		return n.hasChain(table, chain)
+	}, 5).Should(BeFalse(), "nftables chain %q in table %q", chain, table)
+}
+
+// AwaitRule waits for a rule whose comment matches stringOrMatcher.
+func (n *NFTables) AwaitRule(table, chain string, stringOrMatcher interface{}) {
+	Eventually(func() []string {
+		return n.ruleComments(table, chain)
+	}, 5).Should(ContainElement(stringOrMatcher), "Rules for nftables table %q, chain %q", table, chain)
+}
+
+// AwaitNoRule waits until there's no rule whose comment matches stringOrMatcher.
+func (n *NFTables) AwaitNoRule(table, chain string, stringOrMatcher interface{}) {
+	Eventually(func() []string {
+		return n.ruleComments(table, chain)
+	}, 5).ShouldNot(ContainElement(stringOrMatcher), "Rules for nftables table %q, chain %q", table, chain)
+}
+
+// AwaitMapElement waits for the IPv4 key in the named map to be mapped to the IPv4 value.
+func (n *NFTables) AwaitMapElement(table, set, key, value string) {
+	Eventually(func() map[string]string {
+		return n.elements(table, set)
+	}, 5).Should(HaveKeyWithValue(key, value), "Elements for nftables map %q", set)
+}
+
+func (n *NFTables) AwaitNoMapElement(table, set, key string) {
+	Eventually(func() map[string]string {
+		return n.elements(table, set)
+	}, 5).ShouldNot(HaveKey(key), "Elements for nftables map %q", set)
+}
+
+func (n *NFTables) hasChain(table, chain string) bool {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	_, exists := n.chains[table+"/"+chain]
+
+	return exists
+}
+
+func (n *NFTables) ruleComments(table, chain string) []string {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	comments := make([]string, 0, len(n.rules[table+"/"+chain]))
+	for _, r := range n.rules[table+"/"+chain] {
+		comments = append(comments, nftablesAPI.CommentFrom(r.UserData))
+	}
+
+	return comments
+}
+
+func (n *NFTables) elements(table, set string) map[string]string {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	elements := map[string]string{}
+
+	s, exists := n.sets[table+"/"+set]
+	if !exists {
+		return elements
+	}
+
+	for _, e := range s.elements {
+		value := ""
+
+		switch len(e.Val) {
+		case net.IPv4len:
+			value = net.IP(e.Val).String()
+		case 2 * net.IPv4len:
+			// A "<first> . <last>" range.
+			first, last := net.IP(e.Val[:net.IPv4len]), net.IP(e.Val[net.IPv4len:])
+
+			value = first.String()
+			if !first.Equal(last) {
+				value += "-" + last.String()
+			}
+		}
+
+		elements[net.IP(e.Key).String()] = value
+	}
+
+	return elements
+}
+
+func indexOfElement(elements []nftables.SetElement, key []byte) int {
+	for i := range elements {
+		if bytes.Equal(elements[i].Key, key) {
+			return i
+		}
+	}
+
+	return -1
+}
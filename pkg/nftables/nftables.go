@@ -0,0 +1,96 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nftables
+
+import (
+	"github.com/google/nftables"
+	"github.com/pkg/errors"
+)
+
+// Interface is the subset of the nftables netlink connection used by Submariner. Operations are batched until Flush is called.
+type Interface interface {
+	AddTable(t *nftables.Table) *nftables.Table
+	DelTable(t *nftables.Table)
+	ListTables() ([]*nftables.Table, error)
+	AddChain(c *nftables.Chain) *nftables.Chain
+	DelChain(c *nftables.Chain)
+	FlushChain(c *nftables.Chain)
+	ListChains() ([]*nftables.Chain, error)
+	AddRule(r *nftables.Rule) *nftables.Rule
+	DelRule(r *nftables.Rule) error
+	GetRules(t *nftables.Table, c *nftables.Chain) ([]*nftables.Rule, error)
+	AddSet(s *nftables.Set, vals []nftables.SetElement) error
+	DelSet(s *nftables.Set)
+	FlushSet(s *nftables.Set)
+	GetSets(t *nftables.Table) ([]*nftables.Set, error)
+	GetSetByName(t *nftables.Table, name string) (*nftables.Set, error)
+	GetSetElements(s *nftables.Set) ([]nftables.SetElement, error)
+	SetAddElements(s *nftables.Set, vals []nftables.SetElement) error
+	SetDeleteElements(s *nftables.Set, vals []nftables.SetElement) error
+	Flush() error
+}
+
+var NewFunc func() (Interface, error)
+
+func New() (Interface, error) {
+	if NewFunc != nil {
+		return NewFunc()
+	}
+
+	conn, err := nftables.New()
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the nftables connection")
+	}
+
+	return conn, nil
+}
+
+// The user data type used by the nft tool to store rule comments.
+const userDataTypeComment = 0
+
+// Comment encodes the given string as rule user data in the format used by the nft tool, so it's displayed as the
+// rule's comment when listing the ruleset.
+func Comment(comment string) []byte {
+	value := append([]byte(comment), 0)
+
+	return append([]byte{userDataTypeComment, byte(len(value))}, value...)
+}
+
+// CommentFrom decodes the comment from rule user data encoded by Comment. An empty string is returned if there's no comment.
+func CommentFrom(userData []byte) string {
+	for len(userData) >= 2 {
+		dataType, length := userData[0], int(userData[1])
+		if len(userData) < 2+length {
+			break
+		}
+
+		if dataType == userDataTypeComment {
+			value := userData[2 : 2+length]
+			if n := len(value); n > 0 && value[n-1] == 0 {
+				value = value[:n-1]
+			}
+
+			return string(value)
+		}
+
+		userData = userData[2+length:]
+	}
+
+	return ""
+}
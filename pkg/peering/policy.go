@@ -0,0 +1,76 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package peering
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/set"
+)
+
+// Policy restricts the remote clusters the local cluster connects to. A remote cluster is allowed if it's not in the
+// denied list and, when set, it's in the allowed list and its Cluster labels match the selector. An empty Policy
+// allows all clusters, resulting in a full mesh.
+type Policy struct {
+	allowedClusters set.Set[string]
+	deniedClusters  set.Set[string]
+	clusterSelector labels.Selector
+}
+
+// NewPolicy creates a Policy from the given allowed and denied cluster IDs and Cluster label selector.
+func NewPolicy(allowedClusters, deniedClusters []string, clusterSelector string) (*Policy, error) {
+	selector, err := labels.Parse(clusterSelector)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing the peering cluster selector %q", clusterSelector)
+	}
+
+	return &Policy{
+		allowedClusters: set.New(allowedClusters...),
+		deniedClusters:  set.New(deniedClusters...),
+		clusterSelector: selector,
+	}, nil
+}
+
+// AllowsClusterID returns whether the cluster with the given ID is allowed by the allowed and denied cluster lists.
+// The cluster selector isn't evaluated.
+func (p *Policy) AllowsClusterID(clusterID string) bool {
+	if p == nil {
+		return true
+	}
+
+	if p.deniedClusters.Has(clusterID) {
+		return false
+	}
+
+	return p.allowedClusters.Len() == 0 || p.allowedClusters.Has(clusterID)
+}
+
+// Allows returns whether the cluster with the given ID and Cluster labels is allowed.
+func (p *Policy) Allows(clusterID string, clusterLabels map[string]string) bool {
+	if !p.AllowsClusterID(clusterID) {
+		return false
+	}
+
+	return p == nil || p.clusterSelector.Matches(labels.Set(clusterLabels))
+}
+
+// HasClusterSelector returns whether the policy selects the allowed clusters by their Cluster labels.
+func (p *Policy) HasClusterSelector() bool {
+	return p != nil && !p.clusterSelector.Empty()
+}
@@ -44,6 +44,10 @@ const (
 	// [#] interface on the node that has an IPAddress from the clusterCIDR.
 	CNIInterfaceIP = "submariner.io/cniIfaceIp"
 
+	// The route agent annotates its node with a comma-separated list of the event handlers that have failed to
+	// process events. The annotation is removed once all handlers are healthy.
+	UnhealthyHandlers = "submariner.io/route-agent-unhealthy-handlers"
+
 	RouteAgentInterClusterNetworkTableID = 149
 
 	// To support connectivity for Pods with HostNetworking on the GatewayNode, we program
@@ -51,6 +55,11 @@ const (
 	// the egress traffic to the corresponding CNIInterfaceIP on that host.
 	RouteAgentHostNetworkTableID = 150
 
+	// Remote traffic selected by a RoutingPolicy is routed using table 151. The rules pointing at it are given a
+	// higher precedence than the rules for the tables above so that the policy wins over the default data path.
+	RouteAgentRoutingPolicyTableID      = 151
+	RouteAgentRoutingPolicyRulePriority = 140
+
 	NATTable    = "nat"
 	FilterTable = "filter"
 
@@ -0,0 +1,116 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"strings"
+
+	"github.com/submariner-io/submariner/pkg/ipset"
+)
+
+// ipSet records ipset mutations that would change the current sets.
+type ipSet struct {
+	ipset.Interface
+	recorder *Recorder
+}
+
+func (i *ipSet) record(operation string, args ...interface{}) {
+	i.recorder.Record(SubsystemIPSet, operation, args...)
+}
+
+func (i *ipSet) hasSet(name string) bool {
+	sets, err := i.ListSets()
+	if err != nil {
+		return false
+	}
+
+	for _, s := range sets {
+		if s == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (i *ipSet) hasEntry(entry, set string) bool {
+	exists, err := i.TestEntry(entry, set)
+	return err == nil && exists
+}
+
+func (i *ipSet) FlushSet(set string) error {
+	if entries, err := i.ListEntries(set); err != nil || len(entries) > 0 {
+		i.record("flush", set)
+	}
+
+	return nil
+}
+
+func (i *ipSet) DestroySet(set string) error {
+	if i.hasSet(set) {
+		i.record("destroy", set)
+	}
+
+	return nil
+}
+
+func (i *ipSet) DestroyAllSets() error {
+	i.record("destroy all sets")
+	return nil
+}
+
+func (i *ipSet) CreateSet(set *ipset.IPSet, _ bool) error {
+	if !i.hasSet(set.Name) {
+		i.record("create", set.Name, set.SetType, set.HashFamily)
+	}
+
+	return nil
+}
+
+func (i *ipSet) AddEntry(entry string, set *ipset.IPSet, _ bool) error {
+	if !i.hasEntry(entry, set.Name) {
+		i.record("add", set.Name, entry)
+	}
+
+	return nil
+}
+
+func (i *ipSet) DelEntry(entry, set string) error {
+	if i.hasEntry(entry, set) {
+		i.record("delete", set, entry)
+	}
+
+	return nil
+}
+
+func (i *ipSet) AddEntryWithOptions(entry *ipset.Entry, set *ipset.IPSet, _ bool) error {
+	if !i.hasEntry(entry.String(), set.Name) {
+		i.record("add", set.Name, entry.String())
+	}
+
+	return nil
+}
+
+func (i *ipSet) DelEntryWithOptions(set, entry string, options ...string) error {
+	if i.hasEntry(entry, set) {
+		i.record("delete", set, entry, strings.Join(options, " "))
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"strings"
+
+	"github.com/submariner-io/submariner/pkg/iptables"
+)
+
+// ipTables records iptables mutations that would change the current rules. Rules that already exist are not
+// recorded as additions, nor are missing rules recorded as deletions.
+type ipTables struct {
+	iptables.Basic
+	recorder *Recorder
+}
+
+func (i *ipTables) record(operation, table, chain string, rulespec ...string) {
+	i.recorder.Record(SubsystemIPTables, operation, "-t", table, chain, strings.Join(rulespec, " "))
+}
+
+func (i *ipTables) Append(table, chain string, rulespec ...string) error {
+	i.record("append", table, chain, rulespec...)
+	return nil
+}
+
+func (i *ipTables) AppendUnique(table, chain string, rulespec ...string) error {
+	if exists, err := i.Exists(table, chain, rulespec...); err != nil || !exists {
+		i.record("append", table, chain, rulespec...)
+	}
+
+	return nil
+}
+
+func (i *ipTables) Insert(table, chain string, _ int, rulespec ...string) error {
+	if exists, err := i.Exists(table, chain, rulespec...); err != nil || !exists {
+		i.record("insert", table, chain, rulespec...)
+	}
+
+	return nil
+}
+
+func (i *ipTables) Delete(table, chain string, rulespec ...string) error {
+	if exists, err := i.Exists(table, chain, rulespec...); err != nil || exists {
+		i.record("delete", table, chain, rulespec...)
+	}
+
+	return nil
+}
+
+func (i *ipTables) NewChain(table, chain string) error {
+	if exists, err := i.ChainExists(table, chain); err != nil || !exists {
+		i.record("create chain", table, chain)
+	}
+
+	return nil
+}
+
+func (i *ipTables) ClearChain(table, chain string) error {
+	if rules, err := i.List(table, chain); err != nil || hasRules(rules) {
+		i.record("flush chain", table, chain)
+	}
+
+	return nil
+}
+
+func (i *ipTables) DeleteChain(table, chain string) error {
+	if exists, err := i.ChainExists(table, chain); err != nil || exists {
+		i.record("delete chain", table, chain)
+	}
+
+	return nil
+}
+
+// hasRules returns whether the output of List contains any rules, ignoring the chain and policy definitions.
+func hasRules(rules []string) bool {
+	for _, rule := range rules {
+		if !strings.HasPrefix(rule, "-N ") && !strings.HasPrefix(rule, "-P ") {
+			return true
+		}
+	}
+
+	return false
+}
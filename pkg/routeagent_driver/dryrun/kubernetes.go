@@ -0,0 +1,61 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"net/http"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+type kubeTransport struct {
+	delegate http.RoundTripper
+	recorder *Recorder
+}
+
+// WrapConfig returns a copy of the given rest.Config whose mutating requests are submitted as server-side dry runs and
+// recorded with the given Recorder. The API server validates and admits them and returns realistic responses but
+// nothing is persisted. Read requests are passed through unchanged.
+func WrapConfig(cfg *rest.Config, recorder *Recorder) *rest.Config {
+	wrapped := rest.CopyConfig(cfg)
+	wrapped.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &kubeTransport{delegate: rt, recorder: recorder}
+	})
+
+	return wrapped
+}
+
+func (t *kubeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return t.delegate.RoundTrip(req)
+	}
+
+	t.recorder.Record(SubsystemKubernetes, strings.ToLower(req.Method), req.URL.Path)
+
+	req = req.Clone(req.Context())
+	query := req.URL.Query()
+	query.Set("dryRun", metav1.DryRunAll)
+	req.URL.RawQuery = query.Encode()
+
+	return t.delegate.RoundTrip(req)
+}
@@ -0,0 +1,112 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"github.com/submariner-io/submariner/pkg/netlink"
+	vnetlink "github.com/vishvananda/netlink"
+)
+
+// netLink records netlink and sysctl mutations. Unlike iptables, the kernel state is not consulted to filter out
+// no-op changes since the routes and rules managed by the handlers live in tables that RouteList does not cover.
+type netLink struct {
+	netlink.Basic
+	recorder *Recorder
+}
+
+func (n *netLink) record(operation string, args ...interface{}) error {
+	n.recorder.Record(SubsystemNetlink, operation, args...)
+	return nil
+}
+
+func (n *netLink) LinkAdd(link vnetlink.Link) error {
+	return n.record("add link", link.Type(), link.Attrs().Name)
+}
+
+func (n *netLink) LinkDel(link vnetlink.Link) error {
+	return n.record("delete link", link.Type(), link.Attrs().Name)
+}
+
+func (n *netLink) LinkSetUp(link vnetlink.Link) error {
+	return n.record("set link up", link.Attrs().Name)
+}
+
+func (n *netLink) AddrAdd(link vnetlink.Link, addr *vnetlink.Addr) error {
+	return n.record("add address", addr.String(), "dev", link.Attrs().Name)
+}
+
+func (n *netLink) AddrDel(link vnetlink.Link, addr *vnetlink.Addr) error {
+	return n.record("delete address", addr.String(), "dev", link.Attrs().Name)
+}
+
+func (n *netLink) NeighAppend(neigh *vnetlink.Neigh) error {
+	return n.record("append neighbor", neigh.String())
+}
+
+func (n *netLink) NeighDel(neigh *vnetlink.Neigh) error {
+	return n.record("delete neighbor", neigh.String())
+}
+
+func (n *netLink) RouteAdd(route *vnetlink.Route) error {
+	return n.record("add route", route.String())
+}
+
+func (n *netLink) RouteDel(route *vnetlink.Route) error {
+	return n.record("delete route", route.String())
+}
+
+func (n *netLink) RouteReplace(route *vnetlink.Route) error {
+	return n.record("replace route", route.String())
+}
+
+func (n *netLink) FlushRouteTable(tableID int) error {
+	return n.record("flush route table", tableID)
+}
+
+func (n *netLink) RuleAdd(rule *vnetlink.Rule) error {
+	return n.record("add rule", rule.String())
+}
+
+func (n *netLink) RuleDel(rule *vnetlink.Rule) error {
+	return n.record("delete rule", rule.String())
+}
+
+func (n *netLink) XfrmPolicyAdd(policy *vnetlink.XfrmPolicy) error {
+	return n.record("add xfrm policy", policy.String())
+}
+
+func (n *netLink) XfrmPolicyDel(policy *vnetlink.XfrmPolicy) error {
+	return n.record("delete xfrm policy", policy.String())
+}
+
+func (n *netLink) EnableLooseModeReversePathFilter(interfaceName string) error {
+	return n.record("enable loose mode reverse path filter", interfaceName)
+}
+
+func (n *netLink) EnsureLooseModeIsConfigured(interfaceName string) error {
+	return n.record("ensure loose mode reverse path filter", interfaceName)
+}
+
+func (n *netLink) EnableForwarding(interfaceName string) error {
+	return n.record("enable forwarding", interfaceName)
+}
+
+func (n *netLink) ConfigureTCPMTUProbe(mtuProbe, baseMss string) error {
+	return n.record("configure TCP MTU probing", mtuProbe, baseMss)
+}
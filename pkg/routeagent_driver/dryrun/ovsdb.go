@@ -0,0 +1,76 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"context"
+
+	libovsdbclient "github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+)
+
+// ovsdbClient records the OVSDB transactions, e.g. the changes to the OVN northbound database, instead of committing them.
+// Reads, which are served from the client's cache, are passed through.
+type ovsdbClient struct {
+	libovsdbclient.Client
+	recorder *Recorder
+}
+
+// NewOVSDBClientFunc returns a function that creates OVSDB clients whose transactions are recorded with the given Recorder
+// instead of being committed.
+func NewOVSDBClientFunc(recorder *Recorder) func(model.ClientDBModel, ...libovsdbclient.Option) (libovsdbclient.Client, error) {
+	return func(dbModel model.ClientDBModel, opts ...libovsdbclient.Option) (libovsdbclient.Client, error) {
+		client, err := libovsdbclient.NewOVSDBClient(dbModel, opts...)
+		if err != nil {
+			return nil, err //nolint:wrapcheck  // Let the caller wrap it
+		}
+
+		return &ovsdbClient{Client: client, recorder: recorder}, nil
+	}
+}
+
+func (c *ovsdbClient) Transact(_ context.Context, ops ...ovsdb.Operation) ([]ovsdb.OperationResult, error) {
+	for i := range ops {
+		if ops[i].Op == ovsdb.OperationSelect || ops[i].Op == ovsdb.OperationWait {
+			continue
+		}
+
+		// The named UUIDs of the inserted rows are generated so they're not recorded, otherwise the same insert would be
+		// recorded on every reconciliation.
+		args := []interface{}{ops[i].Table}
+
+		if len(ops[i].Where) > 0 {
+			args = append(args, "where", ops[i].Where)
+		}
+
+		if len(ops[i].Row) > 0 {
+			args = append(args, "row", ops[i].Row)
+		}
+
+		if len(ops[i].Mutations) > 0 {
+			args = append(args, "mutations", ops[i].Mutations)
+		}
+
+		c.recorder.Record(SubsystemOVSDB, ops[i].Op, args...)
+	}
+
+	// The results are empty, as if the operations succeeded without inserting or updating any rows.
+	return make([]ovsdb.OperationResult, len(ops)), nil
+}
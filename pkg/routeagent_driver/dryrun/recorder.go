@@ -0,0 +1,164 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dryrun provides implementations of the netlink, iptables and ipset adapter interfaces, and of the OVSDB client,
+// that record the mutations the route agent handlers intend to make instead of applying them. Read operations are passed through to
+// the real data path so handlers observe the actual node state and the recorded changes form a diff against it.
+// Kubernetes API writes are likewise recorded and submitted as server-side dry runs via WrapConfig.
+package dryrun
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/submariner/pkg/ipset"
+	"github.com/submariner-io/submariner/pkg/iptables"
+	"github.com/submariner-io/submariner/pkg/netlink"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/handlers/ovn/vsctl"
+	utilexec "k8s.io/utils/exec"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	SubsystemNetlink    = "netlink"
+	SubsystemIPTables   = "iptables"
+	SubsystemIPSet      = "ipset"
+	SubsystemKubernetes = "kubernetes"
+	SubsystemOVSDB      = "ovsdb"
+	SubsystemOVS        = "ovs-vsctl"
+)
+
+// Change describes a single data path mutation that would have been applied.
+type Change struct {
+	Subsystem string    `json:"subsystem"`
+	Operation string    `json:"operation"`
+	Args      string    `json:"args"`
+	FirstSeen time.Time `json:"firstSeen"`
+}
+
+// Diff is the set of changes recorded on a node.
+type Diff struct {
+	Node    string   `json:"node"`
+	Changes []Change `json:"changes"`
+}
+
+// Recorder accumulates the intended changes. Identical changes are only recorded once so periodic reconciliation does
+// not inflate the diff.
+type Recorder struct {
+	mutex   sync.Mutex
+	node    string
+	changes []Change
+	seen    map[string]bool
+}
+
+var logger = log.Logger{Logger: logf.Log.WithName("DryRun")}
+
+func NewRecorder(node string) *Recorder {
+	return &Recorder{
+		node: node,
+		seen: map[string]bool{},
+	}
+}
+
+func (r *Recorder) Record(subsystem, operation string, args ...interface{}) {
+	argStrs := make([]string, len(args))
+	for i := range args {
+		argStrs[i] = fmt.Sprintf("%v", args[i])
+	}
+
+	change := Change{
+		Subsystem: subsystem,
+		Operation: operation,
+		Args:      strings.Join(argStrs, " "),
+	}
+
+	key := change.Subsystem + "/" + change.Operation + "/" + change.Args
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.seen[key] {
+		return
+	}
+
+	r.seen[key] = true
+	change.FirstSeen = time.Now()
+	r.changes = append(r.changes, change)
+
+	logger.Infof("Dry run - would %s %s: %s", change.Operation, change.Subsystem, change.Args)
+}
+
+// Diff returns a snapshot of the changes recorded so far.
+func (r *Recorder) Diff() Diff {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return Diff{
+		Node:    r.node,
+		Changes: append([]Change{}, r.changes...),
+	}
+}
+
+// ServeHTTP writes the recorded diff as JSON.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(r.Diff()); err != nil {
+		logger.Errorf(err, "Error encoding the dry run diff")
+	}
+}
+
+// Enable redirects the netlink, iptables and ipset adapters returned by their respective New functions to
+// implementations that record mutations with the given Recorder instead of applying them, and records the ovs-vsctl
+// commands that modify the Open vSwitch database instead of running them. It must be called before any handler is
+// created. The OVN northbound database writes are recorded by the clients returned by NewOVSDBClientFunc.
+func Enable(recorder *Recorder) error {
+	ipt, err := iptables.New()
+	if err != nil {
+		return errors.Wrap(err, "error creating the iptables interface")
+	}
+
+	nl := netlink.New()
+	ips := ipset.New(utilexec.New())
+
+	iptables.NewFunc = func() (iptables.Interface, error) {
+		return &iptables.Adapter{Basic: &ipTables{Basic: ipt, recorder: recorder}}, nil
+	}
+
+	netlink.NewFunc = func() netlink.Interface {
+		return &netlink.Adapter{Basic: &netLink{Basic: nl, recorder: recorder}}
+	}
+
+	ipset.NewFunc = func() ipset.Interface {
+		return &ipSet{Interface: ips, recorder: recorder}
+	}
+
+	vsctl.WriteHook = func(parameters ...string) {
+		recorder.Record(SubsystemOVS, "run", strings.Join(parameters, " "))
+	}
+
+	logger.Info("Dry run mode enabled - data path changes will be recorded but not applied")
+
+	return nil
+}
@@ -0,0 +1,252 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	"github.com/submariner-io/submariner/pkg/ipset"
+	fakeipset "github.com/submariner-io/submariner/pkg/ipset/fake"
+	"github.com/submariner-io/submariner/pkg/iptables"
+	fakeiptables "github.com/submariner-io/submariner/pkg/iptables/fake"
+	netlinkAPI "github.com/submariner-io/submariner/pkg/netlink"
+	fakenetlink "github.com/submariner-io/submariner/pkg/netlink/fake"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/dryrun"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/handlers/ovn/vsctl"
+	"github.com/vishvananda/netlink"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	utilexec "k8s.io/utils/exec"
+)
+
+var _ = Describe("Recorder", func() {
+	var (
+		recorder *dryrun.Recorder
+		ipt      *fakeiptables.IPTables
+		ipSet    *fakeipset.IPSet
+		netLink  *fakenetlink.NetLink
+	)
+
+	BeforeEach(func() {
+		ipt = fakeiptables.New()
+		iptables.NewFunc = func() (iptables.Interface, error) {
+			return ipt, nil
+		}
+
+		ipSet = fakeipset.New()
+		ipset.NewFunc = func() ipset.Interface {
+			return ipSet
+		}
+
+		netLink = fakenetlink.New()
+		netlinkAPI.NewFunc = func() netlinkAPI.Interface {
+			return netLink
+		}
+
+		recorder = dryrun.NewRecorder("node1")
+		Expect(dryrun.Enable(recorder)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		iptables.NewFunc = nil
+		ipset.NewFunc = nil
+		netlinkAPI.NewFunc = nil
+		vsctl.WriteHook = nil
+	})
+
+	changesFor := func(subsystem string) []dryrun.Change {
+		changes := []dryrun.Change{}
+
+		for _, c := range recorder.Diff().Changes {
+			if c.Subsystem == subsystem {
+				changes = append(changes, c)
+			}
+		}
+
+		return changes
+	}
+
+	When("iptables rules are added and deleted", func() {
+		It("should record only the changes to the current rules without applying them", func() {
+			Expect(ipt.AppendUnique("nat", "CHAIN", "-s", "10.0.0.0/24", "-j", "ACCEPT")).To(Succeed())
+
+			dryRunIPT, err := iptables.New()
+			Expect(err).To(Succeed())
+
+			Expect(dryRunIPT.AppendUnique("nat", "CHAIN", "-s", "10.0.0.0/24", "-j", "ACCEPT")).To(Succeed())
+			Expect(dryRunIPT.AppendUnique("nat", "CHAIN", "-s", "10.1.0.0/24", "-j", "ACCEPT")).To(Succeed())
+			Expect(dryRunIPT.AppendUnique("nat", "CHAIN", "-s", "10.1.0.0/24", "-j", "ACCEPT")).To(Succeed())
+			Expect(dryRunIPT.Delete("nat", "CHAIN", "-s", "10.0.0.0/24", "-j", "ACCEPT")).To(Succeed())
+			Expect(dryRunIPT.Delete("nat", "CHAIN", "-s", "10.2.0.0/24", "-j", "ACCEPT")).To(Succeed())
+
+			changes := changesFor(dryrun.SubsystemIPTables)
+			Expect(changes).To(HaveLen(2))
+			Expect(changes[0].Operation).To(Equal("append"))
+			Expect(changes[0].Args).To(ContainSubstring("-s 10.1.0.0/24 -j ACCEPT"))
+			Expect(changes[1].Operation).To(Equal("delete"))
+			Expect(changes[1].Args).To(ContainSubstring("-s 10.0.0.0/24 -j ACCEPT"))
+
+			Expect(ipt.List("nat", "CHAIN")).To(Equal([]string{"-s 10.0.0.0/24 -j ACCEPT"}))
+		})
+	})
+
+	When("netlink routes are added", func() {
+		It("should record the changes without applying them", func() {
+			_, dst, _ := net.ParseCIDR("10.1.0.0/24")
+
+			nl := netlinkAPI.New()
+			Expect(nl.RouteAdd(&netlink.Route{Dst: dst, Table: 100, LinkIndex: 1})).To(Succeed())
+			Expect(nl.RuleAddIfNotPresent(netlinkAPI.NewTableRule(100))).To(Succeed())
+
+			changes := changesFor(dryrun.SubsystemNetlink)
+			Expect(changes).To(HaveLen(2))
+			Expect(changes[0].Operation).To(Equal("add route"))
+			Expect(changes[0].Args).To(ContainSubstring("10.1.0.0/24"))
+			Expect(changes[1].Operation).To(Equal("add rule"))
+
+			netLink.AwaitNoDstRoutes(1, 100, "10.1.0.0/24")
+			netLink.AwaitNoRule(100, "", "")
+		})
+	})
+
+	When("ipset entries are added", func() {
+		It("should record only the new entries without applying them", func() {
+			set := &ipset.IPSet{Name: "test-set", SetType: ipset.HashNet, HashFamily: ipset.ProtocolFamilyIPV4}
+			Expect(ipSet.CreateSet(set, true)).To(Succeed())
+			Expect(ipSet.AddEntry("10.0.0.0/24", set, true)).To(Succeed())
+
+			named := ipset.NewNamed(set, ipset.New(utilexec.New()))
+			Expect(named.Create(true)).To(Succeed())
+			Expect(named.AddEntry("10.0.0.0/24", true)).To(Succeed())
+			Expect(named.AddEntry("10.1.0.0/24", true)).To(Succeed())
+
+			changes := changesFor(dryrun.SubsystemIPSet)
+			Expect(changes).To(HaveLen(1))
+			Expect(changes[0].Operation).To(Equal("add"))
+			Expect(changes[0].Args).To(Equal("test-set 10.1.0.0/24"))
+
+			ipSet.AwaitNoEntry("test-set", "10.1.0.0/24")
+		})
+	})
+
+	When("Kubernetes API requests are made", func() {
+		var (
+			mutex    sync.Mutex
+			requests map[string]string
+			server   *httptest.Server
+		)
+
+		BeforeEach(func() {
+			requests = map[string]string{}
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mutex.Lock()
+				requests[r.Method] = r.URL.Query().Get("dryRun")
+				mutex.Unlock()
+
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}})
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should submit the writes as dry runs and record them", func() {
+			client := kubernetes.NewForConfigOrDie(dryrun.WrapConfig(&rest.Config{Host: server.URL}, recorder))
+
+			_, err := client.CoreV1().Nodes().Get(context.TODO(), "node1", metav1.GetOptions{})
+			Expect(err).To(Succeed())
+
+			_, err = client.CoreV1().Nodes().Update(context.TODO(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+				metav1.UpdateOptions{})
+			Expect(err).To(Succeed())
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			Expect(requests).To(HaveKeyWithValue(http.MethodGet, ""))
+			Expect(requests).To(HaveKeyWithValue(http.MethodPut, metav1.DryRunAll))
+
+			changes := changesFor(dryrun.SubsystemKubernetes)
+			Expect(changes).To(HaveLen(1))
+			Expect(changes[0].Operation).To(Equal("put"))
+			Expect(changes[0].Args).To(Equal("/api/v1/nodes/node1"))
+		})
+	})
+
+	When("OVN northbound database transactions are made", func() {
+		It("should record the writes without committing them", func() {
+			dbModel, err := nbdb.FullDatabaseModel()
+			Expect(err).To(Succeed())
+
+			client, err := dryrun.NewOVSDBClientFunc(recorder)(dbModel)
+			Expect(err).To(Succeed())
+
+			results, err := client.Transact(context.TODO(),
+				ovsdb.Operation{Op: ovsdb.OperationWait, Table: "Logical_Router"},
+				ovsdb.Operation{
+					Op: ovsdb.OperationInsert, Table: "Logical_Router_Static_Route", UUIDName: "u1",
+					Row: ovsdb.Row{"ip_prefix": "10.1.0.0/24"},
+				})
+			Expect(err).To(Succeed())
+			Expect(results).To(HaveLen(2))
+
+			changes := changesFor(dryrun.SubsystemOVSDB)
+			Expect(changes).To(HaveLen(1))
+			Expect(changes[0].Operation).To(Equal(ovsdb.OperationInsert))
+			Expect(changes[0].Args).To(ContainSubstring("10.1.0.0/24"))
+			Expect(changes[0].Args).ToNot(ContainSubstring("u1"))
+		})
+	})
+
+	When("ovs-vsctl commands are run", func() {
+		It("should record the writes without running them", func() {
+			Expect(vsctl.AddBridge("br-test")).To(Succeed())
+
+			changes := changesFor(dryrun.SubsystemOVS)
+			Expect(changes).To(HaveLen(1))
+			Expect(changes[0].Args).To(Equal("--may-exist add-br br-test"))
+		})
+	})
+
+	It("should serve the diff as JSON", func() {
+		recorder.Record(dryrun.SubsystemIPSet, "add", "test-set", "10.1.0.0/24")
+
+		w := httptest.NewRecorder()
+		recorder.ServeHTTP(w, httptest.NewRequest("GET", "/diff", nil))
+
+		diff := dryrun.Diff{}
+		Expect(json.Unmarshal(w.Body.Bytes(), &diff)).To(Succeed())
+		Expect(diff.Node).To(Equal("node1"))
+		Expect(diff.Changes).To(HaveLen(1))
+		Expect(diff.Changes[0].Args).To(Equal("test-set 10.1.0.0/24"))
+	})
+})
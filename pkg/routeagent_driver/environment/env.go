@@ -18,6 +18,8 @@ limitations under the License.
 
 package environment
 
+import "time"
+
 type Specification struct {
 	ClusterID   string
 	Namespace   string
@@ -26,4 +28,24 @@ type Specification struct {
 	GlobalCidr  []string
 	Uninstall   bool
 	WaitForNode bool
+	// ReconcileInterval specifies how often the event handlers reconcile the full data path state.
+	ReconcileInterval time.Duration `default:"2m"`
+	// IntraClusterRouting specifies how remote traffic is carried from non-gateway nodes to the gateway node:
+	// "vxlan", "direct" or "auto".
+	IntraClusterRouting string `default:"vxlan"`
+	// HealthReportInterval specifies how often the health of the event handlers is reported on the node.
+	HealthReportInterval time.Duration `default:"30s"`
+	// DryRun records the netlink, iptables, ipset, OVN northbound database and ovs-vsctl changes the handlers would make instead
+	// of applying them.
+	DryRun bool
+	// MetricsPort is the port on which the route agent serves its metrics.
+	MetricsPort string `default:"32782"`
+	// DebugPort is the port on which the route agent serves its state and dry run diff. It's only bound to the
+	// loopback interface since these endpoints expose the node's data path configuration without authentication.
+	DebugPort string `default:"32783"`
+	// CalicoBGPPeers specifies the IPs of the BGP fabric peers the gateway node advertises the remote subnets to, with
+	// Calico in BGP mode. The advertisement is disabled if empty.
+	CalicoBGPPeers []string
+	// CalicoBGPASNumber specifies the AS number of the BGP fabric peers.
+	CalicoBGPASNumber uint32
 }
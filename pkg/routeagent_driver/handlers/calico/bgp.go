@@ -0,0 +1,176 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package calico
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	calicoapi "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	"github.com/projectcalico/api/pkg/lib/numorstring"
+	"github.com/submariner-io/admiral/pkg/util"
+	"github.com/submariner-io/submariner/pkg/cidr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/set"
+)
+
+const (
+	SubmarinerBGP          = "submariner.io/bgp"
+	RemoteSubnetsBGPFilter = "submariner-remote-subnets"
+
+	// asTrans is the AS number reserved to represent 4-byte AS numbers to 2-byte speakers.
+	asTrans = 23456
+)
+
+// BGPConfig configures the advertisement of the remote subnets to the BGP fabric. The gateway node peers with the given
+// fabric peers and exports the remote subnets to them, so the fabric routes remote traffic via the gateway node.
+type BGPConfig struct {
+	PeerIPs  []string
+	ASNumber uint32
+}
+
+// Validate checks that the peer IPs and AS number are usable.
+func (c *BGPConfig) Validate() error {
+	switch c.ASNumber {
+	case 0, asTrans, 65535, 4294967295:
+		return fmt.Errorf("invalid BGP AS number %d", c.ASNumber)
+	}
+
+	for _, peerIP := range c.PeerIPs {
+		if net.ParseIP(peerIP) == nil {
+			return fmt.Errorf("invalid BGP peer IP %q", peerIP)
+		}
+	}
+
+	return nil
+}
+
+// advertiseRemoteSubnets maintains the BGPFilter exporting the remote subnets and the gateway node's BGPPeers it's
+// applied to. BGPPeers left by previous gateway nodes, for instance if they crashed, or for peers no longer configured
+// are deleted.
+func (h *calicoIPPoolHandler) advertiseRemoteSubnets() error {
+	if h.config.BGP == nil {
+		return nil
+	}
+
+	filter := &calicoapi.BGPFilter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   RemoteSubnetsBGPFilter,
+			Labels: map[string]string{SubmarinerBGP: "true"},
+		},
+	}
+
+	for _, subnet := range h.remoteSubnets() {
+		filter.Spec.ExportV4 = append(filter.Spec.ExportV4, calicoapi.BGPFilterRuleV4{
+			CIDR:          subnet,
+			MatchOperator: calicoapi.Equal,
+			Action:        calicoapi.Accept,
+		})
+	}
+
+	_, err := util.CreateOrUpdate(context.TODO(), h.resources.bgpFilters, filter, util.Replace(filter))
+	if err != nil {
+		return errors.Wrapf(err, "error creating or updating Calico BGPFilter %q", filter.Name)
+	}
+
+	desiredPeers := set.New[string]()
+
+	for _, peerIP := range h.config.BGP.PeerIPs {
+		peer := &calicoapi.BGPPeer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   getGatewayBGPPeerName(h.config.NodeName, peerIP),
+				Labels: map[string]string{SubmarinerBGP: "true"},
+			},
+			Spec: calicoapi.BGPPeerSpec{
+				Node:     h.config.NodeName,
+				PeerIP:   peerIP,
+				ASNumber: numorstring.ASNumber(h.config.BGP.ASNumber),
+				Filters:  []string{RemoteSubnetsBGPFilter},
+			},
+		}
+
+		_, err := util.CreateOrUpdate(context.TODO(), h.resources.bgpPeers, peer, util.Replace(peer))
+		if err != nil {
+			return errors.Wrapf(err, "error creating or updating Calico BGPPeer %q", peer.Name)
+		}
+
+		desiredPeers.Insert(peer.Name)
+	}
+
+	return h.deleteBGPPeers(func(peer *calicoapi.BGPPeer) bool {
+		return !desiredPeers.Has(peer.Name)
+	})
+}
+
+// withdrawRemoteSubnets deletes the local node's BGPPeers so it no longer advertises the remote subnets. The BGPFilter
+// is left for the new gateway node.
+func (h *calicoIPPoolHandler) withdrawRemoteSubnets() error {
+	if h.config.BGP == nil {
+		return nil
+	}
+
+	return h.deleteBGPPeers(func(peer *calicoapi.BGPPeer) bool {
+		return peer.Spec.Node == h.config.NodeName
+	})
+}
+
+// deleteBGPPeers deletes the BGPPeers created by Submariner that match the given predicate.
+func (h *calicoIPPoolHandler) deleteBGPPeers(matches func(peer *calicoapi.BGPPeer) bool) error {
+	peers, err := h.resources.bgpPeers.List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{SubmarinerBGP: "true"}).String(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "error listing Calico BGPPeers")
+	}
+
+	for _, peer := range peers {
+		if !matches(peer) {
+			continue
+		}
+
+		err := h.resources.bgpPeers.Delete(context.TODO(), peer.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "error deleting Calico BGPPeer %q", peer.Name)
+		}
+
+		logger.Infof("Successfully deleted Calico BGPPeer %q", peer.Name)
+	}
+
+	return nil
+}
+
+func (h *calicoIPPoolHandler) remoteSubnets() []string {
+	subnets := set.New[string]()
+
+	endpoints := h.State().GetRemoteEndpoints()
+	for i := range endpoints {
+		subnets.Insert(cidr.ExtractIPv4Subnets(endpoints[i].Spec.Subnets)...)
+	}
+
+	return subnets.SortedList()
+}
+
+func getGatewayBGPPeerName(nodeName, peerIP string) string {
+	return fmt.Sprintf("submariner-%s-%s", nodeName, strings.NewReplacer(".", "-", ":", "-").Replace(peerIP))
+}
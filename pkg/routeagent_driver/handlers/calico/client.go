@@ -0,0 +1,228 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package calico
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	calicoapi "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	calicocs "github.com/projectcalico/api/pkg/client/clientset_generated/clientset"
+	"github.com/submariner-io/admiral/pkg/resource"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+// CRDGroupVersion is the group version of the CRDs backing the Calico resources when the Calico API server isn't installed.
+var CRDGroupVersion = schema.GroupVersion{Group: "crd.projectcalico.org", Version: "v1"}
+
+var NewDynamicClient = func(restConfig *rest.Config) (dynamic.Interface, error) {
+	return dynamic.NewForConfig(restConfig) //nolint:wrapcheck // No need to wrap
+}
+
+// calicoResources provides access to the Calico resources managed by the handler, either through the projectcalico.org/v3
+// API server or, if it isn't installed, directly through the crd.projectcalico.org/v1 CRDs which share the same schema.
+type calicoResources struct {
+	ipPools    resource.Interface[*calicoapi.IPPool]
+	bgpFilters resource.Interface[*calicoapi.BGPFilter]
+	bgpPeers   resource.Interface[*calicoapi.BGPPeer]
+}
+
+func newCalicoResources(restConfig *rest.Config) (*calicoResources, error) {
+	client, err := NewClient(restConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "error initializing Calico clientset")
+	}
+
+	_, err = client.Discovery().ServerResourcesForGroupVersion(calicoapi.GroupVersionCurrent)
+	if err == nil {
+		logger.Infof("Using the Calico %s API server", calicoapi.GroupVersionCurrent)
+
+		return forAPIServer(client), nil
+	}
+
+	if !apierrors.IsNotFound(err) {
+		return nil, errors.Wrapf(err, "error discovering the Calico %s API", calicoapi.GroupVersionCurrent)
+	}
+
+	logger.Infof("The Calico API server isn't installed - using the %s CRDs", CRDGroupVersion)
+
+	dynClient, err := NewDynamicClient(restConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "error initializing dynamic client")
+	}
+
+	return forCRDs(dynClient), nil
+}
+
+func forAPIServer(client calicocs.Interface) *calicoResources {
+	return &calicoResources{
+		ipPools:    forTyped[*calicoapi.IPPool, *calicoapi.IPPoolList](client.ProjectcalicoV3().IPPools()),
+		bgpFilters: forTyped[*calicoapi.BGPFilter, *calicoapi.BGPFilterList](client.ProjectcalicoV3().BGPFilters()),
+		bgpPeers:   forTyped[*calicoapi.BGPPeer, *calicoapi.BGPPeerList](client.ProjectcalicoV3().BGPPeers()),
+	}
+}
+
+func forCRDs(client dynamic.Interface) *calicoResources {
+	return &calicoResources{
+		ipPools: forCRD(client.Resource(CRDGroupVersion.WithResource("ippools")), calicoapi.KindIPPool,
+			func() *calicoapi.IPPool {
+				return &calicoapi.IPPool{}
+			}),
+		bgpFilters: forCRD(client.Resource(CRDGroupVersion.WithResource("bgpfilters")), calicoapi.KindBGPFilter,
+			func() *calicoapi.BGPFilter {
+				return &calicoapi.BGPFilter{}
+			}),
+		bgpPeers: forCRD(client.Resource(CRDGroupVersion.WithResource("bgppeers")), calicoapi.KindBGPPeer,
+			func() *calicoapi.BGPPeer {
+				return &calicoapi.BGPPeer{}
+			}),
+	}
+}
+
+func forTyped[T runtime.Object, L runtime.Object](client resource.KubernetesInterface[T, L]) resource.Interface[T] {
+	return &resource.InterfaceFuncs[T]{
+		GetFunc:    client.Get,
+		CreateFunc: client.Create,
+		UpdateFunc: client.Update,
+		DeleteFunc: func(ctx context.Context, name string, options metav1.DeleteOptions) error {
+			return client.Delete(ctx, name, options)
+		},
+		ListFunc: func(ctx context.Context, options metav1.ListOptions) ([]T, error) {
+			list, err := client.List(ctx, options)
+			if err != nil {
+				return nil, err //nolint:wrapcheck  // Let the caller wrap it
+			}
+
+			return resource.MustExtractList[T](list), nil
+		},
+	}
+}
+
+//nolint:wrapcheck  // Let the caller wrap errors
+func forCRD[T runtime.Object](client dynamic.ResourceInterface, kind string, newObj func() T) resource.Interface[T] {
+	fromUnstructured := func(from *unstructured.Unstructured) (T, error) {
+		to := newObj()
+		err := runtime.DefaultUnstructuredConverter.FromUnstructured(from.Object, to)
+
+		return to, err
+	}
+
+	toUnstructured := func(from T) (*unstructured.Unstructured, error) {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(from)
+		if err != nil {
+			return nil, err
+		}
+
+		to := &unstructured.Unstructured{Object: obj}
+		to.SetAPIVersion(CRDGroupVersion.String())
+		to.SetKind(kind)
+
+		return to, nil
+	}
+
+	return &resource.InterfaceFuncs[T]{
+		GetFunc: func(ctx context.Context, name string, options metav1.GetOptions) (T, error) {
+			obj, err := client.Get(ctx, name, options)
+			if err != nil {
+				return *new(T), err
+			}
+
+			return fromUnstructured(obj)
+		},
+		CreateFunc: func(ctx context.Context, obj T, options metav1.CreateOptions) (T, error) {
+			u, err := toUnstructured(obj)
+			if err != nil {
+				return *new(T), err
+			}
+
+			u, err = client.Create(ctx, u, options)
+			if err != nil {
+				return *new(T), err
+			}
+
+			return fromUnstructured(u)
+		},
+		UpdateFunc: func(ctx context.Context, obj T, options metav1.UpdateOptions) (T, error) {
+			u, err := toUnstructured(obj)
+			if err != nil {
+				return *new(T), err
+			}
+
+			u, err = client.Update(ctx, u, options)
+			if err != nil {
+				return *new(T), err
+			}
+
+			return fromUnstructured(u)
+		},
+		DeleteFunc: func(ctx context.Context, name string, options metav1.DeleteOptions) error {
+			return client.Delete(ctx, name, options)
+		},
+		ListFunc: func(ctx context.Context, options metav1.ListOptions) ([]T, error) {
+			list, err := client.List(ctx, options)
+			if err != nil {
+				return nil, err
+			}
+
+			objs := make([]T, len(list.Items))
+
+			for i := range list.Items {
+				objs[i], err = fromUnstructured(&list.Items[i])
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			return objs, nil
+		},
+	}
+}
+
+// deleteLabeled deletes the resources with the given label set to "true".
+func deleteLabeled[T runtime.Object](client resource.Interface[T], label string) error {
+	objs, err := client.List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{label: "true"}).String(),
+	})
+	if apierrors.IsNotFound(err) {
+		// The resource type isn't supported by the installed Calico version.
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "error listing resources with label %q", label)
+	}
+
+	for _, obj := range objs {
+		name := resource.MustToMeta(obj).GetName()
+
+		err := client.Delete(context.TODO(), name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "error deleting %q", name)
+		}
+	}
+
+	return nil
+}
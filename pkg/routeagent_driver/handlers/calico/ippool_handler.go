@@ -33,7 +33,6 @@ import (
 	"github.com/submariner-io/submariner/pkg/event"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	errorutils "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/rest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -43,10 +42,17 @@ const (
 	SubmarinerIPPool = "submariner.io/ippool"
 )
 
+type HandlerConfig struct {
+	RestConfig *rest.Config
+	NodeName   string
+	// BGP enables advertising the remote subnets to the BGP fabric via the gateway node, if set.
+	BGP *BGPConfig
+}
+
 type calicoIPPoolHandler struct {
 	event.HandlerBase
-	restConfig *rest.Config
-	client     calicocs.Interface
+	config    *HandlerConfig
+	resources *calicoResources
 }
 
 var NewClient = func(restConfig *rest.Config) (calicocs.Interface, error) {
@@ -55,9 +61,9 @@ var NewClient = func(restConfig *rest.Config) (calicocs.Interface, error) {
 
 var logger = log.Logger{Logger: logf.Log.WithName("CalicoIPPool")}
 
-func NewCalicoIPPoolHandler(restConfig *rest.Config) event.Handler {
+func NewCalicoIPPoolHandler(config *HandlerConfig) event.Handler {
 	return &calicoIPPoolHandler{
-		restConfig: restConfig,
+		config: config,
 	}
 }
 
@@ -70,11 +76,17 @@ func (h *calicoIPPoolHandler) GetName() string {
 }
 
 func (h *calicoIPPoolHandler) Init() error {
+	if h.config.BGP != nil {
+		if err := h.config.BGP.Validate(); err != nil {
+			return err
+		}
+	}
+
 	var err error
 
-	h.client, err = NewClient(h.restConfig)
+	h.resources, err = newCalicoResources(h.config.RestConfig)
 
-	return errors.Wrap(err, "error initializing Calico clientset")
+	return err
 }
 
 func (h *calicoIPPoolHandler) RemoteEndpointCreated(endpoint *submV1.Endpoint) error {
@@ -83,7 +95,7 @@ func (h *calicoIPPoolHandler) RemoteEndpointCreated(endpoint *submV1.Endpoint) e
 		return nil
 	}
 
-	err := h.createIPPool(endpoint)
+	err := errorutils.NewAggregate([]error{h.createIPPool(endpoint), h.advertiseRemoteSubnets()})
 
 	return errors.Wrap(err, "failed to handle RemoteEndpointCreated event")
 }
@@ -94,7 +106,7 @@ func (h *calicoIPPoolHandler) RemoteEndpointRemoved(endpoint *submV1.Endpoint) e
 		return nil
 	}
 
-	err := h.deleteIPPool(endpoint)
+	err := errorutils.NewAggregate([]error{h.deleteIPPool(endpoint), h.advertiseRemoteSubnets()})
 
 	return errors.Wrap(err, "failed to handle RemoteEndpointRemoved event")
 }
@@ -113,21 +125,35 @@ func (h *calicoIPPoolHandler) TransitionToGateway() error {
 		}
 	}
 
+	if err := h.advertiseRemoteSubnets(); err != nil {
+		retErrors = append(retErrors, err)
+	}
+
 	return errorutils.NewAggregate(retErrors)
 }
 
+func (h *calicoIPPoolHandler) TransitionToNonGateway() error {
+	return h.withdrawRemoteSubnets()
+}
+
 func (h *calicoIPPoolHandler) Uninstall() error {
-	logger.Info("Uninstalling Calico IPPools used for Submariner")
+	logger.Info("Uninstalling Calico IPPools and BGP resources used for Submariner")
+
+	if err := deleteLabeled(h.resources.ipPools, SubmarinerIPPool); err != nil {
+		return errors.Wrap(err, "failed to delete Calico IPPools")
+	}
+
+	logger.Infof("Successfully deleted Calico IPPools with label %q", SubmarinerIPPool)
 
-	labelSelector := labels.SelectorFromSet(map[string]string{SubmarinerIPPool: "true"}).String()
-	err := h.client.ProjectcalicoV3().IPPools().DeleteCollection(context.TODO(), metav1.DeleteOptions{},
-		metav1.ListOptions{LabelSelector: labelSelector})
+	if err := deleteLabeled(h.resources.bgpPeers, SubmarinerBGP); err != nil {
+		return errors.Wrap(err, "failed to delete Calico BGPPeers")
+	}
 
-	if err != nil && !apierrors.IsNotFound(err) {
-		return errors.Wrapf(err, "Failed to delete Calico IPPools using labelSelector %q", labelSelector)
+	if err := deleteLabeled(h.resources.bgpFilters, SubmarinerBGP); err != nil {
+		return errors.Wrap(err, "failed to delete Calico BGPFilters")
 	}
 
-	logger.Infof("Successfully delete Calico IPPools using labelSelector %q", labelSelector)
+	logger.Infof("Successfully deleted Calico BGP resources with label %q", SubmarinerBGP)
 
 	return nil
 }
@@ -146,9 +172,11 @@ func (h *calicoIPPoolHandler) createIPPool(endpoint *submV1.Endpoint) error {
 				CIDR:        subnet,
 				NATOutgoing: false,
 				Disabled:    true,
+				// BGP export is left enabled so the remote subnets can be advertised, the BGPFilter applied to the
+				// gateway node's BGPPeers restricts what is exported to the fabric.
 			},
 		}
-		_, err := h.client.ProjectcalicoV3().IPPools().Create(context.TODO(), iPPoolObj, metav1.CreateOptions{})
+		_, err := h.resources.ipPools.Create(context.TODO(), iPPoolObj, metav1.CreateOptions{})
 
 		if err == nil {
 			logger.Infof("Successfully created Calico IPPool %q", iPPoolObj.GetName())
@@ -157,7 +185,7 @@ func (h *calicoIPPoolHandler) createIPPool(endpoint *submV1.Endpoint) error {
 
 		if !apierrors.IsAlreadyExists(err) {
 			retErrors = append(retErrors,
-				errors.Wrapf(err, "error creating Calico IPPool for ClusterID %q subnet %q (is Calico API server running?)",
+				errors.Wrapf(err, "error creating Calico IPPool for ClusterID %q subnet %q",
 					endpoint.Spec.ClusterID, subnet))
 		}
 	}
@@ -172,8 +200,7 @@ func (h *calicoIPPoolHandler) deleteIPPool(endpoint *submV1.Endpoint) error {
 	for _, subnet := range subnets {
 		poolName := getEndpointSubnetIPPoolName(endpoint, subnet)
 
-		err := h.client.ProjectcalicoV3().IPPools().Delete(context.TODO(),
-			poolName, metav1.DeleteOptions{})
+		err := h.resources.ipPools.Delete(context.TODO(), poolName, metav1.DeleteOptions{})
 
 		if err == nil {
 			logger.Infof("Successfully deleted Calico IPPool %q", poolName)
@@ -182,7 +209,7 @@ func (h *calicoIPPoolHandler) deleteIPPool(endpoint *submV1.Endpoint) error {
 
 		if !apierrors.IsNotFound(err) {
 			retErrors = append(retErrors,
-				errors.Wrapf(err, "error deleting Calico IPPool for ClusterID %q subnet %q (is Calico API server running?)",
+				errors.Wrapf(err, "error deleting Calico IPPool for ClusterID %q subnet %q",
 					endpoint.Spec.ClusterID, subnet))
 		}
 	}
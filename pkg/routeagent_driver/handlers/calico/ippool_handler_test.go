@@ -26,11 +26,17 @@ import (
 	calicoapi "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 	calicocs "github.com/projectcalico/api/pkg/client/clientset_generated/clientset"
 	calicocsfake "github.com/projectcalico/api/pkg/client/clientset_generated/clientset/fake"
+	"github.com/projectcalico/api/pkg/lib/numorstring"
 	"github.com/submariner-io/admiral/pkg/fake"
 	"github.com/submariner-io/submariner/pkg/event"
 	"github.com/submariner-io/submariner/pkg/event/testing"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/handlers/calico"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/rest"
 )
 
@@ -39,20 +45,24 @@ var _ = Describe("IPPool Handler", func() {
 
 	var (
 		calicoClient *calicocsfake.Clientset
+		config       *calico.HandlerConfig
 		handler      event.Handler
 	)
 
 	BeforeEach(func() {
 		calicoClient = calicocsfake.NewSimpleClientset()
+		calicoClient.Resources = []*metav1.APIResourceList{{GroupVersion: calicoapi.GroupVersionCurrent}}
 		fake.AddDeleteCollectionReactor(&calicoClient.Fake)
 
 		calico.NewClient = func(_ *rest.Config) (calicocs.Interface, error) {
 			return calicoClient, nil
 		}
+
+		config = &calico.HandlerConfig{NodeName: "gateway-node"}
 	})
 
 	JustBeforeEach(func() {
-		handler = calico.NewCalicoIPPoolHandler(nil)
+		handler = calico.NewCalicoIPPoolHandler(config)
 		t.Start(handler)
 	})
 
@@ -82,6 +92,138 @@ var _ = Describe("IPPool Handler", func() {
 		})
 	})
 
+	When("BGP advertisement is enabled", func() {
+		const peerIP = "172.16.0.1"
+
+		BeforeEach(func() {
+			config.BGP = &calico.BGPConfig{
+				PeerIPs:  []string{peerIP},
+				ASNumber: 64512,
+			}
+		})
+
+		It("should advertise the remote subnets via the gateway node", func() {
+			subnets1 := []string{"192.0.2.0/24", "192.0.3.0/24"}
+			t.CreateEndpoint(testing.NewEndpoint("remote-cluster1", "host", subnets1...))
+
+			localEP := t.CreateLocalHostEndpoint()
+			awaitIPPools(calicoClient, subnets1...)
+			awaitBGPFilterCIDRs(calicoClient, subnets1...)
+
+			peer := awaitBGPPeer(calicoClient, "submariner-gateway-node-172-16-0-1")
+			Expect(peer.Spec.Node).To(Equal(config.NodeName))
+			Expect(peer.Spec.PeerIP).To(Equal(peerIP))
+			Expect(peer.Spec.ASNumber).To(Equal(numorstring.ASNumber(64512)))
+			Expect(peer.Spec.Filters).To(Equal([]string{calico.RemoteSubnetsBGPFilter}))
+
+			pool, err := calicoClient.ProjectcalicoV3().IPPools().Get(context.Background(),
+				"submariner-remote-cluster1-192.0.2.0-24", metav1.GetOptions{})
+			Expect(err).To(Succeed())
+			Expect(pool.Spec.DisableBGPExport).To(BeFalse())
+
+			subnets2 := []string{"192.0.4.0/24"}
+			remoteEP2 := t.CreateEndpoint(testing.NewEndpoint("remote-cluster2", "host", subnets2...))
+			awaitBGPFilterCIDRs(calicoClient, append(subnets1, subnets2...)...)
+
+			t.DeleteEndpoint(remoteEP2.Name)
+			awaitBGPFilterCIDRs(calicoClient, subnets1...)
+
+			t.DeleteEndpoint(localEP.Name)
+			awaitNoBGPPeer(calicoClient, peer.Name)
+
+			Expect(handler.Uninstall()).To(Succeed())
+
+			_, err = calicoClient.ProjectcalicoV3().BGPFilters().Get(context.Background(), calico.RemoteSubnetsBGPFilter,
+				metav1.GetOptions{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	When("BGP advertisement is enabled and a previous gateway node left BGPPeers", func() {
+		BeforeEach(func() {
+			config.BGP = &calico.BGPConfig{
+				PeerIPs:  []string{"172.16.0.1"},
+				ASNumber: 64512,
+			}
+
+			_, err := calicoClient.ProjectcalicoV3().BGPPeers().Create(context.Background(), &calicoapi.BGPPeer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "submariner-crashed-node-172-16-0-1",
+					Labels: map[string]string{calico.SubmarinerBGP: "true"},
+				},
+				Spec: calicoapi.BGPPeerSpec{Node: "crashed-node", PeerIP: "172.16.0.1"},
+			}, metav1.CreateOptions{})
+			Expect(err).To(Succeed())
+		})
+
+		It("should delete them on transition to gateway", func() {
+			t.CreateLocalHostEndpoint()
+			awaitBGPPeer(calicoClient, "submariner-gateway-node-172-16-0-1")
+			awaitNoBGPPeer(calicoClient, "submariner-crashed-node-172-16-0-1")
+		})
+	})
+
+	When("the Calico API server isn't installed", func() {
+		var dynClient *dynamicfake.FakeDynamicClient
+
+		BeforeEach(func() {
+			calicoClient.Resources = nil
+
+			dynClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{
+					calico.CRDGroupVersion.WithResource("ippools"):    calicoapi.KindIPPoolList,
+					calico.CRDGroupVersion.WithResource("bgpfilters"): calicoapi.KindBGPFilterList,
+					calico.CRDGroupVersion.WithResource("bgppeers"):   calicoapi.KindBGPPeerList,
+				})
+
+			calico.NewDynamicClient = func(_ *rest.Config) (dynamic.Interface, error) {
+				return dynClient, nil
+			}
+		})
+
+		It("should manage the IPPools through the CRDs", func() {
+			ipPools := dynClient.Resource(calico.CRDGroupVersion.WithResource("ippools"))
+
+			remoteEP := t.CreateEndpoint(testing.NewEndpoint("remote-cluster1", "host", "192.0.2.0/24"))
+			t.CreateLocalHostEndpoint()
+
+			poolName := "submariner-remote-cluster1-192.0.2.0-24"
+
+			Eventually(func() error {
+				_, err := ipPools.Get(context.Background(), poolName, metav1.GetOptions{})
+				return err
+			}).Should(Succeed())
+
+			pool, err := ipPools.Get(context.Background(), poolName, metav1.GetOptions{})
+			Expect(err).To(Succeed())
+			Expect(pool.GetAPIVersion()).To(Equal(calico.CRDGroupVersion.String()))
+			Expect(pool.GetKind()).To(Equal(calicoapi.KindIPPool))
+			Expect(pool.GetLabels()).To(HaveKeyWithValue(calico.SubmarinerIPPool, "true"))
+			Expect(pool.Object).To(HaveKeyWithValue("spec", And(HaveKeyWithValue("cidr", "192.0.2.0/24"),
+				HaveKeyWithValue("disabled", true))))
+
+			t.DeleteEndpoint(remoteEP.Name)
+
+			Eventually(func() bool {
+				_, err := ipPools.Get(context.Background(), poolName, metav1.GetOptions{})
+				return apierrors.IsNotFound(err)
+			}).Should(BeTrue())
+
+			t.CreateEndpoint(testing.NewEndpoint("remote-cluster2", "host", "192.0.3.0/24"))
+
+			Eventually(func() error {
+				_, err := ipPools.Get(context.Background(), "submariner-remote-cluster2-192.0.3.0-24", metav1.GetOptions{})
+				return err
+			}).Should(Succeed())
+
+			Expect(handler.Uninstall()).To(Succeed())
+
+			list, err := ipPools.List(context.Background(), metav1.ListOptions{})
+			Expect(err).To(Succeed())
+			Expect(list.Items).To(BeEmpty())
+		})
+	})
+
 	Context("on Uninstall", func() {
 		It("should delete all IPPools", func() {
 			_, err := calicoClient.ProjectcalicoV3().IPPools().Create(context.Background(), &calicoapi.IPPool{
@@ -111,6 +253,48 @@ var _ = Describe("IPPool Handler", func() {
 	})
 })
 
+func awaitBGPFilterCIDRs(client calicocs.Interface, subnets ...string) {
+	Eventually(func() []string {
+		filter, err := client.ProjectcalicoV3().BGPFilters().Get(context.Background(), calico.RemoteSubnetsBGPFilter,
+			metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		Expect(err).To(Succeed())
+
+		cidrs := []string{}
+
+		for i := range filter.Spec.ExportV4 {
+			Expect(filter.Spec.ExportV4[i].Action).To(Equal(calicoapi.Accept))
+			cidrs = append(cidrs, filter.Spec.ExportV4[i].CIDR)
+		}
+
+		return cidrs
+	}).Should(ConsistOf(toAny(subnets)...))
+}
+
+func awaitBGPPeer(client calicocs.Interface, name string) *calicoapi.BGPPeer {
+	var peer *calicoapi.BGPPeer
+
+	Eventually(func() error {
+		var err error
+
+		peer, err = client.ProjectcalicoV3().BGPPeers().Get(context.Background(), name, metav1.GetOptions{})
+
+		return err
+	}).Should(Succeed())
+
+	return peer
+}
+
+func awaitNoBGPPeer(client calicocs.Interface, name string) {
+	Eventually(func() bool {
+		_, err := client.ProjectcalicoV3().BGPPeers().Get(context.Background(), name, metav1.GetOptions{})
+		return apierrors.IsNotFound(err)
+	}).Should(BeTrue())
+}
+
 func getIPPoolCIDRs(client calicocs.Interface) []string {
 	list, err := client.ProjectcalicoV3().IPPools().List(context.Background(), metav1.ListOptions{
 		LabelSelector: calico.SubmarinerIPPool + "=true",
@@ -157,3 +341,15 @@ func toAny(s []string) []any {
 
 	return ia
 }
+
+var _ = Describe("BGPConfig", func() {
+	It("should reject invalid AS numbers", func() {
+		Expect((&calico.BGPConfig{PeerIPs: []string{"172.16.0.1"}, ASNumber: 64512}).Validate()).To(Succeed())
+		Expect((&calico.BGPConfig{PeerIPs: []string{"172.16.0.1"}}).Validate()).ToNot(Succeed())
+		Expect((&calico.BGPConfig{PeerIPs: []string{"172.16.0.1"}, ASNumber: 23456}).Validate()).ToNot(Succeed())
+	})
+
+	It("should reject invalid peer IPs", func() {
+		Expect((&calico.BGPConfig{PeerIPs: []string{"not-an-ip"}, ASNumber: 64512}).Validate()).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,259 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cilium
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/admiral/pkg/resource"
+	"github.com/submariner-io/admiral/pkg/util"
+	submV1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/cidr"
+	"github.com/submariner-io/submariner/pkg/cni"
+	"github.com/submariner-io/submariner/pkg/event"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/set"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// RemoteCIDRsPolicyName is the name of the CiliumClusterwideNetworkPolicy that allows traffic to and from the
+	// remote clusters.
+	RemoteCIDRsPolicyName = "submariner-remote-cidrs"
+
+	// SubmarinerManaged labels the Cilium resources created by the handler.
+	SubmarinerManaged = "submariner.io/managed"
+
+	// AgentDaemonSet is the DaemonSet running the Cilium agent, whose image determines the Cilium version.
+	AgentDaemonSet = "cilium"
+
+	agentContainer = "cilium-agent"
+)
+
+// minDefaultDenyVersion is the first Cilium version supporting the enableDefaultDeny policy field.
+var minDefaultDenyVersion = utilversion.MajorMinor(1, 15)
+
+var ClusterwideNetworkPolicyGVR = schema.GroupVersionResource{
+	Group:    "cilium.io",
+	Version:  "v2",
+	Resource: "ciliumclusterwidenetworkpolicies",
+}
+
+var logger = log.Logger{Logger: logf.Log.WithName("Cilium")}
+
+// The Cilium handler keeps Cilium from interfering with inter-cluster traffic when it replaces kube-proxy. The routes
+// that steer the traffic to the gateway are programmed by the kube-proxy handler, which also handles Cilium. This
+// handler excludes the remote CIDRs from masquerading via the ip-masq-agent ConfigMap and allows them through eBPF
+// policy enforcement via a CiliumClusterwideNetworkPolicy. Both are cluster-wide so they're only maintained by the
+// gateway node.
+type ciliumHandler struct {
+	event.HandlerBase
+	k8sClient kubernetes.Interface
+	dynClient dynamic.Interface
+}
+
+func NewHandler(k8sClient kubernetes.Interface, dynClient dynamic.Interface) event.Handler {
+	return &ciliumHandler{
+		k8sClient: k8sClient,
+		dynClient: dynClient,
+	}
+}
+
+func (h *ciliumHandler) GetNetworkPlugins() []string {
+	return []string{cni.Cilium}
+}
+
+func (h *ciliumHandler) GetName() string {
+	return "Cilium handler"
+}
+
+func (h *ciliumHandler) TransitionToGateway() error {
+	return h.syncRemoteCIDRs()
+}
+
+func (h *ciliumHandler) RemoteEndpointCreated(_ *submV1.Endpoint) error {
+	return h.syncRemoteCIDRsOnGateway()
+}
+
+func (h *ciliumHandler) RemoteEndpointUpdated(_ *submV1.Endpoint) error {
+	return h.syncRemoteCIDRsOnGateway()
+}
+
+func (h *ciliumHandler) RemoteEndpointRemoved(_ *submV1.Endpoint) error {
+	return h.syncRemoteCIDRsOnGateway()
+}
+
+func (h *ciliumHandler) Reconcile(_ event.HandlerState) error {
+	return h.syncRemoteCIDRsOnGateway()
+}
+
+func (h *ciliumHandler) Uninstall() error {
+	logger.Info("Uninstalling the Cilium resources used for Submariner")
+
+	return errorutils.NewAggregate([]error{
+		h.updateIPMasqAgentConfig(nil),
+		h.deleteNetworkPolicy(),
+	})
+}
+
+func (h *ciliumHandler) syncRemoteCIDRsOnGateway() error {
+	if !h.State().IsOnGateway() {
+		logger.V(log.TRACE).Info("Ignoring event (node isn't Gateway)")
+		return nil
+	}
+
+	return h.syncRemoteCIDRs()
+}
+
+func (h *ciliumHandler) syncRemoteCIDRs() error {
+	remoteCIDRs := set.New[string]()
+
+	endpoints := h.State().GetRemoteEndpoints()
+	for i := range endpoints {
+		remoteCIDRs.Insert(cidr.ExtractIPv4Subnets(endpoints[i].Spec.Subnets)...)
+	}
+
+	cidrs := remoteCIDRs.SortedList()
+
+	return errorutils.NewAggregate([]error{
+		h.updateIPMasqAgentConfig(cidrs),
+		h.updateNetworkPolicy(cidrs),
+	})
+}
+
+func (h *ciliumHandler) updateNetworkPolicy(cidrs []string) error {
+	if len(cidrs) == 0 {
+		return h.deleteNetworkPolicy()
+	}
+
+	supported, err := h.supportsDefaultDenyOverride()
+	if err != nil {
+		return err
+	}
+
+	if !supported {
+		logger.Warningf("The installed Cilium version doesn't support disabling default deny in a network policy (requires %s"+
+			" or later) - not creating CiliumClusterwideNetworkPolicy %q as it would deny all other traffic", minDefaultDenyVersion,
+			RemoteCIDRsPolicyName)
+
+		return h.deleteNetworkPolicy()
+	}
+
+	policy := newRemoteCIDRsPolicy(cidrs)
+
+	result, err := util.CreateOrUpdate(context.TODO(), resource.ForDynamic(h.dynClient.Resource(ClusterwideNetworkPolicyGVR)),
+		policy, util.Replace(policy))
+	if err != nil {
+		return errors.Wrapf(err, "error creating/updating CiliumClusterwideNetworkPolicy %q (are the Cilium CRDs installed?)",
+			RemoteCIDRsPolicyName)
+	}
+
+	logger.V(log.TRACE).Infof("CiliumClusterwideNetworkPolicy %q %s for remote CIDRs %v", RemoteCIDRsPolicyName, result, cidrs)
+
+	return nil
+}
+
+func (h *ciliumHandler) deleteNetworkPolicy() error {
+	err := h.dynClient.Resource(ClusterwideNetworkPolicyGVR).Delete(context.TODO(), RemoteCIDRsPolicyName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "error deleting CiliumClusterwideNetworkPolicy %q", RemoteCIDRsPolicyName)
+	}
+
+	return nil
+}
+
+// supportsDefaultDenyOverride determines whether the running Cilium version supports the enableDefaultDeny policy field
+// from the image of the Cilium agent DaemonSet. Older versions ignore the field so a policy selecting all endpoints
+// would enable default deny for all of them.
+func (h *ciliumHandler) supportsDefaultDenyOverride() (bool, error) {
+	daemonSet, err := h.k8sClient.AppsV1().DaemonSets(Namespace).Get(context.TODO(), AgentDaemonSet, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, errors.Wrapf(err, "error retrieving the %q DaemonSet", AgentDaemonSet)
+	}
+
+	for i := range daemonSet.Spec.Template.Spec.Containers {
+		container := &daemonSet.Spec.Template.Spec.Containers[i]
+		if container.Name != agentContainer {
+			continue
+		}
+
+		image, _, _ := strings.Cut(container.Image, "@")
+
+		tagIndex := strings.LastIndex(image, ":")
+		if tagIndex < 0 || strings.Contains(image[tagIndex:], "/") {
+			return false, nil
+		}
+
+		version, err := utilversion.ParseGeneric(image[tagIndex+1:])
+		if err != nil {
+			logger.Warningf("Unable to determine the Cilium version from image %q: %v", container.Image, err)
+			return false, nil
+		}
+
+		return version.AtLeast(minDefaultDenyVersion), nil
+	}
+
+	return false, nil
+}
+
+// newRemoteCIDRsPolicy returns a policy that selects all endpoints and allows ingress from and egress to the remote
+// CIDRs. Default deny is disabled so the policy doesn't restrict any other traffic.
+func newRemoteCIDRsPolicy(cidrs []string) *unstructured.Unstructured {
+	cidrList := make([]interface{}, len(cidrs))
+	for i := range cidrs {
+		cidrList[i] = cidrs[i]
+	}
+
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": ClusterwideNetworkPolicyGVR.GroupVersion().String(),
+		"kind":       "CiliumClusterwideNetworkPolicy",
+		"metadata": map[string]interface{}{
+			"name":   RemoteCIDRsPolicyName,
+			"labels": map[string]interface{}{SubmarinerManaged: "true"},
+		},
+		"spec": map[string]interface{}{
+			"description":      "Allows traffic to and from the remote clusters connected by Submariner",
+			"endpointSelector": map[string]interface{}{},
+			"enableDefaultDeny": map[string]interface{}{
+				"ingress": false,
+				"egress":  false,
+			},
+			"ingress": []interface{}{
+				map[string]interface{}{"fromCIDR": cidrList},
+			},
+			"egress": []interface{}{
+				map[string]interface{}{"toCIDR": cidrList},
+			},
+		},
+	}}
+}
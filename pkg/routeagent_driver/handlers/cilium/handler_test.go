@@ -0,0 +1,244 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cilium_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner/pkg/event"
+	"github.com/submariner-io/submariner/pkg/event/testing"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/handlers/cilium"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	fakek8s "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+)
+
+var _ = Describe("Handler", func() {
+	t := testing.NewControllerSupport()
+
+	var (
+		k8sClient *fakek8s.Clientset
+		dynClient *dynamicfake.FakeDynamicClient
+		handler   event.Handler
+	)
+
+	BeforeEach(func() {
+		k8sClient = fakek8s.NewSimpleClientset(newAgentDaemonSet("quay.io/cilium/cilium:v1.15.1@sha256:3516f8c4"))
+		dynClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{cilium.ClusterwideNetworkPolicyGVR: "CiliumClusterwideNetworkPolicyList"})
+	})
+
+	JustBeforeEach(func() {
+		handler = cilium.NewHandler(k8sClient, dynClient)
+		t.Start(handler)
+	})
+
+	When("remote Endpoints are created and deleted on the gateway", func() {
+		It("should update the non-masquerade CIDRs and network policy", func() {
+			remoteEP1 := t.CreateEndpoint(testing.NewEndpoint("remote-cluster1", "host", "172.32.0.0/16"))
+			ensureNoNetworkPolicy(dynClient)
+
+			localEP := t.CreateLocalHostEndpoint()
+			awaitNonMasqueradeCIDRs(k8sClient, append(cilium.DefaultNonMasqueradeCIDRs, "172.32.0.0/16")...)
+			awaitNetworkPolicyCIDRs(dynClient, "172.32.0.0/16")
+
+			remoteEP2 := t.CreateEndpoint(testing.NewEndpoint("remote-cluster2", "host", "172.33.0.0/16"))
+			awaitNonMasqueradeCIDRs(k8sClient, append(cilium.DefaultNonMasqueradeCIDRs, "172.32.0.0/16", "172.33.0.0/16")...)
+			awaitNetworkPolicyCIDRs(dynClient, "172.32.0.0/16", "172.33.0.0/16")
+
+			t.DeleteEndpoint(remoteEP1.Name)
+			awaitNonMasqueradeCIDRs(k8sClient, append(cilium.DefaultNonMasqueradeCIDRs, "172.33.0.0/16")...)
+			awaitNetworkPolicyCIDRs(dynClient, "172.33.0.0/16")
+
+			t.DeleteEndpoint(remoteEP2.Name)
+			awaitNoIPMasqAgentConfigMap(k8sClient)
+			awaitNoNetworkPolicy(dynClient)
+
+			t.DeleteEndpoint(localEP.Name)
+		})
+	})
+
+	When("the remote CIDRs are already excluded from masquerading by default", func() {
+		It("should not create the ip-masq-agent ConfigMap", func() {
+			t.CreateLocalHostEndpoint()
+			t.CreateEndpoint(testing.NewEndpoint("remote-cluster1", "host", "10.1.0.0/16"))
+			awaitNetworkPolicyCIDRs(dynClient, "10.1.0.0/16")
+
+			Consistently(func() map[string]interface{} {
+				return getIPMasqAgentConfig(k8sClient)
+			}).Should(BeNil())
+		})
+	})
+
+	When("the Cilium version doesn't support disabling default deny", func() {
+		BeforeEach(func() {
+			k8sClient = fakek8s.NewSimpleClientset(newAgentDaemonSet("quay.io/cilium/cilium:v1.14.5"))
+		})
+
+		It("should not create the network policy", func() {
+			t.CreateLocalHostEndpoint()
+			t.CreateEndpoint(testing.NewEndpoint("remote-cluster1", "host", "172.32.0.0/16"))
+			awaitNonMasqueradeCIDRs(k8sClient, append(cilium.DefaultNonMasqueradeCIDRs, "172.32.0.0/16")...)
+			ensureNoNetworkPolicy(dynClient)
+		})
+	})
+
+	When("the ip-masq-agent ConfigMap already exists", func() {
+		BeforeEach(func() {
+			_, err := k8sClient.CoreV1().ConfigMaps(cilium.Namespace).Create(context.Background(), &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cilium.IPMasqAgentConfigMap},
+				Data: map[string]string{
+					cilium.IPMasqAgentConfigKey: "nonMasqueradeCIDRs:\n- 10.0.0.0/8\nmasqLinkLocal: true\n",
+				},
+			}, metav1.CreateOptions{})
+			Expect(err).To(Succeed())
+		})
+
+		It("should preserve the existing configuration", func() {
+			t.CreateLocalHostEndpoint()
+
+			remoteEP := t.CreateEndpoint(testing.NewEndpoint("remote-cluster1", "host", "172.32.0.0/16"))
+			awaitNonMasqueradeCIDRs(k8sClient, "10.0.0.0/8", "172.32.0.0/16")
+			Expect(getIPMasqAgentConfig(k8sClient)).To(HaveKeyWithValue("masqLinkLocal", true))
+
+			t.DeleteEndpoint(remoteEP.Name)
+			awaitNonMasqueradeCIDRs(k8sClient, "10.0.0.0/8")
+
+			Expect(handler.Uninstall()).To(Succeed())
+			Expect(getIPMasqAgentConfig(k8sClient)).To(HaveKeyWithValue("masqLinkLocal", true))
+		})
+	})
+
+	Context("on Uninstall", func() {
+		It("should remove the Cilium resources", func() {
+			t.CreateLocalHostEndpoint()
+			t.CreateEndpoint(testing.NewEndpoint("remote-cluster1", "host", "172.32.0.0/16"))
+			awaitNetworkPolicyCIDRs(dynClient, "172.32.0.0/16")
+
+			Expect(handler.Uninstall()).To(Succeed())
+
+			_, err := k8sClient.CoreV1().ConfigMaps(cilium.Namespace).Get(context.Background(), cilium.IPMasqAgentConfigMap,
+				metav1.GetOptions{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			_, err = dynClient.Resource(cilium.ClusterwideNetworkPolicyGVR).Get(context.Background(), cilium.RemoteCIDRsPolicyName,
+				metav1.GetOptions{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+})
+
+func newAgentDaemonSet(image string) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cilium.AgentDaemonSet,
+			Namespace: cilium.Namespace,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "cilium-agent", Image: image}},
+				},
+			},
+		},
+	}
+}
+
+func getIPMasqAgentConfig(client kubernetes.Interface) map[string]interface{} {
+	configMap, err := client.CoreV1().ConfigMaps(cilium.Namespace).Get(context.Background(), cilium.IPMasqAgentConfigMap,
+		metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	Expect(err).To(Succeed())
+
+	config := map[string]interface{}{}
+	Expect(yaml.Unmarshal([]byte(configMap.Data[cilium.IPMasqAgentConfigKey]), &config)).To(Succeed())
+
+	return config
+}
+
+func awaitNonMasqueradeCIDRs(client kubernetes.Interface, cidrs ...string) {
+	Eventually(func() interface{} {
+		return getIPMasqAgentConfig(client)["nonMasqueradeCIDRs"]
+	}).Should(ConsistOf(toAny(cidrs)...))
+}
+
+func awaitNoIPMasqAgentConfigMap(client kubernetes.Interface) {
+	Eventually(func() bool {
+		_, err := client.CoreV1().ConfigMaps(cilium.Namespace).Get(context.Background(), cilium.IPMasqAgentConfigMap,
+			metav1.GetOptions{})
+		return apierrors.IsNotFound(err)
+	}).Should(BeTrue())
+}
+
+func getNetworkPolicyCIDRs(client *dynamicfake.FakeDynamicClient) []interface{} {
+	policy, err := client.Resource(cilium.ClusterwideNetworkPolicyGVR).Get(context.Background(), cilium.RemoteCIDRsPolicyName,
+		metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	Expect(err).To(Succeed())
+
+	ingress, _, _ := unstructured.NestedSlice(policy.Object, "spec", "ingress")
+	Expect(ingress).To(HaveLen(1))
+
+	cidrs, _, _ := unstructured.NestedSlice(ingress[0].(map[string]interface{}), "fromCIDR")
+
+	return cidrs
+}
+
+func awaitNetworkPolicyCIDRs(client *dynamicfake.FakeDynamicClient, cidrs ...string) {
+	Eventually(func() []interface{} {
+		return getNetworkPolicyCIDRs(client)
+	}).Should(ConsistOf(toAny(cidrs)...))
+}
+
+func awaitNoNetworkPolicy(client *dynamicfake.FakeDynamicClient) {
+	Eventually(func() []interface{} {
+		return getNetworkPolicyCIDRs(client)
+	}).Should(BeNil())
+}
+
+func ensureNoNetworkPolicy(client *dynamicfake.FakeDynamicClient) {
+	Consistently(func() []interface{} {
+		return getNetworkPolicyCIDRs(client)
+	}).Should(BeNil())
+}
+
+func toAny(s []string) []any {
+	ia := make([]any, len(s))
+	for i := range s {
+		ia[i] = s[i]
+	}
+
+	return ia
+}
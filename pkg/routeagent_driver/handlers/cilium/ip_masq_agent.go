@@ -0,0 +1,226 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cilium
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/set"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// IPMasqAgentConfigMap is the ConfigMap read by Cilium's ip-masq-agent.
+	IPMasqAgentConfigMap = "ip-masq-agent"
+	IPMasqAgentConfigKey = "config"
+
+	// NonMasqueradeCIDRsAnnotation records the CIDRs added to the ip-masq-agent configuration by Submariner so they
+	// can be removed without disturbing the entries configured by the user.
+	NonMasqueradeCIDRsAnnotation = "submariner.io/non-masquerade-cidrs"
+
+	nonMasqueradeCIDRsKey = "nonMasqueradeCIDRs"
+)
+
+var Namespace = "kube-system"
+
+// DefaultNonMasqueradeCIDRs are the CIDRs Cilium's ip-masq-agent excludes from masquerading when its configuration
+// doesn't specify any. They're merged into the configuration written by Submariner so they aren't masqueraded once it
+// lists the remote CIDRs.
+var DefaultNonMasqueradeCIDRs = []string{
+	"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10", "192.0.0.0/24", "192.0.2.0/24", "192.88.99.0/24",
+	"198.18.0.0/15", "198.51.100.0/24", "203.0.113.0/24", "240.0.0.0/4",
+}
+
+// updateIPMasqAgentConfig ensures the ip-masq-agent configuration excludes exactly the given CIDRs, in addition to
+// the ones configured by the user or Cilium's defaults if none are, from masquerading. The ConfigMap is created if
+// needed and deleted once it no longer contains any CIDRs owned by Submariner if it was created by Submariner.
+func (h *ciliumHandler) updateIPMasqAgentConfig(cidrs []string) error {
+	configMaps := h.k8sClient.CoreV1().ConfigMaps(Namespace)
+	updated := false
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		updated = false
+
+		configMap, err := configMaps.Get(context.TODO(), IPMasqAgentConfigMap, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   IPMasqAgentConfigMap,
+					Labels: map[string]string{SubmarinerManaged: "true"},
+				},
+			}
+
+			hasOwned, err := setNonMasqueradeCIDRs(configMap, map[string]interface{}{}, DefaultNonMasqueradeCIDRs, cidrs)
+			if err != nil || !hasOwned {
+				return err
+			}
+
+			_, err = configMaps.Create(context.TODO(), configMap, metav1.CreateOptions{})
+			updated = err == nil
+
+			return err //nolint:wrapcheck  // Let the caller wrap it
+		}
+
+		if err != nil {
+			return err //nolint:wrapcheck  // Let the caller wrap it
+		}
+
+		config := map[string]interface{}{}
+		if data := configMap.Data[IPMasqAgentConfigKey]; data != "" {
+			if err := yaml.Unmarshal([]byte(data), &config); err != nil {
+				return errors.Wrapf(err, "error parsing the %q ConfigMap", IPMasqAgentConfigMap)
+			}
+		}
+
+		owned := set.New[string]()
+
+		for _, c := range strings.Split(configMap.Annotations[NonMasqueradeCIDRsAnnotation], ",") {
+			if c != "" {
+				owned.Insert(c)
+			}
+		}
+
+		var userCIDRs []string
+
+		for _, c := range toStrings(config[nonMasqueradeCIDRsKey]) {
+			if !owned.Has(c) {
+				userCIDRs = append(userCIDRs, c)
+			}
+		}
+
+		if len(userCIDRs) == 0 {
+			userCIDRs = DefaultNonMasqueradeCIDRs
+		}
+
+		orig := configMap.DeepCopy()
+
+		hasOwned, err := setNonMasqueradeCIDRs(configMap, config, userCIDRs, cidrs)
+		if err != nil {
+			return err
+		}
+
+		if !hasOwned && configMap.Labels[SubmarinerManaged] == "true" &&
+			set.New(userCIDRs...).Equal(set.New(DefaultNonMasqueradeCIDRs...)) {
+			err = configMaps.Delete(context.TODO(), IPMasqAgentConfigMap, metav1.DeleteOptions{})
+			updated = err == nil
+
+			return err //nolint:wrapcheck  // Let the caller wrap it
+		}
+
+		if orig.Data[IPMasqAgentConfigKey] == configMap.Data[IPMasqAgentConfigKey] &&
+			orig.Annotations[NonMasqueradeCIDRsAnnotation] == configMap.Annotations[NonMasqueradeCIDRsAnnotation] {
+			return nil
+		}
+
+		_, err = configMaps.Update(context.TODO(), configMap, metav1.UpdateOptions{})
+		updated = err == nil
+
+		return err //nolint:wrapcheck  // Let the caller wrap it
+	})
+
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "error updating the %q ConfigMap in namespace %q", IPMasqAgentConfigMap, Namespace)
+	}
+
+	if updated {
+		logger.V(log.TRACE).Infof("Updated the %q ConfigMap with non-masquerade CIDRs %v", IPMasqAgentConfigMap, cidrs)
+	}
+
+	return nil
+}
+
+// setNonMasqueradeCIDRs sets the given CIDRs, in addition to the user's, in the ip-masq-agent configuration and
+// records the ones not already covered by the user's in the ConfigMap's annotation. It returns whether there are any.
+func setNonMasqueradeCIDRs(configMap *corev1.ConfigMap, config map[string]interface{}, userCIDRs, cidrs []string) (bool, error) {
+	all := append([]string{}, userCIDRs...)
+
+	var owned []string
+
+	for _, c := range cidrs {
+		if !containedIn(c, userCIDRs) {
+			all = append(all, c)
+			owned = append(owned, c)
+		}
+	}
+
+	config[nonMasqueradeCIDRsKey] = all
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return false, errors.Wrapf(err, "error marshalling the %q configuration", IPMasqAgentConfigMap)
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+
+	configMap.Data[IPMasqAgentConfigKey] = string(data)
+
+	if configMap.Annotations == nil {
+		configMap.Annotations = map[string]string{}
+	}
+
+	configMap.Annotations[NonMasqueradeCIDRsAnnotation] = strings.Join(owned, ",")
+
+	return len(owned) > 0, nil
+}
+
+func containedIn(cidr string, cidrs []string) bool {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false
+	}
+
+	ones, _ := ipNet.Mask.Size()
+
+	for _, c := range cidrs {
+		_, outer, err := net.ParseCIDR(c)
+		if err != nil {
+			continue
+		}
+
+		outerOnes, _ := outer.Mask.Size()
+		if outerOnes <= ones && outer.Contains(ipNet.IP) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func toStrings(v interface{}) []string {
+	list, _ := v.([]interface{})
+	strs := make([]string, 0, len(list))
+
+	for _, e := range list {
+		if s, ok := e.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+
+	return strs
+}
@@ -0,0 +1,276 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeproxy
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// IntraClusterRouting specifies how traffic destined to remote clusters is carried from non-gateway nodes to the
+// gateway node.
+type IntraClusterRouting string
+
+const (
+	// RoutingVxLAN always uses the vx-submariner VxLAN overlay.
+	RoutingVxLAN IntraClusterRouting = "vxlan"
+	// RoutingDirect routes via the gateway node IP, falling back to VxLAN if the routes can't be installed.
+	RoutingDirect IntraClusterRouting = "direct"
+	// RoutingAuto routes via the gateway node IP if it's directly reachable (on-link), otherwise VxLAN is used.
+	RoutingAuto IntraClusterRouting = "auto"
+
+	// DirectRouteProtocol identifies the routes via the gateway node IP installed by the route agent.
+	DirectRouteProtocol = 0x53
+)
+
+// ParseIntraClusterRouting returns the IntraClusterRouting for the given value, or an error if it isn't supported.
+func ParseIntraClusterRouting(value string) (IntraClusterRouting, error) {
+	switch routing := IntraClusterRouting(strings.ToLower(value)); routing {
+	case RoutingVxLAN, RoutingDirect, RoutingAuto:
+		return routing, nil
+	default:
+		return "", fmt.Errorf("unsupported intra-cluster routing %q - must be one of %q, %q or %q", value, RoutingVxLAN,
+			RoutingDirect, RoutingAuto)
+	}
+}
+
+// directGateway holds the next hop used to route remote traffic to the gateway node without encapsulation.
+type directGateway struct {
+	hostname  string
+	ip        net.IP
+	linkIndex int
+}
+
+func (kp *SyncHandler) directRoutingEnabled() bool {
+	return kp.intraClusterRouting == RoutingDirect || kp.intraClusterRouting == RoutingAuto
+}
+
+// directGatewayFor returns the directGateway to use for the given gateway node, or nil if VxLAN must be used.
+func (kp *SyncHandler) directGatewayFor(hostname string, gatewayNodeIP net.IP) *directGateway {
+	if !kp.directRoutingEnabled() {
+		return nil
+	}
+
+	linkIndex := kp.defaultHostIface.Index
+
+	routes, err := kp.netLink.RouteGet(gatewayNodeIP)
+	if err != nil {
+		logger.Warningf("Unable to retrieve the route to gateway node IP %s: %v", gatewayNodeIP, err)
+	}
+
+	if len(routes) > 0 {
+		if kp.intraClusterRouting == RoutingAuto && routes[0].Gw != nil {
+			logger.Infof("Gateway node IP %s is reached via %s - using VxLAN", gatewayNodeIP, routes[0].Gw)
+			return nil
+		}
+
+		linkIndex = routes[0].LinkIndex
+	} else if kp.intraClusterRouting == RoutingAuto {
+		return nil
+	}
+
+	return &directGateway{
+		hostname:  hostname,
+		ip:        gatewayNodeIP,
+		linkIndex: linkIndex,
+	}
+}
+
+// enableDirectRouting routes the remote subnets via the given gateway and removes the VxLAN interface, which is no
+// longer needed.
+func (kp *SyncHandler) enableDirectRouting(gw *directGateway) error {
+	if kp.vxlanDevice != nil {
+		if err := kp.vxlanDevice.deleteVxLanIface(); err != nil {
+			return errors.Wrapf(err, "failed to delete the vxlan interface that points to endpoint %s",
+				kp.vxlanDevice.activeEndpointHostname)
+		}
+
+		kp.vxlanDevice = nil
+		kp.vxlanGwIP = nil
+	} else if link, err := kp.netLink.LinkByName(VxLANIface); err == nil {
+		if err := kp.netLink.LinkDel(link); err != nil {
+			return errors.Wrapf(err, "failed to delete the stale %s interface", VxLANIface)
+		}
+	}
+
+	if kp.directGw != nil && kp.directGw.linkIndex != gw.linkIndex {
+		kp.disableDirectRouting()
+	}
+
+	kp.directGw = gw
+
+	_, err := kp.reconcileDirectRoutes()
+	if err != nil {
+		kp.disableDirectRouting()
+		return err
+	}
+
+	logger.Infof("Routing remote subnets directly via gateway node %q (%s)", gw.hostname, gw.ip)
+
+	return nil
+}
+
+// disableDirectRouting removes all the direct routes.
+func (kp *SyncHandler) disableDirectRouting() {
+	if kp.directGw == nil {
+		return
+	}
+
+	routes, err := kp.listDirectRoutes(kp.directGw.linkIndex)
+	if err != nil {
+		logger.Errorf(err, "Unable to remove the direct routes to gateway node %s", kp.directGw.ip)
+	}
+
+	for i := range routes {
+		if err := kp.netLink.RouteDel(&routes[i]); err != nil {
+			logger.Errorf(err, "Error removing route %s", routes[i])
+		}
+	}
+
+	kp.directGw = nil
+}
+
+func (kp *SyncHandler) directRoute(remoteSubnet string) (*netlink.Route, error) {
+	_, dst, err := net.ParseCIDR(remoteSubnet)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing cidr block %s", remoteSubnet)
+	}
+
+	route := &netlink.Route{
+		Dst:       dst,
+		Gw:        kp.directGw.ip,
+		Scope:     unix.RT_SCOPE_UNIVERSE,
+		LinkIndex: kp.directGw.linkIndex,
+		Protocol:  DirectRouteProtocol,
+	}
+
+	// Use the CNI interface IP as the source so host networking traffic can be routed back from the remote clusters.
+	if kp.cniIface != nil {
+		route.Src = net.ParseIP(kp.cniIface.IPAddress)
+	}
+
+	return route, nil
+}
+
+func (kp *SyncHandler) listDirectRoutes(linkIndex int) ([]netlink.Route, error) {
+	routes, err := kp.netLink.RouteList(&netlink.GenericLink{LinkAttrs: netlink.LinkAttrs{Index: linkIndex}}, syscall.AF_INET)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error retrieving routes for link index %d", linkIndex)
+	}
+
+	owned := []netlink.Route{}
+
+	for i := range routes {
+		if routes[i].Protocol == DirectRouteProtocol {
+			owned = append(owned, routes[i])
+		}
+	}
+
+	return owned, nil
+}
+
+// updateDirectRoutes adds or deletes the direct routes for the given remote subnets.
+func (kp *SyncHandler) updateDirectRoutes(remoteCIDRs []string, operation Operation) error {
+	for _, cidrBlock := range remoteCIDRs {
+		route, err := kp.directRoute(cidrBlock)
+		if err != nil {
+			return err
+		}
+
+		switch operation {
+		case Add:
+			err = kp.netLink.RouteAdd(route)
+			if err != nil && !os.IsExist(err) {
+				return errors.Wrapf(err, "error adding route %s", route)
+			}
+		case Delete:
+			err = kp.netLink.RouteDel(route)
+			if err != nil {
+				return errors.Wrapf(err, "error deleting route %s", route)
+			}
+		case Flush:
+		}
+	}
+
+	return nil
+}
+
+// reconcileDirectRoutes ensures a direct route exists for each remote subnet and removes any others previously
+// installed. Returns the number of routes that were added or removed.
+func (kp *SyncHandler) reconcileDirectRoutes() (int, error) {
+	current, err := kp.listDirectRoutes(kp.directGw.linkIndex)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	found := map[string]bool{}
+
+	for i := range current {
+		if current[i].Dst != nil && current[i].Gw.Equal(kp.directGw.ip) && kp.remoteSubnets.Has(current[i].Dst.String()) {
+			found[current[i].Dst.String()] = true
+			continue
+		}
+
+		logger.V(log.DEBUG).Infof("Removing direct route %s", current[i])
+
+		if err := kp.netLink.RouteDel(&current[i]); err != nil {
+			logger.Errorf(err, "Error removing route %s", current[i])
+		} else {
+			changed++
+		}
+	}
+
+	for _, cidrBlock := range kp.remoteSubnets.UnsortedList() {
+		if found[cidrBlock] {
+			continue
+		}
+
+		route, err := kp.directRoute(cidrBlock)
+		if err != nil {
+			return changed, err
+		}
+
+		if err := kp.netLink.RouteAdd(route); err != nil && !os.IsExist(err) {
+			return changed, errors.Wrapf(err, "error adding route %s", route)
+		}
+
+		changed++
+	}
+
+	return changed, nil
+}
+
+func (kp *SyncHandler) directRoutesOwnedState() []string {
+	routes := []string{}
+
+	for _, cidrBlock := range kp.remoteSubnets.SortedList() {
+		routes = append(routes, fmt.Sprintf("%s via %s proto %d", cidrBlock, kp.directGw.ip, DirectRouteProtocol))
+	}
+
+	return routes
+}
@@ -31,6 +31,19 @@ func (kp *SyncHandler) LocalEndpointCreated(endpoint *submV1.Endpoint) error {
 
 	// We are on nonGateway node
 	if !kp.State().IsOnGateway() {
+		localClusterGwNodeIP := net.ParseIP(endpoint.Spec.PrivateIP)
+
+		if gw := kp.directGatewayFor(endpoint.Spec.Hostname, localClusterGwNodeIP); gw != nil {
+			err := kp.enableDirectRouting(gw)
+			if err == nil {
+				return nil
+			}
+
+			logger.Warningf("Unable to route directly via gateway node %s - falling back to VxLAN: %v", localClusterGwNodeIP, err)
+		}
+
+		kp.disableDirectRouting()
+
 		// If the node already has a vxLAN interface that points to an oldEndpoint
 		// (i.e., during gateway migration), delete it.
 		if kp.vxlanDevice != nil && kp.vxlanDevice.activeEndpointHostname != endpoint.Spec.Hostname {
@@ -43,8 +56,6 @@ func (kp *SyncHandler) LocalEndpointCreated(endpoint *submV1.Endpoint) error {
 			kp.vxlanDevice = nil
 		}
 
-		localClusterGwNodeIP := net.ParseIP(endpoint.Spec.PrivateIP)
-
 		remoteVtepIP, err := getVxlanVtepIPAddress(localClusterGwNodeIP.String())
 		if err != nil {
 			return errors.Wrap(err, "failed to derive the remoteVtepIP")
@@ -59,7 +70,7 @@ func (kp *SyncHandler) LocalEndpointCreated(endpoint *submV1.Endpoint) error {
 
 		kp.vxlanGwIP = &remoteVtepIP
 
-		err = kp.reconcileRoutes(remoteVtepIP)
+		_, err = kp.reconcileRoutes(remoteVtepIP)
 		if err != nil {
 			return errors.Wrap(err, "error while reconciling routes")
 		}
@@ -69,6 +80,10 @@ func (kp *SyncHandler) LocalEndpointCreated(endpoint *submV1.Endpoint) error {
 }
 
 func (kp *SyncHandler) LocalEndpointRemoved(endpoint *submV1.Endpoint) error {
+	if kp.directGw != nil && kp.directGw.hostname == endpoint.Spec.Hostname {
+		kp.disableDirectRouting()
+	}
+
 	// If the vxLAN device exists and it points to the same endpoint, delete it.
 	if kp.vxlanDevice != nil && kp.vxlanDevice.activeEndpointHostname == endpoint.Spec.Hostname {
 		err := kp.vxlanDevice.deleteVxLanIface()
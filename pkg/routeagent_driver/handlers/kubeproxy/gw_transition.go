@@ -37,13 +37,14 @@ func (kp *SyncHandler) TransitionToNonGateway() error {
 			constants.RouteAgentHostNetworkTableID, kp.hostname)
 	}
 
-	return nil
+	return kp.routingPolicyGw.SetOnGateway(false) //nolint:wrapcheck  // Let the caller wrap it
 }
 
 func (kp *SyncHandler) TransitionToGateway() error {
 	logger.V(log.DEBUG).Info("The current node has become a Gateway")
 
 	kp.cleanVxSubmarinerRoutes()
+	kp.disableDirectRouting()
 
 	logger.Infof("Creating the vxlan interface: %s on the gateway node", VxLANIface)
 
@@ -52,6 +53,15 @@ func (kp *SyncHandler) TransitionToGateway() error {
 		logger.Fatalf("Unable to create VxLAN interface on gateway node (%s): %v", kp.hostname, err)
 	}
 
+	if kp.directRoutingEnabled() {
+		// Traffic routed directly from the non-Gateway nodes arrives on the host interface with a source IP
+		// that isn't routed via that interface.
+		err = kp.netLink.EnsureLooseModeIsConfigured(kp.defaultHostIface.Name)
+		if err != nil {
+			logger.Errorf(err, "Unable to configure loose mode reverse path filtering on %q", kp.defaultHostIface.Name)
+		}
+	}
+
 	err = kp.netLink.RuleAddIfNotPresent(netlinkAPI.NewTableRule(constants.RouteAgentHostNetworkTableID))
 	if err != nil {
 		logger.Errorf(err, "Unable to add ip rule to table %d on Gateway node %s",
@@ -61,5 +71,5 @@ func (kp *SyncHandler) TransitionToGateway() error {
 	// Add routes to the new endpoint on the GatewayNode.
 	kp.updateRoutingRulesForHostNetworkSupport(kp.remoteSubnets.UnsortedList(), Add)
 
-	return nil
+	return kp.routingPolicyGw.SetOnGateway(true) //nolint:wrapcheck  // Let the caller wrap it
 }
@@ -92,8 +92,7 @@ func (kp *SyncHandler) updateIptableRulesForInterClusterTraffic(inputCidrBlocks
 
 func (kp *SyncHandler) programIptableRulesForInterClusterTraffic(remoteCidrBlock string, operation Operation) error {
 	for _, localClusterCidr := range kp.localClusterCidr {
-		outboundRuleSpec := []string{"-s", localClusterCidr, "-d", remoteCidrBlock, "-j", "ACCEPT"}
-		incomingRuleSpec := []string{"-s", remoteCidrBlock, "-d", localClusterCidr, "-j", "ACCEPT"}
+		outboundRuleSpec, incomingRuleSpec := interClusterTrafficRuleSpecs(localClusterCidr, remoteCidrBlock)
 
 		if operation == Add {
 			logger.V(log.DEBUG).Infof("Installing iptables rule for outgoing traffic: %s", strings.Join(outboundRuleSpec, " "))
@@ -124,3 +123,8 @@ func (kp *SyncHandler) programIptableRulesForInterClusterTraffic(remoteCidrBlock
 
 	return nil
 }
+
+func interClusterTrafficRuleSpecs(localClusterCidr, remoteCidrBlock string) (outbound, incoming []string) {
+	return []string{"-s", localClusterCidr, "-d", remoteCidrBlock, "-j", "ACCEPT"},
+		[]string{"-s", remoteCidrBlock, "-d", localClusterCidr, "-j", "ACCEPT"}
+}
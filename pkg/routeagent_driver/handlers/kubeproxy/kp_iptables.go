@@ -30,6 +30,7 @@ import (
 	"github.com/submariner-io/submariner/pkg/iptables"
 	"github.com/submariner-io/submariner/pkg/netlink"
 	cniapi "github.com/submariner-io/submariner/pkg/routeagent_driver/cni"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/routingpolicy"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/utils/set"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -41,6 +42,8 @@ type SyncHandler struct {
 	localClusterCidr []string
 	localServiceCidr []string
 
+	intraClusterRouting IntraClusterRouting
+
 	remoteSubnets    set.Set[string]
 	remoteSubnetGw   map[string]net.IP
 	remoteVTEPs      set.Set[string]
@@ -50,28 +53,39 @@ type SyncHandler struct {
 	netLink          netlink.Interface
 	vxlanDevice      *vxLanIface
 	vxlanGwIP        *net.IP
+	directGw         *directGateway
 	hostname         string
 	cniIface         *cniapi.Interface
 	defaultHostIface *net.Interface
+	routingPolicies  *routingpolicy.HostDataplane
+	routingPolicyGw  *routingpolicy.ActiveGatewayFilter
 }
 
 var logger = log.Logger{Logger: logf.Log.WithName("KubeProxy")}
 
-func NewSyncHandler(localClusterCidr, localServiceCidr []string) *SyncHandler {
+func NewSyncHandler(localClusterCidr, localServiceCidr []string, intraClusterRouting IntraClusterRouting) *SyncHandler {
 	ipTables, err := iptables.New()
 	utilruntime.Must(err)
 
-	return &SyncHandler{
-		localClusterCidr: cidr.ExtractIPv4Subnets(localClusterCidr),
-		localServiceCidr: cidr.ExtractIPv4Subnets(localServiceCidr),
-		localCableDriver: "",
-		remoteSubnets:    set.New[string](),
-		remoteSubnetGw:   map[string]net.IP{},
-		remoteVTEPs:      set.New[string](),
-		routeCacheGWNode: set.New[string](),
-		netLink:          netlink.New(),
-		ipTables:         ipTables,
+	netLink := netlink.New()
+
+	kp := &SyncHandler{
+		localClusterCidr:    cidr.ExtractIPv4Subnets(localClusterCidr),
+		localServiceCidr:    cidr.ExtractIPv4Subnets(localServiceCidr),
+		localCableDriver:    "",
+		intraClusterRouting: intraClusterRouting,
+		remoteSubnets:       set.New[string](),
+		remoteSubnetGw:      map[string]net.IP{},
+		remoteVTEPs:         set.New[string](),
+		routeCacheGWNode:    set.New[string](),
+		netLink:             netLink,
+		ipTables:            ipTables,
+		routingPolicies:     routingpolicy.NewHostDataplane(netLink, VxLANIface),
 	}
+
+	kp.routingPolicyGw = routingpolicy.NewActiveGatewayFilter(kp.routingPolicies.Apply, kp.routingPolicies.Remove)
+
+	return kp
 }
 
 func (kp *SyncHandler) GetName() string {
@@ -121,6 +135,11 @@ func (kp *SyncHandler) Init() error {
 		logger.Errorf(err, "Error discovering the CNI interface")
 	}
 
+	// The RoutingPolicies are re-applied once their controller starts.
+	if err := kp.routingPolicies.Flush(); err != nil {
+		logger.Errorf(err, "Error flushing the RoutingPolicy rules")
+	}
+
 	// Create the necessary IPTable chains in the filter and nat tables.
 	err = kp.createIPTableChains()
 	if err != nil {
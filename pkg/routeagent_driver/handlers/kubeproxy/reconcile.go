@@ -0,0 +1,173 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeproxy
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/submariner/pkg/event"
+	netlinkAPI "github.com/submariner-io/submariner/pkg/netlink"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
+	"github.com/vishvananda/netlink"
+)
+
+func (kp *SyncHandler) Reconcile(state event.HandlerState) error {
+	if err := kp.createIPTableChains(); err != nil {
+		return errors.Wrap(err, "error reconciling the iptables chains")
+	}
+
+	drifted, err := kp.reconcileInterClusterIPTableRules()
+	event.RecordDriftCorrected(kp.GetName(), event.DriftIPTables, drifted)
+
+	if err != nil {
+		return err
+	}
+
+	if !state.IsOnGateway() {
+		if kp.directGw != nil {
+			drifted, err = kp.reconcileDirectRoutes()
+			event.RecordDriftCorrected(kp.GetName(), event.DriftRoutes, drifted)
+
+			return errors.Wrap(err, "error reconciling the direct routes")
+		}
+
+		if kp.vxlanGwIP == nil {
+			return nil
+		}
+
+		drifted, err = kp.reconcileRoutes(*kp.vxlanGwIP)
+		event.RecordDriftCorrected(kp.GetName(), event.DriftRoutes, drifted)
+
+		return errors.Wrap(err, "error reconciling the VxLAN routes")
+	}
+
+	drifted, err = kp.reconcileHostNetworkRule()
+	event.RecordDriftCorrected(kp.GetName(), event.DriftRules, drifted)
+
+	if err != nil {
+		return err
+	}
+
+	event.RecordDriftCorrected(kp.GetName(), event.DriftRoutes, kp.reconcileHostNetworkRoutes())
+
+	return nil
+}
+
+func (kp *SyncHandler) reconcileInterClusterIPTableRules() (int, error) {
+	drifted := 0
+
+	for _, remoteCidrBlock := range kp.remoteSubnets.UnsortedList() {
+		for _, localClusterCidr := range kp.localClusterCidr {
+			outboundRuleSpec, incomingRuleSpec := interClusterTrafficRuleSpecs(localClusterCidr, remoteCidrBlock)
+
+			for _, ruleSpec := range [][]string{outboundRuleSpec, incomingRuleSpec} {
+				exists, err := kp.ipTables.Exists(constants.NATTable, constants.SmPostRoutingChain, ruleSpec...)
+				if err != nil {
+					return drifted, errors.Wrapf(err, "error checking for iptables rule %q", strings.Join(ruleSpec, " "))
+				}
+
+				if exists {
+					continue
+				}
+
+				if err := kp.ipTables.AppendUnique(constants.NATTable, constants.SmPostRoutingChain, ruleSpec...); err != nil {
+					return drifted, errors.Wrapf(err, "error appending iptables rule %q", strings.Join(ruleSpec, " "))
+				}
+
+				drifted++
+			}
+		}
+	}
+
+	return drifted, nil
+}
+
+func (kp *SyncHandler) reconcileHostNetworkRule() (int, error) {
+	rules, err := kp.netLink.RuleList(netlink.FAMILY_ALL)
+	if err != nil {
+		return 0, errors.Wrap(err, "error listing the ip rules")
+	}
+
+	for i := range rules {
+		if rules[i].Table == constants.RouteAgentHostNetworkTableID {
+			return 0, nil
+		}
+	}
+
+	err = kp.netLink.RuleAdd(netlinkAPI.NewTableRule(constants.RouteAgentHostNetworkTableID))
+	if err != nil && !os.IsExist(err) {
+		return 0, errors.Wrapf(err, "error adding ip rule to table %d", constants.RouteAgentHostNetworkTableID)
+	}
+
+	return 1, nil
+}
+
+func (kp *SyncHandler) reconcileHostNetworkRoutes() int {
+	if kp.cniIface == nil {
+		return 0
+	}
+
+	drifted := 0
+
+	for _, cidrBlock := range kp.routeCacheGWNode.UnsortedList() {
+		viaGW, err := kp.viaGatewayFor(cidrBlock)
+		if err != nil {
+			logger.Errorf(err, "Error determining the next hop of the HostNetwork route for %q", cidrBlock)
+			continue
+		}
+
+		route, err := kp.hostNetworkRoute(cidrBlock, viaGW)
+		if err != nil {
+			logger.Errorf(err, "Error building the HostNetwork route for %q", cidrBlock)
+			continue
+		}
+
+		err = kp.netLink.RouteAdd(route)
+		if err == nil {
+			drifted++
+		} else if !os.IsExist(err) {
+			logger.Errorf(err, "Error adding the HostNetwork route %s", route)
+		}
+	}
+
+	return drifted
+}
+
+func (kp *SyncHandler) OwnedState() event.OwnedState {
+	state := event.OwnedState{}
+
+	if kp.State().IsOnGateway() {
+		for _, cidrBlock := range kp.routeCacheGWNode.SortedList() {
+			state.Routes = append(state.Routes, fmt.Sprintf("%s table %d", cidrBlock, constants.RouteAgentHostNetworkTableID))
+		}
+
+		state.Rules = []string{fmt.Sprintf("lookup %d", constants.RouteAgentHostNetworkTableID)}
+	} else if kp.directGw != nil {
+		state.Routes = kp.directRoutesOwnedState()
+	} else if kp.vxlanGwIP != nil {
+		for _, cidrBlock := range kp.remoteSubnets.SortedList() {
+			state.Routes = append(state.Routes, fmt.Sprintf("%s via %s dev %s", cidrBlock, kp.vxlanGwIP, VxLANIface))
+		}
+	}
+
+	return state
+}
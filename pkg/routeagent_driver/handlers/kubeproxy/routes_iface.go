@@ -19,6 +19,7 @@ limitations under the License.
 package kubeproxy
 
 import (
+	"fmt"
 	"net"
 	"os"
 	"syscall"
@@ -58,17 +59,10 @@ func (kp *SyncHandler) updateRoutingRulesForHostNetworkSupport(inputCidrBlocks [
 
 func (kp *SyncHandler) updateRoutingRulesForCIDRBlock(inputCidrBlock string, operation Operation) {
 	// This must be called with kp.syncHandlerMutex held
-	var viaGW *net.IP
-
-	if kp.isGatewayInRemoteCIDR(inputCidrBlock) {
-		gwIP := kp.remoteSubnetGw[inputCidrBlock]
-
-		routes, err := kp.netLink.RouteGet(gwIP)
-		if err != nil {
-			logger.Errorf(err, "Failed to find route to remote gateway IP %s for cidr %s", gwIP.String(), inputCidrBlock)
-		}
-
-		viaGW = &routes[0].Gw
+	viaGW, err := kp.viaGatewayFor(inputCidrBlock)
+	if err != nil {
+		logger.Errorf(err, "Failed to configure route %q for HostNetwork support on the Gateway node", inputCidrBlock)
+		return
 	}
 
 	switch operation {
@@ -95,6 +89,27 @@ func (kp *SyncHandler) updateRoutingRulesForCIDRBlock(inputCidrBlock string, ope
 	}
 }
 
+// viaGatewayFor returns the next hop required to reach the given remote CIDR, if the remote gateway belongs to it, otherwise
+// nil. An error is returned if the next hop is required but can't be determined.
+func (kp *SyncHandler) viaGatewayFor(remoteCIDR string) (*net.IP, error) {
+	if !kp.isGatewayInRemoteCIDR(remoteCIDR) {
+		return nil, nil
+	}
+
+	gwIP := kp.remoteSubnetGw[remoteCIDR]
+
+	routes, err := kp.netLink.RouteGet(gwIP)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find route to remote gateway IP %s for cidr %s", gwIP.String(), remoteCIDR)
+	}
+
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("no route found to remote gateway IP %s for cidr %s", gwIP.String(), remoteCIDR)
+	}
+
+	return &routes[0].Gw, nil
+}
+
 func (kp *SyncHandler) isGatewayInRemoteCIDR(remoteCIDR string) bool {
 	gwIP, ok := kp.remoteSubnetGw[remoteCIDR]
 	if ok {
@@ -106,11 +121,34 @@ func (kp *SyncHandler) isGatewayInRemoteCIDR(remoteCIDR string) bool {
 }
 
 func (kp *SyncHandler) configureRoute(remoteSubnet string, operation Operation, viaGw *net.IP) error {
+	route, err := kp.hostNetworkRoute(remoteSubnet, viaGw)
+	if err != nil {
+		return err
+	}
+
+	switch operation {
+	case Add:
+		err = kp.netLink.RouteAdd(route)
+		if err != nil && !os.IsExist(err) {
+			return errors.Wrapf(err, "error adding the route %s", route)
+		}
+	case Delete:
+		err = kp.netLink.RouteDel(route)
+		if err != nil {
+			return errors.Wrapf(err, "error deleting the route %s", route)
+		}
+	case Flush:
+	}
+
+	return nil
+}
+
+func (kp *SyncHandler) hostNetworkRoute(remoteSubnet string, viaGw *net.IP) (*netlink.Route, error) {
 	src := net.ParseIP(kp.cniIface.IPAddress)
 
 	_, dst, err := net.ParseCIDR(remoteSubnet)
 	if err != nil {
-		return errors.Wrapf(err, "error parsing cidr block %s", remoteSubnet)
+		return nil, errors.Wrapf(err, "error parsing cidr block %s", remoteSubnet)
 	}
 
 	ifaceIndex := kp.defaultHostIface.Index
@@ -139,21 +177,7 @@ func (kp *SyncHandler) configureRoute(remoteSubnet string, operation Operation,
 		route.Scope = unix.RT_SCOPE_LINK
 	}
 
-	switch operation {
-	case Add:
-		err = kp.netLink.RouteAdd(&route)
-		if err != nil && !os.IsExist(err) {
-			return errors.Wrapf(err, "error adding the route %s", route)
-		}
-	case Delete:
-		err = kp.netLink.RouteDel(&route)
-		if err != nil {
-			return errors.Wrapf(err, "error deleting the route %s", route)
-		}
-	case Flush:
-	}
-
-	return nil
+	return &route, nil
 }
 
 func (kp *SyncHandler) cleanVxSubmarinerRoutes() {
@@ -188,27 +212,27 @@ func (kp *SyncHandler) cleanVxSubmarinerRoutes() {
 	}
 }
 
-// Reconcile the routes installed on this device using rtnetlink.
-func (kp *SyncHandler) reconcileRoutes(vxlanGw net.IP) error {
+// Reconcile the routes installed on this device using rtnetlink. Returns the number of routes that were added or removed.
+func (kp *SyncHandler) reconcileRoutes(vxlanGw net.IP) (int, error) {
 	logger.V(log.DEBUG).Infof("Reconciling routes to gw: %s", vxlanGw.String())
 
 	link, err := kp.netLink.LinkByName(VxLANIface)
 	if err != nil {
-		return errors.Wrapf(err, "error retrieving link by name %s", VxLANIface)
+		return 0, errors.Wrapf(err, "error retrieving link by name %s", VxLANIface)
 	}
 
 	currentRouteList, err := kp.netLink.RouteList(link, syscall.AF_INET)
 	if err != nil {
-		return errors.Wrapf(err, "error retrieving routes for link %s", VxLANIface)
+		return 0, errors.Wrapf(err, "error retrieving routes for link %s", VxLANIface)
 	}
 
 	// First lets delete all of the routes that don't match.
-	kp.removeUnknownRoutes(vxlanGw, currentRouteList)
+	changed := kp.removeUnknownRoutes(vxlanGw, currentRouteList)
 
 	currentRouteList, err = kp.netLink.RouteList(link, syscall.AF_INET)
 
 	if err != nil {
-		return errors.Wrapf(err, "error retrieving routes for link %s", VxLANIface)
+		return changed, errors.Wrapf(err, "error retrieving routes for link %s", VxLANIface)
 	}
 
 	// Let's now add the routes that are missing.
@@ -242,14 +266,18 @@ func (kp *SyncHandler) reconcileRoutes(vxlanGw net.IP) error {
 			err = kp.netLink.RouteAdd(&route)
 			if err != nil {
 				logger.Errorf(err, "Error adding route %s", route)
+			} else {
+				changed++
 			}
 		}
 	}
 
-	return nil
+	return changed, nil
 }
 
-func (kp *SyncHandler) removeUnknownRoutes(vxlanGw net.IP, currentRouteList []netlink.Route) {
+func (kp *SyncHandler) removeUnknownRoutes(vxlanGw net.IP, currentRouteList []netlink.Route) int {
+	removed := 0
+
 	for i := range currentRouteList {
 		// Contains(endpoint destinations, route destination string, and the route gateway is our actual destination.
 		logger.V(log.DEBUG).Infof("Processing route %v", currentRouteList[i])
@@ -263,10 +291,14 @@ func (kp *SyncHandler) removeUnknownRoutes(vxlanGw net.IP, currentRouteList []ne
 				logger.V(log.DEBUG).Infof("Removing route %s", currentRouteList[i])
 				if err := kp.netLink.RouteDel(&currentRouteList[i]); err != nil {
 					logger.Errorf(err, "Error removing route %s", currentRouteList[i])
+				} else {
+					removed++
 				}
 			}
 		}
 	}
+
+	return removed
 }
 
 func (kp *SyncHandler) updateRoutingRulesForInterClusterSupport(remoteCIDRs []string, operation Operation) error {
@@ -276,6 +308,10 @@ func (kp *SyncHandler) updateRoutingRulesForInterClusterSupport(remoteCIDRs []st
 		return nil
 	}
 
+	if kp.directGw != nil {
+		return kp.updateDirectRoutes(remoteCIDRs, operation)
+	}
+
 	if kp.vxlanDevice != nil && kp.vxlanGwIP != nil {
 		link, err := kp.netLink.LinkByName(VxLANIface)
 		if err != nil {
@@ -0,0 +1,34 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeproxy
+
+import (
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/routingpolicy"
+)
+
+// ApplyRoutingPolicy steers the traffic selected by the policy ahead of the VxLAN tunnel to the active gateway: Pod
+// traffic leaves the node through the host routing tables, so the host rules suffice. Policies steering the traffic to
+// another node are withdrawn while this node is the active gateway.
+func (kp *SyncHandler) ApplyRoutingPolicy(policy *routingpolicy.Resolved) error {
+	return kp.routingPolicyGw.ApplyRoutingPolicy(policy) //nolint:wrapcheck  // Let the caller wrap it
+}
+
+func (kp *SyncHandler) RemoveRoutingPolicy(name string) error {
+	return kp.routingPolicyGw.RemoveRoutingPolicy(name) //nolint:wrapcheck  // Let the caller wrap it
+}
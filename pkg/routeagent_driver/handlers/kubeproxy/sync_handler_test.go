@@ -58,6 +58,21 @@ var _ = Describe("SyncHandler", func() {
 	Describe("Gateway transition", testGatewayTransition)
 	Describe("Nodes", testNodes)
 	Describe("Uninstall", testUninstall)
+	Describe("Reconcile", testReconcile)
+	Describe("Direct routing", testDirectRouting)
+})
+
+var _ = Describe("ParseIntraClusterRouting", func() {
+	It("should accept the supported values", func() {
+		Expect(kubeproxy.ParseIntraClusterRouting("vxlan")).To(Equal(kubeproxy.RoutingVxLAN))
+		Expect(kubeproxy.ParseIntraClusterRouting("Direct")).To(Equal(kubeproxy.RoutingDirect))
+		Expect(kubeproxy.ParseIntraClusterRouting("auto")).To(Equal(kubeproxy.RoutingAuto))
+	})
+
+	It("should reject unsupported values", func() {
+		_, err := kubeproxy.ParseIntraClusterRouting("drect")
+		Expect(err).To(HaveOccurred())
+	})
 })
 
 func testEndpoints() {
@@ -350,8 +365,146 @@ func testUninstall() {
 	})
 }
 
+func testReconcile() {
+	t := newTestDriver()
+
+	When("the data path drifts on a non-gateway node", func() {
+		It("should restore the VxLAN routes and iptables rules", func() {
+			t.CreateEndpoint(t.localEndpoint)
+			t.CreateEndpoint(t.remoteEndpoint)
+			t.verifyVxLANRoutes()
+			t.verifyRemoteSubnetIPTableRules()
+
+			link := t.netLink.AwaitLink(kubeproxy.VxLANIface)
+			routes, err := t.netLink.RouteList(link, unix.AF_INET)
+			Expect(err).To(Succeed())
+
+			for i := range routes {
+				Expect(t.netLink.RouteDel(&routes[i])).To(Succeed())
+			}
+
+			t.addVxLANRoute("172.250.1.0/24")
+
+			Expect(t.ipTables.Delete("nat", constants.SmPostRoutingChain, "-s", localClusterCIDR, "-d", remoteSubnet1,
+				"-j", "ACCEPT")).To(Succeed())
+
+			Expect(t.handler.Reconcile(&testing.TestHandlerState{})).To(Succeed())
+
+			t.verifyVxLANRoutes()
+			t.netLink.AwaitNoDstRoutes(t.vxLanInterfaceIndex, 0, "172.250.1.0/24")
+			t.verifyRemoteSubnetIPTableRules()
+		})
+	})
+
+	When("the data path drifts on a gateway node", func() {
+		It("should restore the host networking routes and routing rule", func() {
+			t.CreateEndpoint(t.remoteEndpoint)
+			t.CreateLocalHostEndpoint()
+			t.verifyHostNetworkingRoutes()
+			t.netLink.AwaitRule(constants.RouteAgentHostNetworkTableID, "", "")
+
+			Expect(t.netLink.FlushRouteTable(constants.RouteAgentHostNetworkTableID)).To(Succeed())
+			Expect(t.netLink.RuleDel(netlinkAPI.NewTableRule(constants.RouteAgentHostNetworkTableID))).To(Succeed())
+
+			Expect(t.handler.Reconcile(&testing.TestHandlerState{Gateway: true})).To(Succeed())
+
+			t.verifyHostNetworkingRoutes()
+			t.netLink.AwaitRule(constants.RouteAgentHostNetworkTableID, "", "")
+		})
+	})
+
+	When("the remote gateway IP belongs to a remote subnet and there's no route to it", func() {
+		It("should not fail to reconcile", func() {
+			t.remoteEndpoint.Spec.PrivateIP = "170.250.1.2"
+			t.CreateEndpoint(t.remoteEndpoint)
+			t.CreateLocalHostEndpoint()
+			t.netLink.AwaitRule(constants.RouteAgentHostNetworkTableID, "", "")
+
+			Expect(t.handler.Reconcile(&testing.TestHandlerState{Gateway: true})).To(Succeed())
+		})
+	})
+}
+
+func testDirectRouting() {
+	Describe("in auto mode", testAutoDirectRouting)
+	Describe("in direct mode", testForcedDirectRouting)
+}
+
+func testAutoDirectRouting() {
+	t := newTestDriverWithRouting(kubeproxy.RoutingAuto)
+
+	When("a local Endpoint is created on a non-gateway node and the gateway node is on-link", func() {
+		BeforeEach(func() {
+			t.addGatewayNodeRoute(nil)
+			t.CreateEndpoint(t.remoteEndpoint)
+			t.CreateEndpoint(t.localEndpoint)
+		})
+
+		It("should route the remote subnets via the gateway node IP", func() {
+			t.verifyDirectRoutes()
+			t.netLink.AwaitNoLink(kubeproxy.VxLANIface)
+		})
+
+		Context("and the local Endpoint is subsequently removed", func() {
+			It("should remove the direct routes", func() {
+				t.verifyDirectRoutes()
+				t.DeleteEndpoint(t.localEndpoint.Name)
+				t.netLink.AwaitNoDstRoutes(t.hostInterfaceIndex, 0, t.remoteEndpoint.Spec.Subnets...)
+			})
+		})
+
+		Context("and the direct routes drift", func() {
+			It("should restore them on Reconcile", func() {
+				t.verifyDirectRoutes()
+
+				routes, err := t.netLink.RouteList(&netlink.GenericLink{LinkAttrs: netlink.LinkAttrs{Index: t.hostInterfaceIndex}},
+					unix.AF_INET)
+				Expect(err).To(Succeed())
+
+				for i := range routes {
+					if routes[i].Protocol == kubeproxy.DirectRouteProtocol {
+						Expect(t.netLink.RouteDel(&routes[i])).To(Succeed())
+					}
+				}
+
+				Expect(t.handler.Reconcile(&testing.TestHandlerState{})).To(Succeed())
+				t.verifyDirectRoutes()
+			})
+		})
+	})
+
+	When("a local Endpoint is created on a non-gateway node and the gateway node isn't on-link", func() {
+		BeforeEach(func() {
+			t.addGatewayNodeRoute(net.IPv4(10, 1, 1, 1))
+			t.CreateEndpoint(t.remoteEndpoint)
+		})
+
+		It("should fall back to VxLAN", func() {
+			t.CreateEndpoint(t.localEndpoint)
+			t.verifyVxLANRoutes()
+			t.netLink.AwaitNoDstRoutes(t.hostInterfaceIndex, 0, t.remoteEndpoint.Spec.Subnets...)
+		})
+	})
+}
+
+func testForcedDirectRouting() {
+	t := newTestDriverWithRouting(kubeproxy.RoutingDirect)
+
+	When("a local Endpoint is created on a non-gateway node and the route to the gateway node is unknown", func() {
+		BeforeEach(func() {
+			t.CreateEndpoint(t.remoteEndpoint)
+		})
+
+		It("should route the remote subnets via the gateway node IP on the default interface", func() {
+			t.CreateEndpoint(t.localEndpoint)
+			t.verifyDirectRoutes()
+		})
+	})
+}
+
 type testDriver struct {
 	*testing.ControllerSupport
+	intraClusterRouting kubeproxy.IntraClusterRouting
 	handler             *kubeproxy.SyncHandler
 	ipTables            *fakeIPT.IPTables
 	netLink             *fakeNetlink.NetLink
@@ -362,8 +515,13 @@ type testDriver struct {
 }
 
 func newTestDriver() *testDriver {
+	return newTestDriverWithRouting(kubeproxy.RoutingVxLAN)
+}
+
+func newTestDriverWithRouting(intraClusterRouting kubeproxy.IntraClusterRouting) *testDriver {
 	t := &testDriver{
-		ControllerSupport: testing.NewControllerSupport(),
+		ControllerSupport:   testing.NewControllerSupport(),
+		intraClusterRouting: intraClusterRouting,
 	}
 
 	BeforeEach(func() {
@@ -395,7 +553,7 @@ func newTestDriver() *testDriver {
 		t.localEndpoint = newLocalEndpoint(localNodeName1)
 		t.remoteEndpoint = newRemoteEndpoint()
 
-		t.handler = kubeproxy.NewSyncHandler([]string{localClusterCIDR}, []string{localServiceCIDR})
+		t.handler = kubeproxy.NewSyncHandler([]string{localClusterCIDR}, []string{localServiceCIDR}, t.intraClusterRouting)
 
 		t.Start(t.handler)
 	})
@@ -434,6 +592,20 @@ func (t *testDriver) verifyRemoteSubnetIPTableRules() {
 	}
 }
 
+func (t *testDriver) verifyDirectRoutes() {
+	t.netLink.AwaitGwRoutes(t.hostInterfaceIndex, 0, t.localEndpoint.Spec.PrivateIP)
+	t.netLink.AwaitDstRoutes(t.hostInterfaceIndex, 0, t.remoteEndpoint.Spec.Subnets...)
+}
+
+// addGatewayNodeRoute adds the route that RouteGet returns for the local gateway node IP.
+func (t *testDriver) addGatewayNodeRoute(gw net.IP) {
+	Expect(t.netLink.RouteAdd(&netlink.Route{
+		Dst:       &net.IPNet{IP: net.ParseIP(t.localEndpoint.Spec.PrivateIP), Mask: net.CIDRMask(32, 32)},
+		Gw:        gw,
+		LinkIndex: t.hostInterfaceIndex,
+	})).To(Succeed())
+}
+
 func (t *testDriver) addVxLANRoute(cidr string) {
 	_, dst, err := net.ParseCIDR(cidr)
 	Expect(err).To(Succeed())
@@ -27,6 +27,7 @@ import (
 	"github.com/submariner-io/submariner/pkg/port"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
 	"github.com/vishvananda/netlink"
+	"k8s.io/utils/set"
 )
 
 func (kp *SyncHandler) Uninstall() error {
@@ -46,12 +47,40 @@ func (kp *SyncHandler) Uninstall() error {
 			constants.RouteAgentHostNetworkTableID, err)
 	}
 
+	if err := kp.routingPolicies.Flush(); err != nil {
+		logger.Errorf(err, "Error flushing the RoutingPolicy rules")
+	}
+
+	kp.deleteDirectRoutes()
 	deleteVxLANInterface()
 	deleteIPTableChains()
 
 	return nil
 }
 
+func (kp *SyncHandler) deleteDirectRoutes() {
+	linkIndexes := set.New(kp.defaultHostIface.Index)
+	if kp.directGw != nil {
+		linkIndexes.Insert(kp.directGw.linkIndex)
+	}
+
+	for _, linkIndex := range linkIndexes.UnsortedList() {
+		routes, err := kp.listDirectRoutes(linkIndex)
+		if err != nil {
+			logger.Errorf(err, "Failed to list the direct routes to the gateway node")
+			continue
+		}
+
+		for i := range routes {
+			if err := kp.netLink.RouteDel(&routes[i]); err != nil {
+				logger.Errorf(err, "Failed to delete route %s", routes[i])
+			}
+		}
+	}
+
+	kp.directGw = nil
+}
+
 func deleteVxLANInterface() {
 	iface := &netlink.Vxlan{
 		LinkAttrs: netlink.LinkAttrs{
@@ -27,6 +27,8 @@ import (
 	submV1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/cable/vxlan"
 	"github.com/submariner-io/submariner/pkg/cidr"
+	"github.com/submariner-io/submariner/pkg/client/clientset/versioned"
+	listers "github.com/submariner-io/submariner/pkg/client/listers/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/event"
 	"github.com/submariner-io/submariner/pkg/ipset"
 	"github.com/submariner-io/submariner/pkg/iptables"
@@ -34,6 +36,7 @@ import (
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
 	utilexec "k8s.io/utils/exec"
 	k8snet "k8s.io/utils/net"
+	"k8s.io/utils/set"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -48,6 +51,12 @@ const (
 const (
 	// TCP MSS = Default_Iface_MTU - TCP_H(20)-IP_H(20)-max_IpsecOverhed(80).
 	maxIpsecOverhead = 120
+
+	// TCP MSS = Path_MTU - TCP_H(20)-IP_H(20).
+	tcpIPHeaderSize = 40
+
+	// vxlanIface is the interface the non-gateway nodes route remote traffic through, see the kubeproxy handler.
+	vxlanIface = "vx-submariner"
 )
 
 type mtuHandler struct {
@@ -58,11 +67,24 @@ type mtuHandler struct {
 	localIPSet       ipset.Named
 	forceMss         forceMssSts
 	tcpMssValue      int
+	mssRules         [][]string
+	localSubnets     set.Set[string]
+	submClient       versioned.Interface
+	namespace        string
+	netLink          netlinkAPI.Interface
+	gateways         listers.GatewayLister
+	stopCh           chan struct{}
+	// pathMTURules are the per remote CIDR MSS clamping rules derived from the measured path MTUs.
+	pathMTURules [][]string
+	// routeMTUs maps the remote CIDRs whose route MTU was set to the MTU.
+	routeMTUs map[string]int
 }
 
 var logger = log.Logger{Logger: logf.Log.WithName("MTU")}
 
-func NewMTUHandler(localClusterCidr []string, isGlobalnet bool, tcpMssValue int) event.Handler {
+func NewMTUHandler(localClusterCidr []string, isGlobalnet bool, tcpMssValue int, submClient versioned.Interface,
+	namespace string,
+) event.Handler {
 	forceMss := notNeeded
 	if isGlobalnet || tcpMssValue != 0 {
 		forceMss = needed
@@ -72,6 +94,10 @@ func NewMTUHandler(localClusterCidr []string, isGlobalnet bool, tcpMssValue int)
 		localClusterCidr: cidr.ExtractIPv4Subnets(localClusterCidr),
 		forceMss:         forceMss,
 		tcpMssValue:      tcpMssValue,
+		submClient:       submClient,
+		namespace:        namespace,
+		routeMTUs:        map[string]int{},
+		stopCh:           make(chan struct{}),
 	}
 }
 
@@ -91,6 +117,10 @@ func (h *mtuHandler) Init() error {
 		return errors.Wrap(err, "error initializing iptables")
 	}
 
+	h.netLink = netlinkAPI.New()
+
+	h.startGatewayInformer()
+
 	ipSetIface := ipset.New(utilexec.New())
 
 	if err := h.ipt.CreateChainIfNotExists(constants.MangleTable, constants.SmPostRoutingChain); err != nil {
@@ -141,11 +171,33 @@ func (h *mtuHandler) Init() error {
 		return errors.Wrapf(err, "error appending iptables rule %q", strings.Join(ruleSpecSource, " "))
 	}
 
+	h.mssRules = [][]string{ruleSpecSource, ruleSpecDest}
+
 	return nil
 }
 
+func (h *mtuHandler) Stop() error {
+	close(h.stopCh)
+
+	return nil
+}
+
+func (h *mtuHandler) TransitionToGateway() error {
+	h.routeMTUs = map[string]int{}
+
+	return h.applyPathMTUs()
+}
+
+func (h *mtuHandler) TransitionToNonGateway() error {
+	h.routeMTUs = map[string]int{}
+
+	return h.applyPathMTUs()
+}
+
 func (h *mtuHandler) LocalEndpointCreated(endpoint *submV1.Endpoint) error {
 	subnets := extractIPv4Subnets(&endpoint.Spec)
+	h.localSubnets = set.New(append(subnets, h.localClusterCidr...)...)
+
 	for _, subnet := range subnets {
 		err := h.localIPSet.AddEntry(subnet, true)
 		if err != nil {
@@ -175,6 +227,8 @@ func (h *mtuHandler) LocalEndpointCreated(endpoint *submV1.Endpoint) error {
 }
 
 func (h *mtuHandler) LocalEndpointRemoved(endpoint *submV1.Endpoint) error {
+	h.localSubnets = nil
+
 	subnets := extractIPv4Subnets(&endpoint.Spec)
 	for _, subnet := range subnets {
 		err := h.localIPSet.DelEntry(subnet)
@@ -202,7 +256,11 @@ func (h *mtuHandler) RemoteEndpointCreated(endpoint *submV1.Endpoint) error {
 		}
 	}
 
-	return nil
+	return h.applyPathMTUs()
+}
+
+func (h *mtuHandler) RemoteEndpointUpdated(_ *submV1.Endpoint) error {
+	return h.applyPathMTUs()
 }
 
 func (h *mtuHandler) RemoteEndpointRemoved(endpoint *submV1.Endpoint) error {
@@ -310,10 +368,163 @@ func (h *mtuHandler) forceMssClamping(endpoint *submV1.Endpoint) error {
 		"--set-mss", strconv.Itoa(tcpMssValue),
 	}
 
-	if err := h.ipt.UpdateChainRules(constants.MangleTable, constants.SmPostRoutingChain,
-		[][]string{ruleSpecSource, ruleSpecDest}); err != nil {
+	h.mssRules = [][]string{ruleSpecSource, ruleSpecDest}
+
+	return h.updateChainRules()
+}
+
+func (h *mtuHandler) updateChainRules() error {
+	rules := h.chainRules()
+
+	if err := h.ipt.UpdateChainRules(constants.MangleTable, constants.SmPostRoutingChain, rules); err != nil {
 		return errors.Wrapf(err, "error updating chain %s table %s rules", constants.SmPostRoutingChain, constants.MangleTable)
 	}
 
 	return nil
 }
+
+func (h *mtuHandler) chainRules() [][]string {
+	rules := make([][]string, 0, len(h.pathMTURules)+len(h.mssRules))
+	rules = append(rules, h.pathMTURules...)
+
+	return append(rules, h.mssRules...)
+}
+
+func (h *mtuHandler) Reconcile(state event.HandlerState) error {
+	drifted, err := h.reconcileIPTables()
+	event.RecordDriftCorrected(h.GetName(), event.DriftIPTables, drifted)
+
+	if err != nil {
+		return err
+	}
+
+	remoteSubnets := set.New[string]()
+
+	remoteEndpoints := state.GetRemoteEndpoints()
+	for i := range remoteEndpoints {
+		remoteSubnets.Insert(extractIPv4Subnets(&remoteEndpoints[i].Spec)...)
+	}
+
+	drifted, err = reconcileIPSet(h.remoteIPSet, remoteSubnets)
+	if err == nil && h.localSubnets != nil {
+		var localDrifted int
+
+		localDrifted, err = reconcileIPSet(h.localIPSet, h.localSubnets)
+		drifted += localDrifted
+	}
+
+	event.RecordDriftCorrected(h.GetName(), event.DriftIPSets, drifted)
+
+	if err != nil {
+		return err
+	}
+
+	return h.applyPathMTUs()
+}
+
+func (h *mtuHandler) reconcileIPTables() (int, error) {
+	drifted := 0
+
+	exists, err := h.ipt.ChainExists(constants.MangleTable, constants.SmPostRoutingChain)
+	if err != nil {
+		return drifted, errors.Wrapf(err, "error checking for iptables chain %s", constants.SmPostRoutingChain)
+	}
+
+	if !exists {
+		if err := h.ipt.CreateChainIfNotExists(constants.MangleTable, constants.SmPostRoutingChain); err != nil {
+			return drifted, errors.Wrapf(err, "error creating iptables chain %s", constants.SmPostRoutingChain)
+		}
+
+		drifted++
+	}
+
+	forwardToSubMarinerPostRoutingChain := []string{"-j", constants.SmPostRoutingChain}
+
+	exists, err = h.ipt.Exists(constants.MangleTable, constants.PostRoutingChain, forwardToSubMarinerPostRoutingChain...)
+	if err != nil {
+		return drifted, errors.Wrapf(err, "error checking for iptables rule %q",
+			strings.Join(forwardToSubMarinerPostRoutingChain, " "))
+	}
+
+	if !exists {
+		if err := h.ipt.PrependUnique(constants.MangleTable, constants.PostRoutingChain,
+			forwardToSubMarinerPostRoutingChain); err != nil {
+			return drifted, errors.Wrapf(err, "error inserting iptables rule %q",
+				strings.Join(forwardToSubMarinerPostRoutingChain, " "))
+		}
+
+		drifted++
+	}
+
+	for _, ruleSpec := range h.chainRules() {
+		exists, err := h.ipt.Exists(constants.MangleTable, constants.SmPostRoutingChain, ruleSpec...)
+		if err != nil {
+			return drifted, errors.Wrapf(err, "error checking for iptables rule %q", strings.Join(ruleSpec, " "))
+		}
+
+		if exists {
+			continue
+		}
+
+		if err := h.ipt.AppendUnique(constants.MangleTable, constants.SmPostRoutingChain, ruleSpec...); err != nil {
+			return drifted, errors.Wrapf(err, "error appending iptables rule %q", strings.Join(ruleSpec, " "))
+		}
+
+		drifted++
+	}
+
+	return drifted, nil
+}
+
+func (h *mtuHandler) OwnedState() event.OwnedState {
+	remoteSubnets := set.New[string]()
+
+	remoteEndpoints := h.State().GetRemoteEndpoints()
+	for i := range remoteEndpoints {
+		remoteSubnets.Insert(extractIPv4Subnets(&remoteEndpoints[i].Spec)...)
+	}
+
+	entries := map[string][]string{
+		constants.RemoteCIDRIPSet: remoteSubnets.SortedList(),
+	}
+
+	if h.localSubnets != nil {
+		entries[constants.LocalCIDRIPSet] = h.localSubnets.SortedList()
+	}
+
+	return event.OwnedState{IPSetEntries: entries}
+}
+
+// reconcileIPSet ensures the named set contains exactly the desired entries and returns the number of entries added
+// or removed.
+func reconcileIPSet(named ipset.Named, desired set.Set[string]) (int, error) {
+	if err := named.Create(true); err != nil {
+		return 0, errors.Wrapf(err, "error creating ipset %q", named.Name())
+	}
+
+	entries, err := named.ListEntries()
+	if err != nil {
+		return 0, errors.Wrapf(err, "error listing the entries of ipset %q", named.Name())
+	}
+
+	existing := set.New(entries...)
+	drifted := 0
+
+	for _, entry := range desired.Difference(existing).UnsortedList() {
+		if err := named.AddEntry(entry, true); err != nil {
+			return drifted, errors.Wrapf(err, "error adding entry %q to ipset %q", entry, named.Name())
+		}
+
+		drifted++
+	}
+
+	for _, entry := range existing.Difference(desired).UnsortedList() {
+		if err := named.DelEntry(entry); err != nil {
+			return drifted, errors.Wrapf(err, "error deleting entry %q from ipset %q", entry, named.Name())
+		}
+
+		drifted++
+	}
+
+	return drifted, nil
+}
@@ -18,23 +18,40 @@ limitations under the License.
 package mtu_test
 
 import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	submV1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	fakeClient "github.com/submariner-io/submariner/pkg/client/clientset/versioned/fake"
 	"github.com/submariner-io/submariner/pkg/event"
+	eventtesting "github.com/submariner-io/submariner/pkg/event/testing"
 	"github.com/submariner-io/submariner/pkg/ipset"
 	fakeSet "github.com/submariner-io/submariner/pkg/ipset/fake"
 	"github.com/submariner-io/submariner/pkg/iptables"
 	fakeIPT "github.com/submariner-io/submariner/pkg/iptables/fake"
+	netlinkAPI "github.com/submariner-io/submariner/pkg/netlink"
+	fakeNetlink "github.com/submariner-io/submariner/pkg/netlink/fake"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/handlers/mtu"
+	"github.com/vishvananda/netlink"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/testing"
 )
 
+const namespace = "submariner-operator"
+
 var _ = Describe("MTUHandler", func() {
 	var (
-		ipt     *fakeIPT.IPTables
-		ipSet   *fakeSet.IPSet
-		handler event.Handler
+		ipt        *fakeIPT.IPTables
+		ipSet      *fakeSet.IPSet
+		netLink    *fakeNetlink.NetLink
+		submClient *fakeClient.Clientset
+		handler    event.Handler
 	)
 
 	BeforeEach(func() {
@@ -46,11 +63,19 @@ var _ = Describe("MTUHandler", func() {
 		ipset.NewFunc = func() ipset.Interface {
 			return ipSet
 		}
-		handler = mtu.NewMTUHandler([]string{"10.1.0.0/24"}, false, 0)
+		netLink = fakeNetlink.New()
+		netlinkAPI.NewFunc = func() netlinkAPI.Interface {
+			return netLink
+		}
+		submClient = fakeClient.NewSimpleClientset()
+		handler = mtu.NewMTUHandler([]string{"10.1.0.0/24"}, false, 0, submClient, namespace)
 	})
 
 	AfterEach(func() {
+		Expect(handler.Stop()).To(Succeed())
+
 		iptables.NewFunc = nil
+		netlinkAPI.NewFunc = nil
 	})
 
 	When("endpoint is added and removed", func() {
@@ -82,6 +107,174 @@ var _ = Describe("MTUHandler", func() {
 			}
 		})
 	})
+
+	When("the data path drifts from the desired state", func() {
+		It("should correct it on reconciliation", func() {
+			Expect(handler.Init()).To(Succeed())
+
+			localEndpoint := newSubmEndpoint([]string{"172.1.0.0/24"})
+			Expect(handler.LocalEndpointCreated(localEndpoint)).To(Succeed())
+
+			remoteEndpoint := newSubmEndpoint([]string{"10.0.0.0/24"})
+			Expect(handler.RemoteEndpointCreated(remoteEndpoint)).To(Succeed())
+
+			Expect(ipt.Delete(constants.MangleTable, constants.PostRoutingChain, "-j", constants.SmPostRoutingChain)).To(Succeed())
+			Expect(ipt.ClearChain(constants.MangleTable, constants.SmPostRoutingChain)).To(Succeed())
+			Expect(ipSet.DelEntry("172.1.0.0/24", constants.LocalCIDRIPSet)).To(Succeed())
+			Expect(ipSet.DelEntry("10.0.0.0/24", constants.RemoteCIDRIPSet)).To(Succeed())
+			Expect(ipSet.AddEntry("10.9.0.0/24", &ipset.IPSet{Name: constants.RemoteCIDRIPSet}, true)).To(Succeed())
+
+			Expect(handler.Reconcile(&eventtesting.TestHandlerState{
+				RemoteEndpoints: []submV1.Endpoint{*remoteEndpoint},
+			})).To(Succeed())
+
+			ipt.AwaitRule(constants.MangleTable, constants.PostRoutingChain, ContainSubstring(constants.SmPostRoutingChain))
+			ipt.AwaitRule(constants.MangleTable, constants.SmPostRoutingChain, ContainSubstring(constants.RemoteCIDRIPSet+" src"))
+			ipt.AwaitRule(constants.MangleTable, constants.SmPostRoutingChain, ContainSubstring(constants.RemoteCIDRIPSet+" dst"))
+			ipSet.AwaitEntry(constants.LocalCIDRIPSet, "172.1.0.0/24")
+			ipSet.AwaitEntry(constants.LocalCIDRIPSet, "10.1.0.0/24")
+			ipSet.AwaitEntry(constants.RemoteCIDRIPSet, "10.0.0.0/24")
+			ipSet.AwaitEntryDeleted(constants.RemoteCIDRIPSet, "10.9.0.0/24")
+		})
+	})
+
+	When("the active gateway publishes the path MTU to a remote cluster", func() {
+		const remoteCIDR = "10.0.0.0/24"
+
+		var vxlanLink *netlink.Vxlan
+
+		BeforeEach(func() {
+			vxlanLink = &netlink.Vxlan{LinkAttrs: netlink.LinkAttrs{Name: "vx-submariner", MTU: 1450}}
+			netLink.SetLinkIndex(vxlanLink.Name, 7)
+			Expect(netLink.LinkAdd(vxlanLink)).To(Succeed())
+
+			_, dst, _ := net.ParseCIDR(remoteCIDR)
+			Expect(netLink.RouteAdd(&netlink.Route{LinkIndex: 7, Dst: dst, Gw: net.ParseIP("240.1.0.1")})).To(Succeed())
+
+			_, err := submClient.SubmarinerV1().Gateways(namespace).Create(context.TODO(), &submV1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "gw-node"},
+				Status: submV1.GatewayStatus{
+					HAStatus: submV1.HAStatusActive,
+					Connections: []submV1.Connection{{
+						Status:   submV1.Connected,
+						Endpoint: submV1.EndpointSpec{Subnets: []string{remoteCIDR}},
+						PathMTU:  1380,
+					}},
+				},
+			}, metav1.CreateOptions{})
+			Expect(err).To(Succeed())
+
+			Expect(handler.Init()).To(Succeed())
+			Expect(handler.RemoteEndpointCreated(newSubmEndpoint([]string{remoteCIDR}))).To(Succeed())
+		})
+
+		It("should clamp the TCP MSS for the remote CIDR", func() {
+			ipt.AwaitRule(constants.MangleTable, constants.SmPostRoutingChain,
+				And(ContainSubstring("-d "+remoteCIDR), ContainSubstring("--set-mss 1340")))
+			ipt.AwaitRule(constants.MangleTable, constants.SmPostRoutingChain,
+				And(ContainSubstring("-s "+remoteCIDR), ContainSubstring("--set-mss 1340")))
+			ipt.AwaitRule(constants.MangleTable, constants.SmPostRoutingChain, ContainSubstring("--clamp-mss-to-pmtu"))
+		})
+
+		It("should set the MTU of the route to the remote CIDR", func() {
+			Eventually(func() int {
+				routes, err := netLink.RouteList(vxlanLink, netlink.FAMILY_V4)
+				Expect(err).To(Succeed())
+				Expect(routes).To(HaveLen(1))
+
+				return routes[0].MTU
+			}).Should(Equal(1380))
+		})
+	})
+})
+
+var _ = Describe("MTUHandler path MTUs", func() {
+	const remoteCIDR = "10.0.0.0/24"
+
+	var (
+		ipt        *fakeIPT.IPTables
+		ipSet      *fakeSet.IPSet
+		netLink    *fakeNetlink.NetLink
+		submClient *fakeClient.Clientset
+		handler    event.Handler
+	)
+
+	BeforeEach(func() {
+		ipt = fakeIPT.New()
+		iptables.NewFunc = func() (iptables.Interface, error) {
+			return ipt, nil
+		}
+		ipSet = fakeSet.New()
+		ipset.NewFunc = func() ipset.Interface {
+			return ipSet
+		}
+		netLink = fakeNetlink.New()
+		netlinkAPI.NewFunc = func() netlinkAPI.Interface {
+			return netLink
+		}
+		submClient = fakeClient.NewSimpleClientset()
+
+		_, err := submClient.SubmarinerV1().Gateways(namespace).Create(context.TODO(), &submV1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "gw-node"},
+			Status: submV1.GatewayStatus{
+				HAStatus: submV1.HAStatusActive,
+				Connections: []submV1.Connection{{
+					Status:   submV1.Connected,
+					Endpoint: submV1.EndpointSpec{Subnets: []string{remoteCIDR}},
+					PathMTU:  1380,
+				}},
+			},
+		}, metav1.CreateOptions{})
+		Expect(err).To(Succeed())
+
+		handler = mtu.NewMTUHandler([]string{"10.1.0.0/24"}, false, 0, submClient, namespace)
+	})
+
+	AfterEach(func() {
+		Expect(handler.Stop()).To(Succeed())
+
+		iptables.NewFunc = nil
+		netlinkAPI.NewFunc = nil
+	})
+
+	When("on the gateway node", func() {
+		It("should set the MTU of the host network route to the remote CIDR", func() {
+			_, dst, _ := net.ParseCIDR(remoteCIDR)
+			Expect(netLink.RouteAdd(&netlink.Route{
+				LinkIndex: 2, Dst: dst, Table: constants.RouteAgentHostNetworkTableID,
+			})).To(Succeed())
+
+			handler.SetState(&eventtesting.TestHandlerState{Gateway: true})
+			Expect(handler.Init()).To(Succeed())
+			Expect(handler.RemoteEndpointCreated(newSubmEndpoint([]string{remoteCIDR}))).To(Succeed())
+
+			Eventually(func() int {
+				routes, err := netLink.RouteListTable(constants.RouteAgentHostNetworkTableID, netlink.FAMILY_V4)
+				Expect(err).To(Succeed())
+				Expect(routes).To(HaveLen(1))
+
+				return routes[0].MTU
+			}).Should(Equal(1380))
+		})
+	})
+
+	When("the Gateways can't be listed", func() {
+		BeforeEach(func() {
+			DeferCleanup(mtu.SetGatewaySyncTimeout(100 * time.Millisecond))
+
+			submClient.PrependReactor("list", "gateways", func(_ testing.Action) (bool, runtime.Object, error) {
+				return true, nil, errors.New("fake list error")
+			})
+		})
+
+		It("should still handle the remote endpoint without path MTUs", func() {
+			Expect(handler.Init()).To(Succeed())
+			Expect(handler.RemoteEndpointCreated(newSubmEndpoint([]string{remoteCIDR}))).To(Succeed())
+
+			ipSet.AwaitEntry(constants.RemoteCIDRIPSet, remoteCIDR)
+			ipt.AwaitNoRule(constants.MangleTable, constants.SmPostRoutingChain, ContainSubstring("-d "+remoteCIDR))
+		})
+	})
 })
 
 func newSubmEndpoint(subnets []string) *submV1.Endpoint {
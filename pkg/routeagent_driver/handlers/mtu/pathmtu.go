@@ -0,0 +1,237 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mtu
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	submV1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	informers "github.com/submariner-io/submariner/pkg/client/informers/externalversions"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
+	"github.com/vishvananda/netlink"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// gatewaySyncTimeout is how long the handler waits for the Gateway informer cache to sync on startup.
+var gatewaySyncTimeout = 30 * time.Second
+
+// applyPathMTUs programs a TCP MSS clamp and a route MTU for each remote CIDR whose path MTU was measured by the
+// active gateway. The clamps complement the cluster wide ones: TCPMSS only ever lowers the MSS so the smallest wins.
+func (h *mtuHandler) applyPathMTUs() error {
+	pathMTUs := h.measuredPathMTUs()
+
+	rules := pathMTURules(pathMTUs)
+	if !equalRules(rules, h.pathMTURules) {
+		logger.Infof("Updating the per remote CIDR TCP MSS clamping for path MTUs %v", pathMTUs)
+
+		h.pathMTURules = rules
+
+		if err := h.updateChainRules(); err != nil {
+			return err
+		}
+	}
+
+	return h.updateRouteMTUs(pathMTUs)
+}
+
+// startGatewayInformer starts the informer the Gateways, and the path MTUs they publish, are read from. The handler doesn't
+// wait indefinitely for it to sync: until it does, no path MTUs are applied and they're picked up on the next event or
+// reconciliation.
+func (h *mtuHandler) startGatewayInformer() {
+	if h.submClient == nil {
+		return
+	}
+
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(h.submClient, 0, informers.WithNamespace(h.namespace))
+	h.gateways = informerFactory.Submariner().V1().Gateways().Lister()
+
+	informerFactory.Start(h.stopCh)
+
+	ctx, cancel := context.WithTimeout(context.Background(), gatewaySyncTimeout)
+	defer cancel()
+
+	for _, synced := range informerFactory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			logger.Warning("Timed out waiting for the Gateway informer cache to sync - the path MTUs will be applied once it has")
+		}
+	}
+}
+
+// measuredPathMTUs returns the path MTUs published by the active gateway, keyed by remote IPv4 CIDR. If the Gateways can't
+// be listed, no path MTUs are returned so the other MTU settings are still applied.
+func (h *mtuHandler) measuredPathMTUs() map[string]int {
+	pathMTUs := map[string]int{}
+
+	if h.gateways == nil {
+		return pathMTUs
+	}
+
+	gateways, err := h.gateways.Gateways(h.namespace).List(labels.Everything())
+	if err != nil {
+		logger.Errorf(err, "Error listing Gateways - no path MTUs will be applied")
+		return pathMTUs
+	}
+
+	for i := range gateways {
+		if gateways[i].Status.HAStatus != submV1.HAStatusActive {
+			continue
+		}
+
+		for j := range gateways[i].Status.Connections {
+			connection := &gateways[i].Status.Connections[j]
+			if connection.PathMTU == 0 {
+				continue
+			}
+
+			for _, subnet := range extractIPv4Subnets(&connection.Endpoint) {
+				pathMTUs[subnet] = connection.PathMTU
+			}
+		}
+	}
+
+	return pathMTUs
+}
+
+func pathMTURules(pathMTUs map[string]int) [][]string {
+	remoteCIDRs := make([]string, 0, len(pathMTUs))
+	for remoteCIDR := range pathMTUs {
+		remoteCIDRs = append(remoteCIDRs, remoteCIDR)
+	}
+
+	sort.Strings(remoteCIDRs)
+
+	rules := make([][]string, 0, 2*len(remoteCIDRs))
+
+	for _, remoteCIDR := range remoteCIDRs {
+		mss := strconv.Itoa(pathMTUs[remoteCIDR] - tcpIPHeaderSize)
+
+		rules = append(rules, []string{
+			"-m", "set", "--match-set", constants.LocalCIDRIPSet, "src", "-d", remoteCIDR, "-p", "tcp", "-m", "tcp",
+			"--tcp-flags", "SYN,RST", "SYN", "-j", "TCPMSS", "--set-mss", mss,
+		}, []string{
+			"-s", remoteCIDR, "-m", "set", "--match-set", constants.LocalCIDRIPSet, "dst", "-p", "tcp", "-m", "tcp",
+			"--tcp-flags", "SYN,RST", "SYN", "-j", "TCPMSS", "--set-mss", mss,
+		})
+	}
+
+	return rules
+}
+
+// updateRouteMTUs sets the MTU of the routes to the remote CIDRs, so that non-TCP traffic gets too big errors at the
+// source rather than being dropped along the path. On the non-gateway nodes, these are the routes through the VxLAN
+// interface. On the gateway node, they're the routes in the host network table which carry the traffic from the gateway
+// node itself, including UDP, to the cable.
+func (h *mtuHandler) updateRouteMTUs(pathMTUs map[string]int) error {
+	routes, maxMTU, err := h.remoteRoutes()
+	if err != nil {
+		return err
+	}
+
+	applied := map[string]int{}
+
+	for i := range routes {
+		route := &routes[i]
+		if route.Dst == nil {
+			continue
+		}
+
+		dst := route.Dst.String()
+
+		mtu, ok := pathMTUs[dst]
+		if !ok || (maxMTU > 0 && mtu >= maxMTU) {
+			// Restore the routes we set an MTU on that are no longer measured.
+			if _, set := h.routeMTUs[dst]; !set {
+				continue
+			}
+
+			mtu = 0
+		}
+
+		if mtu != 0 {
+			applied[dst] = mtu
+		}
+
+		if route.MTU == mtu {
+			continue
+		}
+
+		route.MTU = mtu
+
+		if err := h.netLink.RouteReplace(route); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "error setting the MTU of route %s", route)
+		}
+	}
+
+	h.routeMTUs = applied
+
+	return nil
+}
+
+// remoteRoutes returns the routes to the remote CIDRs on this node and the MTU they can't exceed, if any.
+func (h *mtuHandler) remoteRoutes() ([]netlink.Route, int, error) {
+	if h.State().IsOnGateway() {
+		routes, err := h.netLink.RouteListTable(constants.RouteAgentHostNetworkTableID, netlink.FAMILY_V4)
+		if err != nil {
+			return nil, 0, errors.Wrapf(err, "error listing the routes in table %d", constants.RouteAgentHostNetworkTableID)
+		}
+
+		return routes, 0, nil
+	}
+
+	link, err := h.netLink.LinkByName(vxlanIface)
+	if err != nil {
+		if errors.Is(err, netlink.LinkNotFoundError{}) {
+			return nil, 0, nil
+		}
+
+		return nil, 0, errors.Wrapf(err, "error retrieving link %q", vxlanIface)
+	}
+
+	routes, err := h.netLink.RouteList(link, netlink.FAMILY_V4)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "error listing the routes on %q", vxlanIface)
+	}
+
+	return routes, link.Attrs().MTU, nil
+}
+
+func equalRules(a, b [][]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+
+		for j := range a[i] {
+			if a[i][j] != b[i][j] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,381 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netpol
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/admiral/pkg/watcher"
+	submV1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/cni"
+	"github.com/submariner-io/submariner/pkg/event"
+	"github.com/submariner-io/submariner/pkg/ipset"
+	"github.com/submariner-io/submariner/pkg/iptables"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilexec "k8s.io/utils/exec"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// NetworkPolicyChain filters the traffic from the remote clusters according to the InterClusterNetworkPolicies.
+	NetworkPolicyChain = "SUBMARINER-NETPOL"
+
+	// IPSetPrefix prefixes the names of all the IP sets maintained by the handler.
+	IPSetPrefix = "SUBMARINER-NP-"
+
+	// RemoteCIDRIPSet contains the subnets of all the remote clusters.
+	RemoteCIDRIPSet = IPSetPrefix + "REMOTE"
+)
+
+var logger = log.Logger{Logger: logf.Log.WithName("NetworkPolicy")}
+
+type HandlerConfig struct {
+	WatcherConfig *watcher.Config
+}
+
+// The network policy handler enforces InterClusterNetworkPolicies on the gateway node, through which all the traffic
+// from the remote clusters enters the cluster. The local Pods selected by the policies are grouped by the set of
+// policies selecting them; for each group, new connections from the remote clusters that aren't from one of the
+// group's allowed remote CIDRs are dropped in the FORWARD chain. The destination is matched after the PREROUTING
+// DNAT, so with Globalnet the Pod IPs are matched against the remote clusters' global IPs. The policies, Pods and
+// Namespaces are only watched while the node is the active gateway.
+type handler struct {
+	event.HandlerBase
+	mutex        sync.Mutex
+	config       HandlerConfig
+	ipt          iptables.Interface
+	ipSetIface   ipset.Interface
+	stopCh       chan struct{}
+	policies     map[string]*submV1.InterClusterNetworkPolicy
+	pods         map[string]*corev1.Pod
+	namespaces   map[string]map[string]string
+	rules        map[string][]string
+	cachesSynced bool
+}
+
+func NewHandler(config *HandlerConfig) event.Handler {
+	return &handler{
+		config: *config,
+	}
+}
+
+func (h *handler) GetNetworkPlugins() []string {
+	networkPlugins := []string{}
+
+	// OVN-Kubernetes doesn't forward the inter-cluster traffic through the host's FORWARD chain.
+	for _, plugin := range cni.GetNetworkPlugins() {
+		if plugin != cni.OVNKubernetes {
+			networkPlugins = append(networkPlugins, plugin)
+		}
+	}
+
+	return networkPlugins
+}
+
+func (h *handler) GetName() string {
+	return "Inter-cluster network policy handler"
+}
+
+func (h *handler) Init() error {
+	var err error
+
+	h.ipt, err = iptables.New()
+	if err != nil {
+		return errors.Wrap(err, "error initializing iptables")
+	}
+
+	h.ipSetIface = ipset.New(utilexec.New())
+
+	if err := h.ensureChain(); err != nil {
+		return err
+	}
+
+	// The rules are tracked in memory so start from a clean slate, the chain is repopulated once the node becomes
+	// the active gateway.
+	if err := h.ipt.ClearChain(constants.FilterTable, NetworkPolicyChain); err != nil {
+		return errors.Wrapf(err, "error flushing iptables chain %s", NetworkPolicyChain)
+	}
+
+	return h.destroyIPSetsExcept(nil)
+}
+
+func (h *handler) Stop() error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.stopWatching()
+
+	return nil
+}
+
+func (h *handler) TransitionToGateway() error {
+	if err := h.startWatching(); err != nil {
+		return err
+	}
+
+	return h.sync()
+}
+
+func (h *handler) TransitionToNonGateway() error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.stopWatching()
+
+	if err := h.updateRules(nil); err != nil {
+		return err
+	}
+
+	return h.destroyIPSetsExcept(nil)
+}
+
+func (h *handler) RemoteEndpointCreated(_ *submV1.Endpoint) error {
+	return h.sync()
+}
+
+func (h *handler) RemoteEndpointUpdated(_ *submV1.Endpoint) error {
+	return h.sync()
+}
+
+func (h *handler) RemoteEndpointRemoved(_ *submV1.Endpoint) error {
+	return h.sync()
+}
+
+func (h *handler) Reconcile(_ event.HandlerState) error {
+	if err := h.ensureChain(); err != nil {
+		return err
+	}
+
+	return h.sync()
+}
+
+// startWatching starts watching the policies, Pods and Namespaces with fresh caches, if not already watching.
+func (h *handler) startWatching() error {
+	h.mutex.Lock()
+
+	if h.stopCh != nil {
+		h.mutex.Unlock()
+		return nil
+	}
+
+	h.stopCh = make(chan struct{})
+	stopCh := h.stopCh
+	h.policies = map[string]*submV1.InterClusterNetworkPolicy{}
+	h.pods = map[string]*corev1.Pod{}
+	h.namespaces = map[string]map[string]string{}
+
+	h.mutex.Unlock()
+
+	config := *h.config.WatcherConfig
+	config.ResourceConfigs = []watcher.ResourceConfig{
+		{
+			Name:         "InterClusterNetworkPolicy watcher",
+			ResourceType: &submV1.InterClusterNetworkPolicy{},
+			Handler: watcher.EventHandlerFuncs{
+				OnCreateFunc: h.policyCreatedOrUpdated,
+				OnUpdateFunc: h.policyCreatedOrUpdated,
+				OnDeleteFunc: h.policyDeleted,
+			},
+		},
+		{
+			Name:         "Pod watcher for inter-cluster network policies",
+			ResourceType: &corev1.Pod{},
+			Handler: watcher.EventHandlerFuncs{
+				OnCreateFunc: h.podCreatedOrUpdated,
+				OnUpdateFunc: h.podCreatedOrUpdated,
+				OnDeleteFunc: h.podDeleted,
+			},
+		},
+		{
+			Name:         "Namespace watcher for inter-cluster network policies",
+			ResourceType: &corev1.Namespace{},
+			Handler: watcher.EventHandlerFuncs{
+				OnCreateFunc: h.namespaceCreatedOrUpdated,
+				OnUpdateFunc: h.namespaceCreatedOrUpdated,
+				OnDeleteFunc: h.namespaceDeleted,
+			},
+		},
+	}
+
+	resourceWatcher, err := watcher.New(&config)
+	if err == nil {
+		err = resourceWatcher.Start(stopCh)
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if err != nil {
+		h.stopWatching()
+		return errors.Wrap(err, "error starting the resource watcher")
+	}
+
+	h.cachesSynced = h.stopCh == stopCh
+
+	return nil
+}
+
+// stopWatching stops the watchers, if running. The mutex must be held.
+func (h *handler) stopWatching() {
+	if h.stopCh == nil {
+		return
+	}
+
+	close(h.stopCh)
+	h.stopCh = nil
+	h.cachesSynced = false
+}
+
+func (h *handler) Uninstall() error {
+	logger.Infof("Deleting the iptables %q chain and the %q IP sets", NetworkPolicyChain, IPSetPrefix)
+
+	ruleSpec := []string{"-j", NetworkPolicyChain}
+	if err := h.ipt.Delete(constants.FilterTable, constants.ForwardChain, ruleSpec...); err != nil {
+		logger.Errorf(err, "Error deleting iptables rule from %q chain", constants.ForwardChain)
+	}
+
+	if err := h.ipt.ClearChain(constants.FilterTable, NetworkPolicyChain); err != nil {
+		logger.Errorf(err, "Error flushing iptables chain %q", NetworkPolicyChain)
+	}
+
+	if err := h.ipt.DeleteChain(constants.FilterTable, NetworkPolicyChain); err != nil {
+		logger.Errorf(err, "Error deleting iptables chain %q", NetworkPolicyChain)
+	}
+
+	if err := h.destroyIPSetsExcept(nil); err != nil {
+		logger.Error(err, "Error deleting the IP sets")
+	}
+
+	return nil
+}
+
+func (h *handler) ensureChain() error {
+	if err := h.ipt.CreateChainIfNotExists(constants.FilterTable, NetworkPolicyChain); err != nil {
+		return errors.Wrapf(err, "error creating iptables chain %s", NetworkPolicyChain)
+	}
+
+	forwardToNetworkPolicyChain := []string{"-j", NetworkPolicyChain}
+
+	if err := h.ipt.PrependUnique(constants.FilterTable, constants.ForwardChain, forwardToNetworkPolicyChain); err != nil {
+		return errors.Wrapf(err, "error inserting iptables rule %q", strings.Join(forwardToNetworkPolicyChain, " "))
+	}
+
+	return nil
+}
+
+func (h *handler) policyCreatedOrUpdated(obj runtime.Object, _ int) bool {
+	policy := obj.(*submV1.InterClusterNetworkPolicy)
+
+	h.mutex.Lock()
+	h.policies[policy.Name] = policy
+	h.mutex.Unlock()
+
+	return h.resync()
+}
+
+func (h *handler) policyDeleted(obj runtime.Object, _ int) bool {
+	h.mutex.Lock()
+	delete(h.policies, obj.(*submV1.InterClusterNetworkPolicy).Name)
+	h.mutex.Unlock()
+
+	return h.resync()
+}
+
+func (h *handler) podCreatedOrUpdated(obj runtime.Object, _ int) bool {
+	pod := obj.(*corev1.Pod)
+	key := pod.Namespace + "/" + pod.Name
+
+	h.mutex.Lock()
+
+	existing, found := h.pods[key]
+	h.pods[key] = pod
+
+	h.mutex.Unlock()
+
+	if found && existing.Status.PodIP == pod.Status.PodIP && existing.Status.Phase == pod.Status.Phase &&
+		labelsEqual(existing.Labels, pod.Labels) {
+		return false
+	}
+
+	return h.resync()
+}
+
+func (h *handler) podDeleted(obj runtime.Object, _ int) bool {
+	pod := obj.(*corev1.Pod)
+
+	h.mutex.Lock()
+	delete(h.pods, pod.Namespace+"/"+pod.Name)
+	h.mutex.Unlock()
+
+	return h.resync()
+}
+
+func (h *handler) namespaceCreatedOrUpdated(obj runtime.Object, _ int) bool {
+	namespace := obj.(*corev1.Namespace)
+
+	h.mutex.Lock()
+
+	existing, found := h.namespaces[namespace.Name]
+	h.namespaces[namespace.Name] = namespace.Labels
+
+	h.mutex.Unlock()
+
+	if found && labelsEqual(existing, namespace.Labels) {
+		return false
+	}
+
+	return h.resync()
+}
+
+func (h *handler) namespaceDeleted(obj runtime.Object, _ int) bool {
+	h.mutex.Lock()
+	delete(h.namespaces, obj.(*corev1.Namespace).Name)
+	h.mutex.Unlock()
+
+	return h.resync()
+}
+
+// resync is called by the resource watcher and returns whether the event should be requeued.
+func (h *handler) resync() bool {
+	if err := h.sync(); err != nil {
+		logger.Error(err, "Error syncing the inter-cluster network policies")
+		return true
+	}
+
+	return false
+}
+
+func labelsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,249 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netpol_test
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/admiral/pkg/syncer/test"
+	"github.com/submariner-io/admiral/pkg/watcher"
+	submV1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/event"
+	"github.com/submariner-io/submariner/pkg/event/testing"
+	"github.com/submariner-io/submariner/pkg/ipset"
+	fakeSet "github.com/submariner-io/submariner/pkg/ipset/fake"
+	"github.com/submariner-io/submariner/pkg/iptables"
+	fakeIPT "github.com/submariner-io/submariner/pkg/iptables/fake"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/handlers/netpol"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	eastCIDR   = "192.0.2.0/24"
+	westCIDR   = "192.0.3.0/24"
+	frontendIP = "10.1.0.10"
+	backendIP  = "10.1.0.20"
+)
+
+var _ = Describe("Handler", func() {
+	t := testing.NewControllerSupport()
+
+	var (
+		ipt       *fakeIPT.IPTables
+		ipSet     *fakeSet.IPSet
+		dynClient *dynamicfake.FakeDynamicClient
+		policies  dynamic.ResourceInterface
+		pods      dynamic.NamespaceableResourceInterface
+		policy    *submV1.InterClusterNetworkPolicy
+		handler   event.Handler
+		localEP   *submV1.Endpoint
+	)
+
+	BeforeEach(func() {
+		ipt = fakeIPT.New()
+		iptables.NewFunc = func() (iptables.Interface, error) {
+			return ipt, nil
+		}
+
+		ipSet = fakeSet.New()
+		ipset.NewFunc = func() ipset.Interface {
+			return ipSet
+		}
+
+		dynClient = dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
+		restMapper := test.GetRESTMapperFor(&submV1.InterClusterNetworkPolicy{}, &corev1.Pod{}, &corev1.Namespace{})
+		policies = dynClient.Resource(*test.GetGroupVersionResourceFor(restMapper, &submV1.InterClusterNetworkPolicy{}))
+		namespaces := dynClient.Resource(*test.GetGroupVersionResourceFor(restMapper, &corev1.Namespace{}))
+		pods = dynClient.Resource(*test.GetGroupVersionResourceFor(restMapper, &corev1.Pod{}))
+
+		test.CreateResource(namespaces, newNamespace("frontend", "a"))
+		test.CreateResource(namespaces, newNamespace("backend", "b"))
+		test.CreateResource(pods.Namespace("frontend"), newPod("frontend", frontendIP))
+		test.CreateResource(pods.Namespace("backend"), newPod("backend", backendIP))
+
+		policy = &submV1.InterClusterNetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "east-only"},
+			Spec: submV1.InterClusterNetworkPolicySpec{
+				NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+				Ingress:           []submV1.InterClusterNetworkPolicyPeer{{ClusterIDs: []string{"east"}}},
+			},
+		}
+
+		handler = netpol.NewHandler(&netpol.HandlerConfig{
+			WatcherConfig: &watcher.Config{
+				RestMapper: restMapper,
+				Client:     dynClient,
+			},
+		})
+	})
+
+	JustBeforeEach(func() {
+		t.Start(handler)
+
+		localEP = t.CreateLocalHostEndpoint()
+		t.CreateEndpoint(testing.NewEndpoint("east", "host1", eastCIDR))
+		t.CreateEndpoint(testing.NewEndpoint("west", "host2", westCIDR))
+
+		ipSet.AwaitEntry(netpol.RemoteCIDRIPSet, eastCIDR)
+		ipSet.AwaitEntry(netpol.RemoteCIDRIPSet, westCIDR)
+
+		test.CreateResource(policies, policy)
+	})
+
+	AfterEach(func() {
+		iptables.NewFunc = nil
+		ipset.NewFunc = nil
+	})
+
+	awaitGroupSets := func() (string, string) {
+		var localSet, remoteSet string
+
+		Eventually(func() bool {
+			names, _ := ipSet.ListSets()
+			for _, name := range names {
+				if strings.HasSuffix(name, "-L") {
+					localSet = name
+				} else if strings.HasSuffix(name, "-R") {
+					remoteSet = name
+				}
+			}
+
+			return localSet != "" && remoteSet != ""
+		}, 5).Should(BeTrue(), "The IP sets for the Pod group were not created")
+
+		return localSet, remoteSet
+	}
+
+	When("an InterClusterNetworkPolicy selects a namespace", func() {
+		It("should drop new connections from the remote clusters not allowed by the policy", func() {
+			ipt.AwaitRule(constants.FilterTable, constants.ForwardChain, ContainSubstring(netpol.NetworkPolicyChain))
+
+			localSet, remoteSet := awaitGroupSets()
+			ipSet.AwaitEntry(localSet, frontendIP)
+			ipSet.AwaitEntry(remoteSet, eastCIDR)
+			Expect(ipSet.ListEntries(localSet)).ToNot(ContainElement(backendIP))
+			Expect(ipSet.ListEntries(remoteSet)).ToNot(ContainElement(westCIDR))
+
+			ipt.AwaitRule(constants.FilterTable, netpol.NetworkPolicyChain, And(ContainSubstring(localSet+" dst"),
+				ContainSubstring("! --match-set "+remoteSet+" src"), ContainSubstring("-j DROP")))
+		})
+
+		Context("and is subsequently deleted", func() {
+			It("should remove the filtering", func() {
+				localSet, remoteSet := awaitGroupSets()
+				ipt.AwaitRule(constants.FilterTable, netpol.NetworkPolicyChain, ContainSubstring(localSet))
+
+				Expect(policies.Delete(context.Background(), policy.Name, metav1.DeleteOptions{})).To(Succeed())
+
+				ipt.AwaitNoRule(constants.FilterTable, netpol.NetworkPolicyChain, ContainSubstring("-j DROP"))
+				ipSet.AwaitSetDeleted(localSet)
+				ipSet.AwaitSetDeleted(remoteSet)
+			})
+		})
+	})
+
+	When("an InterClusterNetworkPolicy restricts a peer to CIDRs", func() {
+		BeforeEach(func() {
+			policy.Spec.Ingress[0].CIDRs = []string{"192.0.2.128/25", "192.0.3.0/25"}
+		})
+
+		It("should only allow the CIDRs within the peer cluster's subnets", func() {
+			_, remoteSet := awaitGroupSets()
+			ipSet.AwaitEntry(remoteSet, "192.0.2.128/25")
+			Expect(ipSet.ListEntries(remoteSet)).To(HaveLen(1))
+		})
+	})
+
+	When("an InterClusterNetworkPolicy has no ingress peers", func() {
+		BeforeEach(func() {
+			policy.Spec.Ingress = nil
+		})
+
+		It("should drop all new connections from the remote clusters to the selected Pods", func() {
+			localSet, remoteSet := awaitGroupSets()
+			ipSet.AwaitEntry(localSet, frontendIP)
+			ipt.AwaitRule(constants.FilterTable, netpol.NetworkPolicyChain, ContainSubstring("! --match-set "+remoteSet+" src"))
+			Expect(ipSet.ListEntries(remoteSet)).To(BeEmpty())
+		})
+	})
+
+	Context("on transition to non-gateway", func() {
+		It("should remove the filtering and stop syncing", func() {
+			localSet, remoteSet := awaitGroupSets()
+
+			t.DeleteEndpoint(localEP.Name)
+
+			ipt.AwaitNoRule(constants.FilterTable, netpol.NetworkPolicyChain, ContainSubstring("-j DROP"))
+			ipSet.AwaitSetDeleted(localSet)
+			ipSet.AwaitSetDeleted(remoteSet)
+			ipSet.AwaitSetDeleted(netpol.RemoteCIDRIPSet)
+
+			pod := newPod("frontend", "10.1.0.11")
+			pod.Name = "other-pod"
+			test.CreateResource(pods.Namespace("frontend"), pod)
+
+			Consistently(func() []string {
+				names, _ := ipSet.ListSets()
+				return names
+			}).Should(BeEmpty())
+		})
+	})
+
+	Context("on Uninstall", func() {
+		It("should remove the chain and IP sets", func() {
+			awaitGroupSets()
+
+			Expect(handler.Uninstall()).To(Succeed())
+
+			ipt.AwaitNoRule(constants.FilterTable, constants.ForwardChain, ContainSubstring(netpol.NetworkPolicyChain))
+			ipt.AwaitNoChain(constants.FilterTable, netpol.NetworkPolicyChain)
+			ipSet.AwaitSetDeleted(netpol.RemoteCIDRIPSet)
+		})
+	})
+})
+
+func newNamespace(name, team string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"team": team},
+		},
+	}
+}
+
+func newPod(namespace, ip string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod",
+			Namespace: namespace,
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			PodIP: ip,
+		},
+	}
+}
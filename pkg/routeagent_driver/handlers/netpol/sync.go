@@ -0,0 +1,332 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netpol
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	submV1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/cidr"
+	"github.com/submariner-io/submariner/pkg/ipset"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8snet "k8s.io/utils/net"
+	"k8s.io/utils/set"
+)
+
+// podGroup is a set of local Pods selected by the same policies, and the remote CIDRs those policies allow.
+type podGroup struct {
+	localIPs    set.Set[string]
+	remoteCIDRs set.Set[string]
+}
+
+func (h *handler) sync() error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	// The rules are removed on the transition to non-gateway, and the caches are only synced on the gateway.
+	if !h.cachesSynced || h.State() == nil || !h.State().IsOnGateway() {
+		return nil
+	}
+
+	remoteCIDRs := set.New[string]()
+	clusterSubnets := map[string][]string{}
+
+	endpoints := h.State().GetRemoteEndpoints()
+	for i := range endpoints {
+		subnets := cidr.ExtractIPv4Subnets(endpoints[i].Spec.Subnets)
+		remoteCIDRs.Insert(subnets...)
+		clusterSubnets[endpoints[i].Spec.ClusterID] = append(clusterSubnets[endpoints[i].Spec.ClusterID], subnets...)
+	}
+
+	desiredSets := set.New(RemoteCIDRIPSet)
+
+	if err := h.reconcileIPSet(RemoteCIDRIPSet, ipset.HashNet, remoteCIDRs); err != nil {
+		return err
+	}
+
+	groups := h.groupPods(clusterSubnets)
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	rules := make([][]string, 0, len(keys))
+
+	for _, key := range keys {
+		localSet, remoteSet := groupIPSetNames(key)
+		desiredSets.Insert(localSet, remoteSet)
+
+		if err := h.reconcileIPSet(localSet, ipset.HashIP, groups[key].localIPs); err != nil {
+			return err
+		}
+
+		if err := h.reconcileIPSet(remoteSet, ipset.HashNet, groups[key].remoteCIDRs); err != nil {
+			return err
+		}
+
+		rules = append(rules, []string{
+			"-m", "set", "--match-set", RemoteCIDRIPSet, "src", "-m", "set", "--match-set", localSet, "dst",
+			"-m", "set", "!", "--match-set", remoteSet, "src", "-m", "conntrack", "--ctstate", "NEW", "-j", "DROP",
+		})
+	}
+
+	if err := h.updateRules(rules); err != nil {
+		return err
+	}
+
+	// The sets of stale groups can only be destroyed once no rule references them.
+	return h.destroyIPSetsExcept(desiredSets)
+}
+
+// updateRules ensures the chain contains the given rules, and removes the rules previously added that aren't needed
+// anymore. The rules are independent of each other so their order doesn't matter.
+func (h *handler) updateRules(rules [][]string) error {
+	desired := map[string][]string{}
+
+	for _, ruleSpec := range rules {
+		desired[strings.Join(ruleSpec, " ")] = ruleSpec
+	}
+
+	for key, ruleSpec := range h.rules {
+		if _, ok := desired[key]; ok {
+			continue
+		}
+
+		if err := h.ipt.Delete(constants.FilterTable, NetworkPolicyChain, ruleSpec...); err != nil {
+			return errors.Wrapf(err, "error deleting iptables rule %q", key)
+		}
+	}
+
+	h.rules = desired
+
+	for _, ruleSpec := range rules {
+		if err := h.ipt.AppendUnique(constants.FilterTable, NetworkPolicyChain, ruleSpec...); err != nil {
+			return errors.Wrapf(err, "error appending iptables rule %q", strings.Join(ruleSpec, " "))
+		}
+	}
+
+	return nil
+}
+
+// groupPods groups the local Pods by the policies selecting them. Pods not selected by any policy aren't restricted.
+func (h *handler) groupPods(clusterSubnets map[string][]string) map[string]*podGroup {
+	type resolvedPolicy struct {
+		name              string
+		namespaceSelector labels.Selector
+		podSelector       labels.Selector
+		remoteCIDRs       []string
+	}
+
+	policies := make([]resolvedPolicy, 0, len(h.policies))
+
+	for _, policy := range h.policies {
+		namespaceSelector, err := metav1.LabelSelectorAsSelector(&policy.Spec.NamespaceSelector)
+		if err != nil {
+			logger.Errorf(err, "Ignoring InterClusterNetworkPolicy %q with an invalid namespace selector", policy.Name)
+			continue
+		}
+
+		podSelector, err := metav1.LabelSelectorAsSelector(&policy.Spec.PodSelector)
+		if err != nil {
+			logger.Errorf(err, "Ignoring InterClusterNetworkPolicy %q with an invalid Pod selector", policy.Name)
+			continue
+		}
+
+		policies = append(policies, resolvedPolicy{
+			name:              policy.Name,
+			namespaceSelector: namespaceSelector,
+			podSelector:       podSelector,
+			remoteCIDRs:       allowedRemoteCIDRs(policy, clusterSubnets),
+		})
+	}
+
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].name < policies[j].name
+	})
+
+	groups := map[string]*podGroup{}
+
+	for _, pod := range h.pods {
+		podIP := pod.Status.PodIP
+		if pod.Spec.HostNetwork || !k8snet.IsIPv4String(podIP) || pod.Status.Phase == corev1.PodSucceeded ||
+			pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		var selectedBy []string
+
+		remoteCIDRs := set.New[string]()
+
+		for i := range policies {
+			if policies[i].namespaceSelector.Matches(labels.Set(h.namespaces[pod.Namespace])) &&
+				policies[i].podSelector.Matches(labels.Set(pod.Labels)) {
+				selectedBy = append(selectedBy, policies[i].name)
+				remoteCIDRs.Insert(policies[i].remoteCIDRs...)
+			}
+		}
+
+		if len(selectedBy) == 0 {
+			continue
+		}
+
+		key := strings.Join(selectedBy, ",")
+
+		group, ok := groups[key]
+		if !ok {
+			group = &podGroup{localIPs: set.New[string](), remoteCIDRs: remoteCIDRs}
+			groups[key] = group
+		}
+
+		group.localIPs.Insert(podIP)
+	}
+
+	return groups
+}
+
+// allowedRemoteCIDRs returns the remote CIDRs the policy's peers allow, given the IPv4 subnets of each remote cluster.
+func allowedRemoteCIDRs(policy *submV1.InterClusterNetworkPolicy, clusterSubnets map[string][]string) []string {
+	var allowed []string
+
+	for i := range policy.Spec.Ingress {
+		peer := &policy.Spec.Ingress[i]
+
+		for _, clusterID := range peer.ClusterIDs {
+			subnets := clusterSubnets[clusterID]
+
+			if len(peer.CIDRs) == 0 {
+				allowed = append(allowed, subnets...)
+				continue
+			}
+
+			for _, peerCIDR := range peer.CIDRs {
+				if containedIn(peerCIDR, subnets) {
+					allowed = append(allowed, peerCIDR)
+				} else {
+					logger.V(log.DEBUG).Infof("InterClusterNetworkPolicy %q: CIDR %q is not within the subnets %v of cluster %q",
+						policy.Name, peerCIDR, subnets, clusterID)
+				}
+			}
+		}
+	}
+
+	return allowed
+}
+
+func containedIn(cidrStr string, subnets []string) bool {
+	ip, ipNet, err := net.ParseCIDR(cidrStr)
+	if err != nil {
+		ip = net.ParseIP(cidrStr)
+		if ip == nil {
+			return false
+		}
+
+		ipNet = &net.IPNet{IP: ip, Mask: net.CIDRMask(8*len(ip.To4()), 8*len(ip.To4()))}
+	}
+
+	size, _ := ipNet.Mask.Size()
+
+	for _, subnet := range subnets {
+		_, subnetNet, err := net.ParseCIDR(subnet)
+		if err != nil {
+			continue
+		}
+
+		subnetSize, _ := subnetNet.Mask.Size()
+		if subnetNet.Contains(ip) && size >= subnetSize {
+			return true
+		}
+	}
+
+	return false
+}
+
+// groupIPSetNames returns the names of the IP sets holding the local Pod IPs and the allowed remote CIDRs of a group.
+func groupIPSetNames(key string) (string, string) {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(key))
+
+	prefix := fmt.Sprintf("%s%08x", IPSetPrefix, hash.Sum32())
+
+	return prefix + "-L", prefix + "-R"
+}
+
+// reconcileIPSet ensures the named set exists and contains exactly the desired entries.
+func (h *handler) reconcileIPSet(name string, setType ipset.Type, desired set.Set[string]) error {
+	named := ipset.NewNamed(&ipset.IPSet{
+		Name:       name,
+		SetType:    setType,
+		HashFamily: ipset.ProtocolFamilyIPV4,
+	}, h.ipSetIface)
+
+	if err := named.Create(true); err != nil {
+		return errors.Wrapf(err, "error creating ipset %q", name)
+	}
+
+	entries, err := named.ListEntries()
+	if err != nil {
+		return errors.Wrapf(err, "error listing the entries of ipset %q", name)
+	}
+
+	existing := set.New(entries...)
+
+	for _, entry := range desired.Difference(existing).UnsortedList() {
+		if err := named.AddEntry(entry, true); err != nil {
+			return errors.Wrapf(err, "error adding entry %q to ipset %q", entry, name)
+		}
+	}
+
+	for _, entry := range existing.Difference(desired).UnsortedList() {
+		if err := named.DelEntry(entry); err != nil {
+			return errors.Wrapf(err, "error deleting entry %q from ipset %q", entry, name)
+		}
+	}
+
+	return nil
+}
+
+func (h *handler) destroyIPSetsExcept(keep set.Set[string]) error {
+	names, err := h.ipSetIface.ListSets()
+	if err != nil {
+		return errors.Wrap(err, "error listing the IP sets")
+	}
+
+	for _, name := range names {
+		if !strings.HasPrefix(name, IPSetPrefix) || keep.Has(name) {
+			continue
+		}
+
+		if err := h.ipSetIface.DestroySet(name); err != nil {
+			return errors.Wrapf(err, "error destroying ipset %q", name)
+		}
+	}
+
+	return nil
+}
@@ -21,6 +21,7 @@ package ovn
 import (
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
@@ -34,7 +35,7 @@ func (ovn *Handler) cleanupGatewayDataplane() error {
 		return errors.Wrapf(err, "error reading ip rule list for IPv4")
 	}
 
-	err = ovn.handleSubnets(currentRemoteSubnets.UnsortedList(), ovn.netLink.RuleDel, os.IsNotExist)
+	_, err = ovn.handleSubnets(currentRemoteSubnets.UnsortedList(), ovn.netLink.RuleDel, os.IsNotExist)
 	if err != nil {
 		return errors.Wrapf(err, "error removing routing rule")
 	}
@@ -53,41 +54,63 @@ func (ovn *Handler) cleanupGatewayDataplane() error {
 }
 
 func (ovn *Handler) updateGatewayDataplane() error {
+	_, err := ovn.convergeGatewayDataplane()
+	return err
+}
+
+// convergeGatewayDataplane brings the gateway routing rules, route and forwarding rules in line with the remote
+// subnets and returns the changes that were needed to do so.
+func (ovn *Handler) convergeGatewayDataplane() (dataplaneChanges, error) {
 	ovn.mutex.Lock()
 	defer ovn.mutex.Unlock()
 
+	changes := dataplaneChanges{}
+
 	currentRuleRemotes, err := ovn.getExistingIPv4RuleSubnets()
 	if err != nil {
-		return errors.Wrapf(err, "error reading ip rule list for IPv4")
+		return changes, errors.Wrapf(err, "error reading ip rule list for IPv4")
 	}
 
 	endpointSubnets := ovn.getRemoteSubnets()
 
 	toAdd := endpointSubnets.Difference(currentRuleRemotes).UnsortedList()
 
-	err = ovn.handleSubnets(toAdd, ovn.netLink.RuleAdd, os.IsExist)
+	changes.rules, err = ovn.handleSubnets(toAdd, ovn.netLink.RuleAdd, os.IsExist)
 	if err != nil {
-		return errors.Wrap(err, "error adding routing rule")
+		return changes, errors.Wrap(err, "error adding routing rule")
 	}
 
 	toRemove := currentRuleRemotes.Difference(endpointSubnets).UnsortedList()
 
-	err = ovn.handleSubnets(toRemove, ovn.netLink.RuleDel, os.IsNotExist)
+	removed, err := ovn.handleSubnets(toRemove, ovn.netLink.RuleDel, os.IsNotExist)
+	changes.rules += removed
+
 	if err != nil {
-		return errors.Wrapf(err, "error removing routing rule")
+		return changes, errors.Wrapf(err, "error removing routing rule")
 	}
 
 	defaultRoute, err := ovn.getRouteToOVNDataPlane()
 	if err != nil {
-		return errors.Wrap(err, "error creating default route")
+		return changes, errors.Wrap(err, "error creating default route")
 	}
 
 	err = ovn.netLink.RouteAdd(defaultRoute)
 	if err != nil && !os.IsExist(err) {
-		return errors.Wrap(err, "error adding submariner default")
+		return changes, errors.Wrap(err, "error adding submariner default")
 	}
 
-	return ovn.setupForwardingIptables()
+	if err == nil {
+		changes.routes++
+	}
+
+	missing, err := ovn.countMissingForwardingRules()
+	if err != nil {
+		return changes, err
+	}
+
+	changes.iptables += missing
+
+	return changes, ovn.setupForwardingIptables()
 }
 
 // TODO: if the #1022 workaround needs to be sustained for some time, instead of this we should be calculating
@@ -154,6 +177,46 @@ const (
 	ForwardingSubmarinerFWDChain      = "SUBMARINER-FORWARD"
 )
 
+func (ovn *Handler) countMissingForwardingRules() (int, error) {
+	missing := 0
+
+	for chain, ruleGen := range map[string]forwardRuleSpecGenerator{
+		ForwardingSubmarinerMSSClampChain: ovn.getMSSClampingRuleSpecs,
+		ForwardingSubmarinerFWDChain:      ovn.getForwardingRuleSpecs,
+	} {
+		ruleSpecs, err := ruleGen()
+		if err != nil {
+			return missing, err
+		}
+
+		n, err := ovn.countMissingRules(constants.FilterTable, chain, ruleSpecs)
+		if err != nil {
+			return missing, err
+		}
+
+		missing += n
+	}
+
+	return missing, nil
+}
+
+func (ovn *Handler) countMissingRules(table, chain string, ruleSpecs [][]string) (int, error) {
+	missing := 0
+
+	for _, ruleSpec := range ruleSpecs {
+		exists, err := ovn.ipt.Exists(table, chain, ruleSpec...)
+		if err != nil {
+			return missing, errors.Wrapf(err, "error checking for iptables rule %q", strings.Join(ruleSpec, " "))
+		}
+
+		if !exists {
+			missing++
+		}
+	}
+
+	return missing, nil
+}
+
 func (ovn *Handler) setupForwardingIptables() error {
 	if err := ovn.updateIPtableChains(constants.FilterTable, ForwardingSubmarinerMSSClampChain, ovn.getMSSClampingRuleSpecs); err != nil {
 		return err
@@ -35,6 +35,7 @@ import (
 	"github.com/submariner-io/submariner/pkg/event"
 	"github.com/submariner-io/submariner/pkg/iptables"
 	"github.com/submariner-io/submariner/pkg/netlink"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/routingpolicy"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -62,6 +63,9 @@ type Handler struct {
 	ipt                       iptables.Interface
 	gatewayRouteController    *GatewayRouteController
 	nonGatewayRouteController *NonGatewayRouteController
+	connectionHandler         *ConnectionHandler
+	routingPolicies           *routingpolicy.HostDataplane
+	routingPolicyGw           *routingpolicy.ActiveGatewayFilter
 	stopCh                    chan struct{}
 }
 
@@ -74,13 +78,18 @@ func NewHandler(config *HandlerConfig) *Handler {
 		logger.Fatalf("Error initializing iptables in OVN routeagent handler: %s", err)
 	}
 
+	netLink := netlink.New()
+
 	h := &Handler{
-		HandlerConfig: *config,
-		netLink:       netlink.New(),
-		ipt:           ipt,
-		stopCh:        make(chan struct{}),
+		HandlerConfig:   *config,
+		netLink:         netLink,
+		ipt:             ipt,
+		routingPolicies: routingpolicy.NewHostDataplane(netLink, ""),
+		stopCh:          make(chan struct{}),
 	}
 
+	h.routingPolicyGw = routingpolicy.NewActiveGatewayFilter(h.applyRoutingPolicy, h.removeRoutingPolicy)
+
 	if h.NewOVSDBClient == nil {
 		h.NewOVSDBClient = libovsdbclient.NewOVSDBClient
 	}
@@ -113,6 +122,17 @@ func (ovn *Handler) Init() error {
 		return errors.Wrapf(err, "error getting connection handler to connect to OvnDB")
 	}
 
+	ovn.connectionHandler = connectionHandler
+
+	// The RoutingPolicies are re-applied once their controller starts.
+	if err := connectionHandler.deleteRoutingPolicyLRPs(); err != nil {
+		logger.Errorf(err, "Error deleting the RoutingPolicy router policies")
+	}
+
+	if err := ovn.routingPolicies.Flush(); err != nil {
+		logger.Errorf(err, "Error flushing the RoutingPolicy rules")
+	}
+
 	gatewayRouteController, err := NewGatewayRouteController(*ovn.WatcherConfig, connectionHandler, ovn.Namespace)
 	if err != nil {
 		return err
@@ -229,7 +249,11 @@ func (ovn *Handler) TransitionToNonGateway() error {
 		}
 	}
 
-	return ovn.cleanupGatewayDataplane()
+	if err := ovn.cleanupGatewayDataplane(); err != nil {
+		return err
+	}
+
+	return ovn.routingPolicyGw.SetOnGateway(false) //nolint:wrapcheck  // Let the caller wrap it
 }
 
 func (ovn *Handler) TransitionToGateway() error {
@@ -242,5 +266,9 @@ func (ovn *Handler) TransitionToGateway() error {
 		}
 	}
 
-	return ovn.updateGatewayDataplane()
+	if err := ovn.updateGatewayDataplane(); err != nil {
+		return err
+	}
+
+	return ovn.routingPolicyGw.SetOnGateway(true) //nolint:wrapcheck  // Let the caller wrap it
 }
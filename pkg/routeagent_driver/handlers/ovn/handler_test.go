@@ -35,6 +35,7 @@ import (
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/handlers/ovn"
 	fakeovn "github.com/submariner-io/submariner/pkg/routeagent_driver/handlers/ovn/fake"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/routingpolicy"
 	"github.com/vishvananda/netlink"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -281,6 +282,37 @@ var _ = Describe("Handler", func() {
 		})
 	})
 
+	When("a RoutingPolicy is applied and removed", func() {
+		It("should correctly reconcile OVN router policies", func() {
+			policy := &routingpolicy.Resolved{
+				Name:        "test-policy",
+				RemoteCIDRs: []string{"192.0.4.0/24"},
+				SourceIPs:   []string{"171.0.1.5"},
+				GatewayNode: &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "gateway-node",
+						Annotations: map[string]string{constants.OvnTransitSwitchIPAnnotation: `{"ipv4":"190.1.2.9/24"}`},
+					},
+				},
+			}
+
+			handler := t.handler.(*ovn.Handler)
+			Expect(handler.ApplyRoutingPolicy(policy)).To(Succeed())
+
+			ovsdbClient.AwaitModel(&nbdb.LogicalRouterPolicy{
+				Match:   "ip4.dst == 192.0.4.0/24 && ip4.src == {171.0.1.5}",
+				Nexthop: ptr.To("190.1.2.9"),
+			})
+
+			Expect(handler.RemoveRoutingPolicy(policy.Name)).To(Succeed())
+
+			ovsdbClient.AwaitNoModel(&nbdb.LogicalRouterPolicy{
+				Match:   "192.0.4.0/24",
+				Nexthop: ptr.To("190.1.2.9"),
+			})
+		})
+	})
+
 	When("the OVN management interface address changes", func() {
 		JustBeforeEach(func() {
 			t.CreateLocalHostEndpoint()
@@ -311,6 +343,37 @@ var _ = Describe("Handler", func() {
 		})
 	})
 
+	When("the dataplane drifts on the gateway", func() {
+		It("should be restored on reconciliation", func() {
+			t.CreateLocalHostEndpoint()
+			endpoint := t.CreateEndpoint(testing.NewEndpoint("remote-cluster", "host", "192.0.1.0/24"))
+
+			t.netLink.AwaitRule(constants.RouteAgentHostNetworkTableID, "", "192.0.1.0/24")
+			t.netLink.AwaitRule(constants.RouteAgentInterClusterNetworkTableID, "192.0.1.0/24", clusterCIDR)
+			t.netLink.AwaitGwRoutes(0, constants.RouteAgentInterClusterNetworkTableID, OVNK8sMgmntIntGw)
+			t.ipTables.AwaitRule(constants.NATTable, constants.SmPostRoutingChain, Equal("-d 192.0.1.0/24 -j ACCEPT"))
+
+			Expect(t.netLink.FlushRouteTable(constants.RouteAgentInterClusterNetworkTableID)).To(Succeed())
+			Expect(t.netLink.FlushRouteTable(constants.RouteAgentHostNetworkTableID)).To(Succeed())
+			Expect(t.netLink.RuleDel(&netlink.Rule{
+				Table: constants.RouteAgentHostNetworkTableID,
+				Dst:   toIPNet("192.0.1.0/24"),
+			})).To(Succeed())
+			Expect(t.ipTables.Delete(constants.NATTable, constants.SmPostRoutingChain, "-d", "192.0.1.0/24", "-j", "ACCEPT")).
+				To(Succeed())
+
+			Expect(t.handler.Reconcile(&testing.TestHandlerState{
+				Gateway:         true,
+				RemoteEndpoints: []submarinerv1.Endpoint{*endpoint},
+			})).To(Succeed())
+
+			t.netLink.AwaitRule(constants.RouteAgentHostNetworkTableID, "", "192.0.1.0/24")
+			t.netLink.AwaitGwRoutes(0, constants.RouteAgentHostNetworkTableID, OVNK8sMgmntIntGw)
+			t.netLink.AwaitGwRoutes(0, constants.RouteAgentInterClusterNetworkTableID, OVNK8sMgmntIntGw)
+			t.ipTables.AwaitRule(constants.NATTable, constants.SmPostRoutingChain, Equal("-d 192.0.1.0/24 -j ACCEPT"))
+		})
+	})
+
 	Context("on Uninstall", func() {
 		It("should delete the table rules", func() {
 			Expect(t.ipTables.ChainExists(constants.FilterTable, ovn.ForwardingSubmarinerFWDChain)).To(BeTrue())
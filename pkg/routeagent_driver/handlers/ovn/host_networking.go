@@ -36,33 +36,44 @@ const (
 )
 
 func (ovn *Handler) updateHostNetworkDataplane() error {
+	_, err := ovn.convergeHostNetworkDataplane()
+	return err
+}
+
+// convergeHostNetworkDataplane brings the host networking routing rules and route in line with the remote subnets
+// and returns the changes that were needed to do so.
+func (ovn *Handler) convergeHostNetworkDataplane() (dataplaneChanges, error) {
 	ovn.mutex.Lock()
 	defer ovn.mutex.Unlock()
 
+	changes := dataplaneChanges{}
+
 	currentRuleRemotes, err := ovn.getExistingIPv4HostNetworkRoutes()
 	if err != nil {
-		return errors.Wrapf(err, "error reading ip rule list for IPv4")
+		return changes, errors.Wrapf(err, "error reading ip rule list for IPv4")
 	}
 
 	endpointSubnets := ovn.getRemoteSubnets()
 
 	toAdd := endpointSubnets.Difference(currentRuleRemotes).UnsortedList()
 
-	err = ovn.programRulesForRemoteSubnets(toAdd, ovn.netLink.RuleAdd, os.IsExist)
+	changes.rules, err = ovn.programRulesForRemoteSubnets(toAdd, ovn.netLink.RuleAdd, os.IsExist)
 	if err != nil {
-		return errors.Wrap(err, "error adding routing rule")
+		return changes, errors.Wrap(err, "error adding routing rule")
 	}
 
 	toRemove := currentRuleRemotes.Difference(endpointSubnets).UnsortedList()
 
-	err = ovn.programRulesForRemoteSubnets(toRemove, ovn.netLink.RuleDel, os.IsNotExist)
+	removed, err := ovn.programRulesForRemoteSubnets(toRemove, ovn.netLink.RuleDel, os.IsNotExist)
+	changes.rules += removed
+
 	if err != nil {
-		return errors.Wrapf(err, "error removing routing rule")
+		return changes, errors.Wrapf(err, "error removing routing rule")
 	}
 
 	nextHop, err := ovn.getNextHopOnK8sMgmtIntf()
 	if err != nil {
-		return errors.Wrapf(err, "getNextHopOnK8sMgmtIntf returned error")
+		return changes, errors.Wrapf(err, "getNextHopOnK8sMgmtIntf returned error")
 	}
 
 	route := &netlink.Route{
@@ -72,10 +83,14 @@ func (ovn *Handler) updateHostNetworkDataplane() error {
 
 	err = ovn.netLink.RouteAdd(route)
 	if err != nil && !os.IsExist(err) {
-		return errors.Wrap(err, "error adding submariner default")
+		return changes, errors.Wrap(err, "error adding submariner default")
 	}
 
-	return nil
+	if err == nil {
+		changes.routes++
+	}
+
+	return changes, nil
 }
 
 func (ovn *Handler) getExistingIPv4HostNetworkRoutes() (set.Set[string], error) {
@@ -97,20 +112,26 @@ func (ovn *Handler) getExistingIPv4HostNetworkRoutes() (set.Set[string], error)
 
 func (ovn *Handler) programRulesForRemoteSubnets(subnets []string, ruleFunc func(rule *netlink.Rule) error,
 	ignoredErrorFunc func(error) bool,
-) error {
+) (int, error) {
+	programmed := 0
+
 	for _, remoteSubnet := range subnets {
 		rule, err := ovn.getRuleSpec(remoteSubnet, "", constants.RouteAgentHostNetworkTableID)
 		if err != nil {
-			return errors.Wrapf(err, "error creating rule %#v", rule)
+			return programmed, errors.Wrapf(err, "error creating rule %#v", rule)
 		}
 
 		err = ruleFunc(rule)
 		if err != nil && !ignoredErrorFunc(err) {
-			return errors.Wrapf(err, "error handling rule %#v", rule)
+			return programmed, errors.Wrapf(err, "error handling rule %#v", rule)
+		}
+
+		if err == nil {
+			programmed++
 		}
 	}
 
-	return nil
+	return programmed, nil
 }
 
 func (ovn *Handler) getNextHopOnK8sMgmtIntf() (*net.IP, error) {
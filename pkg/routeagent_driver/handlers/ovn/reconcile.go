@@ -0,0 +1,136 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovn
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/submariner/pkg/cidr"
+	"github.com/submariner-io/submariner/pkg/event"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
+	"k8s.io/utils/set"
+)
+
+// dataplaneChanges counts the entries that had to be added or removed to converge the data path.
+type dataplaneChanges struct {
+	rules    int
+	routes   int
+	iptables int
+}
+
+func (c *dataplaneChanges) add(other dataplaneChanges) {
+	c.rules += other.rules
+	c.routes += other.routes
+	c.iptables += other.iptables
+}
+
+func (ovn *Handler) Reconcile(state event.HandlerState) error {
+	if err := ovn.initIPtablesChains(); err != nil {
+		return err
+	}
+
+	drift, err := ovn.convergeHostNetworkDataplane()
+	if err != nil {
+		ovn.recordDrift(drift)
+		return errors.Wrap(err, "error reconciling the host network dataplane")
+	}
+
+	if state.IsOnGateway() {
+		var gwDrift dataplaneChanges
+
+		gwDrift, err = ovn.reconcileGatewayDataplane(state)
+		drift.add(gwDrift)
+	}
+
+	ovn.recordDrift(drift)
+
+	return err
+}
+
+func (ovn *Handler) reconcileGatewayDataplane(state event.HandlerState) (dataplaneChanges, error) {
+	drift := dataplaneChanges{}
+
+	endpoints := state.GetRemoteEndpoints()
+	for i := range endpoints {
+		if cidr.OverlappingSubnets(ovn.ServiceCIDR, ovn.ClusterCIDR, endpoints[i].Spec.Subnets) != nil {
+			continue
+		}
+
+		for _, subnet := range endpoints[i].Spec.Subnets {
+			ruleSpecs := [][]string{{"-d", subnet, "-j", "ACCEPT"}, {"-s", subnet, "-j", "ACCEPT"}}
+
+			missing, err := ovn.countMissingRules(constants.NATTable, constants.SmPostRoutingChain, ruleSpecs)
+			if err != nil {
+				return drift, err
+			}
+
+			if missing == 0 {
+				continue
+			}
+
+			if err := ovn.addNoMasqueradeIPTables(subnet); err != nil {
+				return drift, errors.Wrapf(err, "error adding no-masquerade rules for subnet %q", subnet)
+			}
+
+			drift.iptables += missing
+		}
+	}
+
+	gwDrift, err := ovn.convergeGatewayDataplane()
+	drift.add(gwDrift)
+
+	return drift, errors.Wrap(err, "error reconciling the gateway dataplane")
+}
+
+func (ovn *Handler) recordDrift(drift dataplaneChanges) {
+	event.RecordDriftCorrected(ovn.GetName(), event.DriftRules, drift.rules)
+	event.RecordDriftCorrected(ovn.GetName(), event.DriftRoutes, drift.routes)
+	event.RecordDriftCorrected(ovn.GetName(), event.DriftIPTables, drift.iptables)
+}
+
+func (ovn *Handler) OwnedState() event.OwnedState {
+	remoteSubnets := ovn.getRemoteSubnets().SortedList()
+
+	state := event.OwnedState{
+		Routes: []string{fmt.Sprintf("default table %d dev %s", constants.RouteAgentHostNetworkTableID, OVNK8sMgmntIntfName)},
+	}
+
+	for _, subnet := range remoteSubnets {
+		state.Rules = append(state.Rules, fmt.Sprintf("to %s lookup %d", subnet, constants.RouteAgentHostNetworkTableID))
+	}
+
+	if !ovn.State().IsOnGateway() {
+		return state
+	}
+
+	state.Routes = append(state.Routes,
+		fmt.Sprintf("default table %d dev %s", constants.RouteAgentInterClusterNetworkTableID, OVNK8sMgmntIntfName))
+
+	localCIDRs := set.New(ovn.ClusterCIDR...).Insert(ovn.ServiceCIDR...).SortedList()
+
+	for _, subnet := range remoteSubnets {
+		for _, localCIDR := range localCIDRs {
+			state.Rules = append(state.Rules, fmt.Sprintf("from %s to %s lookup %d", subnet, localCIDR,
+				constants.RouteAgentInterClusterNetworkTableID))
+		}
+	}
+
+	return state
+}
@@ -0,0 +1,171 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovn
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/libovsdbops"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/nbdb"
+	"github.com/pkg/errors"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/routingpolicy"
+	"github.com/submariner-io/submariner/pkg/versions"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// RoutingPolicy router policies take precedence over the policies steering remote traffic to the active gateway.
+	ovnRoutingPoliciesPrio  = 20100
+	routingPolicyExternalID = "submariner-routing-policy"
+)
+
+// ApplyRoutingPolicy reroutes the Pod traffic selected by the policy in the OVN cluster router. Traffic pinned to
+// another node is sent to that node's transit switch port, unless this node is the active gateway; traffic pinned to
+// an egress interface on this node is sent to the host via the management port and routed out of the interface by the
+// host rules.
+func (ovn *Handler) ApplyRoutingPolicy(policy *routingpolicy.Resolved) error {
+	return ovn.routingPolicyGw.ApplyRoutingPolicy(policy) //nolint:wrapcheck  // Let the caller wrap it
+}
+
+func (ovn *Handler) RemoveRoutingPolicy(name string) error {
+	return ovn.routingPolicyGw.RemoveRoutingPolicy(name) //nolint:wrapcheck  // Let the caller wrap it
+}
+
+func (ovn *Handler) applyRoutingPolicy(policy *routingpolicy.Resolved) error {
+	if !policy.IsLocal() {
+		if err := ovn.routingPolicies.Remove(policy.Name); err != nil {
+			return err //nolint:wrapcheck  // Let the caller wrap it
+		}
+
+		nextHop, err := transitSwitchIPOf(policy)
+		if err != nil {
+			return err
+		}
+
+		return ovn.connectionHandler.reconcileRoutingPolicyLRPs(policy.Name, buildRoutingPolicyLRPs(policy, nextHop))
+	}
+
+	if policy.EgressInterface == "" {
+		return ovn.removeRoutingPolicy(policy.Name)
+	}
+
+	if err := ovn.routingPolicies.Apply(policy); err != nil {
+		return err //nolint:wrapcheck  // Let the caller wrap it
+	}
+
+	nextHop, err := getNextHopOnK8sMgmtIntf()
+	if err != nil {
+		return errors.Wrap(err, "error retrieving the management interface address")
+	}
+
+	return ovn.connectionHandler.reconcileRoutingPolicyLRPs(policy.Name, buildRoutingPolicyLRPs(policy, nextHop))
+}
+
+func (ovn *Handler) removeRoutingPolicy(name string) error {
+	if err := ovn.connectionHandler.reconcileRoutingPolicyLRPs(name, nil); err != nil {
+		return err
+	}
+
+	return ovn.routingPolicies.Remove(name) //nolint:wrapcheck  // Let the caller wrap it
+}
+
+func transitSwitchIPOf(policy *routingpolicy.Resolved) (string, error) {
+	annotation := policy.GatewayNode.GetAnnotations()[constants.OvnTransitSwitchIPAnnotation]
+	if annotation == "" {
+		return "", fmt.Errorf("gateway node %q has no transit switch IP, RoutingPolicies require OVN interconnect",
+			policy.GatewayNode.Name)
+	}
+
+	ip, err := jsonToIP(annotation)
+
+	return ip, errors.Wrapf(err, "error parsing the transit switch IP of gateway node %q", policy.GatewayNode.Name)
+}
+
+func buildRoutingPolicyLRPs(policy *routingpolicy.Resolved, nextHop string) []*nbdb.LogicalRouterPolicy {
+	srcMatch := ""
+	if len(policy.SourceIPs) > 0 {
+		srcMatch = " && ip4.src == {" + strings.Join(policy.SourceIPs, ", ") + "}"
+	}
+
+	lrps := []*nbdb.LogicalRouterPolicy{}
+
+	for _, remoteCIDR := range policy.RemoteCIDRs {
+		lrps = append(lrps, &nbdb.LogicalRouterPolicy{
+			Priority: ovnRoutingPoliciesPrio,
+			Action:   "reroute",
+			Match:    "ip4.dst == " + remoteCIDR + srcMatch,
+			Nexthop:  ptr.To(nextHop),
+			ExternalIDs: map[string]string{
+				"submariner":            versions.Submariner(),
+				routingPolicyExternalID: policy.Name,
+			},
+		})
+	}
+
+	return lrps
+}
+
+// reconcileRoutingPolicyLRPs brings the router policies of the named RoutingPolicy in line with the expected ones.
+func (c *ConnectionHandler) reconcileRoutingPolicyLRPs(name string, expectedLRPs []*nbdb.LogicalRouterPolicy) error {
+	expected := map[string]string{}
+	for _, lrp := range expectedLRPs {
+		expected[lrp.Match] = *lrp.Nexthop
+	}
+
+	lrpStalePredicate := func(item *nbdb.LogicalRouterPolicy) bool {
+		if item.Priority != ovnRoutingPoliciesPrio || item.ExternalIDs[routingPolicyExternalID] != name {
+			return false
+		}
+
+		nextHop, ok := expected[item.Match]
+
+		return !ok || item.Nexthop == nil || *item.Nexthop != nextHop
+	}
+
+	err := libovsdbops.DeleteLogicalRouterPoliciesWithPredicate(c.nbdb, OVNClusterRouter, lrpStalePredicate)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete stale logical router policies for RoutingPolicy %q", name)
+	}
+
+	for _, lrp := range expectedLRPs {
+		lrpPredicate := func(item *nbdb.LogicalRouterPolicy) bool {
+			return item.Priority == ovnRoutingPoliciesPrio && item.ExternalIDs[routingPolicyExternalID] == name &&
+				item.Match == lrp.Match
+		}
+
+		if err := libovsdbops.CreateOrUpdateLogicalRouterPolicyWithPredicate(c.nbdb,
+			OVNClusterRouter, lrp, lrpPredicate); err != nil {
+			return errors.Wrapf(err, "failed to create logical router policy %v for RoutingPolicy %q", lrp, name)
+		}
+	}
+
+	return nil
+}
+
+// deleteRoutingPolicyLRPs removes the router policies of all the RoutingPolicies.
+func (c *ConnectionHandler) deleteRoutingPolicyLRPs() error {
+	err := libovsdbops.DeleteLogicalRouterPoliciesWithPredicate(c.nbdb, OVNClusterRouter, func(item *nbdb.LogicalRouterPolicy) bool {
+		_, ok := item.ExternalIDs[routingPolicyExternalID]
+		return item.Priority == ovnRoutingPoliciesPrio && ok
+	})
+
+	return errors.Wrap(err, "failed to delete the RoutingPolicy logical router policies")
+}
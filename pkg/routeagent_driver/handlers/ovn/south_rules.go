@@ -30,30 +30,36 @@ import (
 
 // handleSubnets builds ip rules, and passes them to the specified netlink function
 //
-//	for provided subnet list
+//	for provided subnet list. It returns the number of rules that were successfully handled.
 func (ovn *Handler) handleSubnets(remoteSubnets []string, ruleFunc func(rule *netlink.Rule) error,
 	ignoredErrorFunc func(error) bool,
-) error {
+) (int, error) {
 	localCIDRs := set.New(ovn.ClusterCIDR...)
 	localCIDRs.Insert(ovn.ServiceCIDR...)
 
+	handled := 0
+
 	for _, subnetToHandle := range remoteSubnets {
 		for _, localSubnet := range localCIDRs.UnsortedList() {
 			rule, err := ovn.getRuleSpec(localSubnet, subnetToHandle, constants.RouteAgentInterClusterNetworkTableID)
 			if err != nil {
-				return errors.Wrapf(err, "error creating rule %#v", rule)
+				return handled, errors.Wrapf(err, "error creating rule %#v", rule)
 			}
 
 			logger.V(log.DEBUG).Infof("Adding routes in table 149: %v", rule)
 
 			err = ruleFunc(rule)
 			if err != nil && !ignoredErrorFunc(err) {
-				return errors.Wrapf(err, "error handling rule %#v", rule)
+				return handled, errors.Wrapf(err, "error handling rule %#v", rule)
+			}
+
+			if err == nil {
+				handled++
 			}
 		}
 	}
 
-	return nil
+	return handled, nil
 }
 
 func (ovn *Handler) getRuleSpec(dest, src string, tableID int) (*netlink.Rule, error) {
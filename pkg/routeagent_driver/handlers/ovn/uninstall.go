@@ -53,6 +53,10 @@ func (ovn *Handler) Uninstall() error {
 			constants.RouteAgentHostNetworkTableID)
 	}
 
+	if err := ovn.routingPolicies.Flush(); err != nil {
+		logger.Errorf(err, "Error flushing the RoutingPolicy rules")
+	}
+
 	ovn.flushAndDeleteIPTableChains(constants.FilterTable, constants.ForwardChain, ForwardingSubmarinerFWDChain)
 	ovn.flushAndDeleteIPTableChains(constants.NATTable, constants.PostRoutingChain, constants.SmPostRoutingChain)
 
@@ -33,7 +33,16 @@ const ovsCommandTimeout = 15
 
 var logger = log.Logger{Logger: logf.Log.WithName("ovs-vsctl")}
 
+// WriteHook, if set, is called with the parameters of the commands that modify the Open vSwitch database instead of running
+// them, e.g. to record them in dry run mode.
+var WriteHook func(parameters ...string)
+
 func vsctlCmd(parameters ...string) (output string, err error) {
+	if WriteHook != nil && !isReadOnly(parameters) {
+		WriteHook(parameters...)
+		return "", nil
+	}
+
 	allParameters := []string{fmt.Sprintf("--timeout=%d", ovsCommandTimeout)}
 	allParameters = append(allParameters, parameters...)
 
@@ -58,6 +67,16 @@ func vsctlCmd(parameters ...string) (output string, err error) {
 	return stdout, nil
 }
 
+func isReadOnly(parameters []string) bool {
+	for _, p := range parameters {
+		if !strings.HasPrefix(p, "--") {
+			return p == "get" || p == "list" || p == "find"
+		}
+	}
+
+	return false
+}
+
 func AddBridge(bridgeName string) error {
 	_, err := vsctlCmd("--may-exist", "add-br", bridgeName)
 
@@ -0,0 +1,97 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/submariner/pkg/event/controller"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// healthReporter annotates the local node with the names of the event handlers that have outstanding failures so
+// that a degraded feature is visible without inspecting the route agent logs.
+type healthReporter struct {
+	k8sClientSet kubernetes.Interface
+	ctl          *controller.Controller
+	nodeName     string
+	reported     *string
+}
+
+func newHealthReporter(k8sClientSet kubernetes.Interface, ctl *controller.Controller) (*healthReporter, error) {
+	nodeName, ok := os.LookupEnv("NODE_NAME")
+	if !ok {
+		return nil, errors.New("error reading the NODE_NAME from the environment")
+	}
+
+	return &healthReporter{
+		k8sClientSet: k8sClientSet,
+		ctl:          ctl,
+		nodeName:     nodeName,
+	}, nil
+}
+
+func (r *healthReporter) report() {
+	var unhealthy []string
+
+	for _, status := range r.ctl.State().Handlers {
+		if !status.Healthy {
+			unhealthy = append(unhealthy, status.Name)
+		}
+	}
+
+	value := strings.Join(unhealthy, ",")
+	if r.reported != nil && *r.reported == value {
+		return
+	}
+
+	var annotation *string
+	if value != "" {
+		annotation = &value
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]*string{constants.UnhealthyHandlers: annotation},
+		},
+	})
+	if err != nil {
+		logger.Errorf(err, "Error marshalling the handler health patch")
+		return
+	}
+
+	_, err = r.k8sClientSet.CoreV1().Nodes().Patch(context.TODO(), r.nodeName, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		logger.Errorf(err, "Error patching node %q with the unhealthy event handlers %q", r.nodeName, value)
+		return
+	}
+
+	if value != "" {
+		logger.Warningf("Event handlers %q have outstanding failures", value)
+	}
+
+	r.reported = &value
+}
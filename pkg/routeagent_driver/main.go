@@ -19,15 +19,18 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io/fs"
+	"net/http"
 	"os"
 	"strconv"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/submariner-io/admiral/pkg/log"
 	"github.com/submariner-io/admiral/pkg/log/kzerolog"
 	"github.com/submariner-io/admiral/pkg/names"
@@ -43,16 +46,22 @@ import (
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/cabledriver"
 	cniapi "github.com/submariner-io/submariner/pkg/routeagent_driver/cni"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/dryrun"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/environment"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/handlers/calico"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/handlers/cilium"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/handlers/kubeproxy"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/handlers/mtu"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/handlers/netpol"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/handlers/ovn"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/routingpolicy"
 	"github.com/submariner-io/submariner/pkg/versions"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	nodeutil "k8s.io/component-helpers/node/util"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -95,6 +104,20 @@ func main() {
 	cfg, err := clientcmd.BuildConfigFromFlags(masterURL, kubeconfig)
 	logger.FatalOnError(err, "Error building kubeconfig")
 
+	var recorder *dryrun.Recorder
+
+	if env.DryRun {
+		hostname, err := os.Hostname()
+		logger.FatalOnError(err, "Unable to determine hostname")
+
+		recorder = dryrun.NewRecorder(hostname)
+
+		err = dryrun.Enable(recorder)
+		logger.FatalOnError(err, "Error enabling dry run mode")
+
+		cfg = dryrun.WrapConfig(cfg, recorder)
+	}
+
 	k8sClientSet, err := kubernetes.NewForConfig(cfg)
 	logger.FatalOnError(err, "Error building clientset")
 
@@ -119,26 +142,45 @@ func main() {
 		np = cni.Generic
 	}
 
+	if np == cni.OVNKubernetes {
+		logger.Warning("InterClusterNetworkPolicies aren't enforced with the OVN-Kubernetes network plugin")
+	}
+
 	config := &watcher.Config{RestConfig: cfg}
 
+	intraClusterRouting, err := kubeproxy.ParseIntraClusterRouting(env.IntraClusterRouting)
+	logger.FatalOnError(err, "Error reading the intra-cluster routing mode")
+
+	kubeProxyHandler := kubeproxy.NewSyncHandler(env.ClusterCidr, env.ServiceCidr, intraClusterRouting)
+
+	ovnHandlerConfig := &ovn.HandlerConfig{
+		Namespace:     env.Namespace,
+		ClusterCIDR:   env.ClusterCidr,
+		ServiceCIDR:   env.ServiceCidr,
+		SubmClient:    smClientset,
+		K8sClient:     k8sClientSet,
+		DynClient:     dynamicClientSet,
+		WatcherConfig: config,
+	}
+
+	if env.DryRun {
+		ovnHandlerConfig.NewOVSDBClient = dryrun.NewOVSDBClientFunc(recorder)
+	}
+
+	ovnHandler := ovn.NewHandler(ovnHandlerConfig)
+
 	registry, err := event.NewRegistry("routeagent_driver", np,
 		eventlogger.NewHandler(),
-		kubeproxy.NewSyncHandler(env.ClusterCidr, env.ServiceCidr),
-		ovn.NewHandler(&ovn.HandlerConfig{
-			Namespace:     env.Namespace,
-			ClusterCIDR:   env.ClusterCidr,
-			ServiceCIDR:   env.ServiceCidr,
-			SubmClient:    smClientset,
-			K8sClient:     k8sClientSet,
-			DynClient:     dynamicClientSet,
-			WatcherConfig: config,
-		}),
+		kubeProxyHandler,
+		ovnHandler,
 		ovn.NewGatewayRouteHandler(smClientset),
 		ovn.NewNonGatewayRouteHandler(smClientset, k8sClientSet),
 		cabledriver.NewXRFMCleanupHandler(),
 		cabledriver.NewVXLANCleanup(),
-		mtu.NewMTUHandler(env.ClusterCidr, len(env.GlobalCidr) != 0, getTCPMssValue(k8sClientSet)),
-		calico.NewCalicoIPPoolHandler(cfg))
+		mtu.NewMTUHandler(env.ClusterCidr, len(env.GlobalCidr) != 0, getTCPMssValue(k8sClientSet), smClientset, env.Namespace),
+		calico.NewCalicoIPPoolHandler(calicoHandlerConfig(cfg, &env)),
+		cilium.NewHandler(k8sClientSet, dynamicClientSet),
+		netpol.NewHandler(&netpol.HandlerConfig{WatcherConfig: config}))
 
 	logger.FatalOnError(err, "Error registering the handlers")
 
@@ -153,19 +195,89 @@ func main() {
 	}
 
 	ctl, err := controller.New(&controller.Config{
-		Registry:   registry,
-		MasterURL:  masterURL,
-		Kubeconfig: kubeconfig,
+		Registry:          registry,
+		MasterURL:         masterURL,
+		Kubeconfig:        kubeconfig,
+		ReconcileInterval: env.ReconcileInterval,
 	})
 	logger.FatalOnError(err, "Error creating controller for event handling")
 
 	err = ctl.Start(stopCh)
 	logger.FatalOnError(err, "Error starting controller")
 
+	startRoutingPolicyController(&env, np, config, k8sClientSet, dynamicClientSet, kubeProxyHandler, ovnHandler, stopCh)
+
+	httpServers := startHTTPServers(&env, ctl, recorder)
+
+	healthReporter, err := newHealthReporter(k8sClientSet, ctl)
+	if err != nil {
+		logger.Errorf(err, "Unable to report the event handler health on the node")
+	} else {
+		go wait.Until(healthReporter.report, env.HealthReportInterval, stopCh)
+	}
+
 	<-stopCh
 	ctl.Stop()
 
 	logger.Info("All controllers stopped or exited. Stopping submariner-route-agent")
+
+	for _, httpServer := range httpServers {
+		if err := httpServer.Shutdown(context.TODO()); err != nil {
+			logger.Errorf(err, "Error shutting down HTTP server")
+		}
+	}
+}
+
+func startRoutingPolicyController(env *environment.Specification, np string, config *watcher.Config, k8sClientSet kubernetes.Interface,
+	dynamicClientSet dynamic.Interface, kubeProxyHandler, ovnHandler routingpolicy.Dataplane, stopCh <-chan struct{},
+) {
+	routingPolicyDataplane := kubeProxyHandler
+	if np == cni.OVNKubernetes {
+		routingPolicyDataplane = ovnHandler
+	}
+
+	routingPolicyController, err := routingpolicy.New(routingpolicy.Config{
+		Namespace:     env.Namespace,
+		NodeName:      os.Getenv("NODE_NAME"),
+		K8sClient:     k8sClientSet,
+		DynClient:     dynamicClientSet,
+		Dataplane:     routingPolicyDataplane,
+		WatcherConfig: *config,
+		ResyncPeriod:  env.ReconcileInterval,
+	})
+	logger.FatalOnError(err, "Error creating the RoutingPolicy controller")
+
+	err = routingPolicyController.Start(stopCh)
+	logger.FatalOnError(err, "Error starting the RoutingPolicy controller")
+}
+
+func startHTTPServers(env *environment.Specification, ctl *controller.Controller, recorder *dryrun.Recorder) []*http.Server {
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", promhttp.Handler())
+
+	debugMux := http.NewServeMux()
+	debugMux.Handle("/state", ctl)
+
+	if recorder != nil {
+		debugMux.Handle("/diff", recorder)
+	}
+
+	return []*http.Server{
+		startHTTPServer(":"+env.MetricsPort, metricsMux),
+		startHTTPServer("127.0.0.1:"+env.DebugPort, debugMux),
+	}
+}
+
+func startHTTPServer(addr string, handler http.Handler) *http.Server {
+	srv := &http.Server{Addr: addr, Handler: handler, ReadHeaderTimeout: 60 * time.Second}
+
+	go func() {
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf(err, "Error starting HTTP server on %q", addr)
+		}
+	}()
+
+	return srv
 }
 
 func init() {
@@ -189,6 +301,22 @@ func annotateNode(clusterCidr []string, k8sClientSet *kubernetes.Clientset) erro
 	return nil
 }
 
+func calicoHandlerConfig(cfg *rest.Config, env *environment.Specification) *calico.HandlerConfig {
+	config := &calico.HandlerConfig{
+		RestConfig: cfg,
+		NodeName:   os.Getenv("NODE_NAME"),
+	}
+
+	if len(env.CalicoBGPPeers) > 0 {
+		config.BGP = &calico.BGPConfig{
+			PeerIPs:  env.CalicoBGPPeers,
+			ASNumber: env.CalicoBGPASNumber,
+		}
+	}
+
+	return config
+}
+
 func getTCPMssValue(k8sClientSet *kubernetes.Clientset) int {
 	localNode, err := node.GetLocalNode(k8sClientSet)
 	if err != nil {
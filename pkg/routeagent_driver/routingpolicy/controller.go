@@ -0,0 +1,343 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routingpolicy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/admiral/pkg/watcher"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/retry"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var logger = log.Logger{Logger: logf.Log.WithName("RoutingPolicy")}
+
+type Config struct {
+	Namespace     string
+	NodeName      string
+	K8sClient     kubernetes.Interface
+	DynClient     dynamic.Interface
+	Dataplane     Dataplane
+	WatcherConfig watcher.Config
+	// ResyncPeriod specifies how often the policies are re-resolved, to pick up changes to the Pods selected by their
+	// namespace selectors.
+	ResyncPeriod time.Duration
+}
+
+// Controller watches RoutingPolicies, resolves them for the local node, hands them to the network plugin's
+// Dataplane and reports the outcome in the policies' status.
+type Controller struct {
+	config          Config
+	watcher         watcher.Interface
+	informerFactory informers.SharedInformerFactory
+	podFactory      informers.SharedInformerFactory
+	nodeLister      corelisters.NodeLister
+	namespaceLister corelisters.NamespaceLister
+	podLister       corelisters.PodLister
+}
+
+//nolint:gocritic // Ignore hugeParam
+func New(config Config) (*Controller, error) {
+	c := &Controller{
+		config:          config,
+		informerFactory: informers.NewSharedInformerFactory(config.K8sClient, 0),
+		podFactory: informers.NewSharedInformerFactoryWithOptions(config.K8sClient, 0,
+			informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+				options.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", config.NodeName).String()
+			})),
+	}
+
+	c.nodeLister = c.informerFactory.Core().V1().Nodes().Lister()
+	c.namespaceLister = c.informerFactory.Core().V1().Namespaces().Lister()
+	c.podLister = c.podFactory.Core().V1().Pods().Lister()
+
+	config.WatcherConfig.ResyncPeriod = config.ResyncPeriod
+	config.WatcherConfig.ResourceConfigs = []watcher.ResourceConfig{
+		{
+			Name:         "RoutingPolicy watcher",
+			ResourceType: &submarinerv1.RoutingPolicy{},
+			Handler: watcher.EventHandlerFuncs{
+				OnCreateFunc: c.routingPolicyCreatedOrUpdated,
+				OnUpdateFunc: c.routingPolicyCreatedOrUpdated,
+				OnDeleteFunc: c.routingPolicyDeleted,
+			},
+			SourceNamespace: config.Namespace,
+		},
+	}
+
+	var err error
+
+	c.watcher, err = watcher.New(&config.WatcherConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating resource watcher")
+	}
+
+	return c, nil
+}
+
+func (c *Controller) Start(stopCh <-chan struct{}) error {
+	for _, factory := range []informers.SharedInformerFactory{c.informerFactory, c.podFactory} {
+		factory.Start(stopCh)
+
+		for informerType, synced := range factory.WaitForCacheSync(stopCh) {
+			if !synced {
+				return fmt.Errorf("timed out waiting for the %v informer cache to sync", informerType)
+			}
+		}
+	}
+
+	if err := c.watcher.Start(stopCh); err != nil {
+		return errors.Wrap(err, "error starting the RoutingPolicy watcher")
+	}
+
+	logger.Info("Started RoutingPolicy controller")
+
+	return nil
+}
+
+func (c *Controller) routingPolicyCreatedOrUpdated(obj runtime.Object, _ int) bool {
+	policy := obj.(*submarinerv1.RoutingPolicy)
+
+	message, err := c.apply(policy)
+	if err != nil {
+		logger.Errorf(err, "Error applying RoutingPolicy %q", policy.Name)
+		message = err.Error()
+	}
+
+	if statusErr := c.updateStatus(policy.Name, err == nil, message); statusErr != nil {
+		logger.Error(statusErr, "Error reporting the RoutingPolicy status")
+		return true
+	}
+
+	return err != nil
+}
+
+func (c *Controller) routingPolicyDeleted(obj runtime.Object, _ int) bool {
+	policy := obj.(*submarinerv1.RoutingPolicy)
+
+	if err := c.config.Dataplane.RemoveRoutingPolicy(policy.Name); err != nil {
+		logger.Errorf(err, "Error removing RoutingPolicy %q", policy.Name)
+		return true
+	}
+
+	return false
+}
+
+// apply resolves the policy for the local node and programs it. The returned message is reported in the status.
+func (c *Controller) apply(policy *submarinerv1.RoutingPolicy) (string, error) {
+	resolved, message, err := c.resolve(policy)
+	if err != nil {
+		return "", err
+	}
+
+	if policy.Spec.NamespaceSelector != nil && len(resolved.SourceIPs) == 0 {
+		return "No Pods on the node match the namespace selector",
+			c.config.Dataplane.RemoveRoutingPolicy(policy.Name) //nolint:wrapcheck  // Let the caller wrap it
+	}
+
+	if err := c.config.Dataplane.ApplyRoutingPolicy(resolved); err != nil {
+		return "", err //nolint:wrapcheck  // Let the caller wrap it
+	}
+
+	if resolved.IsLocal() && resolved.EgressInterface == "" {
+		return "This node is the policy's gateway node", nil
+	}
+
+	return message, nil
+}
+
+// resolve resolves the policy for the local node. The returned message flags a gateway node that isn't the active
+// gateway.
+func (c *Controller) resolve(policy *submarinerv1.RoutingPolicy) (*Resolved, string, error) {
+	if len(policy.Spec.RemoteCIDRs) == 0 {
+		return nil, "", errors.New("the policy does not specify any remote CIDRs")
+	}
+
+	if policy.Spec.GatewayNode == "" && policy.Spec.EgressInterface == "" {
+		return nil, "", errors.New("the policy must specify a gateway node or an egress interface")
+	}
+
+	resolved := &Resolved{
+		Name:            policy.Name,
+		RemoteCIDRs:     policy.Spec.RemoteCIDRs,
+		EgressInterface: policy.Spec.EgressInterface,
+	}
+
+	message := ""
+
+	if policy.Spec.GatewayNode != "" && policy.Spec.GatewayNode != c.config.NodeName {
+		node, err := c.nodeLister.Get(policy.Spec.GatewayNode)
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "error retrieving gateway node %q", policy.Spec.GatewayNode)
+		}
+
+		haStatus, err := c.gatewayHAStatus(policy.Spec.GatewayNode)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if haStatus != submarinerv1.HAStatusActive {
+			message = fmt.Sprintf("Node %q is not the active gateway, the traffic is forwarded to the active gateway from there",
+				policy.Spec.GatewayNode)
+		}
+
+		resolved.GatewayNode = node
+	}
+
+	if policy.Spec.NamespaceSelector != nil {
+		var err error
+
+		resolved.SourceIPs, err = c.sourceIPsFor(policy.Spec.NamespaceSelector)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	return resolved, message, nil
+}
+
+// gatewayHAStatus returns the HA status of the Gateway running on the given node, or an error if there's none.
+func (c *Controller) gatewayHAStatus(nodeName string) (submarinerv1.HAStatus, error) {
+	obj, err := c.config.DynClient.Resource(submarinerv1.SchemeGroupVersion.WithResource("gateways")).
+		Namespace(c.config.Namespace).Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("node %q is not a Submariner gateway node", nodeName)
+	}
+
+	if err != nil {
+		return "", errors.Wrapf(err, "error retrieving the Gateway for node %q", nodeName)
+	}
+
+	haStatus, _, _ := unstructured.NestedString(obj.Object, "status", "haStatus")
+
+	return submarinerv1.HAStatus(haStatus), nil
+}
+
+// sourceIPsFor returns the IPs of the Pods running on the local node in the namespaces matching the selector.
+func (c *Controller) sourceIPsFor(namespaceSelector *metav1.LabelSelector) ([]string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(namespaceSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid namespace selector")
+	}
+
+	namespaces, err := c.namespaceLister.List(selector)
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing namespaces")
+	}
+
+	var sourceIPs []string
+
+	for i := range namespaces {
+		pods, err := c.podLister.Pods(namespaces[i].Name).List(labels.Everything())
+		if err != nil {
+			return nil, errors.Wrapf(err, "error listing Pods in namespace %q", namespaces[i].Name)
+		}
+
+		for _, pod := range pods {
+			if pod.Spec.NodeName != c.config.NodeName || pod.Spec.HostNetwork || pod.Status.PodIP == "" ||
+				pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+
+			sourceIPs = append(sourceIPs, pod.Status.PodIP)
+		}
+	}
+
+	return sourceIPs, nil
+}
+
+func (c *Controller) updateStatus(name string, applied bool, message string) error {
+	client := c.config.DynClient.Resource(submarinerv1.SchemeGroupVersion.WithResource("routingpolicies")).
+		Namespace(c.config.Namespace)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := client.Get(context.TODO(), name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		if err != nil {
+			return errors.Wrapf(err, "error retrieving RoutingPolicy %q", name)
+		}
+
+		policy := &submarinerv1.RoutingPolicy{}
+
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, policy); err != nil {
+			return errors.Wrap(err, "error converting RoutingPolicy")
+		}
+
+		if !setNodeStatus(&policy.Status, submarinerv1.RoutingPolicyNodeStatus{
+			Name:        c.config.NodeName,
+			Applied:     applied,
+			Message:     message,
+			LastUpdated: metav1.Now(),
+		}) {
+			return nil
+		}
+
+		obj.Object, err = runtime.DefaultUnstructuredConverter.ToUnstructured(policy)
+		if err != nil {
+			return errors.Wrap(err, "error converting RoutingPolicy")
+		}
+
+		_, err = client.UpdateStatus(context.TODO(), &unstructured.Unstructured{Object: obj.Object}, metav1.UpdateOptions{})
+
+		return err //nolint:wrapcheck // We wrap it below in the enclosing function
+	})
+
+	return errors.Wrapf(err, "error updating the status of RoutingPolicy %q", name)
+}
+
+// setNodeStatus sets the status for the node and returns whether it changed. The timestamp alone is not considered a
+// change, to avoid updating the status on every resync.
+func setNodeStatus(status *submarinerv1.RoutingPolicyStatus, nodeStatus submarinerv1.RoutingPolicyNodeStatus) bool {
+	for i := range status.Nodes {
+		if status.Nodes[i].Name != nodeStatus.Name {
+			continue
+		}
+
+		if status.Nodes[i].Applied == nodeStatus.Applied && status.Nodes[i].Message == nodeStatus.Message {
+			return false
+		}
+
+		status.Nodes[i] = nodeStatus
+
+		return true
+	}
+
+	status.Nodes = append(status.Nodes, nodeStatus)
+
+	return true
+}
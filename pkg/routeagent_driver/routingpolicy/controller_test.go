@@ -0,0 +1,303 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routingpolicy_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/admiral/pkg/syncer/test"
+	"github.com/submariner-io/admiral/pkg/watcher"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	fakenetlink "github.com/submariner-io/submariner/pkg/netlink/fake"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/routingpolicy"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	fakek8s "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	namespace     = "submariner-operator"
+	localNode     = "local-node"
+	gatewayNode   = "gateway-node"
+	gatewayNodeIP = "10.0.0.2"
+	remoteCIDR    = "192.0.4.0/24"
+	egressIface   = "eth1"
+	egressIndex   = 5
+	routingTable  = constants.RouteAgentRoutingPolicyTableID
+	vxlanIface    = "vx-submariner"
+)
+
+var _ = Describe("Controller", func() {
+	var (
+		k8sClient *fakek8s.Clientset
+		policies  dynamic.ResourceInterface
+		netLink   *fakenetlink.NetLink
+		policy    *submarinerv1.RoutingPolicy
+		gateway   *submarinerv1.Gateway
+		stopCh    chan struct{}
+	)
+
+	BeforeEach(func() {
+		k8sClient = fakek8s.NewSimpleClientset(&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: gatewayNode},
+			Status: corev1.NodeStatus{
+				Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: gatewayNodeIP}},
+			},
+		})
+
+		netLink = fakenetlink.New()
+		stopCh = make(chan struct{})
+
+		gateway = &submarinerv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      gatewayNode,
+				Namespace: namespace,
+			},
+			Status: submarinerv1.GatewayStatus{HAStatus: submarinerv1.HAStatusActive},
+		}
+
+		policy = &submarinerv1.RoutingPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-policy",
+				Namespace: namespace,
+			},
+			Spec: submarinerv1.RoutingPolicySpec{
+				RemoteCIDRs: []string{remoteCIDR},
+				GatewayNode: gatewayNode,
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		dynClient := fakedynamic.NewSimpleDynamicClient(scheme.Scheme)
+		policies = dynClient.Resource(submarinerv1.SchemeGroupVersion.WithResource("routingpolicies")).Namespace(namespace)
+
+		test.CreateResource(policies, policy)
+
+		if gateway != nil {
+			test.CreateResource(dynClient.Resource(submarinerv1.SchemeGroupVersion.WithResource("gateways")).Namespace(namespace),
+				gateway)
+		}
+
+		controller, err := routingpolicy.New(routingpolicy.Config{
+			Namespace: namespace,
+			NodeName:  localNode,
+			K8sClient: k8sClient,
+			DynClient: dynClient,
+			Dataplane: &hostDataplane{routingpolicy.NewHostDataplane(netLink, vxlanIface)},
+			WatcherConfig: watcher.Config{
+				RestMapper: test.GetRESTMapperFor(&submarinerv1.RoutingPolicy{}),
+				Client:     dynClient,
+			},
+		})
+		Expect(err).To(Succeed())
+		Expect(controller.Start(stopCh)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		close(stopCh)
+	})
+
+	awaitNodeStatus := func(applied bool, message string) {
+		Eventually(func() []submarinerv1.RoutingPolicyNodeStatus {
+			obj := test.GetResource(policies, policy)
+			return obj.Status.Nodes
+		}, 5).Should(ContainElement(And(
+			HaveField("Name", localNode),
+			HaveField("Applied", applied),
+			HaveField("Message", ContainSubstring(message)))))
+	}
+
+	When("a RoutingPolicy pins remote CIDRs to another gateway node", func() {
+		It("should route the remote CIDRs via the gateway node and report it as applied", func() {
+			netLink.AwaitRule(routingTable, "", remoteCIDR)
+			netLink.AwaitGwRoutes(0, routingTable, gatewayNodeIP)
+			awaitNodeStatus(true, "")
+		})
+
+		It("should exclude the traffic arriving from the overlay interface", func() {
+			netLink.AwaitRule(unix.RT_TABLE_MAIN, "", remoteCIDR)
+
+			rules, err := netLink.RuleList(netlink.FAMILY_V4)
+			Expect(err).To(Succeed())
+			Expect(rules).To(ContainElement(And(
+				HaveField("Table", unix.RT_TABLE_MAIN),
+				HaveField("IifName", vxlanIface),
+				HaveField("Priority", constants.RouteAgentRoutingPolicyRulePriority-1))))
+		})
+
+		Context("and is subsequently deleted", func() {
+			It("should remove the rules and routes", func() {
+				netLink.AwaitRule(routingTable, "", remoteCIDR)
+
+				Expect(policies.Delete(context.Background(), policy.Name, metav1.DeleteOptions{})).To(Succeed())
+
+				netLink.AwaitNoRule(routingTable, "", remoteCIDR)
+				netLink.AwaitNoGwRoutes(0, routingTable, gatewayNodeIP)
+			})
+		})
+	})
+
+	When("the gateway node of a RoutingPolicy is not a Submariner gateway node", func() {
+		BeforeEach(func() {
+			gateway = nil
+		})
+
+		It("should report it as not applied", func() {
+			awaitNodeStatus(false, "not a Submariner gateway node")
+			netLink.AwaitNoRule(routingTable, "", remoteCIDR)
+		})
+	})
+
+	When("the gateway node of a RoutingPolicy is not the active gateway", func() {
+		BeforeEach(func() {
+			gateway.Status.HAStatus = submarinerv1.HAStatusPassive
+		})
+
+		It("should report it as applied and flag it", func() {
+			netLink.AwaitRule(routingTable, "", remoteCIDR)
+			awaitNodeStatus(true, "not the active gateway")
+		})
+	})
+
+	When("a RoutingPolicy selects namespaces", func() {
+		BeforeEach(func() {
+			policy.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"pinned": "true"}}
+
+			createNamespace(k8sClient, "pinned", map[string]string{"pinned": "true"})
+			createNamespace(k8sClient, "other", nil)
+			createPod(k8sClient, "pinned", localNode, "10.1.1.5")
+			createPod(k8sClient, "pinned", "other-node", "10.1.1.6")
+			createPod(k8sClient, "other", localNode, "10.1.1.7")
+		})
+
+		It("should only select the traffic from the matching Pods on the local node", func() {
+			netLink.AwaitRule(routingTable, "10.1.1.5/32", remoteCIDR)
+			awaitNodeStatus(true, "")
+
+			rules, err := netLink.RuleList(netlink.FAMILY_V4)
+			Expect(err).To(Succeed())
+			Expect(rules).To(ConsistOf(HaveField("Table", routingTable), HaveField("Table", unix.RT_TABLE_MAIN)))
+		})
+	})
+
+	When("a RoutingPolicy pins remote CIDRs to an egress interface on the local node", func() {
+		BeforeEach(func() {
+			policy.Spec.GatewayNode = localNode
+			policy.Spec.EgressInterface = egressIface
+
+			netLink.SetLinkIndex(egressIface, egressIndex)
+			Expect(netLink.LinkAdd(&netlink.GenericLink{
+				LinkAttrs: netlink.LinkAttrs{Index: egressIndex, Name: egressIface},
+			})).To(Succeed())
+		})
+
+		It("should route the remote CIDRs out of the egress interface", func() {
+			netLink.AwaitRule(routingTable, "", remoteCIDR)
+			netLink.AwaitDstRoutes(egressIndex, routingTable, remoteCIDR)
+			awaitNodeStatus(true, "")
+		})
+	})
+
+	When("the gateway node of a RoutingPolicy does not exist", func() {
+		BeforeEach(func() {
+			policy.Spec.GatewayNode = "missing-node"
+		})
+
+		It("should report it as not applied", func() {
+			awaitNodeStatus(false, "missing-node")
+			netLink.AwaitNoRule(routingTable, "", remoteCIDR)
+		})
+	})
+})
+
+var _ = Describe("ActiveGatewayFilter", func() {
+	var (
+		applied map[string]*routingpolicy.Resolved
+		filter  *routingpolicy.ActiveGatewayFilter
+	)
+
+	BeforeEach(func() {
+		applied = map[string]*routingpolicy.Resolved{}
+		filter = routingpolicy.NewActiveGatewayFilter(func(policy *routingpolicy.Resolved) error {
+			applied[policy.Name] = policy
+			return nil
+		}, func(name string) error {
+			delete(applied, name)
+			return nil
+		})
+
+		Expect(filter.ApplyRoutingPolicy(&routingpolicy.Resolved{Name: "remote", GatewayNode: &corev1.Node{}})).To(Succeed())
+		Expect(filter.ApplyRoutingPolicy(&routingpolicy.Resolved{Name: "local", EgressInterface: egressIface})).To(Succeed())
+	})
+
+	It("should withdraw the policies pinned to another node while on the active gateway", func() {
+		Expect(applied).To(HaveLen(2))
+
+		Expect(filter.SetOnGateway(true)).To(Succeed())
+		Expect(applied).To(HaveLen(1))
+		Expect(applied).To(HaveKey("local"))
+
+		Expect(filter.ApplyRoutingPolicy(&routingpolicy.Resolved{Name: "remote", GatewayNode: &corev1.Node{}})).To(Succeed())
+		Expect(applied).ToNot(HaveKey("remote"))
+
+		Expect(filter.SetOnGateway(false)).To(Succeed())
+		Expect(applied).To(HaveLen(2))
+
+		Expect(filter.RemoveRoutingPolicy("remote")).To(Succeed())
+		Expect(filter.SetOnGateway(false)).To(Succeed())
+		Expect(applied).ToNot(HaveKey("remote"))
+	})
+})
+
+type hostDataplane struct {
+	*routingpolicy.HostDataplane
+}
+
+func (d *hostDataplane) ApplyRoutingPolicy(policy *routingpolicy.Resolved) error {
+	return d.Apply(policy)
+}
+
+func (d *hostDataplane) RemoveRoutingPolicy(name string) error {
+	return d.Remove(name)
+}
+
+func createNamespace(k8sClient *fakek8s.Clientset, name string, labels map[string]string) {
+	_, err := k8sClient.CoreV1().Namespaces().Create(context.Background(), &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+	}, metav1.CreateOptions{})
+	Expect(err).To(Succeed())
+}
+
+func createPod(k8sClient *fakek8s.Clientset, namespace, nodeName, podIP string) {
+	_, err := k8sClient.CoreV1().Pods(namespace).Create(context.Background(), &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-" + podIP},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status:     corev1.PodStatus{PodIP: podIP, Phase: corev1.PodRunning},
+	}, metav1.CreateOptions{})
+	Expect(err).To(Succeed())
+}
@@ -0,0 +1,412 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routingpolicy
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+	netlinkAPI "github.com/submariner-io/submariner/pkg/netlink"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+	corev1 "k8s.io/api/core/v1"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
+	k8snet "k8s.io/utils/net"
+)
+
+// Resolved is a RoutingPolicy resolved for the local node.
+type Resolved struct {
+	Name        string
+	RemoteCIDRs []string
+	// SourceIPs restricts the policy to traffic originating from these addresses. If empty, all traffic to the
+	// remote CIDRs is selected.
+	SourceIPs []string
+	// GatewayNode is the node the selected traffic is steered to. It is nil if the local node is the policy's gateway.
+	GatewayNode     *corev1.Node
+	EgressInterface string
+}
+
+func (r *Resolved) IsLocal() bool {
+	return r.GatewayNode == nil
+}
+
+// Dataplane is implemented by the network plugin specific handlers to program a resolved RoutingPolicy.
+type Dataplane interface {
+	ApplyRoutingPolicy(policy *Resolved) error
+	RemoveRoutingPolicy(name string) error
+}
+
+// GatewayNodeIP returns the IPv4 internal address of the given node.
+func GatewayNodeIP(node *corev1.Node) (net.IP, error) {
+	for i := range node.Status.Addresses {
+		addr := &node.Status.Addresses[i]
+		if addr.Type == corev1.NodeInternalIP && k8snet.IsIPv4String(addr.Address) {
+			return net.ParseIP(addr.Address), nil
+		}
+	}
+
+	return nil, fmt.Errorf("node %q has no IPv4 internal address", node.Name)
+}
+
+// ActiveGatewayFilter wraps a Dataplane to withdraw the policies steering traffic to another node while the local node
+// is the active gateway: the traffic it receives from the other nodes must leave the cluster through its tunnels rather
+// than be sent back into the cluster. The withdrawn policies are re-applied once the local node is no longer the active
+// gateway.
+type ActiveGatewayFilter struct {
+	mutex     sync.Mutex
+	apply     func(policy *Resolved) error
+	remove    func(name string) error
+	policies  map[string]*Resolved
+	onGateway bool
+}
+
+func NewActiveGatewayFilter(apply func(policy *Resolved) error, remove func(name string) error) *ActiveGatewayFilter {
+	return &ActiveGatewayFilter{
+		apply:    apply,
+		remove:   remove,
+		policies: map[string]*Resolved{},
+	}
+}
+
+func (f *ActiveGatewayFilter) ApplyRoutingPolicy(policy *Resolved) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.policies[policy.Name] = policy
+
+	return f.applyOrWithdraw(policy)
+}
+
+func (f *ActiveGatewayFilter) RemoveRoutingPolicy(name string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	delete(f.policies, name)
+
+	return f.remove(name)
+}
+
+// SetOnGateway records whether the local node is the active gateway and withdraws or re-applies the policies steering
+// traffic to another node accordingly.
+func (f *ActiveGatewayFilter) SetOnGateway(onGateway bool) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.onGateway = onGateway
+
+	var errs []error
+
+	for _, policy := range f.policies {
+		if err := f.applyOrWithdraw(policy); err != nil {
+			errs = append(errs, errors.Wrapf(err, "error updating RoutingPolicy %q", policy.Name))
+		}
+	}
+
+	return errorutils.NewAggregate(errs)
+}
+
+func (f *ActiveGatewayFilter) applyOrWithdraw(policy *Resolved) error {
+	if f.onGateway && !policy.IsLocal() {
+		return f.remove(policy.Name)
+	}
+
+	return f.apply(policy)
+}
+
+type hostState struct {
+	rules  []netlink.Rule
+	routes []netlink.Route
+}
+
+// HostDataplane programs RoutingPolicies in the host routing tables: a rule per remote CIDR and source selects
+// the routing policy table, which contains a route per remote CIDR via the gateway node or the egress interface.
+// Since a rule can't exclude an input interface, traffic to the remote CIDRs arriving from the overlay interface, if
+// any, is sent to the main table by a rule preceding them so traffic forwarded by other nodes is never steered back.
+type HostDataplane struct {
+	mutex            sync.Mutex
+	netLink          netlinkAPI.Interface
+	overlayInterface string
+	applied          map[string]*hostState
+}
+
+func NewHostDataplane(netLink netlinkAPI.Interface, overlayInterface string) *HostDataplane {
+	return &HostDataplane{
+		netLink:          netLink,
+		overlayInterface: overlayInterface,
+		applied:          map[string]*hostState{},
+	}
+}
+
+// Flush removes all the routing policy rules and routes from the host.
+func (d *HostDataplane) Flush() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.applied = map[string]*hostState{}
+
+	rules, err := d.netLink.RuleList(netlink.FAMILY_V4)
+	if err != nil {
+		return errors.Wrap(err, "error listing rules")
+	}
+
+	for i := range rules {
+		if rules[i].Table == constants.RouteAgentRoutingPolicyTableID || d.isOverlayRule(&rules[i]) {
+			if err := d.netLink.RuleDelIfPresent(&rules[i]); err != nil {
+				return err //nolint:wrapcheck  // Already wrapped
+			}
+		}
+	}
+
+	return errors.Wrapf(d.netLink.FlushRouteTable(constants.RouteAgentRoutingPolicyTableID),
+		"error flushing routing table %d", constants.RouteAgentRoutingPolicyTableID)
+}
+
+func (d *HostDataplane) Apply(policy *Resolved) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	desired, err := d.desiredState(policy)
+	if err != nil {
+		return err
+	}
+
+	for i := range desired.routes {
+		if owner := d.conflictingPolicy(policy.Name, &desired.routes[i]); owner != "" {
+			return fmt.Errorf("remote CIDR %s is already routed differently by RoutingPolicy %q", desired.routes[i].Dst, owner)
+		}
+	}
+
+	if err := d.removeStale(policy.Name, desired); err != nil {
+		return err
+	}
+
+	for i := range desired.rules {
+		if err := d.netLink.RuleAddIfNotPresent(&desired.rules[i]); err != nil {
+			return err //nolint:wrapcheck  // Already wrapped
+		}
+	}
+
+	for i := range desired.routes {
+		if err := d.netLink.RouteAddOrReplace(&desired.routes[i]); err != nil {
+			return errors.Wrapf(err, "error adding route %s", desired.routes[i])
+		}
+	}
+
+	d.applied[policy.Name] = desired
+
+	return nil
+}
+
+func (d *HostDataplane) Remove(name string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if err := d.removeStale(name, &hostState{}); err != nil {
+		return err
+	}
+
+	delete(d.applied, name)
+
+	return nil
+}
+
+// removeStale removes the rules and routes previously applied for the named policy that aren't in the desired state,
+// leaving routes still used by other policies.
+func (d *HostDataplane) removeStale(name string, desired *hostState) error {
+	current, ok := d.applied[name]
+	if !ok {
+		return nil
+	}
+
+	for i := range current.rules {
+		if !containsRule(desired.rules, &current.rules[i]) {
+			if err := d.netLink.RuleDelIfPresent(&current.rules[i]); err != nil {
+				return err //nolint:wrapcheck  // Already wrapped
+			}
+		}
+	}
+
+	for i := range current.routes {
+		if containsRoute(desired.routes, &current.routes[i]) || d.routeInUse(name, &current.routes[i]) {
+			continue
+		}
+
+		if err := d.netLink.RouteDel(&current.routes[i]); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "error deleting route %s", current.routes[i])
+		}
+	}
+
+	current.rules = desired.rules
+	current.routes = desired.routes
+
+	return nil
+}
+
+func (d *HostDataplane) conflictingPolicy(name string, route *netlink.Route) string {
+	for other, state := range d.applied {
+		if other == name {
+			continue
+		}
+
+		for i := range state.routes {
+			if state.routes[i].Dst.String() == route.Dst.String() && routeKey(&state.routes[i]) != routeKey(route) {
+				return other
+			}
+		}
+	}
+
+	return ""
+}
+
+func (d *HostDataplane) routeInUse(name string, route *netlink.Route) bool {
+	for other, state := range d.applied {
+		if other != name && containsRoute(state.routes, route) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d *HostDataplane) desiredState(policy *Resolved) (*hostState, error) {
+	state := &hostState{}
+
+	if policy.IsLocal() && policy.EgressInterface == "" {
+		// Traffic already leaves the cluster from this node, there's nothing to steer.
+		return state, nil
+	}
+
+	route := netlink.Route{
+		Table: constants.RouteAgentRoutingPolicyTableID,
+	}
+
+	if policy.IsLocal() {
+		link, err := d.netLink.LinkByName(policy.EgressInterface)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error retrieving egress interface %q", policy.EgressInterface)
+		}
+
+		route.LinkIndex = link.Attrs().Index
+		route.Scope = unix.RT_SCOPE_LINK
+	} else {
+		gwIP, err := GatewayNodeIP(policy.GatewayNode)
+		if err != nil {
+			return nil, err
+		}
+
+		route.Gw = gwIP
+	}
+
+	for _, remoteCIDR := range policy.RemoteCIDRs {
+		_, dst, err := net.ParseCIDR(remoteCIDR)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing remote CIDR %q", remoteCIDR)
+		}
+
+		r := route
+		r.Dst = dst
+		state.routes = append(state.routes, r)
+
+		if d.overlayInterface != "" {
+			state.rules = append(state.rules, *d.newOverlayRule(dst))
+		}
+
+		if len(policy.SourceIPs) == 0 {
+			state.rules = append(state.rules, *newRule(nil, dst))
+			continue
+		}
+
+		for _, sourceIP := range policy.SourceIPs {
+			ip := net.ParseIP(sourceIP)
+			if ip == nil || ip.To4() == nil {
+				continue
+			}
+
+			state.rules = append(state.rules, *newRule(&net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)}, dst))
+		}
+	}
+
+	return state, nil
+}
+
+func newRule(src, dst *net.IPNet) *netlink.Rule {
+	rule := netlink.NewRule()
+	rule.Table = constants.RouteAgentRoutingPolicyTableID
+	rule.Priority = constants.RouteAgentRoutingPolicyRulePriority
+	rule.Family = netlink.FAMILY_V4
+	rule.Src = src
+	rule.Dst = dst
+
+	return rule
+}
+
+// newOverlayRule returns the rule sending the traffic to the given remote CIDR arriving from the overlay interface to the
+// main table, ahead of the routing policy rules.
+func (d *HostDataplane) newOverlayRule(dst *net.IPNet) *netlink.Rule {
+	rule := netlink.NewRule()
+	rule.Table = unix.RT_TABLE_MAIN
+	rule.Priority = constants.RouteAgentRoutingPolicyRulePriority - 1
+	rule.Family = netlink.FAMILY_V4
+	rule.IifName = d.overlayInterface
+	rule.Dst = dst
+
+	return rule
+}
+
+func (d *HostDataplane) isOverlayRule(r *netlink.Rule) bool {
+	return d.overlayInterface != "" && r.IifName == d.overlayInterface && r.Table == unix.RT_TABLE_MAIN &&
+		r.Priority == constants.RouteAgentRoutingPolicyRulePriority-1
+}
+
+func ruleKey(r *netlink.Rule) string {
+	src := ""
+	if r.Src != nil {
+		src = r.Src.String()
+	}
+
+	return fmt.Sprintf("%s>%s/%s/%d", src, r.Dst, r.IifName, r.Table)
+}
+
+func routeKey(r *netlink.Route) string {
+	return fmt.Sprintf("%s/%s/%d", r.Dst, r.Gw, r.LinkIndex)
+}
+
+func containsRule(rules []netlink.Rule, rule *netlink.Rule) bool {
+	for i := range rules {
+		if ruleKey(&rules[i]) == ruleKey(rule) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsRoute(routes []netlink.Route, route *netlink.Route) bool {
+	for i := range routes {
+		if routeKey(&routes[i]) == routeKey(route) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -48,5 +48,14 @@ type SubmarinerSpecification struct {
 	HaltOnCertError               bool `split_words:"true"`
 	HealthCheckInterval           uint
 	HealthCheckMaxPacketLossCount uint
+	PathMTUDiscoveryInterval      uint   `default:"300"`
 	MetricsPort                   string `default:"32780"`
+	PeeringAllowedClusters        []string
+	PeeringDeniedClusters         []string
+	PeeringClusterSelector        string
+	TransitEnabled                bool
+	TransitClusters               []string
+	WebhookPort                   int
+	WebhookCertDir                string
+	StaleEndpointTTL              uint
 }
@@ -0,0 +1,114 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	submarinerv2 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v2"
+	"github.com/submariner-io/submariner/pkg/webhook"
+	apix "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Conversion webhook", func() {
+	var handler http.Handler
+
+	BeforeEach(func() {
+		var err error
+
+		handler, err = webhook.NewConversionHandler()
+		Expect(err).To(Succeed())
+	})
+
+	convert := func(obj runtime.Object, desiredAPIVersion string) *apix.ConversionResponse {
+		raw, err := json.Marshal(obj)
+		Expect(err).To(Succeed())
+
+		review, err := json.Marshal(&apix.ConversionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: apix.SchemeGroupVersion.String(), Kind: "ConversionReview"},
+			Request: &apix.ConversionRequest{
+				UID:               types.UID("1234"),
+				DesiredAPIVersion: desiredAPIVersion,
+				Objects:           []runtime.RawExtension{{Raw: raw}},
+			},
+		})
+		Expect(err).To(Succeed())
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, webhook.ConversionPath, bytes.NewReader(review)))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		response := &apix.ConversionReview{}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), response)).To(Succeed())
+		Expect(response.Response).ToNot(BeNil())
+		Expect(response.Response.Result.Status).To(Equal(metav1.StatusSuccess), "%s", response.Response.Result.Message)
+		Expect(response.Response.ConvertedObjects).To(HaveLen(1))
+
+		return response.Response
+	}
+
+	When("a v1 Endpoint is converted to v2", func() {
+		It("should return the v2 Endpoint with typed backend config", func() {
+			response := convert(&submarinerv1.Endpoint{
+				TypeMeta:   metav1.TypeMeta{APIVersion: submarinerv1.SchemeGroupVersion.String(), Kind: "Endpoint"},
+				ObjectMeta: metav1.ObjectMeta{Name: "east-cable", Namespace: "submariner-operator"},
+				Spec: submarinerv1.EndpointSpec{
+					ClusterID:     "east",
+					CableName:     "cable",
+					BackendConfig: map[string]string{submarinerv1.UDPPortConfig: "4500"},
+				},
+			}, submarinerv2.SchemeGroupVersion.String())
+
+			endpoint := &submarinerv2.Endpoint{}
+			Expect(json.Unmarshal(response.ConvertedObjects[0].Raw, endpoint)).To(Succeed())
+			Expect(endpoint.APIVersion).To(Equal(submarinerv2.SchemeGroupVersion.String()))
+			Expect(endpoint.Name).To(Equal("east-cable"))
+			Expect(endpoint.Spec.ClusterID).To(Equal("east"))
+			Expect(endpoint.Spec.BackendConfig.UDPPort).To(HaveValue(Equal(int32(4500))))
+		})
+	})
+
+	When("a v2 Cluster is converted to v1", func() {
+		It("should return the v1 Cluster", func() {
+			response := convert(&submarinerv2.Cluster{
+				TypeMeta:   metav1.TypeMeta{APIVersion: submarinerv2.SchemeGroupVersion.String(), Kind: "Cluster"},
+				ObjectMeta: metav1.ObjectMeta{Name: "east", Namespace: "submariner-operator"},
+				Spec: submarinerv2.ClusterSpec{
+					ClusterID:   "east",
+					ServiceCIDR: []string{"100.0.0.0/16"},
+				},
+			}, submarinerv1.SchemeGroupVersion.String())
+
+			cluster := &submarinerv1.Cluster{}
+			Expect(json.Unmarshal(response.ConvertedObjects[0].Raw, cluster)).To(Succeed())
+			Expect(cluster.APIVersion).To(Equal(submarinerv1.SchemeGroupVersion.String()))
+			Expect(cluster.Spec.ClusterID).To(Equal("east"))
+			Expect(cluster.Spec.ServiceCIDR).To(Equal([]string{"100.0.0.0/16"}))
+		})
+	})
+})
@@ -0,0 +1,86 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	submarinerv2 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v2"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+// ConversionPath is the path the CRD conversion webhook is served on.
+const ConversionPath = "/convert"
+
+// NewScheme returns a scheme with all the served versions of the submariner.io API.
+func NewScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+
+	if err := submarinerv1.AddToScheme(scheme); err != nil {
+		return nil, errors.Wrap(err, "error adding the submariner.io/v1 types to the scheme")
+	}
+
+	if err := submarinerv2.AddToScheme(scheme); err != nil {
+		return nil, errors.Wrap(err, "error adding the submariner.io/v2 types to the scheme")
+	}
+
+	return scheme, nil
+}
+
+// NewConversionHandler returns the handler converting submariner.io objects between the served API versions, so clusters
+// running different versions can keep exchanging them through the broker.
+func NewConversionHandler() (http.Handler, error) {
+	scheme, err := NewScheme()
+	if err != nil {
+		return nil, err
+	}
+
+	return conversion.NewWebhookHandler(scheme), nil
+}
+
+// NewServer returns a webhook server serving the conversion and validation webhooks on the given port, using the TLS
+// certificate and key in the given directory.
+func NewServer(port int, certDir string) (webhook.Server, error) {
+	conversionHandler, err := NewConversionHandler()
+	if err != nil {
+		return nil, err
+	}
+
+	validationHandlers, err := NewValidationHandlers()
+	if err != nil {
+		return nil, err
+	}
+
+	server := webhook.NewServer(webhook.Options{
+		Port:    port,
+		CertDir: certDir,
+	})
+
+	server.Register(ConversionPath, conversionHandler)
+
+	for path, handler := range validationHandlers {
+		server.Register(path, handler)
+	}
+
+	return server, nil
+}
@@ -0,0 +1,262 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/cidr"
+	"github.com/submariner-io/submariner/pkg/globalnet/constants"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Paths the validating admission webhooks are served on.
+const (
+	ValidateEndpointPath              = "/validate-submariner-io-v1-endpoint"
+	ValidateGlobalEgressIPPath        = "/validate-submariner-io-v1-globalegressip"
+	ValidateClusterGlobalEgressIPPath = "/validate-submariner-io-v1-clusterglobalegressip"
+	ValidateGlobalIngressIPPath       = "/validate-submariner-io-v1-globalingressip"
+)
+
+// maxNumberOfIPs mirrors the maximum enforced by the GlobalEgressIP and ClusterGlobalEgressIP CRD schemas.
+const maxNumberOfIPs = 20
+
+type objectValidator struct {
+	kind     string
+	validate func(obj runtime.Object) field.ErrorList
+}
+
+// NewValidationHandlers returns the handlers rejecting invalid submariner.io objects on creation and update, keyed by
+// the path they're served on.
+func NewValidationHandlers() (map[string]http.Handler, error) {
+	scheme, err := NewScheme()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]http.Handler{
+		ValidateEndpointPath: admission.WithCustomValidator(scheme, &submarinerv1.Endpoint{}, &objectValidator{
+			kind: "Endpoint",
+			validate: func(obj runtime.Object) field.ErrorList {
+				return validateEndpoint(obj.(*submarinerv1.Endpoint))
+			},
+		}),
+		ValidateGlobalEgressIPPath: admission.WithCustomValidator(scheme, &submarinerv1.GlobalEgressIP{}, &objectValidator{
+			kind: "GlobalEgressIP",
+			validate: func(obj runtime.Object) field.ErrorList {
+				return validateGlobalEgressIP(obj.(*submarinerv1.GlobalEgressIP))
+			},
+		}),
+		ValidateClusterGlobalEgressIPPath: admission.WithCustomValidator(scheme, &submarinerv1.ClusterGlobalEgressIP{}, &objectValidator{
+			kind: "ClusterGlobalEgressIP",
+			validate: func(obj runtime.Object) field.ErrorList {
+				return validateClusterGlobalEgressIP(obj.(*submarinerv1.ClusterGlobalEgressIP))
+			},
+		}),
+		ValidateGlobalIngressIPPath: admission.WithCustomValidator(scheme, &submarinerv1.GlobalIngressIP{}, &objectValidator{
+			kind: "GlobalIngressIP",
+			validate: func(obj runtime.Object) field.ErrorList {
+				return validateGlobalIngressIP(obj.(*submarinerv1.GlobalIngressIP))
+			},
+		}),
+	}, nil
+}
+
+func (v *objectValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validateObject(obj)
+}
+
+func (v *objectValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validateObject(newObj)
+}
+
+func (v *objectValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *objectValidator) validateObject(obj runtime.Object) error {
+	errs := v.validate(obj)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(submarinerv1.SchemeGroupVersion.WithKind(v.kind).GroupKind(), obj.(metav1.Object).GetName(), errs)
+}
+
+func validateEndpoint(endpoint *submarinerv1.Endpoint) field.ErrorList {
+	var errs field.ErrorList
+
+	specPath := field.NewPath("spec")
+
+	if endpoint.Spec.ClusterID == "" {
+		errs = append(errs, field.Required(specPath.Child("cluster_id"), "the cluster ID must be specified"))
+	}
+
+	errs = append(errs, validateCIDRs(endpoint.Spec.Subnets, specPath.Child("subnets"))...)
+
+	for _, ip := range []struct {
+		name  string
+		value string
+	}{
+		{"private_ip", endpoint.Spec.PrivateIP},
+		{"public_ip", endpoint.Spec.PublicIP},
+		{"healthCheckIP", endpoint.Spec.HealthCheckIP},
+	} {
+		if ip.value != "" && net.ParseIP(ip.value) == nil {
+			errs = append(errs, field.Invalid(specPath.Child(ip.name), ip.value, "must be a valid IP address"))
+		}
+	}
+
+	for i := range endpoint.Spec.TransitRoutes {
+		errs = append(errs, validateCIDRs(endpoint.Spec.TransitRoutes[i].Subnets,
+			specPath.Child("transitRoutes").Index(i).Child("subnets"))...)
+	}
+
+	return errs
+}
+
+func validateCIDRs(cidrs []string, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	for i, c := range cidrs {
+		if err := cidr.Validate(c); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Index(i), c, err.Error()))
+		}
+	}
+
+	return errs
+}
+
+func validateGlobalEgressIP(egressIP *submarinerv1.GlobalEgressIP) field.ErrorList {
+	specPath := field.NewPath("spec")
+
+	errs := validateNumberOfIPs(egressIP.Spec.NumberOfIPs, specPath.Child("numberOfIPs"))
+	errs = append(errs, validateLabelSelector(egressIP.Spec.PodSelector, specPath.Child("podSelector"))...)
+
+	if egressIP.Spec.BandwidthLimit != nil && egressIP.Spec.BandwidthLimit.Sign() <= 0 {
+		errs = append(errs, field.Invalid(specPath.Child("bandwidthLimit"), egressIP.Spec.BandwidthLimit.String(),
+			"must be greater than 0"))
+	}
+
+	if egressIP.Spec.MaxConnections != nil && *egressIP.Spec.MaxConnections < 1 {
+		errs = append(errs, field.Invalid(specPath.Child("maxConnections"), *egressIP.Spec.MaxConnections,
+			"must be greater than 0"))
+	}
+
+	return errs
+}
+
+// validateClusterGlobalEgressIP enforces the same rules as the globalnet ClusterGlobalEgressIP controller, so invalid
+// instances are rejected up front rather than reported through their status.
+func validateClusterGlobalEgressIP(egressIP *submarinerv1.ClusterGlobalEgressIP) field.ErrorList {
+	var errs field.ErrorList
+
+	specPath := field.NewPath("spec")
+	hasSelectors := egressIP.Spec.PodSelector != nil || egressIP.Spec.NamespaceSelector != nil
+
+	if egressIP.Name != constants.ClusterGlobalEgressIPName && !hasSelectors {
+		errs = append(errs, field.Invalid(field.NewPath("metadata", "name"), egressIP.Name,
+			fmt.Sprintf("only the ClusterGlobalEgressIP instance with the well-known name %q is supported "+
+				"without a podSelector or namespaceSelector", constants.ClusterGlobalEgressIPName)))
+	}
+
+	if egressIP.Name == constants.ClusterGlobalEgressIPName && hasSelectors {
+		errs = append(errs, field.Forbidden(specPath,
+			"the podSelector and namespaceSelector are not supported for the well-known instance"))
+	}
+
+	errs = append(errs, validateNumberOfIPs(egressIP.Spec.NumberOfIPs, specPath.Child("numGlobalIPs"))...)
+	errs = append(errs, validateLabelSelector(egressIP.Spec.PodSelector, specPath.Child("podSelector"))...)
+
+	return append(errs, validateLabelSelector(egressIP.Spec.NamespaceSelector, specPath.Child("namespaceSelector"))...)
+}
+
+func validateNumberOfIPs(numberOfIPs *int, fldPath *field.Path) field.ErrorList {
+	if numberOfIPs == nil {
+		return nil
+	}
+
+	if *numberOfIPs < 0 {
+		return field.ErrorList{field.Invalid(fldPath, *numberOfIPs, "cannot be negative")}
+	}
+
+	if *numberOfIPs > maxNumberOfIPs {
+		return field.ErrorList{field.Invalid(fldPath, *numberOfIPs, fmt.Sprintf("cannot be greater than %d", maxNumberOfIPs))}
+	}
+
+	return nil
+}
+
+func validateLabelSelector(selector *metav1.LabelSelector, fldPath *field.Path) field.ErrorList {
+	if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, selector, fmt.Sprintf("invalid label selector: %v", err))}
+	}
+
+	return nil
+}
+
+func validateGlobalIngressIP(ingressIP *submarinerv1.GlobalIngressIP) field.ErrorList {
+	var errs field.ErrorList
+
+	specPath := field.NewPath("spec")
+	hasServiceRef := ingressIP.Spec.ServiceRef != nil
+	hasPodRef := ingressIP.Spec.PodRef != nil
+
+	requireRef := func(name string, present bool) {
+		if !present {
+			errs = append(errs, field.Required(specPath.Child(name),
+				fmt.Sprintf("%s must be specified for the %s target", name, ingressIP.Spec.Target)))
+		}
+	}
+
+	forbidRef := func(name string, present bool) {
+		if present {
+			errs = append(errs, field.Forbidden(specPath.Child(name),
+				fmt.Sprintf("%s cannot be specified for the %s target", name, ingressIP.Spec.Target)))
+		}
+	}
+
+	switch ingressIP.Spec.Target {
+	case submarinerv1.ClusterIPService, submarinerv1.HeadlessServiceEndpoints:
+		requireRef("serviceRef", hasServiceRef)
+		forbidRef("podRef", hasPodRef)
+	case submarinerv1.HeadlessServicePod:
+		// A headless Service's Pod is referenced along with its Service.
+		requireRef("serviceRef", hasServiceRef)
+		requireRef("podRef", hasPodRef)
+	case submarinerv1.Pod:
+		requireRef("podRef", hasPodRef)
+		forbidRef("serviceRef", hasServiceRef)
+	default:
+		errs = append(errs, field.NotSupported(specPath.Child("target"), ingressIP.Spec.Target, []string{
+			string(submarinerv1.ClusterIPService), string(submarinerv1.HeadlessServicePod),
+			string(submarinerv1.HeadlessServiceEndpoints), string(submarinerv1.Pod),
+		}))
+	}
+
+	return errs
+}
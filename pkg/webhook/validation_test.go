@@ -0,0 +1,256 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/globalnet/constants"
+	"github.com/submariner-io/submariner/pkg/webhook"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("Validation webhook", func() {
+	var handlers map[string]http.Handler
+
+	BeforeEach(func() {
+		var err error
+
+		handlers, err = webhook.NewValidationHandlers()
+		Expect(err).To(Succeed())
+	})
+
+	review := func(path string, obj runtime.Object) *admissionv1.AdmissionResponse {
+		raw, err := json.Marshal(obj)
+		Expect(err).To(Succeed())
+
+		gvk := obj.GetObjectKind().GroupVersionKind()
+
+		body, err := json.Marshal(&admissionv1.AdmissionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: admissionv1.SchemeGroupVersion.String(), Kind: "AdmissionReview"},
+			Request: &admissionv1.AdmissionRequest{
+				UID:       types.UID("1234"),
+				Kind:      metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		})
+		Expect(err).To(Succeed())
+
+		Expect(handlers).To(HaveKey(path))
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		handlers[path].ServeHTTP(recorder, request)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		response := &admissionv1.AdmissionReview{}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), response)).To(Succeed())
+		Expect(response.Response).ToNot(BeNil())
+
+		return response.Response
+	}
+
+	expectAllowed := func(path string, obj runtime.Object) {
+		response := review(path, obj)
+		Expect(response.Allowed).To(BeTrue(), "%v", response.Result)
+	}
+
+	expectDenied := func(path string, obj runtime.Object, messages ...string) {
+		response := review(path, obj)
+		Expect(response.Allowed).To(BeFalse())
+		Expect(response.Result).ToNot(BeNil())
+
+		for _, m := range messages {
+			Expect(response.Result.Message).To(ContainSubstring(m))
+		}
+	}
+
+	typeMeta := func(kind string) metav1.TypeMeta {
+		return metav1.TypeMeta{APIVersion: submarinerv1.SchemeGroupVersion.String(), Kind: kind}
+	}
+
+	Context("for an Endpoint", func() {
+		var endpoint *submarinerv1.Endpoint
+
+		BeforeEach(func() {
+			endpoint = &submarinerv1.Endpoint{
+				TypeMeta:   typeMeta("Endpoint"),
+				ObjectMeta: metav1.ObjectMeta{Name: "east-cable"},
+				Spec: submarinerv1.EndpointSpec{
+					ClusterID: "east",
+					CableName: "cable",
+					Subnets:   []string{"10.0.0.0/16", "fd00::/64"},
+					PrivateIP: "192.168.0.1",
+					PublicIP:  "1.2.3.4",
+				},
+			}
+		})
+
+		It("should allow a valid Endpoint", func() {
+			expectAllowed(webhook.ValidateEndpointPath, endpoint)
+		})
+
+		When("a subnet isn't a valid CIDR", func() {
+			It("should deny the Endpoint", func() {
+				endpoint.Spec.Subnets = append(endpoint.Spec.Subnets, "10.1.0.0")
+				expectDenied(webhook.ValidateEndpointPath, endpoint, "spec.subnets[2]", `"10.1.0.0"`)
+			})
+		})
+
+		When("a transit route subnet isn't a valid CIDR", func() {
+			It("should deny the Endpoint", func() {
+				endpoint.Spec.TransitRoutes = []submarinerv1.TransitRoute{{ClusterID: "west", Subnets: []string{"bogus"}}}
+				expectDenied(webhook.ValidateEndpointPath, endpoint, "spec.transitRoutes[0].subnets[0]")
+			})
+		})
+
+		When("the private IP isn't a valid IP address", func() {
+			It("should deny the Endpoint", func() {
+				endpoint.Spec.PrivateIP = "192.168.0"
+				expectDenied(webhook.ValidateEndpointPath, endpoint, "spec.private_ip", "must be a valid IP address")
+			})
+		})
+	})
+
+	Context("for a GlobalEgressIP", func() {
+		var egressIP *submarinerv1.GlobalEgressIP
+
+		BeforeEach(func() {
+			egressIP = &submarinerv1.GlobalEgressIP{
+				TypeMeta:   typeMeta("GlobalEgressIP"),
+				ObjectMeta: metav1.ObjectMeta{Name: "egress", Namespace: "default"},
+				Spec: submarinerv1.GlobalEgressIPSpec{
+					NumberOfIPs:    ptr.To(2),
+					BandwidthLimit: ptr.To(resource.MustParse("10M")),
+				},
+			}
+		})
+
+		It("should allow a valid GlobalEgressIP", func() {
+			expectAllowed(webhook.ValidateGlobalEgressIPPath, egressIP)
+		})
+
+		When("the NumberOfIPs is negative", func() {
+			It("should deny the GlobalEgressIP", func() {
+				egressIP.Spec.NumberOfIPs = ptr.To(-1)
+				expectDenied(webhook.ValidateGlobalEgressIPPath, egressIP, "spec.numberOfIPs", "cannot be negative")
+			})
+		})
+
+		When("the MaxConnections is 0", func() {
+			It("should deny the GlobalEgressIP", func() {
+				egressIP.Spec.MaxConnections = ptr.To(0)
+				expectDenied(webhook.ValidateGlobalEgressIPPath, egressIP, "spec.maxConnections")
+			})
+		})
+	})
+
+	Context("for a ClusterGlobalEgressIP", func() {
+		var egressIP *submarinerv1.ClusterGlobalEgressIP
+
+		BeforeEach(func() {
+			egressIP = &submarinerv1.ClusterGlobalEgressIP{
+				TypeMeta:   typeMeta("ClusterGlobalEgressIP"),
+				ObjectMeta: metav1.ObjectMeta{Name: constants.ClusterGlobalEgressIPName},
+				Spec:       submarinerv1.ClusterGlobalEgressIPSpec{NumberOfIPs: ptr.To(5)},
+			}
+		})
+
+		It("should allow the well-known instance", func() {
+			expectAllowed(webhook.ValidateClusterGlobalEgressIPPath, egressIP)
+		})
+
+		When("an instance other than the well-known one has no selectors", func() {
+			It("should deny the ClusterGlobalEgressIP", func() {
+				egressIP.Name = "other"
+				expectDenied(webhook.ValidateClusterGlobalEgressIPPath, egressIP, "metadata.name", constants.ClusterGlobalEgressIPName)
+			})
+		})
+
+		When("an instance other than the well-known one has a selector", func() {
+			It("should allow the ClusterGlobalEgressIP", func() {
+				egressIP.Name = "other"
+				egressIP.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": "red"}}
+				expectAllowed(webhook.ValidateClusterGlobalEgressIPPath, egressIP)
+			})
+		})
+
+		When("the well-known instance has a selector", func() {
+			It("should deny the ClusterGlobalEgressIP", func() {
+				egressIP.Spec.PodSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+				expectDenied(webhook.ValidateClusterGlobalEgressIPPath, egressIP, "not supported for the well-known instance")
+			})
+		})
+	})
+
+	Context("for a GlobalIngressIP", func() {
+		var ingressIP *submarinerv1.GlobalIngressIP
+
+		BeforeEach(func() {
+			ingressIP = &submarinerv1.GlobalIngressIP{
+				TypeMeta:   typeMeta("GlobalIngressIP"),
+				ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "default"},
+				Spec: submarinerv1.GlobalIngressIPSpec{
+					Target:     submarinerv1.ClusterIPService,
+					ServiceRef: &corev1.LocalObjectReference{Name: "nginx"},
+				},
+			}
+		})
+
+		It("should allow a valid GlobalIngressIP", func() {
+			expectAllowed(webhook.ValidateGlobalIngressIPPath, ingressIP)
+		})
+
+		When("both the ServiceRef and PodRef are specified for a ClusterIPService target", func() {
+			It("should deny the GlobalIngressIP", func() {
+				ingressIP.Spec.PodRef = &corev1.LocalObjectReference{Name: "nginx-pod"}
+				expectDenied(webhook.ValidateGlobalIngressIPPath, ingressIP, "spec.podRef", "cannot be specified")
+			})
+		})
+
+		When("both the ServiceRef and PodRef are specified for a HeadlessServicePod target", func() {
+			It("should allow the GlobalIngressIP", func() {
+				ingressIP.Spec.Target = submarinerv1.HeadlessServicePod
+				ingressIP.Spec.PodRef = &corev1.LocalObjectReference{Name: "nginx-pod"}
+				expectAllowed(webhook.ValidateGlobalIngressIPPath, ingressIP)
+			})
+		})
+
+		When("the PodRef isn't specified for a Pod target", func() {
+			It("should deny the GlobalIngressIP", func() {
+				ingressIP.Spec.Target = submarinerv1.Pod
+				expectDenied(webhook.ValidateGlobalIngressIPPath, ingressIP, "spec.podRef", "spec.serviceRef")
+			})
+		})
+	})
+})
@@ -0,0 +1,77 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// The submariner-webhook serves the submariner.io conversion and validation webhooks on its own, without a gateway. It's
+// meant to be deployed on the broker cluster, which has no gateway, behind a Service referenced by the conversion
+// webhook client config of the broker's Endpoint and Cluster CRDs, so the broker's API server can convert the objects
+// exchanged by clusters running different versions.
+package main
+
+import (
+	"flag"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/admiral/pkg/log/kzerolog"
+	admversion "github.com/submariner-io/admiral/pkg/version"
+	"github.com/submariner-io/submariner/pkg/versions"
+	"github.com/submariner-io/submariner/pkg/webhook"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+)
+
+const component = "submariner-webhook"
+
+type specification struct {
+	WebhookPort    int `default:"9443"`
+	WebhookCertDir string
+}
+
+var (
+	showVersion = false
+	logger      = log.Logger{Logger: logf.Log.WithName("main")}
+)
+
+func init() {
+	flag.BoolVar(&showVersion, "version", showVersion, "Show version")
+}
+
+func main() {
+	kzerolog.AddFlags(nil)
+	flag.Parse()
+
+	admversion.Print(component, versions.Submariner())
+
+	if showVersion {
+		return
+	}
+
+	kzerolog.InitK8sLogging()
+
+	versions.Log(&logger)
+
+	spec := specification{}
+	logger.FatalOnError(envconfig.Process("submariner", &spec), "Error processing env vars")
+
+	server, err := webhook.NewServer(spec.WebhookPort, spec.WebhookCertDir)
+	logger.FatalOnError(err, "Error creating the webhook server")
+
+	logger.Infof("Starting the webhook server on port %d", spec.WebhookPort)
+
+	logger.FatalOnError(server.Start(signals.SetupSignalHandler()), "Error running the webhook server")
+}